@@ -1 +1,22 @@
 package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/wiring"
+)
+
+func main() {
+	configFilePath := flag.String("config", "configs/local.yaml", "path to the config file")
+	flag.Parse()
+
+	server, cleanup, err := wiring.InitializeServer(configs.ConfigFilePath(*configFilePath))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer cleanup()
+
+	server.Start()
+}
@@ -2,36 +2,85 @@ package app
 
 import (
 	"context"
+	"os/signal"
+	"sync"
 	"syscall"
 
 	"github.com/quockhanhcao/my-internet-download-manager/internal/handler/grpc"
 	"github.com/quockhanhcao/my-internet-download-manager/internal/handler/http"
-	"github.com/quockhanhcao/my-internet-download-manager/internal/utils"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/logic"
 	"go.uber.org/zap"
 )
 
 type Server struct {
-	grpcServer grpc.Server
-	httpServer http.Server
-	logger     *zap.Logger
+	grpcServer            grpc.Server
+	httpServer            http.Server
+	downloadTaskExecutor  logic.DownloadTaskExecutor
+	downloadTaskScheduler logic.DownloadTaskScheduler
+	webhookDispatcher     logic.WebhookDispatcher
+	urlWatcher            logic.URLWatcher
+	logger                *zap.Logger
 }
 
-func NewServer(grpcServer grpc.Server, httpServer http.Server, logger *zap.Logger) *Server {
+func NewServer(
+	grpcServer grpc.Server,
+	httpServer http.Server,
+	downloadTaskExecutor logic.DownloadTaskExecutor,
+	downloadTaskScheduler logic.DownloadTaskScheduler,
+	webhookDispatcher logic.WebhookDispatcher,
+	urlWatcher logic.URLWatcher,
+	logger *zap.Logger,
+) *Server {
 	return &Server{
-		grpcServer: grpcServer,
-		httpServer: httpServer,
-		logger:     logger,
+		grpcServer:            grpcServer,
+		httpServer:            httpServer,
+		downloadTaskExecutor:  downloadTaskExecutor,
+		downloadTaskScheduler: downloadTaskScheduler,
+		webhookDispatcher:     webhookDispatcher,
+		urlWatcher:            urlWatcher,
+		logger:                logger,
 	}
 }
 
 func (s Server) Start() {
+	// cancelling the context on a signal lets the executor requeue the running downloads before the server exits
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(6)
 	go func() {
-		err := s.grpcServer.Start(context.Background())
+		defer waitGroup.Done()
+		err := s.grpcServer.Start(ctx)
 		s.logger.With(zap.Error(err)).Info("gRPC server stopped")
 	}()
 	go func() {
-		err := s.httpServer.Start(context.Background())
+		defer waitGroup.Done()
+		err := s.httpServer.Start(ctx)
 		s.logger.With(zap.Error(err)).Info("HTTP server stopped")
 	}()
-	utils.BlockUntilSignal(syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		defer waitGroup.Done()
+		err := s.downloadTaskExecutor.Start(ctx)
+		s.logger.With(zap.Error(err)).Info("download task executor stopped")
+	}()
+	go func() {
+		defer waitGroup.Done()
+		err := s.downloadTaskScheduler.Start(ctx)
+		s.logger.With(zap.Error(err)).Info("download task scheduler stopped")
+	}()
+	go func() {
+		defer waitGroup.Done()
+		err := s.webhookDispatcher.Start(ctx)
+		s.logger.With(zap.Error(err)).Info("webhook dispatcher stopped")
+	}()
+	go func() {
+		defer waitGroup.Done()
+		err := s.urlWatcher.Start(ctx)
+		s.logger.With(zap.Error(err)).Info("URL watcher stopped")
+	}()
+
+	<-ctx.Done()
+	s.logger.Info("shutting down")
+	waitGroup.Wait()
 }
@@ -15,6 +15,8 @@ type TokenConfig struct {
 type AuthConfig struct {
 	HashConfig  HashConfig
 	TokenConfig TokenConfig
+	// AdminAccountNames are the accounts allowed to call admin RPCs
+	AdminAccountNames []string `yaml:"admin_account_names"`
 }
 
 func (t TokenConfig) GetExpiresInDuration() (time.Duration, error) {
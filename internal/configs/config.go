@@ -13,6 +13,10 @@ type Config struct {
 	AuthConfig     AuthConfig     `yaml:"auth_config"`
 	LogConfig      LogConfig      `yaml:"log_config"`
 	CacheConfig    CacheConfig    `yaml:"cache_config"`
+	DownloadConfig DownloadConfig `yaml:"download_config"`
+	// PostDownloadConfig is left empty when no step is run after downloads
+	PostDownloadConfig PostDownloadConfig `yaml:"post_download_config"`
+	WebhookConfig      WebhookConfig      `yaml:"webhook_config"`
 }
 
 func NewConfig(filePath ConfigFilePath) (Config, error) {
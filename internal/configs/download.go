@@ -0,0 +1,160 @@
+package configs
+
+import (
+	"time"
+	// the configured time zone has to be found on hosts without a time zone database
+	_ "time/tzdata"
+)
+
+type FTPConfig struct {
+	DialTimeout        string `yaml:"dial_timeout"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+type SFTPConfig struct {
+	DialTimeout string `yaml:"dial_timeout"`
+}
+
+type TorrentConfig struct {
+	// Port is the port announced to trackers, incoming connections are not accepted since seeding is off
+	Port         int    `yaml:"port"`
+	MaxPeerCount int    `yaml:"max_peer_count"`
+	DialTimeout  string `yaml:"dial_timeout"`
+}
+
+// S3ProfileConfig holds the endpoint and credentials of an S3-compatible object store. Download tasks refer to a
+// profile by name instead of carrying the credentials themselves.
+type S3ProfileConfig struct {
+	// Endpoint is the URL of an S3-compatible service, buckets are then addressed in the path. When it is empty,
+	// AWS S3 in Region is used with virtual-hosted buckets.
+	Endpoint        string `yaml:"endpoint"`
+	Region          string `yaml:"region"`
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+	SessionToken    string `yaml:"session_token"`
+}
+
+type S3Config struct {
+	Profiles map[string]S3ProfileConfig `yaml:"profiles"`
+}
+
+// SpeedLimitPeriodConfig sets the speed limit of the whole server from Start to End, both times of day like "09:00" in
+// the time zone of the download config. A period whose End is not after its Start spans midnight.
+type SpeedLimitPeriodConfig struct {
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+	// Weekdays are days like "Mon" or "Monday" the period starts on, it applies to every day when empty
+	Weekdays    []string `yaml:"weekdays"`
+	BytesPerSec uint64   `yaml:"bytes_per_sec"`
+}
+
+// SpeedLimitConfig limits download bandwidth, 0 always means unlimited.
+type SpeedLimitConfig struct {
+	// ServerBytesPerSec limits all downloads together whenever no period of Schedule applies
+	ServerBytesPerSec uint64 `yaml:"server_bytes_per_sec"`
+	// AccountBytesPerSec limits the downloads of each account together, accounts can only lower it for themselves
+	AccountBytesPerSec uint64                   `yaml:"account_bytes_per_sec"`
+	Schedule           []SpeedLimitPeriodConfig `yaml:"schedule"`
+}
+
+// FairShareConfig sets the weights download slots are shared across accounts with, an account with twice the weight of
+// another gets twice as many downloads running at once while both have pending tasks.
+type FairShareConfig struct {
+	// DefaultAccountWeight applies to accounts missing from AccountWeights, 1 is used when it is 0
+	DefaultAccountWeight uint32            `yaml:"default_account_weight"`
+	AccountWeights       map[string]uint32 `yaml:"account_weights"`
+}
+
+// RetryConfig sets how download tasks failing with transient errors, like timeouts, connection resets and 5xx
+// responses, are retried. The wait before each retry doubles from InitialBackoff up to MaxBackoff, with random jitter.
+type RetryConfig struct {
+	// MaxAttemptCount applies to tasks created without their own, 1 turns retries off
+	MaxAttemptCount uint32 `yaml:"max_attempt_count"`
+	InitialBackoff  string `yaml:"initial_backoff"`
+	MaxBackoff      string `yaml:"max_backoff"`
+}
+
+func (r RetryConfig) GetInitialBackoffDuration() (time.Duration, error) {
+	return time.ParseDuration(r.InitialBackoff)
+}
+
+func (r RetryConfig) GetMaxBackoffDuration() (time.Duration, error) {
+	return time.ParseDuration(r.MaxBackoff)
+}
+
+// ArchiveExtractionConfig limits what extracting the archive of a download task may write, so that a small archive
+// cannot fill the disk. 0 uses the default of each limit.
+type ArchiveExtractionConfig struct {
+	MaxExtractedByteCount int64 `yaml:"max_extracted_byte_count"`
+	MaxExtractedFileCount int   `yaml:"max_extracted_file_count"`
+	// MaxCompressionRatio is how many times the size of the archive its extracted files may add up to
+	MaxCompressionRatio int64 `yaml:"max_compression_ratio"`
+}
+
+// DuplicateURLConfig sets how download tasks for URLs that were already downloaded reuse the existing file.
+type DuplicateURLConfig struct {
+	// ReuseAcrossAccounts lets a task reuse a file downloaded by another account, which tells accounts asking before
+	// reusing that someone else downloaded the URL
+	ReuseAcrossAccounts bool `yaml:"reuse_across_accounts"`
+	// FreshnessCheckTimeout bounds the conditional request checking the origin still serves the same file
+	FreshnessCheckTimeout string `yaml:"freshness_check_timeout"`
+}
+
+func (d DuplicateURLConfig) GetFreshnessCheckTimeoutDuration() (time.Duration, error) {
+	return time.ParseDuration(d.FreshnessCheckTimeout)
+}
+
+type DownloadConfig struct {
+	DownloadDirectory          string                  `yaml:"download_directory"`
+	PollInterval               string                  `yaml:"poll_interval"`
+	MaxConcurrentDownloadCount int                     `yaml:"max_concurrent_download_count"`
+	SegmentCount               int                     `yaml:"segment_count"`
+	MinSegmentSize             int64                   `yaml:"min_segment_size"`
+	ProgressSaveInterval       string                  `yaml:"progress_save_interval"`
+	FTPConfig                  FTPConfig               `yaml:"ftp_config"`
+	SFTPConfig                 SFTPConfig              `yaml:"sftp_config"`
+	TorrentConfig              TorrentConfig           `yaml:"torrent_config"`
+	S3Config                   S3Config                `yaml:"s3_config"`
+	SpeedLimitConfig           SpeedLimitConfig        `yaml:"speed_limit_config"`
+	FairShareConfig            FairShareConfig         `yaml:"fair_share_config"`
+	RetryConfig                RetryConfig             `yaml:"retry_config"`
+	ArchiveExtractionConfig    ArchiveExtractionConfig `yaml:"archive_extraction_config"`
+	DuplicateURLConfig         DuplicateURLConfig      `yaml:"duplicate_url_config"`
+	// LeaseDuration is how long a running download stays claimed by its server without renewing the claim, the tasks
+	// of a server that stopped are picked up by the others once it passes
+	LeaseDuration string `yaml:"lease_duration"`
+	// TimeZone is the IANA time zone of speed limit schedules and download queue time windows, the local time zone is
+	// used when it is empty
+	TimeZone string `yaml:"time_zone"`
+}
+
+func (d DownloadConfig) GetPollIntervalDuration() (time.Duration, error) {
+	return time.ParseDuration(d.PollInterval)
+}
+
+func (d DownloadConfig) GetProgressSaveIntervalDuration() (time.Duration, error) {
+	return time.ParseDuration(d.ProgressSaveInterval)
+}
+
+func (d DownloadConfig) GetLeaseDurationDuration() (time.Duration, error) {
+	return time.ParseDuration(d.LeaseDuration)
+}
+
+func (d DownloadConfig) GetTimeZoneLocation() (*time.Location, error) {
+	if d.TimeZone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(d.TimeZone)
+}
+
+func (f FTPConfig) GetDialTimeoutDuration() (time.Duration, error) {
+	return time.ParseDuration(f.DialTimeout)
+}
+
+func (s SFTPConfig) GetDialTimeoutDuration() (time.Duration, error) {
+	return time.ParseDuration(s.DialTimeout)
+}
+
+func (t TorrentConfig) GetDialTimeoutDuration() (time.Duration, error) {
+	return time.ParseDuration(t.DialTimeout)
+}
@@ -0,0 +1,41 @@
+package configs
+
+import "time"
+
+// PostDownloadStepConfig is one step run on the file of a download task once it succeeded. Values of the step may use
+// the variables of the task, like ${task_id}, ${file_name} or ${file_path}.
+type PostDownloadStepConfig struct {
+	// Name tells the step apart in the results recorded on the task, Type is used when it is empty
+	Name string `yaml:"name"`
+	// Type is one of rename, move, chmod, exec and http
+	Type string `yaml:"type"`
+	// OnFailure is "fail" to mark the task Failed and skip the remaining steps when the step fails, or "warn" to only
+	// record the failure, which is the default
+	OnFailure string `yaml:"on_failure"`
+	// FileName is the new name of the file for rename steps
+	FileName string `yaml:"file_name"`
+	// Directory is where move steps move the file, relative to the download directory
+	Directory string `yaml:"directory"`
+	// Mode is the octal permission chmod steps set, like "0640"
+	Mode string `yaml:"mode"`
+	// Command and Args are run by exec steps without a shell, the variables are also set in the environment
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args"`
+	// URL is called by http steps with Method, POST by default, and the variables of the task as a JSON body
+	URL     string            `yaml:"url"`
+	Method  string            `yaml:"method"`
+	Headers map[string]string `yaml:"headers"`
+	// Timeout bounds exec and http steps
+	Timeout string `yaml:"timeout"`
+}
+
+func (p PostDownloadStepConfig) GetTimeoutDuration() (time.Duration, error) {
+	return time.ParseDuration(p.Timeout)
+}
+
+// PostDownloadConfig sets the steps run, in order, on the file of every download task that succeeded.
+type PostDownloadConfig struct {
+	Steps []PostDownloadStepConfig `yaml:"steps"`
+	// AccountSteps replace Steps for the tasks of the named accounts
+	AccountSteps map[string][]PostDownloadStepConfig `yaml:"account_steps"`
+}
@@ -0,0 +1,22 @@
+package configs
+
+import "time"
+
+// WebhookConfig sets how download task events are delivered to the webhooks of accounts.
+type WebhookConfig struct {
+	PollInterval string `yaml:"poll_interval"`
+	// RequestTimeout bounds every delivery attempt
+	RequestTimeout string `yaml:"request_timeout"`
+	// ProgressMilestones are percentages of a download, like 25, 50 and 75, reported once the download passes them
+	ProgressMilestones []uint32 `yaml:"progress_milestones"`
+	// RetryConfig sets how failed deliveries are retried, a delivery failing its last attempt is dead-lettered
+	RetryConfig RetryConfig `yaml:"retry_config"`
+}
+
+func (w WebhookConfig) GetPollIntervalDuration() (time.Duration, error) {
+	return time.ParseDuration(w.PollInterval)
+}
+
+func (w WebhookConfig) GetRequestTimeoutDuration() (time.Duration, error) {
+	return time.ParseDuration(w.RequestTimeout)
+}
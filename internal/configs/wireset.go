@@ -8,4 +8,7 @@ var WireSet = wire.NewSet(
 	wire.FieldsOf(new(Config), "AuthConfig"),
 	wire.FieldsOf(new(Config), "LogConfig"),
     wire.FieldsOf(new(Config), "CacheConfig"),
+    wire.FieldsOf(new(Config), "DownloadConfig"),
+    wire.FieldsOf(new(Config), "PostDownloadConfig"),
+    wire.FieldsOf(new(Config), "WebhookConfig"),
 )
@@ -12,14 +12,16 @@ const (
 )
 
 type Account struct {
-	ID          uint64 `sql:"id"`
-	AccountName string `sql:"account_name"`
+	ID                    uint64 `db:"id"`
+	AccountName           string `db:"account_name"`
+	SpeedLimitBytesPerSec uint64 `db:"speed_limit_bytes_per_sec"`
 }
 
 type AccountDataAccessor interface {
 	CreateAccount(ctx context.Context, accountName string, password string) (uint64, error)
 	GetAccountByID(ctx context.Context, id uint64) (Account, error)
 	GetAccountByAccountName(ctx context.Context, accountName string) (Account, error)
+	UpdateAccountSpeedLimit(ctx context.Context, id uint64, speedLimitBytesPerSec uint64) error
 	WithDatabase(database Database) AccountDataAccessor
 }
 type accountDataAccessor struct {
@@ -60,7 +62,7 @@ func (a accountDataAccessor) GetAccountByAccountName(ctx context.Context, accoun
 
 	var account Account
 	found, err := a.database.From(TableAccount).
-		Select("id", "account_name").
+		Select("id", "account_name", "speed_limit_bytes_per_sec").
 		Where(goqu.Ex{"account_name": accountName}).
 		ScanStructContext(ctx, &account)
 
@@ -83,7 +85,7 @@ func (a accountDataAccessor) GetAccountByID(ctx context.Context, id uint64) (Acc
 
 	var account Account
 	found, err := a.database.From(TableAccount).
-		Select("id", "account_name").
+		Select("id", "account_name", "speed_limit_bytes_per_sec").
 		Where(goqu.Ex{"id": id}).
 		ScanStructContext(ctx, &account)
 
@@ -101,6 +103,22 @@ func (a accountDataAccessor) GetAccountByID(ctx context.Context, id uint64) (Acc
 	return account, nil
 }
 
+func (a accountDataAccessor) UpdateAccountSpeedLimit(ctx context.Context, id uint64, speedLimitBytesPerSec uint64) error {
+	a.logger.With(zap.Uint64("accountID", id), zap.Uint64("speedLimitBytesPerSec", speedLimitBytesPerSec)).Info("updating account speed limit")
+
+	_, err := a.database.Update(TableAccount).
+		Set(goqu.Record{"speed_limit_bytes_per_sec": speedLimitBytesPerSec}).
+		Where(goqu.Ex{"id": id}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		a.logger.With(zap.Error(err), zap.Uint64("accountID", id)).Error("failed to update account speed limit")
+		return err
+	}
+
+	return nil
+}
+
 func (a accountDataAccessor) WithDatabase(database Database) AccountDataAccessor {
 	return &accountDataAccessor{
 		database: database,
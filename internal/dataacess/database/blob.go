@@ -0,0 +1,140 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"go.uber.org/zap"
+)
+
+const (
+	TableBlob             = "blobs"
+	ColBlobSHA256         = "sha256"
+	ColBlobReferenceCount = "reference_count"
+)
+
+// Blob is a completed file stored once under its SHA-256 digest, ReferenceCount being how many download tasks have it
+// as their file.
+type Blob struct {
+	SHA256         string `db:"sha256" goqu:"skipupdate"`
+	Size           int64  `db:"size" goqu:"skipupdate"`
+	ReferenceCount uint32 `db:"reference_count"`
+}
+
+type BlobDataAccessor interface {
+	CreateBlob(ctx context.Context, blob Blob) error
+	GetBlobBySHA256WithXLock(ctx context.Context, sha256 string) (Blob, error)
+	GetBlobsBySHA256s(ctx context.Context, sha256s []string) ([]Blob, error)
+	UpdateBlobReferenceCount(ctx context.Context, sha256 string, referenceCount uint32) error
+	DeleteBlob(ctx context.Context, sha256 string) error
+	WithDatabase(database Database) BlobDataAccessor
+}
+
+type blobDataAccessor struct {
+	database Database
+	logger   *zap.Logger
+}
+
+func NewBlobDataAccessor(database *goqu.Database, logger *zap.Logger) BlobDataAccessor {
+	return &blobDataAccessor{
+		database: database,
+		logger:   logger,
+	}
+}
+
+// CreateBlob implements BlobDataAccessor.
+func (d blobDataAccessor) CreateBlob(ctx context.Context, blob Blob) error {
+	d.logger.With(zap.String("sha256", blob.SHA256), zap.Int64("size", blob.Size)).Info("creating blob in database")
+
+	_, err := d.database.Insert(TableBlob).Rows(blob).Executor().ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.String("sha256", blob.SHA256)).Error("failed to insert blob")
+		return err
+	}
+
+	return nil
+}
+
+// GetBlobBySHA256WithXLock implements BlobDataAccessor. The lock is also taken when the blob does not exist, which
+// keeps others from creating it until the transaction ends.
+func (d blobDataAccessor) GetBlobBySHA256WithXLock(ctx context.Context, sha256 string) (Blob, error) {
+	d.logger.With(zap.String("sha256", sha256)).Info("getting blob by SHA-256 with exclusive lock")
+
+	var blob Blob
+	found, err := d.database.From(TableBlob).
+		Where(goqu.Ex{ColBlobSHA256: sha256}).
+		ForUpdate(exp.Wait).
+		ScanStructContext(ctx, &blob)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.String("sha256", sha256)).Error("failed to get blob by SHA-256")
+		return Blob{}, err
+	}
+
+	if !found {
+		return Blob{}, sql.ErrNoRows
+	}
+
+	return blob, nil
+}
+
+// GetBlobsBySHA256s implements BlobDataAccessor.
+func (d blobDataAccessor) GetBlobsBySHA256s(ctx context.Context, sha256s []string) ([]Blob, error) {
+	d.logger.With(zap.Int("count", len(sha256s))).Info("getting blobs by SHA-256")
+
+	blobs := make([]Blob, 0, len(sha256s))
+	if len(sha256s) == 0 {
+		return blobs, nil
+	}
+
+	err := d.database.From(TableBlob).
+		Where(goqu.C(ColBlobSHA256).In(sha256s)).
+		ScanStructsContext(ctx, &blobs)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to get blobs by SHA-256")
+		return nil, err
+	}
+
+	return blobs, nil
+}
+
+// UpdateBlobReferenceCount implements BlobDataAccessor.
+func (d blobDataAccessor) UpdateBlobReferenceCount(ctx context.Context, sha256 string, referenceCount uint32) error {
+	d.logger.With(zap.String("sha256", sha256), zap.Uint32("referenceCount", referenceCount)).Info("updating blob reference count")
+
+	_, err := d.database.Update(TableBlob).
+		Set(goqu.Record{ColBlobReferenceCount: referenceCount}).
+		Where(goqu.Ex{ColBlobSHA256: sha256}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.String("sha256", sha256)).Error("failed to update blob reference count")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteBlob implements BlobDataAccessor.
+func (d blobDataAccessor) DeleteBlob(ctx context.Context, sha256 string) error {
+	d.logger.With(zap.String("sha256", sha256)).Info("deleting blob")
+
+	_, err := d.database.Delete(TableBlob).
+		Where(goqu.Ex{ColBlobSHA256: sha256}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.String("sha256", sha256)).Error("failed to delete blob")
+		return err
+	}
+
+	return nil
+}
+
+func (d blobDataAccessor) WithDatabase(database Database) BlobDataAccessor {
+	return &blobDataAccessor{
+		database: database,
+		logger:   d.logger,
+	}
+}
@@ -0,0 +1,177 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"go.uber.org/zap"
+)
+
+const (
+	TableDownloadQueue = "download_queues"
+	ColDownloadQueueID = "id"
+	ColPriority        = "priority"
+)
+
+type DownloadQueue struct {
+	ID                         uint64 `db:"id" goqu:"skipinsert,skipupdate"`
+	OfAccountID                uint64 `db:"of_account_id" goqu:"skipupdate"`
+	Name                       string `db:"name"`
+	Priority                   int32  `db:"priority"`
+	MaxConcurrentDownloadCount uint32 `db:"max_concurrent_download_count"`
+	// ActiveStartTime and ActiveEndTime are times of day like "09:00", both are empty when the queue is always active
+	ActiveStartTime string `db:"active_start_time"`
+	ActiveEndTime   string `db:"active_end_time"`
+}
+
+type DownloadQueueDataAccessor interface {
+	CreateDownloadQueue(ctx context.Context, queue DownloadQueue) (uint64, error)
+	GetDownloadQueueByID(ctx context.Context, id uint64) (DownloadQueue, error)
+	GetDownloadQueueByIDWithXLock(ctx context.Context, id uint64) (DownloadQueue, error)
+	GetDownloadQueuesByAccountID(ctx context.Context, accountID uint64) ([]DownloadQueue, error)
+	GetDownloadQueues(ctx context.Context) ([]DownloadQueue, error)
+	UpdateDownloadQueue(ctx context.Context, queue DownloadQueue) error
+	DeleteDownloadQueue(ctx context.Context, id uint64) error
+	WithDatabase(database Database) DownloadQueueDataAccessor
+}
+
+type downloadQueueDataAccessor struct {
+	database Database
+	logger   *zap.Logger
+}
+
+func NewDownloadQueueDataAccessor(database *goqu.Database, logger *zap.Logger) DownloadQueueDataAccessor {
+	return &downloadQueueDataAccessor{
+		database: database,
+		logger:   logger,
+	}
+}
+
+// CreateDownloadQueue implements DownloadQueueDataAccessor.
+func (d downloadQueueDataAccessor) CreateDownloadQueue(ctx context.Context, queue DownloadQueue) (uint64, error) {
+	d.logger.With(zap.Uint64("accountID", queue.OfAccountID), zap.String("name", queue.Name)).Info("creating download queue in database")
+
+	result, err := d.database.Insert(TableDownloadQueue).Rows(queue).Executor().ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("accountID", queue.OfAccountID)).Error("failed to insert download queue")
+		return 0, err
+	}
+
+	queueID, err := result.LastInsertId()
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("accountID", queue.OfAccountID)).Error("failed to get last insert ID")
+		return 0, err
+	}
+
+	return uint64(queueID), nil
+}
+
+func (d downloadQueueDataAccessor) getDownloadQueueByID(ctx context.Context, id uint64, lock bool) (DownloadQueue, error) {
+	query := d.database.From(TableDownloadQueue).Where(goqu.Ex{ColDownloadQueueID: id})
+	if lock {
+		query = query.ForUpdate(exp.Wait)
+	}
+
+	var queue DownloadQueue
+	found, err := query.ScanStructContext(ctx, &queue)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("queueID", id)).Error("failed to get download queue by ID")
+		return DownloadQueue{}, err
+	}
+
+	if !found {
+		d.logger.With(zap.Uint64("queueID", id)).Warn("download queue not found")
+		return DownloadQueue{}, sql.ErrNoRows
+	}
+
+	return queue, nil
+}
+
+// GetDownloadQueueByID implements DownloadQueueDataAccessor.
+func (d downloadQueueDataAccessor) GetDownloadQueueByID(ctx context.Context, id uint64) (DownloadQueue, error) {
+	d.logger.With(zap.Uint64("queueID", id)).Info("getting download queue by ID")
+	return d.getDownloadQueueByID(ctx, id, false)
+}
+
+// GetDownloadQueueByIDWithXLock implements DownloadQueueDataAccessor.
+//
+// Changes to the tasks of a queue lock the queue first, so that concurrent moves do not mix up the task order.
+func (d downloadQueueDataAccessor) GetDownloadQueueByIDWithXLock(ctx context.Context, id uint64) (DownloadQueue, error) {
+	d.logger.With(zap.Uint64("queueID", id)).Info("getting download queue by ID with exclusive lock")
+	return d.getDownloadQueueByID(ctx, id, true)
+}
+
+// GetDownloadQueuesByAccountID implements DownloadQueueDataAccessor.
+func (d downloadQueueDataAccessor) GetDownloadQueuesByAccountID(ctx context.Context, accountID uint64) ([]DownloadQueue, error) {
+	d.logger.With(zap.Uint64("accountID", accountID)).Info("getting download queues by account ID")
+
+	queues := make([]DownloadQueue, 0)
+	err := d.database.From(TableDownloadQueue).
+		Where(goqu.Ex{ColOfAccountID: accountID}).
+		Order(goqu.C(ColDownloadQueueID).Asc()).
+		ScanStructsContext(ctx, &queues)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("accountID", accountID)).Error("failed to get download queues by account ID")
+		return nil, err
+	}
+
+	return queues, nil
+}
+
+// GetDownloadQueues implements DownloadQueueDataAccessor.
+func (d downloadQueueDataAccessor) GetDownloadQueues(ctx context.Context) ([]DownloadQueue, error) {
+	d.logger.Debug("getting all download queues")
+
+	queues := make([]DownloadQueue, 0)
+	err := d.database.From(TableDownloadQueue).
+		Order(goqu.C(ColDownloadQueueID).Asc()).
+		ScanStructsContext(ctx, &queues)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to get download queues")
+		return nil, err
+	}
+
+	return queues, nil
+}
+
+// UpdateDownloadQueue implements DownloadQueueDataAccessor.
+func (d downloadQueueDataAccessor) UpdateDownloadQueue(ctx context.Context, queue DownloadQueue) error {
+	d.logger.With(zap.Uint64("queueID", queue.ID)).Info("updating download queue")
+
+	_, err := d.database.Update(TableDownloadQueue).
+		Set(queue).
+		Where(goqu.Ex{ColDownloadQueueID: queue.ID}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("queueID", queue.ID)).Error("failed to update download queue")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteDownloadQueue implements DownloadQueueDataAccessor.
+func (d downloadQueueDataAccessor) DeleteDownloadQueue(ctx context.Context, id uint64) error {
+	d.logger.With(zap.Uint64("queueID", id)).Info("deleting download queue")
+
+	_, err := d.database.Delete(TableDownloadQueue).
+		Where(goqu.Ex{ColDownloadQueueID: id}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("queueID", id)).Error("failed to delete download queue")
+		return err
+	}
+
+	return nil
+}
+
+func (d downloadQueueDataAccessor) WithDatabase(database Database) DownloadQueueDataAccessor {
+	return &downloadQueueDataAccessor{
+		database: database,
+		logger:   d.logger,
+	}
+}
@@ -2,56 +2,741 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"go.uber.org/zap"
+)
+
+const (
+	TableDownloadTask = "download_tasks"
+	ColDownloadTaskID = "id"
+	ColDownloadType   = "download_type"
+	ColURL            = "url"
+	ColDownloadStatus = "download_status"
+	ColMetadata       = "metadata"
+	ColOfQueueID      = "of_queue_id"
+	ColQueuePosition  = "queue_position"
+	ColNextRunAt      = "next_run_at"
+	ColAttemptCount   = "attempt_count"
+	ColNextRetryAt    = "next_retry_at"
+	ColBlobSHA256Ref  = "blob_sha256"
+	ColNextCheckAt    = "next_check_at"
+	ColOwnerID        = "owner_id"
+	ColLeaseExpiresAt = "lease_expires_at"
 )
 
 type DownloadTask struct {
-	OfAccountId    uint64 `sql:"of_account_id"`
-	TaskID         uint64 `sql:"task_id"`
-	DownloadType   string `sql:"download_type"`
-	URL            string `sql:"url"`
-	DownloadStatus int    `sql:"download_status"`
-	Metadata       string `sql:"metadata"`
+	ID             uint64 `db:"id" goqu:"skipinsert,skipupdate"`
+	OfAccountID    uint64 `db:"of_account_id"`
+	DownloadType   uint16 `db:"download_type"`
+	URL            string `db:"url"`
+	DownloadStatus uint16 `db:"download_status"`
+	Metadata       string `db:"metadata"`
+	// SpeedLimitBytesPerSec is kept out of the metadata, which the executor keeps overwriting while downloading
+	SpeedLimitBytesPerSec uint64 `db:"speed_limit_bytes_per_sec"`
+	// OfQueueID is 0 for tasks outside of any download queue, QueuePosition orders the tasks of a queue
+	OfQueueID     uint64 `db:"of_queue_id"`
+	QueuePosition uint64 `db:"queue_position"`
+	// NextRunAt is when a Scheduled task is due. Recurring tasks have a CronExpression read in TimeZone, they stay
+	// Scheduled and start a new task on every run.
+	NextRunAt      sql.NullTime `db:"next_run_at"`
+	CronExpression string       `db:"cron_expression"`
+	TimeZone       string       `db:"time_zone"`
+	// AttemptCount is how many attempts at downloading the task ended. A task failing with a transient error is kept
+	// Pending until NextRetryAt while it has attempts left, MaxAttemptCount is 0 when the configured default applies.
+	AttemptCount    uint32       `db:"attempt_count"`
+	MaxAttemptCount uint32       `db:"max_attempt_count"`
+	NextRetryAt     sql.NullTime `db:"next_retry_at"`
+	// MirrorURLs are other URLs serving the same file as URL, one per line
+	MirrorURLs string `db:"mirror_urls"`
+	// BlobSHA256 is the blob the file of a completed task is stored as, it is empty for files stored on their own
+	BlobSHA256 string `db:"blob_sha256"`
+	// NextCheckAt is when a watched task checks its URL for a new version of the file next
+	NextCheckAt sql.NullTime `db:"next_check_at"`
+	// OwnerID is the executor running a Downloading task, it keeps the task until LeaseExpiresAt unless it renews the
+	// lease. Updating a whole task leaves both alone, only the executor changes them.
+	OwnerID        string       `db:"owner_id" goqu:"skipupdate"`
+	LeaseExpiresAt sql.NullTime `db:"lease_expires_at" goqu:"skipupdate"`
 }
 
 type DownloadTaskDataAccessor interface {
-	CreateDownloadTask(ctx context.Context, task DownloadTask) error
+	CreateDownloadTask(ctx context.Context, task DownloadTask) (uint64, error)
 	GetDownloadTaskByID(ctx context.Context, id uint64) (DownloadTask, error)
-	GetDownloadTasksByAccountID(ctx context.Context, accountID uint64) ([]DownloadTask, error)
+	GetDownloadTaskByIDWithXLock(ctx context.Context, id uint64) (DownloadTask, error)
+	GetDownloadTasksByAccountID(ctx context.Context, accountID uint64, offset, limit uint64) ([]DownloadTask, error)
+	GetDownloadTaskCountByAccountID(ctx context.Context, accountID uint64) (uint64, error)
+	GetDownloadTasksByAccountIDAndStatus(ctx context.Context, accountID uint64, status uint16) ([]DownloadTask, error)
+	GetDownloadTasksByURLAndStatus(ctx context.Context, url string, status uint16, limit uint64) ([]DownloadTask, error)
+	GetDownloadTasksByStatus(ctx context.Context, status uint16, limit uint64) ([]DownloadTask, error)
+	GetDownloadTasksByQueueID(ctx context.Context, queueID uint64) ([]DownloadTask, error)
+	GetDownloadTasksByStatusInPriorityOrder(ctx context.Context, status uint16, accountID uint64, queueIDs []uint64, dueTime time.Time, limit uint64) ([]DownloadTask, error)
+	GetDownloadTaskCountsByQueueID(ctx context.Context, status uint16) (map[uint64]uint64, error)
+	GetDownloadTaskCountsByAccountID(ctx context.Context, status uint16) (map[uint64]uint64, error)
+	GetDownloadTasksByStatusDueBefore(ctx context.Context, status uint16, dueTime time.Time, limit uint64) ([]DownloadTask, error)
+	GetDownloadTasksByStatusCheckDueBefore(ctx context.Context, status uint16, dueTime time.Time, limit uint64) ([]DownloadTask, error)
 	UpdateDownloadTask(ctx context.Context, task DownloadTask) error
+	UpdateDownloadTaskStatusIfMatch(ctx context.Context, id uint64, fromStatus, toStatus uint16) (bool, error)
+	UpdateDownloadTaskStatusAndMetadata(ctx context.Context, id uint64, status uint16, metadata string) error
+	UpdateDownloadTaskMetadata(ctx context.Context, id uint64, metadata string) error
+	UpdateDownloadTaskMetadataIfOwner(ctx context.Context, id uint64, status uint16, ownerID string, metadata string) (bool, error)
+	ClaimDownloadTask(ctx context.Context, id uint64, fromStatus, toStatus uint16, ownerID string, leaseExpiresAt time.Time) (bool, error)
+	RenewDownloadTaskLease(ctx context.Context, id uint64, status uint16, ownerID string, leaseExpiresAt time.Time) (bool, error)
+	UpdateDownloadTaskStatusIfLeaseExpired(ctx context.Context, fromStatus, toStatus uint16, now time.Time) (uint64, error)
+	UpdateDownloadTaskQueue(ctx context.Context, id uint64, queueID uint64, queuePosition uint64) error
+	UpdateDownloadTaskNextRunAt(ctx context.Context, id uint64, nextRunAt time.Time) error
+	UpdateDownloadTaskNextCheckAtIfDue(ctx context.Context, id uint64, status uint16, dueTime time.Time, nextCheckAt time.Time) (bool, error)
+	UpdateDownloadTaskAfterAttempt(ctx context.Context, id uint64, status uint16, metadata string, attemptCount uint32, nextRetryAt sql.NullTime) error
+	UpdateDownloadTaskBlobSHA256(ctx context.Context, id uint64, blobSHA256 string) error
+	RemoveDownloadTasksFromQueue(ctx context.Context, queueID uint64) error
 	DeleteDownloadTask(ctx context.Context, id uint64) error
+	WithDatabase(database Database) DownloadTaskDataAccessor
+}
+
+type downloadTaskDataAccessor struct {
+	database Database
+	logger   *zap.Logger
 }
 
-type downloadTaskAccessor struct {
-	database *goqu.Database
+func NewDownloadTaskDataAccessor(database *goqu.Database, logger *zap.Logger) DownloadTaskDataAccessor {
+	return &downloadTaskDataAccessor{
+		database: database,
+		logger:   logger,
+	}
 }
 
 // CreateDownloadTask implements DownloadTaskDataAccessor.
-func (a *downloadTaskAccessor) CreateDownloadTask(ctx context.Context, task DownloadTask) error {
-	panic("unimplemented")
+func (d downloadTaskDataAccessor) CreateDownloadTask(ctx context.Context, task DownloadTask) (uint64, error) {
+	d.logger.With(zap.Uint64("accountID", task.OfAccountID), zap.String("url", task.URL)).Info("creating download task in database")
+
+	result, err := d.database.Insert(TableDownloadTask).Rows(task).Executor().ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("accountID", task.OfAccountID)).Error("failed to insert download task")
+		return 0, err
+	}
+
+	taskID, err := result.LastInsertId()
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("accountID", task.OfAccountID)).Error("failed to get last insert ID")
+		return 0, err
+	}
+
+	d.logger.With(zap.Uint64("taskID", uint64(taskID))).Info("download task created successfully in database")
+	return uint64(taskID), nil
+}
+
+func (d downloadTaskDataAccessor) getDownloadTaskByID(ctx context.Context, id uint64, lock bool) (DownloadTask, error) {
+	query := d.database.From(TableDownloadTask).Where(goqu.Ex{ColDownloadTaskID: id})
+	if lock {
+		query = query.ForUpdate(exp.Wait)
+	}
+
+	var task DownloadTask
+	found, err := query.ScanStructContext(ctx, &task)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", id)).Error("failed to get download task by ID")
+		return DownloadTask{}, err
+	}
+
+	if !found {
+		d.logger.With(zap.Uint64("taskID", id)).Warn("download task not found")
+		return DownloadTask{}, sql.ErrNoRows
+	}
+
+	return task, nil
 }
 
 // GetDownloadTaskByID implements DownloadTaskDataAccessor.
-func (a *downloadTaskAccessor) GetDownloadTaskByID(ctx context.Context, id uint64) (DownloadTask, error) {
-	panic("unimplemented")
+func (d downloadTaskDataAccessor) GetDownloadTaskByID(ctx context.Context, id uint64) (DownloadTask, error) {
+	d.logger.With(zap.Uint64("taskID", id)).Info("getting download task by ID")
+	return d.getDownloadTaskByID(ctx, id, false)
+}
+
+// GetDownloadTaskByIDWithXLock implements DownloadTaskDataAccessor.
+func (d downloadTaskDataAccessor) GetDownloadTaskByIDWithXLock(ctx context.Context, id uint64) (DownloadTask, error) {
+	d.logger.With(zap.Uint64("taskID", id)).Info("getting download task by ID with exclusive lock")
+	return d.getDownloadTaskByID(ctx, id, true)
 }
 
 // GetDownloadTasksByAccountID implements DownloadTaskDataAccessor.
-func (a *downloadTaskAccessor) GetDownloadTasksByAccountID(ctx context.Context, accountID uint64) ([]DownloadTask, error) {
-	panic("unimplemented")
+func (d downloadTaskDataAccessor) GetDownloadTasksByAccountID(ctx context.Context, accountID uint64, offset, limit uint64) ([]DownloadTask, error) {
+	d.logger.With(zap.Uint64("accountID", accountID), zap.Uint64("offset", offset), zap.Uint64("limit", limit)).Info("getting download tasks by account ID")
+
+	tasks := make([]DownloadTask, 0)
+	err := d.database.From(TableDownloadTask).
+		Where(goqu.Ex{ColOfAccountID: accountID}).
+		Order(goqu.C(ColDownloadTaskID).Asc()).
+		Offset(uint(offset)).
+		Limit(uint(limit)).
+		ScanStructsContext(ctx, &tasks)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("accountID", accountID)).Error("failed to get download tasks by account ID")
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// GetDownloadTaskCountByAccountID implements DownloadTaskDataAccessor.
+func (d downloadTaskDataAccessor) GetDownloadTaskCountByAccountID(ctx context.Context, accountID uint64) (uint64, error) {
+	d.logger.With(zap.Uint64("accountID", accountID)).Info("counting download tasks by account ID")
+
+	count, err := d.database.From(TableDownloadTask).
+		Where(goqu.Ex{ColOfAccountID: accountID}).
+		CountContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("accountID", accountID)).Error("failed to count download tasks by account ID")
+		return 0, err
+	}
+
+	return uint64(count), nil
+}
+
+// GetDownloadTasksByAccountIDAndStatus implements DownloadTaskDataAccessor.
+func (d downloadTaskDataAccessor) GetDownloadTasksByAccountIDAndStatus(ctx context.Context, accountID uint64, status uint16) ([]DownloadTask, error) {
+	d.logger.With(zap.Uint64("accountID", accountID), zap.Uint16("status", status)).Info("getting download tasks by account ID and status")
+
+	tasks := make([]DownloadTask, 0)
+	err := d.database.From(TableDownloadTask).
+		Where(goqu.Ex{ColOfAccountID: accountID, ColDownloadStatus: status}).
+		Order(goqu.C(ColDownloadTaskID).Asc()).
+		ScanStructsContext(ctx, &tasks)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("accountID", accountID)).Error("failed to get download tasks by account ID and status")
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// GetDownloadTasksByURLAndStatus implements DownloadTaskDataAccessor. The newest tasks are returned first.
+func (d downloadTaskDataAccessor) GetDownloadTasksByURLAndStatus(ctx context.Context, url string, status uint16, limit uint64) ([]DownloadTask, error) {
+	d.logger.With(zap.String("url", url), zap.Uint16("status", status)).Info("getting download tasks by URL and status")
+
+	tasks := make([]DownloadTask, 0)
+	err := d.database.From(TableDownloadTask).
+		Where(goqu.Ex{ColURL: url, ColDownloadStatus: status}).
+		Order(goqu.C(ColDownloadTaskID).Desc()).
+		Limit(uint(limit)).
+		ScanStructsContext(ctx, &tasks)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.String("url", url)).Error("failed to get download tasks by URL and status")
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// GetDownloadTasksByStatus implements DownloadTaskDataAccessor.
+func (d downloadTaskDataAccessor) GetDownloadTasksByStatus(ctx context.Context, status uint16, limit uint64) ([]DownloadTask, error) {
+	d.logger.With(zap.Uint16("status", status), zap.Uint64("limit", limit)).Debug("getting download tasks by status")
+
+	tasks := make([]DownloadTask, 0)
+	err := d.database.From(TableDownloadTask).
+		Where(goqu.Ex{ColDownloadStatus: status}).
+		Order(goqu.C(ColDownloadTaskID).Asc()).
+		Limit(uint(limit)).
+		ScanStructsContext(ctx, &tasks)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint16("status", status)).Error("failed to get download tasks by status")
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// GetDownloadTasksByQueueID implements DownloadTaskDataAccessor.
+func (d downloadTaskDataAccessor) GetDownloadTasksByQueueID(ctx context.Context, queueID uint64) ([]DownloadTask, error) {
+	d.logger.With(zap.Uint64("queueID", queueID)).Info("getting download tasks by queue ID")
+
+	tasks := make([]DownloadTask, 0)
+	err := d.database.From(TableDownloadTask).
+		Where(goqu.Ex{ColOfQueueID: queueID}).
+		Order(goqu.C(ColQueuePosition).Asc(), goqu.C(ColDownloadTaskID).Asc()).
+		ScanStructsContext(ctx, &tasks)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("queueID", queueID)).Error("failed to get download tasks by queue ID")
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// GetDownloadTasksByStatusInPriorityOrder implements DownloadTaskDataAccessor.
+//
+// Only tasks of the account in the given queues are returned, 0 standing for tasks outside of any queue, leaving out
+// tasks waiting to be retried after dueTime. Tasks of queues with a higher priority come first, then tasks earlier in
+// their queue, then older tasks.
+func (d downloadTaskDataAccessor) GetDownloadTasksByStatusInPriorityOrder(ctx context.Context, status uint16, accountID uint64, queueIDs []uint64, dueTime time.Time, limit uint64) ([]DownloadTask, error) {
+	d.logger.With(zap.Uint16("status", status), zap.Uint64("accountID", accountID), zap.Uint64s("queueIDs", queueIDs), zap.Uint64("limit", limit)).
+		Debug("getting download tasks by status in priority order")
+
+	tasks := make([]DownloadTask, 0)
+	if len(queueIDs) == 0 {
+		return tasks, nil
+	}
+
+	err := d.database.From(goqu.T(TableDownloadTask).As("t")).
+		Select(goqu.T("t").All()).
+		LeftJoin(goqu.T(TableDownloadQueue).As("q"), goqu.On(goqu.T("t").Col(ColOfQueueID).Eq(goqu.T("q").Col(ColDownloadQueueID)))).
+		Where(
+			goqu.T("t").Col(ColDownloadStatus).Eq(status),
+			goqu.T("t").Col(ColOfAccountID).Eq(accountID),
+			goqu.T("t").Col(ColOfQueueID).In(queueIDs),
+			goqu.Or(goqu.T("t").Col(ColNextRetryAt).IsNull(), goqu.T("t").Col(ColNextRetryAt).Lte(dueTime)),
+		).
+		Order(
+			goqu.COALESCE(goqu.T("q").Col(ColPriority), 0).Desc(),
+			goqu.T("t").Col(ColQueuePosition).Asc(),
+			goqu.T("t").Col(ColDownloadTaskID).Asc(),
+		).
+		Limit(uint(limit)).
+		ScanStructsContext(ctx, &tasks)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to get download tasks by status in priority order")
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// GetDownloadTaskCountsByQueueID implements DownloadTaskDataAccessor.
+func (d downloadTaskDataAccessor) GetDownloadTaskCountsByQueueID(ctx context.Context, status uint16) (map[uint64]uint64, error) {
+	d.logger.With(zap.Uint16("status", status)).Debug("counting download tasks by queue ID")
+
+	rows := make([]struct {
+		QueueID uint64 `db:"of_queue_id"`
+		Count   uint64 `db:"count"`
+	}, 0)
+	err := d.database.From(TableDownloadTask).
+		Select(goqu.C(ColOfQueueID), goqu.COUNT("*").As("count")).
+		Where(goqu.Ex{ColDownloadStatus: status}).
+		GroupBy(goqu.C(ColOfQueueID)).
+		ScanStructsContext(ctx, &rows)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint16("status", status)).Error("failed to count download tasks by queue ID")
+		return nil, err
+	}
+
+	counts := make(map[uint64]uint64, len(rows))
+	for _, row := range rows {
+		counts[row.QueueID] = row.Count
+	}
+	return counts, nil
+}
+
+// GetDownloadTaskCountsByAccountID implements DownloadTaskDataAccessor.
+func (d downloadTaskDataAccessor) GetDownloadTaskCountsByAccountID(ctx context.Context, status uint16) (map[uint64]uint64, error) {
+	d.logger.With(zap.Uint16("status", status)).Debug("counting download tasks by account ID")
+
+	rows := make([]struct {
+		AccountID uint64 `db:"of_account_id"`
+		Count     uint64 `db:"count"`
+	}, 0)
+	err := d.database.From(TableDownloadTask).
+		Select(goqu.C(ColOfAccountID), goqu.COUNT("*").As("count")).
+		Where(goqu.Ex{ColDownloadStatus: status}).
+		GroupBy(goqu.C(ColOfAccountID)).
+		ScanStructsContext(ctx, &rows)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint16("status", status)).Error("failed to count download tasks by account ID")
+		return nil, err
+	}
+
+	counts := make(map[uint64]uint64, len(rows))
+	for _, row := range rows {
+		counts[row.AccountID] = row.Count
+	}
+	return counts, nil
+}
+
+// GetDownloadTasksByStatusDueBefore implements DownloadTaskDataAccessor.
+func (d downloadTaskDataAccessor) GetDownloadTasksByStatusDueBefore(ctx context.Context, status uint16, dueTime time.Time, limit uint64) ([]DownloadTask, error) {
+	d.logger.With(zap.Uint16("status", status), zap.Time("dueTime", dueTime), zap.Uint64("limit", limit)).
+		Debug("getting due download tasks by status")
+
+	tasks := make([]DownloadTask, 0)
+	err := d.database.From(TableDownloadTask).
+		Where(goqu.C(ColDownloadStatus).Eq(status), goqu.C(ColNextRunAt).Lte(dueTime)).
+		Order(goqu.C(ColNextRunAt).Asc(), goqu.C(ColDownloadTaskID).Asc()).
+		Limit(uint(limit)).
+		ScanStructsContext(ctx, &tasks)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint16("status", status)).Error("failed to get due download tasks by status")
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// GetDownloadTasksByStatusCheckDueBefore implements DownloadTaskDataAccessor.
+func (d downloadTaskDataAccessor) GetDownloadTasksByStatusCheckDueBefore(ctx context.Context, status uint16, dueTime time.Time, limit uint64) ([]DownloadTask, error) {
+	d.logger.With(zap.Uint16("status", status), zap.Time("dueTime", dueTime), zap.Uint64("limit", limit)).
+		Debug("getting download tasks due for a check by status")
+
+	tasks := make([]DownloadTask, 0)
+	err := d.database.From(TableDownloadTask).
+		Where(goqu.C(ColDownloadStatus).Eq(status), goqu.C(ColNextCheckAt).Lte(dueTime)).
+		Order(goqu.C(ColNextCheckAt).Asc(), goqu.C(ColDownloadTaskID).Asc()).
+		Limit(uint(limit)).
+		ScanStructsContext(ctx, &tasks)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint16("status", status)).Error("failed to get download tasks due for a check by status")
+		return nil, err
+	}
+
+	return tasks, nil
 }
 
 // UpdateDownloadTask implements DownloadTaskDataAccessor.
-func (a *downloadTaskAccessor) UpdateDownloadTask(ctx context.Context, task DownloadTask) error {
-	panic("unimplemented")
+func (d downloadTaskDataAccessor) UpdateDownloadTask(ctx context.Context, task DownloadTask) error {
+	d.logger.With(zap.Uint64("taskID", task.ID)).Info("updating download task")
+
+	_, err := d.database.Update(TableDownloadTask).
+		Set(task).
+		Where(goqu.Ex{ColDownloadTaskID: task.ID}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", task.ID)).Error("failed to update download task")
+		return err
+	}
+
+	return nil
+}
+
+// UpdateDownloadTaskStatusIfMatch implements DownloadTaskDataAccessor.
+//
+// The status is only changed when the row still has fromStatus, so several workers can race to claim the same task
+// and exactly one of them wins.
+func (d downloadTaskDataAccessor) UpdateDownloadTaskStatusIfMatch(ctx context.Context, id uint64, fromStatus, toStatus uint16) (bool, error) {
+	d.logger.With(zap.Uint64("taskID", id), zap.Uint16("fromStatus", fromStatus), zap.Uint16("toStatus", toStatus)).Info("updating download task status")
+
+	result, err := d.database.Update(TableDownloadTask).
+		Set(goqu.Record{ColDownloadStatus: toStatus}).
+		Where(goqu.Ex{ColDownloadTaskID: id, ColDownloadStatus: fromStatus}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", id)).Error("failed to update download task status")
+		return false, err
+	}
+
+	affectedRowCount, err := result.RowsAffected()
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", id)).Error("failed to get affected row count")
+		return false, err
+	}
+
+	return affectedRowCount == 1, nil
+}
+
+// UpdateDownloadTaskStatusAndMetadata implements DownloadTaskDataAccessor.
+func (d downloadTaskDataAccessor) UpdateDownloadTaskStatusAndMetadata(ctx context.Context, id uint64, status uint16, metadata string) error {
+	d.logger.With(zap.Uint64("taskID", id), zap.Uint16("status", status)).Info("updating download task status and metadata")
+
+	_, err := d.database.Update(TableDownloadTask).
+		Set(goqu.Record{ColDownloadStatus: status, ColMetadata: metadata}).
+		Where(goqu.Ex{ColDownloadTaskID: id}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", id)).Error("failed to update download task status and metadata")
+		return err
+	}
+
+	return nil
+}
+
+// UpdateDownloadTaskMetadata implements DownloadTaskDataAccessor.
+func (d downloadTaskDataAccessor) UpdateDownloadTaskMetadata(ctx context.Context, id uint64, metadata string) error {
+	d.logger.With(zap.Uint64("taskID", id)).Debug("updating download task metadata")
+
+	_, err := d.database.Update(TableDownloadTask).
+		Set(goqu.Record{ColMetadata: metadata}).
+		Where(goqu.Ex{ColDownloadTaskID: id}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", id)).Error("failed to update download task metadata")
+		return err
+	}
+
+	return nil
+}
+
+// UpdateDownloadTaskMetadataIfOwner implements DownloadTaskDataAccessor. It reports whether the task was still in the
+// status and owned by ownerID, an executor that lost its task does not overwrite the progress of the one running it now.
+func (d downloadTaskDataAccessor) UpdateDownloadTaskMetadataIfOwner(
+	ctx context.Context,
+	id uint64,
+	status uint16,
+	ownerID string,
+	metadata string,
+) (bool, error) {
+	d.logger.With(zap.Uint64("taskID", id), zap.String("ownerID", ownerID)).Debug("updating download task metadata if owner")
+
+	result, err := d.database.Update(TableDownloadTask).
+		Set(goqu.Record{ColMetadata: metadata}).
+		Where(goqu.Ex{ColDownloadTaskID: id, ColDownloadStatus: status, ColOwnerID: ownerID}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", id)).Error("failed to update download task metadata")
+		return false, err
+	}
+
+	affectedRowCount, err := result.RowsAffected()
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", id)).Error("failed to get affected row count")
+		return false, err
+	}
+
+	return affectedRowCount == 1, nil
+}
+
+// ClaimDownloadTask implements DownloadTaskDataAccessor.
+//
+// Like UpdateDownloadTaskStatusIfMatch, exactly one of the executors racing for the task wins, and it owns the task
+// until the lease expires.
+func (d downloadTaskDataAccessor) ClaimDownloadTask(
+	ctx context.Context,
+	id uint64,
+	fromStatus, toStatus uint16,
+	ownerID string,
+	leaseExpiresAt time.Time,
+) (bool, error) {
+	d.logger.With(zap.Uint64("taskID", id), zap.String("ownerID", ownerID)).Info("claiming download task")
+
+	result, err := d.database.Update(TableDownloadTask).
+		Set(goqu.Record{ColDownloadStatus: toStatus, ColOwnerID: ownerID, ColLeaseExpiresAt: leaseExpiresAt}).
+		Where(goqu.Ex{ColDownloadTaskID: id, ColDownloadStatus: fromStatus}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", id)).Error("failed to claim download task")
+		return false, err
+	}
+
+	affectedRowCount, err := result.RowsAffected()
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", id)).Error("failed to get affected row count")
+		return false, err
+	}
+
+	return affectedRowCount == 1, nil
+}
+
+// RenewDownloadTaskLease implements DownloadTaskDataAccessor. It reports whether the task was still in the status and
+// owned by ownerID.
+func (d downloadTaskDataAccessor) RenewDownloadTaskLease(
+	ctx context.Context,
+	id uint64,
+	status uint16,
+	ownerID string,
+	leaseExpiresAt time.Time,
+) (bool, error) {
+	d.logger.With(zap.Uint64("taskID", id), zap.Time("leaseExpiresAt", leaseExpiresAt)).Debug("renewing download task lease")
+
+	result, err := d.database.Update(TableDownloadTask).
+		Set(goqu.Record{ColLeaseExpiresAt: leaseExpiresAt}).
+		Where(goqu.Ex{ColDownloadTaskID: id, ColDownloadStatus: status, ColOwnerID: ownerID}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", id)).Error("failed to renew download task lease")
+		return false, err
+	}
+
+	affectedRowCount, err := result.RowsAffected()
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", id)).Error("failed to get affected row count")
+		return false, err
+	}
+
+	return affectedRowCount == 1, nil
+}
+
+// UpdateDownloadTaskStatusIfLeaseExpired implements DownloadTaskDataAccessor. Tasks without a lease, left by servers
+// from before leases, count as expired. The owner is cleared so that the previous one can tell it lost the task.
+func (d downloadTaskDataAccessor) UpdateDownloadTaskStatusIfLeaseExpired(ctx context.Context, fromStatus, toStatus uint16, now time.Time) (uint64, error) {
+	d.logger.With(zap.Uint16("fromStatus", fromStatus), zap.Uint16("toStatus", toStatus)).Debug("updating status of download tasks with expired lease")
+
+	result, err := d.database.Update(TableDownloadTask).
+		Set(goqu.Record{ColDownloadStatus: toStatus, ColOwnerID: "", ColLeaseExpiresAt: nil}).
+		Where(
+			goqu.C(ColDownloadStatus).Eq(fromStatus),
+			goqu.Or(goqu.C(ColLeaseExpiresAt).IsNull(), goqu.C(ColLeaseExpiresAt).Lt(now)),
+		).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to update status of download tasks with expired lease")
+		return 0, err
+	}
+
+	affectedRowCount, err := result.RowsAffected()
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to get affected row count")
+		return 0, err
+	}
+
+	return uint64(affectedRowCount), nil
+}
+
+// UpdateDownloadTaskQueue implements DownloadTaskDataAccessor.
+func (d downloadTaskDataAccessor) UpdateDownloadTaskQueue(ctx context.Context, id uint64, queueID uint64, queuePosition uint64) error {
+	d.logger.With(zap.Uint64("taskID", id), zap.Uint64("queueID", queueID), zap.Uint64("queuePosition", queuePosition)).Debug("updating download task queue")
+
+	_, err := d.database.Update(TableDownloadTask).
+		Set(goqu.Record{ColOfQueueID: queueID, ColQueuePosition: queuePosition}).
+		Where(goqu.Ex{ColDownloadTaskID: id}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", id)).Error("failed to update download task queue")
+		return err
+	}
+
+	return nil
+}
+
+// UpdateDownloadTaskNextRunAt implements DownloadTaskDataAccessor.
+func (d downloadTaskDataAccessor) UpdateDownloadTaskNextRunAt(ctx context.Context, id uint64, nextRunAt time.Time) error {
+	d.logger.With(zap.Uint64("taskID", id), zap.Time("nextRunAt", nextRunAt)).Debug("updating download task next run time")
+
+	_, err := d.database.Update(TableDownloadTask).
+		Set(goqu.Record{ColNextRunAt: nextRunAt}).
+		Where(goqu.Ex{ColDownloadTaskID: id}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", id)).Error("failed to update download task next run time")
+		return err
+	}
+
+	return nil
+}
+
+// UpdateDownloadTaskNextCheckAtIfDue implements DownloadTaskDataAccessor. It reports whether the task was still in the
+// status and due, which lets one of the servers sharing the database claim a due task.
+func (d downloadTaskDataAccessor) UpdateDownloadTaskNextCheckAtIfDue(
+	ctx context.Context,
+	id uint64,
+	status uint16,
+	dueTime time.Time,
+	nextCheckAt time.Time,
+) (bool, error) {
+	d.logger.With(zap.Uint64("taskID", id), zap.Time("nextCheckAt", nextCheckAt)).Debug("updating download task next check time if due")
+
+	result, err := d.database.Update(TableDownloadTask).
+		Set(goqu.Record{ColNextCheckAt: nextCheckAt}).
+		Where(
+			goqu.C(ColDownloadTaskID).Eq(id),
+			goqu.C(ColDownloadStatus).Eq(status),
+			goqu.C(ColNextCheckAt).Lte(dueTime),
+		).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", id)).Error("failed to update download task next check time")
+		return false, err
+	}
+
+	affectedRowCount, err := result.RowsAffected()
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", id)).Error("failed to get affected row count")
+		return false, err
+	}
+
+	return affectedRowCount == 1, nil
+}
+
+// UpdateDownloadTaskAfterAttempt implements DownloadTaskDataAccessor.
+func (d downloadTaskDataAccessor) UpdateDownloadTaskAfterAttempt(
+	ctx context.Context,
+	id uint64,
+	status uint16,
+	metadata string,
+	attemptCount uint32,
+	nextRetryAt sql.NullTime,
+) error {
+	d.logger.With(zap.Uint64("taskID", id), zap.Uint16("status", status), zap.Uint32("attemptCount", attemptCount)).
+		Info("updating download task after attempt")
+
+	_, err := d.database.Update(TableDownloadTask).
+		Set(goqu.Record{
+			ColDownloadStatus: status,
+			ColMetadata:       metadata,
+			ColAttemptCount:   attemptCount,
+			ColNextRetryAt:    nextRetryAt,
+		}).
+		Where(goqu.Ex{ColDownloadTaskID: id}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", id)).Error("failed to update download task after attempt")
+		return err
+	}
+
+	return nil
+}
+
+// UpdateDownloadTaskBlobSHA256 implements DownloadTaskDataAccessor.
+func (d downloadTaskDataAccessor) UpdateDownloadTaskBlobSHA256(ctx context.Context, id uint64, blobSHA256 string) error {
+	d.logger.With(zap.Uint64("taskID", id), zap.String("blobSHA256", blobSHA256)).Info("updating download task blob")
+
+	_, err := d.database.Update(TableDownloadTask).
+		Set(goqu.Record{ColBlobSHA256Ref: blobSHA256}).
+		Where(goqu.Ex{ColDownloadTaskID: id}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", id)).Error("failed to update download task blob")
+		return err
+	}
+
+	return nil
+}
+
+// RemoveDownloadTasksFromQueue implements DownloadTaskDataAccessor.
+func (d downloadTaskDataAccessor) RemoveDownloadTasksFromQueue(ctx context.Context, queueID uint64) error {
+	d.logger.With(zap.Uint64("queueID", queueID)).Info("removing download tasks from queue")
+
+	_, err := d.database.Update(TableDownloadTask).
+		Set(goqu.Record{ColOfQueueID: 0, ColQueuePosition: 0}).
+		Where(goqu.Ex{ColOfQueueID: queueID}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("queueID", queueID)).Error("failed to remove download tasks from queue")
+		return err
+	}
+
+	return nil
 }
 
 // DeleteDownloadTask implements DownloadTaskDataAccessor.
-func (a *downloadTaskAccessor) DeleteDownloadTask(ctx context.Context, id uint64) error {
-	panic("unimplemented")
+func (d downloadTaskDataAccessor) DeleteDownloadTask(ctx context.Context, id uint64) error {
+	d.logger.With(zap.Uint64("taskID", id)).Info("deleting download task")
+
+	_, err := d.database.Delete(TableDownloadTask).
+		Where(goqu.Ex{ColDownloadTaskID: id}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", id)).Error("failed to delete download task")
+		return err
+	}
+
+	return nil
 }
 
-func NewDownloadTaskDataAccessor(database *goqu.Database) DownloadTaskDataAccessor {
-	return &downloadTaskAccessor{database}
+func (d downloadTaskDataAccessor) WithDatabase(database Database) DownloadTaskDataAccessor {
+	return &downloadTaskDataAccessor{
+		database: database,
+		logger:   d.logger,
+	}
 }
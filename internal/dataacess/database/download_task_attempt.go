@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	TableDownloadTaskAttempt = "download_task_attempts"
+	ColDownloadTaskAttemptID = "id"
+	ColOfDownloadTaskID      = "of_download_task_id"
+)
+
+// DownloadTaskAttempt records how one attempt at downloading a task ended.
+type DownloadTaskAttempt struct {
+	ID               uint64    `db:"id" goqu:"skipinsert,skipupdate"`
+	OfDownloadTaskID uint64    `db:"of_download_task_id"`
+	AttemptNumber    uint32    `db:"attempt_number"`
+	StartedAt        time.Time `db:"started_at"`
+	FinishedAt       time.Time `db:"finished_at"`
+	Succeeded        bool      `db:"succeeded"`
+	ErrorClass       uint16    `db:"error_class"`
+	// HTTPStatusCode is set when the attempt failed on an unexpected HTTP response
+	HTTPStatusCode uint32 `db:"http_status_code"`
+	ErrorMessage   string `db:"error_message"`
+}
+
+type DownloadTaskAttemptDataAccessor interface {
+	CreateDownloadTaskAttempt(ctx context.Context, attempt DownloadTaskAttempt) (uint64, error)
+	GetDownloadTaskAttemptsByDownloadTaskID(ctx context.Context, downloadTaskID uint64) ([]DownloadTaskAttempt, error)
+	WithDatabase(database Database) DownloadTaskAttemptDataAccessor
+}
+
+type downloadTaskAttemptDataAccessor struct {
+	database Database
+	logger   *zap.Logger
+}
+
+func NewDownloadTaskAttemptDataAccessor(database *goqu.Database, logger *zap.Logger) DownloadTaskAttemptDataAccessor {
+	return &downloadTaskAttemptDataAccessor{
+		database: database,
+		logger:   logger,
+	}
+}
+
+// CreateDownloadTaskAttempt implements DownloadTaskAttemptDataAccessor.
+func (d downloadTaskAttemptDataAccessor) CreateDownloadTaskAttempt(ctx context.Context, attempt DownloadTaskAttempt) (uint64, error) {
+	d.logger.With(zap.Uint64("taskID", attempt.OfDownloadTaskID), zap.Uint32("attemptNumber", attempt.AttemptNumber)).
+		Info("creating download task attempt in database")
+
+	result, err := d.database.Insert(TableDownloadTaskAttempt).Rows(attempt).Executor().ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", attempt.OfDownloadTaskID)).Error("failed to insert download task attempt")
+		return 0, err
+	}
+
+	attemptID, err := result.LastInsertId()
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", attempt.OfDownloadTaskID)).Error("failed to get last insert ID")
+		return 0, err
+	}
+
+	return uint64(attemptID), nil
+}
+
+// GetDownloadTaskAttemptsByDownloadTaskID implements DownloadTaskAttemptDataAccessor.
+func (d downloadTaskAttemptDataAccessor) GetDownloadTaskAttemptsByDownloadTaskID(ctx context.Context, downloadTaskID uint64) ([]DownloadTaskAttempt, error) {
+	d.logger.With(zap.Uint64("taskID", downloadTaskID)).Info("getting download task attempts by download task ID")
+
+	attempts := make([]DownloadTaskAttempt, 0)
+	err := d.database.From(TableDownloadTaskAttempt).
+		Where(goqu.Ex{ColOfDownloadTaskID: downloadTaskID}).
+		Order(goqu.C(ColDownloadTaskAttemptID).Asc()).
+		ScanStructsContext(ctx, &attempts)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", downloadTaskID)).Error("failed to get download task attempts")
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+func (d downloadTaskAttemptDataAccessor) WithDatabase(database Database) DownloadTaskAttemptDataAccessor {
+	return &downloadTaskAttemptDataAccessor{
+		database: database,
+		logger:   d.logger,
+	}
+}
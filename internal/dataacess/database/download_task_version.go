@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	TableDownloadTaskVersion = "download_task_versions"
+	ColDownloadTaskVersionID = "id"
+	ColVersionNumber         = "version_number"
+)
+
+// DownloadTaskVersion is one version of the file of a watched download task, kept until it falls out of the retention
+// count of the task.
+type DownloadTaskVersion struct {
+	ID               uint64 `db:"id" goqu:"skipinsert,skipupdate"`
+	OfDownloadTaskID uint64 `db:"of_download_task_id"`
+	VersionNumber    uint32 `db:"version_number"`
+	FileName         string `db:"file_name"`
+	Size             int64  `db:"size"`
+	SHA256           string `db:"sha256"`
+	// ETag and LastModified are the validators of the response the version was downloaded from
+	ETag         string    `db:"etag"`
+	LastModified string    `db:"last_modified"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+type DownloadTaskVersionDataAccessor interface {
+	CreateDownloadTaskVersion(ctx context.Context, version DownloadTaskVersion) (uint64, error)
+	GetDownloadTaskVersionsByDownloadTaskID(ctx context.Context, downloadTaskID uint64) ([]DownloadTaskVersion, error)
+	GetDownloadTaskVersionByVersionNumber(ctx context.Context, downloadTaskID uint64, versionNumber uint32) (DownloadTaskVersion, error)
+	DeleteDownloadTaskVersion(ctx context.Context, id uint64) error
+	WithDatabase(database Database) DownloadTaskVersionDataAccessor
+}
+
+type downloadTaskVersionDataAccessor struct {
+	database Database
+	logger   *zap.Logger
+}
+
+func NewDownloadTaskVersionDataAccessor(database *goqu.Database, logger *zap.Logger) DownloadTaskVersionDataAccessor {
+	return &downloadTaskVersionDataAccessor{
+		database: database,
+		logger:   logger,
+	}
+}
+
+// CreateDownloadTaskVersion implements DownloadTaskVersionDataAccessor.
+func (d downloadTaskVersionDataAccessor) CreateDownloadTaskVersion(ctx context.Context, version DownloadTaskVersion) (uint64, error) {
+	d.logger.With(zap.Uint64("taskID", version.OfDownloadTaskID), zap.Uint32("versionNumber", version.VersionNumber)).
+		Info("creating download task version in database")
+
+	result, err := d.database.Insert(TableDownloadTaskVersion).Rows(version).Executor().ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", version.OfDownloadTaskID)).Error("failed to insert download task version")
+		return 0, err
+	}
+
+	versionID, err := result.LastInsertId()
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", version.OfDownloadTaskID)).Error("failed to get last insert ID")
+		return 0, err
+	}
+
+	return uint64(versionID), nil
+}
+
+// GetDownloadTaskVersionsByDownloadTaskID implements DownloadTaskVersionDataAccessor. The oldest versions are returned
+// first.
+func (d downloadTaskVersionDataAccessor) GetDownloadTaskVersionsByDownloadTaskID(ctx context.Context, downloadTaskID uint64) ([]DownloadTaskVersion, error) {
+	d.logger.With(zap.Uint64("taskID", downloadTaskID)).Info("getting download task versions by download task ID")
+
+	versions := make([]DownloadTaskVersion, 0)
+	err := d.database.From(TableDownloadTaskVersion).
+		Where(goqu.Ex{ColOfDownloadTaskID: downloadTaskID}).
+		Order(goqu.C(ColVersionNumber).Asc()).
+		ScanStructsContext(ctx, &versions)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", downloadTaskID)).Error("failed to get download task versions")
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// GetDownloadTaskVersionByVersionNumber implements DownloadTaskVersionDataAccessor.
+func (d downloadTaskVersionDataAccessor) GetDownloadTaskVersionByVersionNumber(
+	ctx context.Context,
+	downloadTaskID uint64,
+	versionNumber uint32,
+) (DownloadTaskVersion, error) {
+	d.logger.With(zap.Uint64("taskID", downloadTaskID), zap.Uint32("versionNumber", versionNumber)).
+		Info("getting download task version by version number")
+
+	var version DownloadTaskVersion
+	found, err := d.database.From(TableDownloadTaskVersion).
+		Where(goqu.Ex{ColOfDownloadTaskID: downloadTaskID, ColVersionNumber: versionNumber}).
+		ScanStructContext(ctx, &version)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", downloadTaskID)).Error("failed to get download task version")
+		return DownloadTaskVersion{}, err
+	}
+
+	if !found {
+		return DownloadTaskVersion{}, sql.ErrNoRows
+	}
+
+	return version, nil
+}
+
+// DeleteDownloadTaskVersion implements DownloadTaskVersionDataAccessor.
+func (d downloadTaskVersionDataAccessor) DeleteDownloadTaskVersion(ctx context.Context, id uint64) error {
+	d.logger.With(zap.Uint64("versionID", id)).Info("deleting download task version")
+
+	_, err := d.database.Delete(TableDownloadTaskVersion).
+		Where(goqu.Ex{ColDownloadTaskVersionID: id}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("versionID", id)).Error("failed to delete download task version")
+		return err
+	}
+
+	return nil
+}
+
+func (d downloadTaskVersionDataAccessor) WithDatabase(database Database) DownloadTaskVersionDataAccessor {
+	return &downloadTaskVersionDataAccessor{
+		database: database,
+		logger:   d.logger,
+	}
+}
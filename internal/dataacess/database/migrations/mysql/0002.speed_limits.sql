@@ -0,0 +1,5 @@
+ALTER TABLE `accounts`
+  ADD COLUMN `speed_limit_bytes_per_sec` BIGINT UNSIGNED NOT NULL DEFAULT 0;
+
+ALTER TABLE `download_tasks`
+  ADD COLUMN `speed_limit_bytes_per_sec` BIGINT UNSIGNED NOT NULL DEFAULT 0;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS `download_queues` (
+  `id` BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+  `of_account_id` BIGINT UNSIGNED NOT NULL,
+  `name` VARCHAR(100) NOT NULL,
+  `priority` INT NOT NULL DEFAULT 0,
+  `max_concurrent_download_count` INT UNSIGNED NOT NULL DEFAULT 0,
+  `active_start_time` VARCHAR(5) NOT NULL DEFAULT '',
+  `active_end_time` VARCHAR(5) NOT NULL DEFAULT '',
+  UNIQUE (`of_account_id`, `name`),
+  FOREIGN KEY (`of_account_id`) REFERENCES `accounts`(`id`)
+);
+
+ALTER TABLE `download_tasks`
+  ADD COLUMN `of_queue_id` BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  ADD COLUMN `queue_position` BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  ADD INDEX `download_tasks_status_queue` (`download_status`, `of_queue_id`);
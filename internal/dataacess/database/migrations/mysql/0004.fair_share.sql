@@ -0,0 +1,2 @@
+ALTER TABLE `download_tasks`
+  ADD INDEX `download_tasks_status_account` (`download_status`, `of_account_id`);
@@ -0,0 +1,5 @@
+ALTER TABLE `download_tasks`
+  ADD COLUMN `next_run_at` DATETIME NULL,
+  ADD COLUMN `cron_expression` VARCHAR(100) NOT NULL DEFAULT '',
+  ADD COLUMN `time_zone` VARCHAR(64) NOT NULL DEFAULT '',
+  ADD INDEX `download_tasks_status_next_run_at` (`download_status`, `next_run_at`);
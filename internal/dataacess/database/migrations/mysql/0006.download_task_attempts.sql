@@ -0,0 +1,17 @@
+ALTER TABLE `download_tasks`
+  ADD COLUMN `attempt_count` INT UNSIGNED NOT NULL DEFAULT 0,
+  ADD COLUMN `max_attempt_count` INT UNSIGNED NOT NULL DEFAULT 0,
+  ADD COLUMN `next_retry_at` DATETIME NULL;
+
+CREATE TABLE IF NOT EXISTS `download_task_attempts` (
+  `id` BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+  `of_download_task_id` BIGINT UNSIGNED NOT NULL,
+  `attempt_number` INT UNSIGNED NOT NULL,
+  `started_at` DATETIME NOT NULL,
+  `finished_at` DATETIME NOT NULL,
+  `succeeded` BOOLEAN NOT NULL,
+  `error_class` SMALLINT NOT NULL,
+  `http_status_code` INT UNSIGNED NOT NULL DEFAULT 0,
+  `error_message` TEXT NOT NULL,
+  FOREIGN KEY (`of_download_task_id`) REFERENCES `download_tasks`(`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+ALTER TABLE `download_tasks`
+  ADD COLUMN `mirror_urls` TEXT NOT NULL;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS `webhooks` (
+  `id` BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+  `of_account_id` BIGINT UNSIGNED NOT NULL,
+  `url` TEXT NOT NULL,
+  `secret` VARCHAR(64) NOT NULL,
+  `event_types` VARCHAR(64) NOT NULL DEFAULT '',
+  FOREIGN KEY (`of_account_id`) REFERENCES `accounts`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `webhook_deliveries` (
+  `id` BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+  `of_webhook_id` BIGINT UNSIGNED NOT NULL,
+  `event_type` SMALLINT NOT NULL,
+  `download_task_id` BIGINT UNSIGNED NOT NULL,
+  `payload` TEXT NOT NULL,
+  `delivery_status` SMALLINT NOT NULL,
+  `attempt_count` INT UNSIGNED NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL,
+  `next_attempt_at` DATETIME NOT NULL,
+  `last_attempted_at` DATETIME NULL,
+  `http_status_code` INT UNSIGNED NOT NULL DEFAULT 0,
+  `error_message` TEXT NOT NULL,
+  INDEX `webhook_deliveries_status_next_attempt_at` (`delivery_status`, `next_attempt_at`),
+  FOREIGN KEY (`of_webhook_id`) REFERENCES `webhooks`(`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS `blobs` (
+  `sha256` CHAR(64) PRIMARY KEY,
+  `size` BIGINT UNSIGNED NOT NULL,
+  `reference_count` INT UNSIGNED NOT NULL
+);
+
+ALTER TABLE `download_tasks`
+  ADD COLUMN `blob_sha256` CHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+CREATE INDEX `download_tasks_download_status_url` ON `download_tasks` (`download_status`, `url`(255));
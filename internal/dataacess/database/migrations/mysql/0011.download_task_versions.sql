@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS `download_task_versions` (
+  `id` BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+  `of_download_task_id` BIGINT UNSIGNED NOT NULL,
+  `version_number` INT UNSIGNED NOT NULL,
+  `file_name` TEXT NOT NULL,
+  `size` BIGINT NOT NULL,
+  `sha256` CHAR(64) NOT NULL,
+  `etag` VARCHAR(256) NOT NULL DEFAULT '',
+  `last_modified` VARCHAR(64) NOT NULL DEFAULT '',
+  `created_at` DATETIME NOT NULL,
+  UNIQUE KEY `download_task_versions_task_version` (`of_download_task_id`, `version_number`),
+  FOREIGN KEY (`of_download_task_id`) REFERENCES `download_tasks`(`id`) ON DELETE CASCADE
+);
+
+ALTER TABLE `download_tasks`
+  ADD COLUMN `next_check_at` DATETIME NULL,
+  ADD INDEX `download_tasks_download_status_next_check_at` (`download_status`, `next_check_at`);
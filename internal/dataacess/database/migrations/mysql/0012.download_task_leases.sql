@@ -0,0 +1,4 @@
+ALTER TABLE `download_tasks`
+  ADD COLUMN `owner_id` VARCHAR(128) NOT NULL DEFAULT '',
+  ADD COLUMN `lease_expires_at` DATETIME NULL,
+  ADD INDEX `download_tasks_download_status_lease_expires_at` (`download_status`, `lease_expires_at`);
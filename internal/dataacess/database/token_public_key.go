@@ -15,8 +15,8 @@ const (
 )
 
 type TokenPublicKey struct {
-	ID        uint64 `sql:"id"`
-	PublicKey []byte `sql:"public_key"`
+	ID        uint64 `db:"id"`
+	PublicKey []byte `db:"public_key"`
 }
 
 type TokenPublicKeyDataAccessor interface {
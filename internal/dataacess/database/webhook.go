@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/doug-martin/goqu/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	TableWebhook = "webhooks"
+	ColWebhookID = "id"
+)
+
+// Webhook is an endpoint of an account that download task events are delivered to.
+type Webhook struct {
+	ID          uint64 `db:"id" goqu:"skipinsert,skipupdate"`
+	OfAccountID uint64 `db:"of_account_id" goqu:"skipupdate"`
+	URL         string `db:"url"`
+	// Secret is the key the HMAC signature of every delivery is computed with
+	Secret string `db:"secret"`
+	// EventTypes are the comma separated event types the webhook receives, it receives all of them when empty
+	EventTypes string `db:"event_types"`
+}
+
+type WebhookDataAccessor interface {
+	CreateWebhook(ctx context.Context, webhook Webhook) (uint64, error)
+	GetWebhookByID(ctx context.Context, id uint64) (Webhook, error)
+	GetWebhooksByAccountID(ctx context.Context, accountID uint64) ([]Webhook, error)
+	DeleteWebhook(ctx context.Context, id uint64) error
+	WithDatabase(database Database) WebhookDataAccessor
+}
+
+type webhookDataAccessor struct {
+	database Database
+	logger   *zap.Logger
+}
+
+func NewWebhookDataAccessor(database *goqu.Database, logger *zap.Logger) WebhookDataAccessor {
+	return &webhookDataAccessor{
+		database: database,
+		logger:   logger,
+	}
+}
+
+// CreateWebhook implements WebhookDataAccessor.
+func (d webhookDataAccessor) CreateWebhook(ctx context.Context, webhook Webhook) (uint64, error) {
+	d.logger.With(zap.Uint64("accountID", webhook.OfAccountID)).Info("creating webhook in database")
+
+	result, err := d.database.Insert(TableWebhook).Rows(webhook).Executor().ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("accountID", webhook.OfAccountID)).Error("failed to insert webhook")
+		return 0, err
+	}
+
+	webhookID, err := result.LastInsertId()
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("accountID", webhook.OfAccountID)).Error("failed to get last insert ID")
+		return 0, err
+	}
+
+	return uint64(webhookID), nil
+}
+
+// GetWebhookByID implements WebhookDataAccessor.
+func (d webhookDataAccessor) GetWebhookByID(ctx context.Context, id uint64) (Webhook, error) {
+	d.logger.With(zap.Uint64("webhookID", id)).Info("getting webhook by ID")
+
+	var webhook Webhook
+	found, err := d.database.From(TableWebhook).
+		Where(goqu.Ex{ColWebhookID: id}).
+		ScanStructContext(ctx, &webhook)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("webhookID", id)).Error("failed to get webhook by ID")
+		return Webhook{}, err
+	}
+
+	if !found {
+		d.logger.With(zap.Uint64("webhookID", id)).Warn("webhook not found")
+		return Webhook{}, sql.ErrNoRows
+	}
+
+	return webhook, nil
+}
+
+// GetWebhooksByAccountID implements WebhookDataAccessor.
+func (d webhookDataAccessor) GetWebhooksByAccountID(ctx context.Context, accountID uint64) ([]Webhook, error) {
+	d.logger.With(zap.Uint64("accountID", accountID)).Debug("getting webhooks by account ID")
+
+	webhooks := make([]Webhook, 0)
+	err := d.database.From(TableWebhook).
+		Where(goqu.Ex{ColOfAccountID: accountID}).
+		Order(goqu.C(ColWebhookID).Asc()).
+		ScanStructsContext(ctx, &webhooks)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("accountID", accountID)).Error("failed to get webhooks by account ID")
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook implements WebhookDataAccessor.
+func (d webhookDataAccessor) DeleteWebhook(ctx context.Context, id uint64) error {
+	d.logger.With(zap.Uint64("webhookID", id)).Info("deleting webhook")
+
+	_, err := d.database.Delete(TableWebhook).
+		Where(goqu.Ex{ColWebhookID: id}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("webhookID", id)).Error("failed to delete webhook")
+		return err
+	}
+
+	return nil
+}
+
+func (d webhookDataAccessor) WithDatabase(database Database) WebhookDataAccessor {
+	return &webhookDataAccessor{
+		database: database,
+		logger:   d.logger,
+	}
+}
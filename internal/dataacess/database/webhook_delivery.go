@@ -0,0 +1,195 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	TableWebhookDelivery = "webhook_deliveries"
+	ColWebhookDeliveryID = "id"
+	ColOfWebhookID       = "of_webhook_id"
+	ColDeliveryStatus    = "delivery_status"
+	ColNextAttemptAt     = "next_attempt_at"
+)
+
+// WebhookDelivery is one event to deliver to a webhook, together with how its delivery went so far.
+type WebhookDelivery struct {
+	ID             uint64 `db:"id" goqu:"skipinsert,skipupdate"`
+	OfWebhookID    uint64 `db:"of_webhook_id" goqu:"skipupdate"`
+	EventType      uint16 `db:"event_type" goqu:"skipupdate"`
+	DownloadTaskID uint64 `db:"download_task_id" goqu:"skipupdate"`
+	// Payload is the JSON body sent to the webhook, it is kept so that every attempt sends the same body
+	Payload        string    `db:"payload" goqu:"skipupdate"`
+	DeliveryStatus uint16    `db:"delivery_status"`
+	AttemptCount   uint32    `db:"attempt_count"`
+	CreatedAt      time.Time `db:"created_at" goqu:"skipupdate"`
+	// NextAttemptAt is when a pending delivery is attempted, it is pushed back while a server is delivering it
+	NextAttemptAt   time.Time    `db:"next_attempt_at"`
+	LastAttemptedAt sql.NullTime `db:"last_attempted_at"`
+	// HTTPStatusCode and ErrorMessage tell how the last attempt went
+	HTTPStatusCode uint32 `db:"http_status_code"`
+	ErrorMessage   string `db:"error_message"`
+}
+
+type WebhookDeliveryDataAccessor interface {
+	CreateWebhookDelivery(ctx context.Context, delivery WebhookDelivery) (uint64, error)
+	GetWebhookDeliveriesByWebhookID(ctx context.Context, webhookID uint64, offset, limit uint64) ([]WebhookDelivery, error)
+	GetWebhookDeliveryCountByWebhookID(ctx context.Context, webhookID uint64) (uint64, error)
+	GetWebhookDeliveriesByStatusDueBefore(ctx context.Context, status uint16, dueTime time.Time, limit uint64) ([]WebhookDelivery, error)
+	UpdateWebhookDeliveryNextAttemptAtIfDue(ctx context.Context, id uint64, status uint16, dueTime time.Time, nextAttemptAt time.Time) (bool, error)
+	UpdateWebhookDelivery(ctx context.Context, delivery WebhookDelivery) error
+	WithDatabase(database Database) WebhookDeliveryDataAccessor
+}
+
+type webhookDeliveryDataAccessor struct {
+	database Database
+	logger   *zap.Logger
+}
+
+func NewWebhookDeliveryDataAccessor(database *goqu.Database, logger *zap.Logger) WebhookDeliveryDataAccessor {
+	return &webhookDeliveryDataAccessor{
+		database: database,
+		logger:   logger,
+	}
+}
+
+// CreateWebhookDelivery implements WebhookDeliveryDataAccessor.
+func (d webhookDeliveryDataAccessor) CreateWebhookDelivery(ctx context.Context, delivery WebhookDelivery) (uint64, error) {
+	d.logger.With(zap.Uint64("webhookID", delivery.OfWebhookID), zap.Uint16("eventType", delivery.EventType)).
+		Info("creating webhook delivery in database")
+
+	result, err := d.database.Insert(TableWebhookDelivery).Rows(delivery).Executor().ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("webhookID", delivery.OfWebhookID)).Error("failed to insert webhook delivery")
+		return 0, err
+	}
+
+	deliveryID, err := result.LastInsertId()
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("webhookID", delivery.OfWebhookID)).Error("failed to get last insert ID")
+		return 0, err
+	}
+
+	return uint64(deliveryID), nil
+}
+
+// GetWebhookDeliveriesByWebhookID implements WebhookDeliveryDataAccessor.
+func (d webhookDeliveryDataAccessor) GetWebhookDeliveriesByWebhookID(ctx context.Context, webhookID uint64, offset, limit uint64) ([]WebhookDelivery, error) {
+	d.logger.With(zap.Uint64("webhookID", webhookID), zap.Uint64("offset", offset), zap.Uint64("limit", limit)).
+		Info("getting webhook deliveries by webhook ID")
+
+	deliveries := make([]WebhookDelivery, 0)
+	err := d.database.From(TableWebhookDelivery).
+		Where(goqu.Ex{ColOfWebhookID: webhookID}).
+		Order(goqu.C(ColWebhookDeliveryID).Desc()).
+		Offset(uint(offset)).
+		Limit(uint(limit)).
+		ScanStructsContext(ctx, &deliveries)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("webhookID", webhookID)).Error("failed to get webhook deliveries by webhook ID")
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// GetWebhookDeliveryCountByWebhookID implements WebhookDeliveryDataAccessor.
+func (d webhookDeliveryDataAccessor) GetWebhookDeliveryCountByWebhookID(ctx context.Context, webhookID uint64) (uint64, error) {
+	d.logger.With(zap.Uint64("webhookID", webhookID)).Info("counting webhook deliveries by webhook ID")
+
+	count, err := d.database.From(TableWebhookDelivery).
+		Where(goqu.Ex{ColOfWebhookID: webhookID}).
+		CountContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("webhookID", webhookID)).Error("failed to count webhook deliveries by webhook ID")
+		return 0, err
+	}
+
+	return uint64(count), nil
+}
+
+// GetWebhookDeliveriesByStatusDueBefore implements WebhookDeliveryDataAccessor.
+func (d webhookDeliveryDataAccessor) GetWebhookDeliveriesByStatusDueBefore(
+	ctx context.Context,
+	status uint16,
+	dueTime time.Time,
+	limit uint64,
+) ([]WebhookDelivery, error) {
+	d.logger.With(zap.Uint16("status", status), zap.Time("dueTime", dueTime), zap.Uint64("limit", limit)).
+		Debug("getting due webhook deliveries by status")
+
+	deliveries := make([]WebhookDelivery, 0)
+	err := d.database.From(TableWebhookDelivery).
+		Where(goqu.C(ColDeliveryStatus).Eq(status), goqu.C(ColNextAttemptAt).Lte(dueTime)).
+		Order(goqu.C(ColNextAttemptAt).Asc(), goqu.C(ColWebhookDeliveryID).Asc()).
+		Limit(uint(limit)).
+		ScanStructsContext(ctx, &deliveries)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint16("status", status)).Error("failed to get due webhook deliveries by status")
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// UpdateWebhookDeliveryNextAttemptAtIfDue implements WebhookDeliveryDataAccessor.
+func (d webhookDeliveryDataAccessor) UpdateWebhookDeliveryNextAttemptAtIfDue(
+	ctx context.Context,
+	id uint64,
+	status uint16,
+	dueTime time.Time,
+	nextAttemptAt time.Time,
+) (bool, error) {
+	d.logger.With(zap.Uint64("deliveryID", id), zap.Time("nextAttemptAt", nextAttemptAt)).Debug("updating webhook delivery next attempt time")
+
+	result, err := d.database.Update(TableWebhookDelivery).
+		Set(goqu.Record{ColNextAttemptAt: nextAttemptAt}).
+		Where(
+			goqu.C(ColWebhookDeliveryID).Eq(id),
+			goqu.C(ColDeliveryStatus).Eq(status),
+			goqu.C(ColNextAttemptAt).Lte(dueTime),
+		).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("deliveryID", id)).Error("failed to update webhook delivery next attempt time")
+		return false, err
+	}
+
+	affectedRowCount, err := result.RowsAffected()
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("deliveryID", id)).Error("failed to get affected row count")
+		return false, err
+	}
+
+	return affectedRowCount == 1, nil
+}
+
+// UpdateWebhookDelivery implements WebhookDeliveryDataAccessor.
+func (d webhookDeliveryDataAccessor) UpdateWebhookDelivery(ctx context.Context, delivery WebhookDelivery) error {
+	d.logger.With(zap.Uint64("deliveryID", delivery.ID), zap.Uint16("status", delivery.DeliveryStatus)).Info("updating webhook delivery")
+
+	_, err := d.database.Update(TableWebhookDelivery).
+		Set(delivery).
+		Where(goqu.Ex{ColWebhookDeliveryID: delivery.ID}).
+		Executor().
+		ExecContext(ctx)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("deliveryID", delivery.ID)).Error("failed to update webhook delivery")
+		return err
+	}
+
+	return nil
+}
+
+func (d webhookDeliveryDataAccessor) WithDatabase(database Database) WebhookDeliveryDataAccessor {
+	return &webhookDeliveryDataAccessor{
+		database: database,
+		logger:   d.logger,
+	}
+}
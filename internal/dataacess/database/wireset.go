@@ -8,4 +8,11 @@ var WireSet = wire.NewSet(
 	NewAccountDataAccessor,
 	NewAccountPasswordDataAccessor,
 	NewTokenPublicKeyDataAccessor,
+	NewDownloadTaskDataAccessor,
+	NewDownloadQueueDataAccessor,
+	NewDownloadTaskAttemptDataAccessor,
+	NewWebhookDataAccessor,
+	NewWebhookDeliveryDataAccessor,
+	NewBlobDataAccessor,
+	NewDownloadTaskVersionDataAccessor,
 )
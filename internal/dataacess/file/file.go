@@ -0,0 +1,203 @@
+package file
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"go.uber.org/zap"
+)
+
+type WriterAtCloser interface {
+	io.WriterAt
+	io.Closer
+}
+
+type Client interface {
+	Write(ctx context.Context, filePath string) (io.WriteCloser, error)
+	// WriteAt opens the file for random access writes, growing it to size without allocating disk blocks for the
+	// part that has not been written yet.
+	WriteAt(ctx context.Context, filePath string, size int64) (WriterAtCloser, error)
+	Read(ctx context.Context, filePath string) (io.ReadCloser, error)
+	// Size returns the size of the file, or an error satisfying errors.Is(err, fs.ErrNotExist) if it does not exist.
+	Size(ctx context.Context, filePath string) (int64, error)
+	// Delete removes the file, or the directory with everything in it for downloads made of several files.
+	Delete(ctx context.Context, filePath string) error
+	// Rename moves the file or directory to a new path, failing with an error satisfying errors.Is(err, fs.ErrExist)
+	// if something is already there.
+	Rename(ctx context.Context, fromFilePath string, toFilePath string) error
+	// Chmod sets the permissions of the file, or of every file in the directory for downloads made of several files.
+	Chmod(ctx context.Context, filePath string, mode fs.FileMode) error
+	// LocalPath returns the path of the file on the local file system, for tools that are given the file.
+	LocalPath(ctx context.Context, filePath string) (string, error)
+	// Link makes toFilePath a hard link to the file at fromFilePath, so that both share the same content on disk.
+	// Anything already at toFilePath is replaced at once, it is never seen missing.
+	Link(ctx context.Context, fromFilePath string, toFilePath string) error
+}
+
+type localClient struct {
+	downloadDirectory string
+	logger            *zap.Logger
+}
+
+func NewLocalClient(configs configs.DownloadConfig, logger *zap.Logger) (Client, error) {
+	err := os.MkdirAll(configs.DownloadDirectory, os.ModePerm)
+	if err != nil {
+		logger.With(zap.Error(err), zap.String("downloadDirectory", configs.DownloadDirectory)).Error("failed to create download directory")
+		return nil, err
+	}
+
+	return &localClient{
+		downloadDirectory: configs.DownloadDirectory,
+		logger:            logger,
+	}, nil
+}
+
+func (l localClient) Write(ctx context.Context, filePath string) (io.WriteCloser, error) {
+	absolutePath := filepath.Join(l.downloadDirectory, filePath)
+	err := l.createParentDirectory(absolutePath)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Create(absolutePath)
+	if err != nil {
+		l.logger.With(zap.Error(err), zap.String("filePath", absolutePath)).Error("failed to create file")
+		return nil, err
+	}
+	return file, nil
+}
+
+func (l localClient) WriteAt(ctx context.Context, filePath string, size int64) (WriterAtCloser, error) {
+	absolutePath := filepath.Join(l.downloadDirectory, filePath)
+	err := l.createParentDirectory(absolutePath)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(absolutePath, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		l.logger.With(zap.Error(err), zap.String("filePath", absolutePath)).Error("failed to open file")
+		return nil, err
+	}
+
+	err = file.Truncate(size)
+	if err != nil {
+		l.logger.With(zap.Error(err), zap.String("filePath", absolutePath), zap.Int64("size", size)).Error("failed to preallocate file")
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+func (l localClient) Read(ctx context.Context, filePath string) (io.ReadCloser, error) {
+	absolutePath := filepath.Join(l.downloadDirectory, filePath)
+	file, err := os.Open(absolutePath)
+	if err != nil {
+		l.logger.With(zap.Error(err), zap.String("filePath", absolutePath)).Error("failed to open file")
+		return nil, err
+	}
+	return file, nil
+}
+
+func (l localClient) Size(ctx context.Context, filePath string) (int64, error) {
+	absolutePath := filepath.Join(l.downloadDirectory, filePath)
+	fileInfo, err := os.Stat(absolutePath)
+	if err != nil {
+		return 0, err
+	}
+	return fileInfo.Size(), nil
+}
+
+func (l localClient) Delete(ctx context.Context, filePath string) error {
+	absolutePath := filepath.Join(l.downloadDirectory, filePath)
+	err := os.RemoveAll(absolutePath)
+	if err != nil {
+		l.logger.With(zap.Error(err), zap.String("filePath", absolutePath)).Error("failed to delete file")
+		return err
+	}
+	return nil
+}
+
+func (l localClient) Rename(ctx context.Context, fromFilePath string, toFilePath string) error {
+	fromAbsolutePath := filepath.Join(l.downloadDirectory, fromFilePath)
+	toAbsolutePath := filepath.Join(l.downloadDirectory, toFilePath)
+	_, err := os.Lstat(toAbsolutePath)
+	if err == nil {
+		return &fs.PathError{Op: "rename", Path: toAbsolutePath, Err: fs.ErrExist}
+	}
+
+	err = l.createParentDirectory(toAbsolutePath)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(fromAbsolutePath, toAbsolutePath)
+	if err != nil {
+		l.logger.With(zap.Error(err), zap.String("fromFilePath", fromAbsolutePath), zap.String("toFilePath", toAbsolutePath)).
+			Error("failed to rename file")
+		return err
+	}
+	return nil
+}
+
+func (l localClient) Chmod(ctx context.Context, filePath string, mode fs.FileMode) error {
+	absolutePath := filepath.Join(l.downloadDirectory, filePath)
+	err := filepath.WalkDir(absolutePath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		return os.Chmod(path, mode)
+	})
+	if err != nil {
+		l.logger.With(zap.Error(err), zap.String("filePath", absolutePath)).Error("failed to change file mode")
+		return err
+	}
+	return nil
+}
+
+func (l localClient) LocalPath(ctx context.Context, filePath string) (string, error) {
+	return filepath.Abs(filepath.Join(l.downloadDirectory, filePath))
+}
+
+func (l localClient) Link(ctx context.Context, fromFilePath string, toFilePath string) error {
+	fromAbsolutePath := filepath.Join(l.downloadDirectory, fromFilePath)
+	toAbsolutePath := filepath.Join(l.downloadDirectory, toFilePath)
+	err := l.createParentDirectory(toAbsolutePath)
+	if err != nil {
+		return err
+	}
+
+	// the link is made next to the target and renamed over it, renaming being atomic where linking over a file is not
+	temporaryPath := toAbsolutePath + ".link"
+	_ = os.Remove(temporaryPath)
+	err = os.Link(fromAbsolutePath, temporaryPath)
+	if err == nil {
+		err = os.Rename(temporaryPath, toAbsolutePath)
+		if err != nil {
+			_ = os.Remove(temporaryPath)
+		}
+	}
+	if err != nil {
+		l.logger.With(zap.Error(err), zap.String("fromFilePath", fromAbsolutePath), zap.String("toFilePath", toAbsolutePath)).
+			Error("failed to link file")
+		return err
+	}
+	return nil
+}
+
+// createParentDirectory creates the directories a file is nested in, for downloads that are made of several files.
+func (l localClient) createParentDirectory(absolutePath string) error {
+	err := os.MkdirAll(filepath.Dir(absolutePath), os.ModePerm)
+	if err != nil {
+		l.logger.With(zap.Error(err), zap.String("filePath", absolutePath)).Error("failed to create parent directory")
+		return err
+	}
+	return nil
+}
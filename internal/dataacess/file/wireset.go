@@ -0,0 +1,7 @@
+package file
+
+import "github.com/google/wire"
+
+var WireSet = wire.NewSet(
+	NewLocalClient,
+)
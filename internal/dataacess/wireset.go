@@ -4,9 +4,11 @@ import (
 	"github.com/google/wire"
 	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/cache"
 	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/database"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
 )
 
 var WireSet = wire.NewSet(
 	database.WireSet,
     cache.WireSet,
+    file.WireSet,
 )
@@ -9,6 +9,7 @@ package go_load
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -26,6 +27,11 @@ type DownloadType int32
 const (
 	DownloadType_UndefinedType DownloadType = 0
 	DownloadType_HTTP          DownloadType = 1
+	DownloadType_FTP           DownloadType = 2
+	DownloadType_SFTP          DownloadType = 3
+	DownloadType_Stream        DownloadType = 4
+	DownloadType_BITTORRENT    DownloadType = 5
+	DownloadType_S3            DownloadType = 6
 )
 
 // Enum value maps for DownloadType.
@@ -33,10 +39,20 @@ var (
 	DownloadType_name = map[int32]string{
 		0: "UndefinedType",
 		1: "HTTP",
+		2: "FTP",
+		3: "SFTP",
+		4: "Stream",
+		5: "BITTORRENT",
+		6: "S3",
 	}
 	DownloadType_value = map[string]int32{
 		"UndefinedType": 0,
 		"HTTP":          1,
+		"FTP":           2,
+		"SFTP":          3,
+		"Stream":        4,
+		"BITTORRENT":    5,
+		"S3":            6,
 	}
 )
 
@@ -75,6 +91,7 @@ const (
 	DownloadStatus_Downloading     DownloadStatus = 2
 	DownloadStatus_Failed          DownloadStatus = 3
 	DownloadStatus_Success         DownloadStatus = 4
+	DownloadStatus_Scheduled       DownloadStatus = 5
 )
 
 // Enum value maps for DownloadStatus.
@@ -85,6 +102,7 @@ var (
 		2: "Downloading",
 		3: "Failed",
 		4: "Success",
+		5: "Scheduled",
 	}
 	DownloadStatus_value = map[string]int32{
 		"UndefinedStatus": 0,
@@ -92,6 +110,7 @@ var (
 		"Downloading":     2,
 		"Failed":          3,
 		"Success":         4,
+		"Scheduled":       5,
 	}
 )
 
@@ -122,84 +141,4032 @@ func (DownloadStatus) EnumDescriptor() ([]byte, []int) {
 	return file_api_go_load_proto_rawDescGZIP(), []int{1}
 }
 
+type DownloadErrorClass int32
+
+const (
+	DownloadErrorClass_UndefinedErrorClass DownloadErrorClass = 0
+	DownloadErrorClass_TimeoutError        DownloadErrorClass = 1
+	DownloadErrorClass_ConnectionError     DownloadErrorClass = 2
+	DownloadErrorClass_ServerError         DownloadErrorClass = 3
+	DownloadErrorClass_ClientError         DownloadErrorClass = 4
+	DownloadErrorClass_IntegrityError      DownloadErrorClass = 5
+	DownloadErrorClass_OtherError          DownloadErrorClass = 6
+)
+
+// Enum value maps for DownloadErrorClass.
+var (
+	DownloadErrorClass_name = map[int32]string{
+		0: "UndefinedErrorClass",
+		1: "TimeoutError",
+		2: "ConnectionError",
+		3: "ServerError",
+		4: "ClientError",
+		5: "IntegrityError",
+		6: "OtherError",
+	}
+	DownloadErrorClass_value = map[string]int32{
+		"UndefinedErrorClass": 0,
+		"TimeoutError":        1,
+		"ConnectionError":     2,
+		"ServerError":         3,
+		"ClientError":         4,
+		"IntegrityError":      5,
+		"OtherError":          6,
+	}
+)
+
+func (x DownloadErrorClass) Enum() *DownloadErrorClass {
+	p := new(DownloadErrorClass)
+	*p = x
+	return p
+}
+
+func (x DownloadErrorClass) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DownloadErrorClass) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_go_load_proto_enumTypes[2].Descriptor()
+}
+
+func (DownloadErrorClass) Type() protoreflect.EnumType {
+	return &file_api_go_load_proto_enumTypes[2]
+}
+
+func (x DownloadErrorClass) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DownloadErrorClass.Descriptor instead.
+func (DownloadErrorClass) EnumDescriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{2}
+}
+
+type DigestAlgorithm int32
+
+const (
+	DigestAlgorithm_UndefinedAlgorithm DigestAlgorithm = 0
+	DigestAlgorithm_MD5                DigestAlgorithm = 1
+	DigestAlgorithm_SHA1               DigestAlgorithm = 2
+	DigestAlgorithm_SHA256             DigestAlgorithm = 3
+	DigestAlgorithm_SHA512             DigestAlgorithm = 4
+	DigestAlgorithm_CRC32              DigestAlgorithm = 5
+)
+
+// Enum value maps for DigestAlgorithm.
+var (
+	DigestAlgorithm_name = map[int32]string{
+		0: "UndefinedAlgorithm",
+		1: "MD5",
+		2: "SHA1",
+		3: "SHA256",
+		4: "SHA512",
+		5: "CRC32",
+	}
+	DigestAlgorithm_value = map[string]int32{
+		"UndefinedAlgorithm": 0,
+		"MD5":                1,
+		"SHA1":               2,
+		"SHA256":             3,
+		"SHA512":             4,
+		"CRC32":              5,
+	}
+)
+
+func (x DigestAlgorithm) Enum() *DigestAlgorithm {
+	p := new(DigestAlgorithm)
+	*p = x
+	return p
+}
+
+func (x DigestAlgorithm) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DigestAlgorithm) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_go_load_proto_enumTypes[3].Descriptor()
+}
+
+func (DigestAlgorithm) Type() protoreflect.EnumType {
+	return &file_api_go_load_proto_enumTypes[3]
+}
+
+func (x DigestAlgorithm) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DigestAlgorithm.Descriptor instead.
+func (DigestAlgorithm) EnumDescriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{3}
+}
+
+type WebhookEventType int32
+
+const (
+	WebhookEventType_UndefinedWebhookEventType WebhookEventType = 0
+	WebhookEventType_DownloadTaskCreated       WebhookEventType = 1
+	WebhookEventType_DownloadTaskStarted       WebhookEventType = 2
+	WebhookEventType_DownloadTaskProgressed    WebhookEventType = 3
+	WebhookEventType_DownloadTaskSucceeded     WebhookEventType = 4
+	WebhookEventType_DownloadTaskFailed        WebhookEventType = 5
+)
+
+// Enum value maps for WebhookEventType.
+var (
+	WebhookEventType_name = map[int32]string{
+		0: "UndefinedWebhookEventType",
+		1: "DownloadTaskCreated",
+		2: "DownloadTaskStarted",
+		3: "DownloadTaskProgressed",
+		4: "DownloadTaskSucceeded",
+		5: "DownloadTaskFailed",
+	}
+	WebhookEventType_value = map[string]int32{
+		"UndefinedWebhookEventType": 0,
+		"DownloadTaskCreated":       1,
+		"DownloadTaskStarted":       2,
+		"DownloadTaskProgressed":    3,
+		"DownloadTaskSucceeded":     4,
+		"DownloadTaskFailed":        5,
+	}
+)
+
+func (x WebhookEventType) Enum() *WebhookEventType {
+	p := new(WebhookEventType)
+	*p = x
+	return p
+}
+
+func (x WebhookEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WebhookEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_go_load_proto_enumTypes[4].Descriptor()
+}
+
+func (WebhookEventType) Type() protoreflect.EnumType {
+	return &file_api_go_load_proto_enumTypes[4]
+}
+
+func (x WebhookEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WebhookEventType.Descriptor instead.
+func (WebhookEventType) EnumDescriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{4}
+}
+
+type DuplicateURLMode int32
+
+const (
+	DuplicateURLMode_UndefinedDuplicateURLMode DuplicateURLMode = 0
+	DuplicateURLMode_RefetchDuplicateURL       DuplicateURLMode = 1
+	DuplicateURLMode_AskOnDuplicateURL         DuplicateURLMode = 2
+	DuplicateURLMode_ReuseDuplicateURL         DuplicateURLMode = 3
+)
+
+// Enum value maps for DuplicateURLMode.
+var (
+	DuplicateURLMode_name = map[int32]string{
+		0: "UndefinedDuplicateURLMode",
+		1: "RefetchDuplicateURL",
+		2: "AskOnDuplicateURL",
+		3: "ReuseDuplicateURL",
+	}
+	DuplicateURLMode_value = map[string]int32{
+		"UndefinedDuplicateURLMode": 0,
+		"RefetchDuplicateURL":       1,
+		"AskOnDuplicateURL":         2,
+		"ReuseDuplicateURL":         3,
+	}
+)
+
+func (x DuplicateURLMode) Enum() *DuplicateURLMode {
+	p := new(DuplicateURLMode)
+	*p = x
+	return p
+}
+
+func (x DuplicateURLMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DuplicateURLMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_go_load_proto_enumTypes[5].Descriptor()
+}
+
+func (DuplicateURLMode) Type() protoreflect.EnumType {
+	return &file_api_go_load_proto_enumTypes[5]
+}
+
+func (x DuplicateURLMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DuplicateURLMode.Descriptor instead.
+func (DuplicateURLMode) EnumDescriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{5}
+}
+
+type WebhookDeliveryStatus int32
+
+const (
+	WebhookDeliveryStatus_UndefinedWebhookDeliveryStatus WebhookDeliveryStatus = 0
+	WebhookDeliveryStatus_DeliveryPending                WebhookDeliveryStatus = 1
+	WebhookDeliveryStatus_Delivered                      WebhookDeliveryStatus = 2
+	WebhookDeliveryStatus_DeadLettered                   WebhookDeliveryStatus = 3
+)
+
+// Enum value maps for WebhookDeliveryStatus.
+var (
+	WebhookDeliveryStatus_name = map[int32]string{
+		0: "UndefinedWebhookDeliveryStatus",
+		1: "DeliveryPending",
+		2: "Delivered",
+		3: "DeadLettered",
+	}
+	WebhookDeliveryStatus_value = map[string]int32{
+		"UndefinedWebhookDeliveryStatus": 0,
+		"DeliveryPending":                1,
+		"Delivered":                      2,
+		"DeadLettered":                   3,
+	}
+)
+
+func (x WebhookDeliveryStatus) Enum() *WebhookDeliveryStatus {
+	p := new(WebhookDeliveryStatus)
+	*p = x
+	return p
+}
+
+func (x WebhookDeliveryStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WebhookDeliveryStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_go_load_proto_enumTypes[6].Descriptor()
+}
+
+func (WebhookDeliveryStatus) Type() protoreflect.EnumType {
+	return &file_api_go_load_proto_enumTypes[6]
+}
+
+func (x WebhookDeliveryStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WebhookDeliveryStatus.Descriptor instead.
+func (WebhookDeliveryStatus) EnumDescriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{6}
+}
+
+type FileCategory int32
+
+const (
+	FileCategory_UndefinedFileCategory FileCategory = 0
+	FileCategory_CompressedFile        FileCategory = 1
+	FileCategory_DocumentFile          FileCategory = 2
+	FileCategory_MusicFile             FileCategory = 3
+	FileCategory_VideoFile             FileCategory = 4
+	FileCategory_ImageFile             FileCategory = 5
+	FileCategory_ProgramFile           FileCategory = 6
+	FileCategory_OtherFile             FileCategory = 7
+)
+
+// Enum value maps for FileCategory.
+var (
+	FileCategory_name = map[int32]string{
+		0: "UndefinedFileCategory",
+		1: "CompressedFile",
+		2: "DocumentFile",
+		3: "MusicFile",
+		4: "VideoFile",
+		5: "ImageFile",
+		6: "ProgramFile",
+		7: "OtherFile",
+	}
+	FileCategory_value = map[string]int32{
+		"UndefinedFileCategory": 0,
+		"CompressedFile":        1,
+		"DocumentFile":          2,
+		"MusicFile":             3,
+		"VideoFile":             4,
+		"ImageFile":             5,
+		"ProgramFile":           6,
+		"OtherFile":             7,
+	}
+)
+
+func (x FileCategory) Enum() *FileCategory {
+	p := new(FileCategory)
+	*p = x
+	return p
+}
+
+func (x FileCategory) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FileCategory) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_go_load_proto_enumTypes[7].Descriptor()
+}
+
+func (FileCategory) Type() protoreflect.EnumType {
+	return &file_api_go_load_proto_enumTypes[7]
+}
+
+func (x FileCategory) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FileCategory.Descriptor instead.
+func (FileCategory) EnumDescriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{7}
+}
+
 type Account struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Id                    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	AccountName           string                 `protobuf:"bytes,2,opt,name=account_name,json=accountName,proto3" json:"account_name,omitempty"`
+	SpeedLimitBytesPerSec uint64                 `protobuf:"varint,3,opt,name=speed_limit_bytes_per_sec,json=speedLimitBytesPerSec,proto3" json:"speed_limit_bytes_per_sec,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *Account) Reset() {
+	*x = Account{}
+	mi := &file_api_go_load_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Account) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Account) ProtoMessage() {}
+
+func (x *Account) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Account.ProtoReflect.Descriptor instead.
+func (*Account) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Account) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Account) GetAccountName() string {
+	if x != nil {
+		return x.AccountName
+	}
+	return ""
+}
+
+func (x *Account) GetSpeedLimitBytesPerSec() uint64 {
+	if x != nil {
+		return x.SpeedLimitBytesPerSec
+	}
+	return 0
+}
+
+type DownloadTask struct {
+	state                   protoimpl.MessageState    `protogen:"open.v1"`
+	Id                      uint64                    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OfAccount               *Account                  `protobuf:"bytes,2,opt,name=of_account,json=ofAccount,proto3" json:"of_account,omitempty"`
+	DownloadType            DownloadType              `protobuf:"varint,3,opt,name=download_type,json=downloadType,proto3,enum=go_load.DownloadType" json:"download_type,omitempty"`
+	Url                     string                    `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	DownloadStatus          DownloadStatus            `protobuf:"varint,5,opt,name=download_status,json=downloadStatus,proto3,enum=go_load.DownloadStatus" json:"download_status,omitempty"`
+	DownloadedByteCount     uint64                    `protobuf:"varint,6,opt,name=downloaded_byte_count,json=downloadedByteCount,proto3" json:"downloaded_byte_count,omitempty"`
+	TotalByteCount          uint64                    `protobuf:"varint,7,opt,name=total_byte_count,json=totalByteCount,proto3" json:"total_byte_count,omitempty"`
+	DownloadedSegmentCount  uint32                    `protobuf:"varint,8,opt,name=downloaded_segment_count,json=downloadedSegmentCount,proto3" json:"downloaded_segment_count,omitempty"`
+	TotalSegmentCount       uint32                    `protobuf:"varint,9,opt,name=total_segment_count,json=totalSegmentCount,proto3" json:"total_segment_count,omitempty"`
+	FilePaths               []string                  `protobuf:"bytes,10,rep,name=file_paths,json=filePaths,proto3" json:"file_paths,omitempty"`
+	Sha256                  string                    `protobuf:"bytes,11,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	FailureReason           string                    `protobuf:"bytes,12,opt,name=failure_reason,json=failureReason,proto3" json:"failure_reason,omitempty"`
+	SpeedLimitBytesPerSec   uint64                    `protobuf:"varint,13,opt,name=speed_limit_bytes_per_sec,json=speedLimitBytesPerSec,proto3" json:"speed_limit_bytes_per_sec,omitempty"`
+	DownloadQueueId         uint64                    `protobuf:"varint,14,opt,name=download_queue_id,json=downloadQueueId,proto3" json:"download_queue_id,omitempty"`
+	NextRunAt               *timestamppb.Timestamp    `protobuf:"bytes,15,opt,name=next_run_at,json=nextRunAt,proto3" json:"next_run_at,omitempty"`
+	CronExpression          string                    `protobuf:"bytes,16,opt,name=cron_expression,json=cronExpression,proto3" json:"cron_expression,omitempty"`
+	TimeZone                string                    `protobuf:"bytes,17,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	AttemptCount            uint32                    `protobuf:"varint,18,opt,name=attempt_count,json=attemptCount,proto3" json:"attempt_count,omitempty"`
+	MaxAttemptCount         uint32                    `protobuf:"varint,19,opt,name=max_attempt_count,json=maxAttemptCount,proto3" json:"max_attempt_count,omitempty"`
+	NextRetryAt             *timestamppb.Timestamp    `protobuf:"bytes,20,opt,name=next_retry_at,json=nextRetryAt,proto3" json:"next_retry_at,omitempty"`
+	MirrorUrls              []string                  `protobuf:"bytes,21,rep,name=mirror_urls,json=mirrorUrls,proto3" json:"mirror_urls,omitempty"`
+	PostDownloadStepResults []*PostDownloadStepResult `protobuf:"bytes,22,rep,name=post_download_step_results,json=postDownloadStepResults,proto3" json:"post_download_step_results,omitempty"`
+	ExtractArchive          bool                      `protobuf:"varint,23,opt,name=extract_archive,json=extractArchive,proto3" json:"extract_archive,omitempty"`
+	ExtractedFiles          []*ExtractedFile          `protobuf:"bytes,24,rep,name=extracted_files,json=extractedFiles,proto3" json:"extracted_files,omitempty"`
+	ReusedExistingDownload  bool                      `protobuf:"varint,25,opt,name=reused_existing_download,json=reusedExistingDownload,proto3" json:"reused_existing_download,omitempty"`
+	WatchOptions            *WatchOptions             `protobuf:"bytes,26,opt,name=watch_options,json=watchOptions,proto3" json:"watch_options,omitempty"`
+	VersionNumber           uint32                    `protobuf:"varint,27,opt,name=version_number,json=versionNumber,proto3" json:"version_number,omitempty"`
+	NextCheckAt             *timestamppb.Timestamp    `protobuf:"bytes,28,opt,name=next_check_at,json=nextCheckAt,proto3" json:"next_check_at,omitempty"`
+	CrawlOptions            *CrawlOptions             `protobuf:"bytes,29,opt,name=crawl_options,json=crawlOptions,proto3" json:"crawl_options,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *DownloadTask) Reset() {
+	*x = DownloadTask{}
+	mi := &file_api_go_load_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadTask) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadTask) ProtoMessage() {}
+
+func (x *DownloadTask) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadTask.ProtoReflect.Descriptor instead.
+func (*DownloadTask) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DownloadTask) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DownloadTask) GetOfAccount() *Account {
+	if x != nil {
+		return x.OfAccount
+	}
+	return nil
+}
+
+func (x *DownloadTask) GetDownloadType() DownloadType {
+	if x != nil {
+		return x.DownloadType
+	}
+	return DownloadType_UndefinedType
+}
+
+func (x *DownloadTask) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *DownloadTask) GetDownloadStatus() DownloadStatus {
+	if x != nil {
+		return x.DownloadStatus
+	}
+	return DownloadStatus_UndefinedStatus
+}
+
+func (x *DownloadTask) GetDownloadedByteCount() uint64 {
+	if x != nil {
+		return x.DownloadedByteCount
+	}
+	return 0
+}
+
+func (x *DownloadTask) GetTotalByteCount() uint64 {
+	if x != nil {
+		return x.TotalByteCount
+	}
+	return 0
+}
+
+func (x *DownloadTask) GetDownloadedSegmentCount() uint32 {
+	if x != nil {
+		return x.DownloadedSegmentCount
+	}
+	return 0
+}
+
+func (x *DownloadTask) GetTotalSegmentCount() uint32 {
+	if x != nil {
+		return x.TotalSegmentCount
+	}
+	return 0
+}
+
+func (x *DownloadTask) GetFilePaths() []string {
+	if x != nil {
+		return x.FilePaths
+	}
+	return nil
+}
+
+func (x *DownloadTask) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *DownloadTask) GetFailureReason() string {
+	if x != nil {
+		return x.FailureReason
+	}
+	return ""
+}
+
+func (x *DownloadTask) GetSpeedLimitBytesPerSec() uint64 {
+	if x != nil {
+		return x.SpeedLimitBytesPerSec
+	}
+	return 0
+}
+
+func (x *DownloadTask) GetDownloadQueueId() uint64 {
+	if x != nil {
+		return x.DownloadQueueId
+	}
+	return 0
+}
+
+func (x *DownloadTask) GetNextRunAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRunAt
+	}
+	return nil
+}
+
+func (x *DownloadTask) GetCronExpression() string {
+	if x != nil {
+		return x.CronExpression
+	}
+	return ""
+}
+
+func (x *DownloadTask) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *DownloadTask) GetAttemptCount() uint32 {
+	if x != nil {
+		return x.AttemptCount
+	}
+	return 0
+}
+
+func (x *DownloadTask) GetMaxAttemptCount() uint32 {
+	if x != nil {
+		return x.MaxAttemptCount
+	}
+	return 0
+}
+
+func (x *DownloadTask) GetNextRetryAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRetryAt
+	}
+	return nil
+}
+
+func (x *DownloadTask) GetMirrorUrls() []string {
+	if x != nil {
+		return x.MirrorUrls
+	}
+	return nil
+}
+
+func (x *DownloadTask) GetPostDownloadStepResults() []*PostDownloadStepResult {
+	if x != nil {
+		return x.PostDownloadStepResults
+	}
+	return nil
+}
+
+func (x *DownloadTask) GetExtractArchive() bool {
+	if x != nil {
+		return x.ExtractArchive
+	}
+	return false
+}
+
+func (x *DownloadTask) GetExtractedFiles() []*ExtractedFile {
+	if x != nil {
+		return x.ExtractedFiles
+	}
+	return nil
+}
+
+func (x *DownloadTask) GetReusedExistingDownload() bool {
+	if x != nil {
+		return x.ReusedExistingDownload
+	}
+	return false
+}
+
+func (x *DownloadTask) GetWatchOptions() *WatchOptions {
+	if x != nil {
+		return x.WatchOptions
+	}
+	return nil
+}
+
+func (x *DownloadTask) GetVersionNumber() uint32 {
+	if x != nil {
+		return x.VersionNumber
+	}
+	return 0
+}
+
+func (x *DownloadTask) GetNextCheckAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextCheckAt
+	}
+	return nil
+}
+
+func (x *DownloadTask) GetCrawlOptions() *CrawlOptions {
+	if x != nil {
+		return x.CrawlOptions
+	}
+	return nil
+}
+
+type Digest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Algorithm     DigestAlgorithm        `protobuf:"varint,1,opt,name=algorithm,proto3,enum=go_load.DigestAlgorithm" json:"algorithm,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Digest) Reset() {
+	*x = Digest{}
+	mi := &file_api_go_load_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Digest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Digest) ProtoMessage() {}
+
+func (x *Digest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Digest.ProtoReflect.Descriptor instead.
+func (*Digest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Digest) GetAlgorithm() DigestAlgorithm {
+	if x != nil {
+		return x.Algorithm
+	}
+	return DigestAlgorithm_UndefinedAlgorithm
+}
+
+func (x *Digest) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type CreateAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountName   string                 `protobuf:"bytes,1,opt,name=account_name,json=accountName,proto3" json:"account_name,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAccountRequest) Reset() {
+	*x = CreateAccountRequest{}
+	mi := &file_api_go_load_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAccountRequest) ProtoMessage() {}
+
+func (x *CreateAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAccountRequest.ProtoReflect.Descriptor instead.
+func (*CreateAccountRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateAccountRequest) GetAccountName() string {
+	if x != nil {
+		return x.AccountName
+	}
+	return ""
+}
+
+func (x *CreateAccountRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type CreateAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     uint64                 `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAccountResponse) Reset() {
+	*x = CreateAccountResponse{}
+	mi := &file_api_go_load_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAccountResponse) ProtoMessage() {}
+
+func (x *CreateAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAccountResponse.ProtoReflect.Descriptor instead.
+func (*CreateAccountResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateAccountResponse) GetAccountId() uint64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+type CreateSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountName   string                 `protobuf:"bytes,1,opt,name=account_name,json=accountName,proto3" json:"account_name,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSessionRequest) Reset() {
+	*x = CreateSessionRequest{}
+	mi := &file_api_go_load_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSessionRequest) ProtoMessage() {}
+
+func (x *CreateSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSessionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSessionRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateSessionRequest) GetAccountName() string {
+	if x != nil {
+		return x.AccountName
+	}
+	return ""
+}
+
+func (x *CreateSessionRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type CreateSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Account       *Account               `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSessionResponse) Reset() {
+	*x = CreateSessionResponse{}
+	mi := &file_api_go_load_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSessionResponse) ProtoMessage() {}
+
+func (x *CreateSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSessionResponse.ProtoReflect.Descriptor instead.
+func (*CreateSessionResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateSessionResponse) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *CreateSessionResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type SFTPOptions struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Password             string                 `protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"`
+	PrivateKey           string                 `protobuf:"bytes,2,opt,name=private_key,json=privateKey,proto3" json:"private_key,omitempty"`
+	PrivateKeyPassphrase string                 `protobuf:"bytes,3,opt,name=private_key_passphrase,json=privateKeyPassphrase,proto3" json:"private_key_passphrase,omitempty"`
+	HostKey              string                 `protobuf:"bytes,4,opt,name=host_key,json=hostKey,proto3" json:"host_key,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *SFTPOptions) Reset() {
+	*x = SFTPOptions{}
+	mi := &file_api_go_load_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SFTPOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SFTPOptions) ProtoMessage() {}
+
+func (x *SFTPOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SFTPOptions.ProtoReflect.Descriptor instead.
+func (*SFTPOptions) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SFTPOptions) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *SFTPOptions) GetPrivateKey() string {
+	if x != nil {
+		return x.PrivateKey
+	}
+	return ""
+}
+
+func (x *SFTPOptions) GetPrivateKeyPassphrase() string {
+	if x != nil {
+		return x.PrivateKeyPassphrase
+	}
+	return ""
+}
+
+func (x *SFTPOptions) GetHostKey() string {
+	if x != nil {
+		return x.HostKey
+	}
+	return ""
+}
+
+type StreamOptions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bandwidth     uint64                 `protobuf:"varint,1,opt,name=bandwidth,proto3" json:"bandwidth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamOptions) Reset() {
+	*x = StreamOptions{}
+	mi := &file_api_go_load_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamOptions) ProtoMessage() {}
+
+func (x *StreamOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamOptions.ProtoReflect.Descriptor instead.
+func (*StreamOptions) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StreamOptions) GetBandwidth() uint64 {
+	if x != nil {
+		return x.Bandwidth
+	}
+	return 0
+}
+
+type TorrentOptions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Torrent       []byte                 `protobuf:"bytes,1,opt,name=torrent,proto3" json:"torrent,omitempty"`
+	FileIndexes   []uint32               `protobuf:"varint,2,rep,packed,name=file_indexes,json=fileIndexes,proto3" json:"file_indexes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TorrentOptions) Reset() {
+	*x = TorrentOptions{}
+	mi := &file_api_go_load_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TorrentOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TorrentOptions) ProtoMessage() {}
+
+func (x *TorrentOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TorrentOptions.ProtoReflect.Descriptor instead.
+func (*TorrentOptions) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TorrentOptions) GetTorrent() []byte {
+	if x != nil {
+		return x.Torrent
+	}
+	return nil
+}
+
+func (x *TorrentOptions) GetFileIndexes() []uint32 {
+	if x != nil {
+		return x.FileIndexes
+	}
+	return nil
+}
+
+type S3Options struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Profile         string                 `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	Endpoint        string                 `protobuf:"bytes,2,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	Region          string                 `protobuf:"bytes,3,opt,name=region,proto3" json:"region,omitempty"`
+	AccessKeyId     string                 `protobuf:"bytes,4,opt,name=access_key_id,json=accessKeyId,proto3" json:"access_key_id,omitempty"`
+	SecretAccessKey string                 `protobuf:"bytes,5,opt,name=secret_access_key,json=secretAccessKey,proto3" json:"secret_access_key,omitempty"`
+	SessionToken    string                 `protobuf:"bytes,6,opt,name=session_token,json=sessionToken,proto3" json:"session_token,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *S3Options) Reset() {
+	*x = S3Options{}
+	mi := &file_api_go_load_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *S3Options) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*S3Options) ProtoMessage() {}
+
+func (x *S3Options) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use S3Options.ProtoReflect.Descriptor instead.
+func (*S3Options) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *S3Options) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *S3Options) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+func (x *S3Options) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *S3Options) GetAccessKeyId() string {
+	if x != nil {
+		return x.AccessKeyId
+	}
+	return ""
+}
+
+func (x *S3Options) GetSecretAccessKey() string {
+	if x != nil {
+		return x.SecretAccessKey
+	}
+	return ""
+}
+
+func (x *S3Options) GetSessionToken() string {
+	if x != nil {
+		return x.SessionToken
+	}
+	return ""
+}
+
+type CreateDownloadTaskRequest struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Token                 string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	DownloadType          DownloadType           `protobuf:"varint,2,opt,name=download_type,json=downloadType,proto3,enum=go_load.DownloadType" json:"download_type,omitempty"`
+	Url                   string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	SegmentCount          uint32                 `protobuf:"varint,4,opt,name=segment_count,json=segmentCount,proto3" json:"segment_count,omitempty"`
+	MinSegmentSize        uint64                 `protobuf:"varint,5,opt,name=min_segment_size,json=minSegmentSize,proto3" json:"min_segment_size,omitempty"`
+	SftpOptions           *SFTPOptions           `protobuf:"bytes,6,opt,name=sftp_options,json=sftpOptions,proto3" json:"sftp_options,omitempty"`
+	StreamOptions         *StreamOptions         `protobuf:"bytes,7,opt,name=stream_options,json=streamOptions,proto3" json:"stream_options,omitempty"`
+	TorrentOptions        *TorrentOptions        `protobuf:"bytes,8,opt,name=torrent_options,json=torrentOptions,proto3" json:"torrent_options,omitempty"`
+	S3Options             *S3Options             `protobuf:"bytes,9,opt,name=s3_options,json=s3Options,proto3" json:"s3_options,omitempty"`
+	ExpectedDigest        *Digest                `protobuf:"bytes,10,opt,name=expected_digest,json=expectedDigest,proto3" json:"expected_digest,omitempty"`
+	SpeedLimitBytesPerSec uint64                 `protobuf:"varint,11,opt,name=speed_limit_bytes_per_sec,json=speedLimitBytesPerSec,proto3" json:"speed_limit_bytes_per_sec,omitempty"`
+	DownloadQueueId       uint64                 `protobuf:"varint,12,opt,name=download_queue_id,json=downloadQueueId,proto3" json:"download_queue_id,omitempty"`
+	StartAt               *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`
+	CronExpression        string                 `protobuf:"bytes,14,opt,name=cron_expression,json=cronExpression,proto3" json:"cron_expression,omitempty"`
+	TimeZone              string                 `protobuf:"bytes,15,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	MaxAttemptCount       uint32                 `protobuf:"varint,16,opt,name=max_attempt_count,json=maxAttemptCount,proto3" json:"max_attempt_count,omitempty"`
+	MirrorUrls            []string               `protobuf:"bytes,17,rep,name=mirror_urls,json=mirrorUrls,proto3" json:"mirror_urls,omitempty"`
+	ExpectedSize          uint64                 `protobuf:"varint,18,opt,name=expected_size,json=expectedSize,proto3" json:"expected_size,omitempty"`
+	PieceDigests          *PieceDigests          `protobuf:"bytes,19,opt,name=piece_digests,json=pieceDigests,proto3" json:"piece_digests,omitempty"`
+	ExtractArchive        bool                   `protobuf:"varint,20,opt,name=extract_archive,json=extractArchive,proto3" json:"extract_archive,omitempty"`
+	DuplicateUrlMode      DuplicateURLMode       `protobuf:"varint,21,opt,name=duplicate_url_mode,json=duplicateUrlMode,proto3,enum=go_load.DuplicateURLMode" json:"duplicate_url_mode,omitempty"`
+	WatchOptions          *WatchOptions          `protobuf:"bytes,22,opt,name=watch_options,json=watchOptions,proto3" json:"watch_options,omitempty"`
+	CrawlOptions          *CrawlOptions          `protobuf:"bytes,23,opt,name=crawl_options,json=crawlOptions,proto3" json:"crawl_options,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *CreateDownloadTaskRequest) Reset() {
+	*x = CreateDownloadTaskRequest{}
+	mi := &file_api_go_load_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDownloadTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDownloadTaskRequest) ProtoMessage() {}
+
+func (x *CreateDownloadTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDownloadTaskRequest.ProtoReflect.Descriptor instead.
+func (*CreateDownloadTaskRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateDownloadTaskRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *CreateDownloadTaskRequest) GetDownloadType() DownloadType {
+	if x != nil {
+		return x.DownloadType
+	}
+	return DownloadType_UndefinedType
+}
+
+func (x *CreateDownloadTaskRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateDownloadTaskRequest) GetSegmentCount() uint32 {
+	if x != nil {
+		return x.SegmentCount
+	}
+	return 0
+}
+
+func (x *CreateDownloadTaskRequest) GetMinSegmentSize() uint64 {
+	if x != nil {
+		return x.MinSegmentSize
+	}
+	return 0
+}
+
+func (x *CreateDownloadTaskRequest) GetSftpOptions() *SFTPOptions {
+	if x != nil {
+		return x.SftpOptions
+	}
+	return nil
+}
+
+func (x *CreateDownloadTaskRequest) GetStreamOptions() *StreamOptions {
+	if x != nil {
+		return x.StreamOptions
+	}
+	return nil
+}
+
+func (x *CreateDownloadTaskRequest) GetTorrentOptions() *TorrentOptions {
+	if x != nil {
+		return x.TorrentOptions
+	}
+	return nil
+}
+
+func (x *CreateDownloadTaskRequest) GetS3Options() *S3Options {
+	if x != nil {
+		return x.S3Options
+	}
+	return nil
+}
+
+func (x *CreateDownloadTaskRequest) GetExpectedDigest() *Digest {
+	if x != nil {
+		return x.ExpectedDigest
+	}
+	return nil
+}
+
+func (x *CreateDownloadTaskRequest) GetSpeedLimitBytesPerSec() uint64 {
+	if x != nil {
+		return x.SpeedLimitBytesPerSec
+	}
+	return 0
+}
+
+func (x *CreateDownloadTaskRequest) GetDownloadQueueId() uint64 {
+	if x != nil {
+		return x.DownloadQueueId
+	}
+	return 0
+}
+
+func (x *CreateDownloadTaskRequest) GetStartAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartAt
+	}
+	return nil
+}
+
+func (x *CreateDownloadTaskRequest) GetCronExpression() string {
+	if x != nil {
+		return x.CronExpression
+	}
+	return ""
+}
+
+func (x *CreateDownloadTaskRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *CreateDownloadTaskRequest) GetMaxAttemptCount() uint32 {
+	if x != nil {
+		return x.MaxAttemptCount
+	}
+	return 0
+}
+
+func (x *CreateDownloadTaskRequest) GetMirrorUrls() []string {
+	if x != nil {
+		return x.MirrorUrls
+	}
+	return nil
+}
+
+func (x *CreateDownloadTaskRequest) GetExpectedSize() uint64 {
+	if x != nil {
+		return x.ExpectedSize
+	}
+	return 0
+}
+
+func (x *CreateDownloadTaskRequest) GetPieceDigests() *PieceDigests {
+	if x != nil {
+		return x.PieceDigests
+	}
+	return nil
+}
+
+func (x *CreateDownloadTaskRequest) GetExtractArchive() bool {
+	if x != nil {
+		return x.ExtractArchive
+	}
+	return false
+}
+
+func (x *CreateDownloadTaskRequest) GetDuplicateUrlMode() DuplicateURLMode {
+	if x != nil {
+		return x.DuplicateUrlMode
+	}
+	return DuplicateURLMode_UndefinedDuplicateURLMode
+}
+
+func (x *CreateDownloadTaskRequest) GetWatchOptions() *WatchOptions {
+	if x != nil {
+		return x.WatchOptions
+	}
+	return nil
+}
+
+func (x *CreateDownloadTaskRequest) GetCrawlOptions() *CrawlOptions {
+	if x != nil {
+		return x.CrawlOptions
+	}
+	return nil
+}
+
+type CreateDownloadTaskResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	DownloadTask      *DownloadTask          `protobuf:"bytes,1,opt,name=download_task,json=downloadTask,proto3" json:"download_task,omitempty"`
+	DuplicateDownload *DuplicateDownload     `protobuf:"bytes,2,opt,name=duplicate_download,json=duplicateDownload,proto3" json:"duplicate_download,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CreateDownloadTaskResponse) Reset() {
+	*x = CreateDownloadTaskResponse{}
+	mi := &file_api_go_load_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDownloadTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDownloadTaskResponse) ProtoMessage() {}
+
+func (x *CreateDownloadTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDownloadTaskResponse.ProtoReflect.Descriptor instead.
+func (*CreateDownloadTaskResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CreateDownloadTaskResponse) GetDownloadTask() *DownloadTask {
+	if x != nil {
+		return x.DownloadTask
+	}
+	return nil
+}
+
+func (x *CreateDownloadTaskResponse) GetDuplicateDownload() *DuplicateDownload {
+	if x != nil {
+		return x.DuplicateDownload
+	}
+	return nil
+}
+
+type GetDownloadTaskListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Offset        uint64                 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit         uint64                 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDownloadTaskListRequest) Reset() {
+	*x = GetDownloadTaskListRequest{}
+	mi := &file_api_go_load_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDownloadTaskListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDownloadTaskListRequest) ProtoMessage() {}
+
+func (x *GetDownloadTaskListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDownloadTaskListRequest.ProtoReflect.Descriptor instead.
+func (*GetDownloadTaskListRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetDownloadTaskListRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *GetDownloadTaskListRequest) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetDownloadTaskListRequest) GetLimit() uint64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type GetDownloadTaskListResponse struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	DownloadTaskList       []*DownloadTask        `protobuf:"bytes,1,rep,name=download_task_list,json=downloadTaskList,proto3" json:"download_task_list,omitempty"`
+	TotalDownloadTaskCount uint64                 `protobuf:"varint,2,opt,name=total_download_task_count,json=totalDownloadTaskCount,proto3" json:"total_download_task_count,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *GetDownloadTaskListResponse) Reset() {
+	*x = GetDownloadTaskListResponse{}
+	mi := &file_api_go_load_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDownloadTaskListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDownloadTaskListResponse) ProtoMessage() {}
+
+func (x *GetDownloadTaskListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDownloadTaskListResponse.ProtoReflect.Descriptor instead.
+func (*GetDownloadTaskListResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetDownloadTaskListResponse) GetDownloadTaskList() []*DownloadTask {
+	if x != nil {
+		return x.DownloadTaskList
+	}
+	return nil
+}
+
+func (x *GetDownloadTaskListResponse) GetTotalDownloadTaskCount() uint64 {
+	if x != nil {
+		return x.TotalDownloadTaskCount
+	}
+	return 0
+}
+
+type UpdateDownloadTaskRequest struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Token                 string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	DownloadTaskId        uint64                 `protobuf:"varint,2,opt,name=download_task_id,json=downloadTaskId,proto3" json:"download_task_id,omitempty"`
+	Url                   string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	SpeedLimitBytesPerSec *uint64                `protobuf:"varint,4,opt,name=speed_limit_bytes_per_sec,json=speedLimitBytesPerSec,proto3,oneof" json:"speed_limit_bytes_per_sec,omitempty"`
+	MirrorUrlList         *MirrorURLList         `protobuf:"bytes,5,opt,name=mirror_url_list,json=mirrorUrlList,proto3" json:"mirror_url_list,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *UpdateDownloadTaskRequest) Reset() {
+	*x = UpdateDownloadTaskRequest{}
+	mi := &file_api_go_load_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDownloadTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDownloadTaskRequest) ProtoMessage() {}
+
+func (x *UpdateDownloadTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDownloadTaskRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDownloadTaskRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UpdateDownloadTaskRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *UpdateDownloadTaskRequest) GetDownloadTaskId() uint64 {
+	if x != nil {
+		return x.DownloadTaskId
+	}
+	return 0
+}
+
+func (x *UpdateDownloadTaskRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *UpdateDownloadTaskRequest) GetSpeedLimitBytesPerSec() uint64 {
+	if x != nil && x.SpeedLimitBytesPerSec != nil {
+		return *x.SpeedLimitBytesPerSec
+	}
+	return 0
+}
+
+func (x *UpdateDownloadTaskRequest) GetMirrorUrlList() *MirrorURLList {
+	if x != nil {
+		return x.MirrorUrlList
+	}
+	return nil
+}
+
+type UpdateDownloadTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DownloadTask  *DownloadTask          `protobuf:"bytes,1,opt,name=download_task,json=downloadTask,proto3" json:"download_task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateDownloadTaskResponse) Reset() {
+	*x = UpdateDownloadTaskResponse{}
+	mi := &file_api_go_load_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDownloadTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDownloadTaskResponse) ProtoMessage() {}
+
+func (x *UpdateDownloadTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDownloadTaskResponse.ProtoReflect.Descriptor instead.
+func (*UpdateDownloadTaskResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UpdateDownloadTaskResponse) GetDownloadTask() *DownloadTask {
+	if x != nil {
+		return x.DownloadTask
+	}
+	return nil
+}
+
+type DeleteDownloadTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	DownloadTask  *DownloadTask          `protobuf:"bytes,2,opt,name=download_task,json=downloadTask,proto3" json:"download_task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDownloadTaskRequest) Reset() {
+	*x = DeleteDownloadTaskRequest{}
+	mi := &file_api_go_load_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDownloadTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDownloadTaskRequest) ProtoMessage() {}
+
+func (x *DeleteDownloadTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDownloadTaskRequest.ProtoReflect.Descriptor instead.
+func (*DeleteDownloadTaskRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DeleteDownloadTaskRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *DeleteDownloadTaskRequest) GetDownloadTask() *DownloadTask {
+	if x != nil {
+		return x.DownloadTask
+	}
+	return nil
+}
+
+type DeleteDownloadTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDownloadTaskResponse) Reset() {
+	*x = DeleteDownloadTaskResponse{}
+	mi := &file_api_go_load_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDownloadTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDownloadTaskResponse) ProtoMessage() {}
+
+func (x *DeleteDownloadTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDownloadTaskResponse.ProtoReflect.Descriptor instead.
+func (*DeleteDownloadTaskResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{18}
+}
+
+type GetDownloadTaskFileRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Token          string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	DownloadTaskId uint64                 `protobuf:"varint,2,opt,name=download_task_id,json=downloadTaskId,proto3" json:"download_task_id,omitempty"`
+	FilePath       string                 `protobuf:"bytes,3,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
+	VersionNumber  uint32                 `protobuf:"varint,4,opt,name=version_number,json=versionNumber,proto3" json:"version_number,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetDownloadTaskFileRequest) Reset() {
+	*x = GetDownloadTaskFileRequest{}
+	mi := &file_api_go_load_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDownloadTaskFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDownloadTaskFileRequest) ProtoMessage() {}
+
+func (x *GetDownloadTaskFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDownloadTaskFileRequest.ProtoReflect.Descriptor instead.
+func (*GetDownloadTaskFileRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetDownloadTaskFileRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *GetDownloadTaskFileRequest) GetDownloadTaskId() uint64 {
+	if x != nil {
+		return x.DownloadTaskId
+	}
+	return 0
+}
+
+func (x *GetDownloadTaskFileRequest) GetFilePath() string {
+	if x != nil {
+		return x.FilePath
+	}
+	return ""
+}
+
+func (x *GetDownloadTaskFileRequest) GetVersionNumber() uint32 {
+	if x != nil {
+		return x.VersionNumber
+	}
+	return 0
+}
+
+type GetDownloadTaskFileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDownloadTaskFileResponse) Reset() {
+	*x = GetDownloadTaskFileResponse{}
+	mi := &file_api_go_load_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDownloadTaskFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDownloadTaskFileResponse) ProtoMessage() {}
+
+func (x *GetDownloadTaskFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDownloadTaskFileResponse.ProtoReflect.Descriptor instead.
+func (*GetDownloadTaskFileResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetDownloadTaskFileResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type GetDownloadTaskDigestsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Token          string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	DownloadTaskId uint64                 `protobuf:"varint,2,opt,name=download_task_id,json=downloadTaskId,proto3" json:"download_task_id,omitempty"`
+	FilePath       string                 `protobuf:"bytes,3,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
+	Algorithms     []DigestAlgorithm      `protobuf:"varint,4,rep,packed,name=algorithms,proto3,enum=go_load.DigestAlgorithm" json:"algorithms,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetDownloadTaskDigestsRequest) Reset() {
+	*x = GetDownloadTaskDigestsRequest{}
+	mi := &file_api_go_load_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDownloadTaskDigestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDownloadTaskDigestsRequest) ProtoMessage() {}
+
+func (x *GetDownloadTaskDigestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDownloadTaskDigestsRequest.ProtoReflect.Descriptor instead.
+func (*GetDownloadTaskDigestsRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetDownloadTaskDigestsRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *GetDownloadTaskDigestsRequest) GetDownloadTaskId() uint64 {
+	if x != nil {
+		return x.DownloadTaskId
+	}
+	return 0
+}
+
+func (x *GetDownloadTaskDigestsRequest) GetFilePath() string {
+	if x != nil {
+		return x.FilePath
+	}
+	return ""
+}
+
+func (x *GetDownloadTaskDigestsRequest) GetAlgorithms() []DigestAlgorithm {
+	if x != nil {
+		return x.Algorithms
+	}
+	return nil
+}
+
+type GetDownloadTaskDigestsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Digests       []*Digest              `protobuf:"bytes,1,rep,name=digests,proto3" json:"digests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDownloadTaskDigestsResponse) Reset() {
+	*x = GetDownloadTaskDigestsResponse{}
+	mi := &file_api_go_load_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDownloadTaskDigestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDownloadTaskDigestsResponse) ProtoMessage() {}
+
+func (x *GetDownloadTaskDigestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDownloadTaskDigestsResponse.ProtoReflect.Descriptor instead.
+func (*GetDownloadTaskDigestsResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetDownloadTaskDigestsResponse) GetDigests() []*Digest {
+	if x != nil {
+		return x.Digests
+	}
+	return nil
+}
+
+type UpdateAccountSpeedLimitRequest struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Token                 string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	SpeedLimitBytesPerSec uint64                 `protobuf:"varint,2,opt,name=speed_limit_bytes_per_sec,json=speedLimitBytesPerSec,proto3" json:"speed_limit_bytes_per_sec,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *UpdateAccountSpeedLimitRequest) Reset() {
+	*x = UpdateAccountSpeedLimitRequest{}
+	mi := &file_api_go_load_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAccountSpeedLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAccountSpeedLimitRequest) ProtoMessage() {}
+
+func (x *UpdateAccountSpeedLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAccountSpeedLimitRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAccountSpeedLimitRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *UpdateAccountSpeedLimitRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *UpdateAccountSpeedLimitRequest) GetSpeedLimitBytesPerSec() uint64 {
+	if x != nil {
+		return x.SpeedLimitBytesPerSec
+	}
+	return 0
+}
+
+type UpdateAccountSpeedLimitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Account       *Account               `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAccountSpeedLimitResponse) Reset() {
+	*x = UpdateAccountSpeedLimitResponse{}
+	mi := &file_api_go_load_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAccountSpeedLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAccountSpeedLimitResponse) ProtoMessage() {}
+
+func (x *UpdateAccountSpeedLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAccountSpeedLimitResponse.ProtoReflect.Descriptor instead.
+func (*UpdateAccountSpeedLimitResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *UpdateAccountSpeedLimitResponse) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+type DownloadQueue struct {
+	state                      protoimpl.MessageState `protogen:"open.v1"`
+	Id                         uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                       string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Priority                   int32                  `protobuf:"varint,3,opt,name=priority,proto3" json:"priority,omitempty"`
+	MaxConcurrentDownloadCount uint32                 `protobuf:"varint,4,opt,name=max_concurrent_download_count,json=maxConcurrentDownloadCount,proto3" json:"max_concurrent_download_count,omitempty"`
+	ActiveStartTime            string                 `protobuf:"bytes,5,opt,name=active_start_time,json=activeStartTime,proto3" json:"active_start_time,omitempty"`
+	ActiveEndTime              string                 `protobuf:"bytes,6,opt,name=active_end_time,json=activeEndTime,proto3" json:"active_end_time,omitempty"`
+	DownloadTaskIds            []uint64               `protobuf:"varint,7,rep,packed,name=download_task_ids,json=downloadTaskIds,proto3" json:"download_task_ids,omitempty"`
+	unknownFields              protoimpl.UnknownFields
+	sizeCache                  protoimpl.SizeCache
+}
+
+func (x *DownloadQueue) Reset() {
+	*x = DownloadQueue{}
+	mi := &file_api_go_load_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadQueue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadQueue) ProtoMessage() {}
+
+func (x *DownloadQueue) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadQueue.ProtoReflect.Descriptor instead.
+func (*DownloadQueue) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *DownloadQueue) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DownloadQueue) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DownloadQueue) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *DownloadQueue) GetMaxConcurrentDownloadCount() uint32 {
+	if x != nil {
+		return x.MaxConcurrentDownloadCount
+	}
+	return 0
+}
+
+func (x *DownloadQueue) GetActiveStartTime() string {
+	if x != nil {
+		return x.ActiveStartTime
+	}
+	return ""
+}
+
+func (x *DownloadQueue) GetActiveEndTime() string {
+	if x != nil {
+		return x.ActiveEndTime
+	}
+	return ""
+}
+
+func (x *DownloadQueue) GetDownloadTaskIds() []uint64 {
+	if x != nil {
+		return x.DownloadTaskIds
+	}
+	return nil
+}
+
+type CreateDownloadQueueRequest struct {
+	state                      protoimpl.MessageState `protogen:"open.v1"`
+	Token                      string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Name                       string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Priority                   int32                  `protobuf:"varint,3,opt,name=priority,proto3" json:"priority,omitempty"`
+	MaxConcurrentDownloadCount uint32                 `protobuf:"varint,4,opt,name=max_concurrent_download_count,json=maxConcurrentDownloadCount,proto3" json:"max_concurrent_download_count,omitempty"`
+	ActiveStartTime            string                 `protobuf:"bytes,5,opt,name=active_start_time,json=activeStartTime,proto3" json:"active_start_time,omitempty"`
+	ActiveEndTime              string                 `protobuf:"bytes,6,opt,name=active_end_time,json=activeEndTime,proto3" json:"active_end_time,omitempty"`
+	unknownFields              protoimpl.UnknownFields
+	sizeCache                  protoimpl.SizeCache
+}
+
+func (x *CreateDownloadQueueRequest) Reset() {
+	*x = CreateDownloadQueueRequest{}
+	mi := &file_api_go_load_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDownloadQueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDownloadQueueRequest) ProtoMessage() {}
+
+func (x *CreateDownloadQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDownloadQueueRequest.ProtoReflect.Descriptor instead.
+func (*CreateDownloadQueueRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *CreateDownloadQueueRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *CreateDownloadQueueRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateDownloadQueueRequest) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *CreateDownloadQueueRequest) GetMaxConcurrentDownloadCount() uint32 {
+	if x != nil {
+		return x.MaxConcurrentDownloadCount
+	}
+	return 0
+}
+
+func (x *CreateDownloadQueueRequest) GetActiveStartTime() string {
+	if x != nil {
+		return x.ActiveStartTime
+	}
+	return ""
+}
+
+func (x *CreateDownloadQueueRequest) GetActiveEndTime() string {
+	if x != nil {
+		return x.ActiveEndTime
+	}
+	return ""
+}
+
+type CreateDownloadQueueResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DownloadQueue *DownloadQueue         `protobuf:"bytes,1,opt,name=download_queue,json=downloadQueue,proto3" json:"download_queue,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDownloadQueueResponse) Reset() {
+	*x = CreateDownloadQueueResponse{}
+	mi := &file_api_go_load_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDownloadQueueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDownloadQueueResponse) ProtoMessage() {}
+
+func (x *CreateDownloadQueueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDownloadQueueResponse.ProtoReflect.Descriptor instead.
+func (*CreateDownloadQueueResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CreateDownloadQueueResponse) GetDownloadQueue() *DownloadQueue {
+	if x != nil {
+		return x.DownloadQueue
+	}
+	return nil
+}
+
+type GetDownloadQueueListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDownloadQueueListRequest) Reset() {
+	*x = GetDownloadQueueListRequest{}
+	mi := &file_api_go_load_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDownloadQueueListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDownloadQueueListRequest) ProtoMessage() {}
+
+func (x *GetDownloadQueueListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDownloadQueueListRequest.ProtoReflect.Descriptor instead.
+func (*GetDownloadQueueListRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetDownloadQueueListRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type GetDownloadQueueListResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	DownloadQueueList []*DownloadQueue       `protobuf:"bytes,1,rep,name=download_queue_list,json=downloadQueueList,proto3" json:"download_queue_list,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *GetDownloadQueueListResponse) Reset() {
+	*x = GetDownloadQueueListResponse{}
+	mi := &file_api_go_load_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDownloadQueueListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDownloadQueueListResponse) ProtoMessage() {}
+
+func (x *GetDownloadQueueListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDownloadQueueListResponse.ProtoReflect.Descriptor instead.
+func (*GetDownloadQueueListResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetDownloadQueueListResponse) GetDownloadQueueList() []*DownloadQueue {
+	if x != nil {
+		return x.DownloadQueueList
+	}
+	return nil
+}
+
+type UpdateDownloadQueueRequest struct {
+	state                      protoimpl.MessageState `protogen:"open.v1"`
+	Token                      string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	DownloadQueueId            uint64                 `protobuf:"varint,2,opt,name=download_queue_id,json=downloadQueueId,proto3" json:"download_queue_id,omitempty"`
+	Name                       string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Priority                   int32                  `protobuf:"varint,4,opt,name=priority,proto3" json:"priority,omitempty"`
+	MaxConcurrentDownloadCount uint32                 `protobuf:"varint,5,opt,name=max_concurrent_download_count,json=maxConcurrentDownloadCount,proto3" json:"max_concurrent_download_count,omitempty"`
+	ActiveStartTime            string                 `protobuf:"bytes,6,opt,name=active_start_time,json=activeStartTime,proto3" json:"active_start_time,omitempty"`
+	ActiveEndTime              string                 `protobuf:"bytes,7,opt,name=active_end_time,json=activeEndTime,proto3" json:"active_end_time,omitempty"`
+	unknownFields              protoimpl.UnknownFields
+	sizeCache                  protoimpl.SizeCache
+}
+
+func (x *UpdateDownloadQueueRequest) Reset() {
+	*x = UpdateDownloadQueueRequest{}
+	mi := &file_api_go_load_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDownloadQueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDownloadQueueRequest) ProtoMessage() {}
+
+func (x *UpdateDownloadQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDownloadQueueRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDownloadQueueRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *UpdateDownloadQueueRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *UpdateDownloadQueueRequest) GetDownloadQueueId() uint64 {
+	if x != nil {
+		return x.DownloadQueueId
+	}
+	return 0
+}
+
+func (x *UpdateDownloadQueueRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateDownloadQueueRequest) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *UpdateDownloadQueueRequest) GetMaxConcurrentDownloadCount() uint32 {
+	if x != nil {
+		return x.MaxConcurrentDownloadCount
+	}
+	return 0
+}
+
+func (x *UpdateDownloadQueueRequest) GetActiveStartTime() string {
+	if x != nil {
+		return x.ActiveStartTime
+	}
+	return ""
+}
+
+func (x *UpdateDownloadQueueRequest) GetActiveEndTime() string {
+	if x != nil {
+		return x.ActiveEndTime
+	}
+	return ""
+}
+
+type UpdateDownloadQueueResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DownloadQueue *DownloadQueue         `protobuf:"bytes,1,opt,name=download_queue,json=downloadQueue,proto3" json:"download_queue,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateDownloadQueueResponse) Reset() {
+	*x = UpdateDownloadQueueResponse{}
+	mi := &file_api_go_load_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDownloadQueueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDownloadQueueResponse) ProtoMessage() {}
+
+func (x *UpdateDownloadQueueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDownloadQueueResponse.ProtoReflect.Descriptor instead.
+func (*UpdateDownloadQueueResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *UpdateDownloadQueueResponse) GetDownloadQueue() *DownloadQueue {
+	if x != nil {
+		return x.DownloadQueue
+	}
+	return nil
+}
+
+type DeleteDownloadQueueRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Token           string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	DownloadQueueId uint64                 `protobuf:"varint,2,opt,name=download_queue_id,json=downloadQueueId,proto3" json:"download_queue_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DeleteDownloadQueueRequest) Reset() {
+	*x = DeleteDownloadQueueRequest{}
+	mi := &file_api_go_load_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDownloadQueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDownloadQueueRequest) ProtoMessage() {}
+
+func (x *DeleteDownloadQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDownloadQueueRequest.ProtoReflect.Descriptor instead.
+func (*DeleteDownloadQueueRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *DeleteDownloadQueueRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *DeleteDownloadQueueRequest) GetDownloadQueueId() uint64 {
+	if x != nil {
+		return x.DownloadQueueId
+	}
+	return 0
+}
+
+type DeleteDownloadQueueResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDownloadQueueResponse) Reset() {
+	*x = DeleteDownloadQueueResponse{}
+	mi := &file_api_go_load_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDownloadQueueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDownloadQueueResponse) ProtoMessage() {}
+
+func (x *DeleteDownloadQueueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDownloadQueueResponse.ProtoReflect.Descriptor instead.
+func (*DeleteDownloadQueueResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{33}
+}
+
+type MoveDownloadTaskRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Token           string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	DownloadTaskId  uint64                 `protobuf:"varint,2,opt,name=download_task_id,json=downloadTaskId,proto3" json:"download_task_id,omitempty"`
+	DownloadQueueId uint64                 `protobuf:"varint,3,opt,name=download_queue_id,json=downloadQueueId,proto3" json:"download_queue_id,omitempty"`
+	Position        uint32                 `protobuf:"varint,4,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MoveDownloadTaskRequest) Reset() {
+	*x = MoveDownloadTaskRequest{}
+	mi := &file_api_go_load_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveDownloadTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveDownloadTaskRequest) ProtoMessage() {}
+
+func (x *MoveDownloadTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveDownloadTaskRequest.ProtoReflect.Descriptor instead.
+func (*MoveDownloadTaskRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *MoveDownloadTaskRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *MoveDownloadTaskRequest) GetDownloadTaskId() uint64 {
+	if x != nil {
+		return x.DownloadTaskId
+	}
+	return 0
+}
+
+func (x *MoveDownloadTaskRequest) GetDownloadQueueId() uint64 {
+	if x != nil {
+		return x.DownloadQueueId
+	}
+	return 0
+}
+
+func (x *MoveDownloadTaskRequest) GetPosition() uint32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+type MoveDownloadTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DownloadTask  *DownloadTask          `protobuf:"bytes,1,opt,name=download_task,json=downloadTask,proto3" json:"download_task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveDownloadTaskResponse) Reset() {
+	*x = MoveDownloadTaskResponse{}
+	mi := &file_api_go_load_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveDownloadTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveDownloadTaskResponse) ProtoMessage() {}
+
+func (x *MoveDownloadTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveDownloadTaskResponse.ProtoReflect.Descriptor instead.
+func (*MoveDownloadTaskResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *MoveDownloadTaskResponse) GetDownloadTask() *DownloadTask {
+	if x != nil {
+		return x.DownloadTask
+	}
+	return nil
+}
+
+type AccountShare struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	AccountId                uint64                 `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	AccountName              string                 `protobuf:"bytes,2,opt,name=account_name,json=accountName,proto3" json:"account_name,omitempty"`
+	Weight                   uint32                 `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	Share                    float64                `protobuf:"fixed64,4,opt,name=share,proto3" json:"share,omitempty"`
+	RunningDownloadTaskCount uint64                 `protobuf:"varint,5,opt,name=running_download_task_count,json=runningDownloadTaskCount,proto3" json:"running_download_task_count,omitempty"`
+	PendingDownloadTaskCount uint64                 `protobuf:"varint,6,opt,name=pending_download_task_count,json=pendingDownloadTaskCount,proto3" json:"pending_download_task_count,omitempty"`
+	RunningShare             float64                `protobuf:"fixed64,7,opt,name=running_share,json=runningShare,proto3" json:"running_share,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *AccountShare) Reset() {
+	*x = AccountShare{}
+	mi := &file_api_go_load_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountShare) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountShare) ProtoMessage() {}
+
+func (x *AccountShare) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountShare.ProtoReflect.Descriptor instead.
+func (*AccountShare) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *AccountShare) GetAccountId() uint64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *AccountShare) GetAccountName() string {
+	if x != nil {
+		return x.AccountName
+	}
+	return ""
+}
+
+func (x *AccountShare) GetWeight() uint32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *AccountShare) GetShare() float64 {
+	if x != nil {
+		return x.Share
+	}
+	return 0
+}
+
+func (x *AccountShare) GetRunningDownloadTaskCount() uint64 {
+	if x != nil {
+		return x.RunningDownloadTaskCount
+	}
+	return 0
+}
+
+func (x *AccountShare) GetPendingDownloadTaskCount() uint64 {
+	if x != nil {
+		return x.PendingDownloadTaskCount
+	}
+	return 0
+}
+
+func (x *AccountShare) GetRunningShare() float64 {
+	if x != nil {
+		return x.RunningShare
+	}
+	return 0
+}
+
+type GetAccountShareListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAccountShareListRequest) Reset() {
+	*x = GetAccountShareListRequest{}
+	mi := &file_api_go_load_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAccountShareListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountShareListRequest) ProtoMessage() {}
+
+func (x *GetAccountShareListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountShareListRequest.ProtoReflect.Descriptor instead.
+func (*GetAccountShareListRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *GetAccountShareListRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type GetAccountShareListResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	AccountShareList []*AccountShare        `protobuf:"bytes,1,rep,name=account_share_list,json=accountShareList,proto3" json:"account_share_list,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetAccountShareListResponse) Reset() {
+	*x = GetAccountShareListResponse{}
+	mi := &file_api_go_load_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAccountShareListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountShareListResponse) ProtoMessage() {}
+
+func (x *GetAccountShareListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountShareListResponse.ProtoReflect.Descriptor instead.
+func (*GetAccountShareListResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *GetAccountShareListResponse) GetAccountShareList() []*AccountShare {
+	if x != nil {
+		return x.AccountShareList
+	}
+	return nil
+}
+
+type DownloadTaskAttempt struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AttemptNumber  uint32                 `protobuf:"varint,1,opt,name=attempt_number,json=attemptNumber,proto3" json:"attempt_number,omitempty"`
+	StartedAt      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	Succeeded      bool                   `protobuf:"varint,4,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	ErrorClass     DownloadErrorClass     `protobuf:"varint,5,opt,name=error_class,json=errorClass,proto3,enum=go_load.DownloadErrorClass" json:"error_class,omitempty"`
+	HttpStatusCode uint32                 `protobuf:"varint,6,opt,name=http_status_code,json=httpStatusCode,proto3" json:"http_status_code,omitempty"`
+	ErrorMessage   string                 `protobuf:"bytes,7,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DownloadTaskAttempt) Reset() {
+	*x = DownloadTaskAttempt{}
+	mi := &file_api_go_load_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadTaskAttempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadTaskAttempt) ProtoMessage() {}
+
+func (x *DownloadTaskAttempt) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadTaskAttempt.ProtoReflect.Descriptor instead.
+func (*DownloadTaskAttempt) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *DownloadTaskAttempt) GetAttemptNumber() uint32 {
+	if x != nil {
+		return x.AttemptNumber
+	}
+	return 0
+}
+
+func (x *DownloadTaskAttempt) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *DownloadTaskAttempt) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+func (x *DownloadTaskAttempt) GetSucceeded() bool {
+	if x != nil {
+		return x.Succeeded
+	}
+	return false
+}
+
+func (x *DownloadTaskAttempt) GetErrorClass() DownloadErrorClass {
+	if x != nil {
+		return x.ErrorClass
+	}
+	return DownloadErrorClass_UndefinedErrorClass
+}
+
+func (x *DownloadTaskAttempt) GetHttpStatusCode() uint32 {
+	if x != nil {
+		return x.HttpStatusCode
+	}
+	return 0
+}
+
+func (x *DownloadTaskAttempt) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type GetDownloadTaskAttemptListRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Token          string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	DownloadTaskId uint64                 `protobuf:"varint,2,opt,name=download_task_id,json=downloadTaskId,proto3" json:"download_task_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetDownloadTaskAttemptListRequest) Reset() {
+	*x = GetDownloadTaskAttemptListRequest{}
+	mi := &file_api_go_load_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDownloadTaskAttemptListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDownloadTaskAttemptListRequest) ProtoMessage() {}
+
+func (x *GetDownloadTaskAttemptListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDownloadTaskAttemptListRequest.ProtoReflect.Descriptor instead.
+func (*GetDownloadTaskAttemptListRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *GetDownloadTaskAttemptListRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *GetDownloadTaskAttemptListRequest) GetDownloadTaskId() uint64 {
+	if x != nil {
+		return x.DownloadTaskId
+	}
+	return 0
+}
+
+type GetDownloadTaskAttemptListResponse struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	DownloadTaskAttemptList []*DownloadTaskAttempt `protobuf:"bytes,1,rep,name=download_task_attempt_list,json=downloadTaskAttemptList,proto3" json:"download_task_attempt_list,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *GetDownloadTaskAttemptListResponse) Reset() {
+	*x = GetDownloadTaskAttemptListResponse{}
+	mi := &file_api_go_load_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDownloadTaskAttemptListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDownloadTaskAttemptListResponse) ProtoMessage() {}
+
+func (x *GetDownloadTaskAttemptListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDownloadTaskAttemptListResponse.ProtoReflect.Descriptor instead.
+func (*GetDownloadTaskAttemptListResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *GetDownloadTaskAttemptListResponse) GetDownloadTaskAttemptList() []*DownloadTaskAttempt {
+	if x != nil {
+		return x.DownloadTaskAttemptList
+	}
+	return nil
+}
+
+type MirrorURLList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Urls          []string               `protobuf:"bytes,1,rep,name=urls,proto3" json:"urls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MirrorURLList) Reset() {
+	*x = MirrorURLList{}
+	mi := &file_api_go_load_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MirrorURLList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MirrorURLList) ProtoMessage() {}
+
+func (x *MirrorURLList) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MirrorURLList.ProtoReflect.Descriptor instead.
+func (*MirrorURLList) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *MirrorURLList) GetUrls() []string {
+	if x != nil {
+		return x.Urls
+	}
+	return nil
+}
+
+type PieceDigests struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Algorithm     DigestAlgorithm        `protobuf:"varint,1,opt,name=algorithm,proto3,enum=go_load.DigestAlgorithm" json:"algorithm,omitempty"`
+	PieceLength   uint64                 `protobuf:"varint,2,opt,name=piece_length,json=pieceLength,proto3" json:"piece_length,omitempty"`
+	Values        []string               `protobuf:"bytes,3,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PieceDigests) Reset() {
+	*x = PieceDigests{}
+	mi := &file_api_go_load_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PieceDigests) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PieceDigests) ProtoMessage() {}
+
+func (x *PieceDigests) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PieceDigests.ProtoReflect.Descriptor instead.
+func (*PieceDigests) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *PieceDigests) GetAlgorithm() DigestAlgorithm {
+	if x != nil {
+		return x.Algorithm
+	}
+	return DigestAlgorithm_UndefinedAlgorithm
+}
+
+func (x *PieceDigests) GetPieceLength() uint64 {
+	if x != nil {
+		return x.PieceLength
+	}
+	return 0
+}
+
+func (x *PieceDigests) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type ImportMetalinkRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Token           string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Metalink        []byte                 `protobuf:"bytes,2,opt,name=metalink,proto3" json:"metalink,omitempty"`
+	DownloadQueueId uint64                 `protobuf:"varint,3,opt,name=download_queue_id,json=downloadQueueId,proto3" json:"download_queue_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ImportMetalinkRequest) Reset() {
+	*x = ImportMetalinkRequest{}
+	mi := &file_api_go_load_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportMetalinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportMetalinkRequest) ProtoMessage() {}
+
+func (x *ImportMetalinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportMetalinkRequest.ProtoReflect.Descriptor instead.
+func (*ImportMetalinkRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ImportMetalinkRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *ImportMetalinkRequest) GetMetalink() []byte {
+	if x != nil {
+		return x.Metalink
+	}
+	return nil
+}
+
+func (x *ImportMetalinkRequest) GetDownloadQueueId() uint64 {
+	if x != nil {
+		return x.DownloadQueueId
+	}
+	return 0
+}
+
+type ImportMetalinkResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	DownloadTaskList []*DownloadTask        `protobuf:"bytes,1,rep,name=download_task_list,json=downloadTaskList,proto3" json:"download_task_list,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ImportMetalinkResponse) Reset() {
+	*x = ImportMetalinkResponse{}
+	mi := &file_api_go_load_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportMetalinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportMetalinkResponse) ProtoMessage() {}
+
+func (x *ImportMetalinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportMetalinkResponse.ProtoReflect.Descriptor instead.
+func (*ImportMetalinkResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ImportMetalinkResponse) GetDownloadTaskList() []*DownloadTask {
+	if x != nil {
+		return x.DownloadTaskList
+	}
+	return nil
+}
+
+type ExportDownloadTaskMetalinkRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Token          string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	DownloadTaskId uint64                 `protobuf:"varint,2,opt,name=download_task_id,json=downloadTaskId,proto3" json:"download_task_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ExportDownloadTaskMetalinkRequest) Reset() {
+	*x = ExportDownloadTaskMetalinkRequest{}
+	mi := &file_api_go_load_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportDownloadTaskMetalinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportDownloadTaskMetalinkRequest) ProtoMessage() {}
+
+func (x *ExportDownloadTaskMetalinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportDownloadTaskMetalinkRequest.ProtoReflect.Descriptor instead.
+func (*ExportDownloadTaskMetalinkRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ExportDownloadTaskMetalinkRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *ExportDownloadTaskMetalinkRequest) GetDownloadTaskId() uint64 {
+	if x != nil {
+		return x.DownloadTaskId
+	}
+	return 0
+}
+
+type ExportDownloadTaskMetalinkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metalink      []byte                 `protobuf:"bytes,1,opt,name=metalink,proto3" json:"metalink,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportDownloadTaskMetalinkResponse) Reset() {
+	*x = ExportDownloadTaskMetalinkResponse{}
+	mi := &file_api_go_load_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportDownloadTaskMetalinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportDownloadTaskMetalinkResponse) ProtoMessage() {}
+
+func (x *ExportDownloadTaskMetalinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportDownloadTaskMetalinkResponse.ProtoReflect.Descriptor instead.
+func (*ExportDownloadTaskMetalinkResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ExportDownloadTaskMetalinkResponse) GetMetalink() []byte {
+	if x != nil {
+		return x.Metalink
+	}
+	return nil
+}
+
+type PostDownloadStepResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Succeeded     bool                   `protobuf:"varint,3,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PostDownloadStepResult) Reset() {
+	*x = PostDownloadStepResult{}
+	mi := &file_api_go_load_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PostDownloadStepResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostDownloadStepResult) ProtoMessage() {}
+
+func (x *PostDownloadStepResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostDownloadStepResult.ProtoReflect.Descriptor instead.
+func (*PostDownloadStepResult) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *PostDownloadStepResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PostDownloadStepResult) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PostDownloadStepResult) GetSucceeded() bool {
+	if x != nil {
+		return x.Succeeded
+	}
+	return false
+}
+
+func (x *PostDownloadStepResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ExtractedFile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FilePath      string                 `protobuf:"bytes,1,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
+	Size          uint64                 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtractedFile) Reset() {
+	*x = ExtractedFile{}
+	mi := &file_api_go_load_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtractedFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtractedFile) ProtoMessage() {}
+
+func (x *ExtractedFile) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtractedFile.ProtoReflect.Descriptor instead.
+func (*ExtractedFile) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ExtractedFile) GetFilePath() string {
+	if x != nil {
+		return x.FilePath
+	}
+	return ""
+}
+
+func (x *ExtractedFile) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type Webhook struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	EventTypes    []WebhookEventType     `protobuf:"varint,3,rep,packed,name=event_types,json=eventTypes,proto3,enum=go_load.WebhookEventType" json:"event_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	mi := &file_api_go_load_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Webhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Webhook) ProtoMessage() {}
+
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *Webhook) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Webhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Webhook) GetEventTypes() []WebhookEventType {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+type CreateWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	EventTypes    []WebhookEventType     `protobuf:"varint,3,rep,packed,name=event_types,json=eventTypes,proto3,enum=go_load.WebhookEventType" json:"event_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWebhookRequest) Reset() {
+	*x = CreateWebhookRequest{}
+	mi := &file_api_go_load_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWebhookRequest) ProtoMessage() {}
+
+func (x *CreateWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWebhookRequest.ProtoReflect.Descriptor instead.
+func (*CreateWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *CreateWebhookRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetEventTypes() []WebhookEventType {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+type CreateWebhookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhook       *Webhook               `protobuf:"bytes,1,opt,name=webhook,proto3" json:"webhook,omitempty"`
+	Secret        string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWebhookResponse) Reset() {
+	*x = CreateWebhookResponse{}
+	mi := &file_api_go_load_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWebhookResponse) ProtoMessage() {}
+
+func (x *CreateWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWebhookResponse.ProtoReflect.Descriptor instead.
+func (*CreateWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *CreateWebhookResponse) GetWebhook() *Webhook {
+	if x != nil {
+		return x.Webhook
+	}
+	return nil
+}
+
+func (x *CreateWebhookResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type GetWebhookListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWebhookListRequest) Reset() {
+	*x = GetWebhookListRequest{}
+	mi := &file_api_go_load_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWebhookListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWebhookListRequest) ProtoMessage() {}
+
+func (x *GetWebhookListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWebhookListRequest.ProtoReflect.Descriptor instead.
+func (*GetWebhookListRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *GetWebhookListRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type GetWebhookListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WebhookList   []*Webhook             `protobuf:"bytes,1,rep,name=webhook_list,json=webhookList,proto3" json:"webhook_list,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWebhookListResponse) Reset() {
+	*x = GetWebhookListResponse{}
+	mi := &file_api_go_load_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWebhookListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWebhookListResponse) ProtoMessage() {}
+
+func (x *GetWebhookListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWebhookListResponse.ProtoReflect.Descriptor instead.
+func (*GetWebhookListResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *GetWebhookListResponse) GetWebhookList() []*Webhook {
+	if x != nil {
+		return x.WebhookList
+	}
+	return nil
+}
+
+type DeleteWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	WebhookId     uint64                 `protobuf:"varint,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookRequest) Reset() {
+	*x = DeleteWebhookRequest{}
+	mi := &file_api_go_load_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookRequest) ProtoMessage() {}
+
+func (x *DeleteWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookRequest.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *DeleteWebhookRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *DeleteWebhookRequest) GetWebhookId() uint64 {
+	if x != nil {
+		return x.WebhookId
+	}
+	return 0
+}
+
+type DeleteWebhookResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	AccountName   string                 `protobuf:"bytes,2,opt,name=account_name,json=accountName,proto3" json:"account_name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Account) Reset() {
-	*x = Account{}
-	mi := &file_api_go_load_proto_msgTypes[0]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *DeleteWebhookResponse) Reset() {
+	*x = DeleteWebhookResponse{}
+	mi := &file_api_go_load_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookResponse) ProtoMessage() {}
+
+func (x *DeleteWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookResponse.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{56}
+}
+
+type WebhookDelivery struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	WebhookId       uint64                 `protobuf:"varint,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	EventType       WebhookEventType       `protobuf:"varint,3,opt,name=event_type,json=eventType,proto3,enum=go_load.WebhookEventType" json:"event_type,omitempty"`
+	DownloadTaskId  uint64                 `protobuf:"varint,4,opt,name=download_task_id,json=downloadTaskId,proto3" json:"download_task_id,omitempty"`
+	DeliveryStatus  WebhookDeliveryStatus  `protobuf:"varint,5,opt,name=delivery_status,json=deliveryStatus,proto3,enum=go_load.WebhookDeliveryStatus" json:"delivery_status,omitempty"`
+	AttemptCount    uint32                 `protobuf:"varint,6,opt,name=attempt_count,json=attemptCount,proto3" json:"attempt_count,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	NextAttemptAt   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"`
+	LastAttemptedAt *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=last_attempted_at,json=lastAttemptedAt,proto3" json:"last_attempted_at,omitempty"`
+	HttpStatusCode  uint32                 `protobuf:"varint,10,opt,name=http_status_code,json=httpStatusCode,proto3" json:"http_status_code,omitempty"`
+	ErrorMessage    string                 `protobuf:"bytes,11,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	Payload         string                 `protobuf:"bytes,12,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *WebhookDelivery) Reset() {
+	*x = WebhookDelivery{}
+	mi := &file_api_go_load_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDelivery) ProtoMessage() {}
+
+func (x *WebhookDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDelivery.ProtoReflect.Descriptor instead.
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *WebhookDelivery) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetWebhookId() uint64 {
+	if x != nil {
+		return x.WebhookId
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetEventType() WebhookEventType {
+	if x != nil {
+		return x.EventType
+	}
+	return WebhookEventType_UndefinedWebhookEventType
+}
+
+func (x *WebhookDelivery) GetDownloadTaskId() uint64 {
+	if x != nil {
+		return x.DownloadTaskId
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetDeliveryStatus() WebhookDeliveryStatus {
+	if x != nil {
+		return x.DeliveryStatus
+	}
+	return WebhookDeliveryStatus_UndefinedWebhookDeliveryStatus
 }
 
-func (x *Account) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *WebhookDelivery) GetAttemptCount() uint32 {
+	if x != nil {
+		return x.AttemptCount
+	}
+	return 0
 }
 
-func (*Account) ProtoMessage() {}
+func (x *WebhookDelivery) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
 
-func (x *Account) ProtoReflect() protoreflect.Message {
-	mi := &file_api_go_load_proto_msgTypes[0]
+func (x *WebhookDelivery) GetNextAttemptAt() *timestamppb.Timestamp {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.NextAttemptAt
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use Account.ProtoReflect.Descriptor instead.
-func (*Account) Descriptor() ([]byte, []int) {
-	return file_api_go_load_proto_rawDescGZIP(), []int{0}
+func (x *WebhookDelivery) GetLastAttemptedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastAttemptedAt
+	}
+	return nil
 }
 
-func (x *Account) GetId() uint64 {
+func (x *WebhookDelivery) GetHttpStatusCode() uint32 {
 	if x != nil {
-		return x.Id
+		return x.HttpStatusCode
 	}
 	return 0
 }
 
-func (x *Account) GetAccountName() string {
+func (x *WebhookDelivery) GetErrorMessage() string {
 	if x != nil {
-		return x.AccountName
+		return x.ErrorMessage
 	}
 	return ""
 }
 
-type DownloadTask struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Id             uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	OfAccount      *Account               `protobuf:"bytes,2,opt,name=of_account,json=ofAccount,proto3" json:"of_account,omitempty"`
-	DownloadType   DownloadType           `protobuf:"varint,3,opt,name=download_type,json=downloadType,proto3,enum=go_load.DownloadType" json:"download_type,omitempty"`
-	Url            string                 `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
-	DownloadStatus DownloadStatus         `protobuf:"varint,5,opt,name=download_status,json=downloadStatus,proto3,enum=go_load.DownloadStatus" json:"download_status,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *WebhookDelivery) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
 }
 
-func (x *DownloadTask) Reset() {
-	*x = DownloadTask{}
-	mi := &file_api_go_load_proto_msgTypes[1]
+type GetWebhookDeliveryListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	WebhookId     uint64                 `protobuf:"varint,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	Offset        uint64                 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit         uint64                 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWebhookDeliveryListRequest) Reset() {
+	*x = GetWebhookDeliveryListRequest{}
+	mi := &file_api_go_load_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DownloadTask) String() string {
+func (x *GetWebhookDeliveryListRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DownloadTask) ProtoMessage() {}
+func (*GetWebhookDeliveryListRequest) ProtoMessage() {}
 
-func (x *DownloadTask) ProtoReflect() protoreflect.Message {
-	mi := &file_api_go_load_proto_msgTypes[1]
+func (x *GetWebhookDeliveryListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -210,69 +4177,62 @@ func (x *DownloadTask) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DownloadTask.ProtoReflect.Descriptor instead.
-func (*DownloadTask) Descriptor() ([]byte, []int) {
-	return file_api_go_load_proto_rawDescGZIP(), []int{1}
-}
-
-func (x *DownloadTask) GetId() uint64 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
+// Deprecated: Use GetWebhookDeliveryListRequest.ProtoReflect.Descriptor instead.
+func (*GetWebhookDeliveryListRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{58}
 }
 
-func (x *DownloadTask) GetOfAccount() *Account {
+func (x *GetWebhookDeliveryListRequest) GetToken() string {
 	if x != nil {
-		return x.OfAccount
+		return x.Token
 	}
-	return nil
+	return ""
 }
 
-func (x *DownloadTask) GetDownloadType() DownloadType {
+func (x *GetWebhookDeliveryListRequest) GetWebhookId() uint64 {
 	if x != nil {
-		return x.DownloadType
+		return x.WebhookId
 	}
-	return DownloadType_UndefinedType
+	return 0
 }
 
-func (x *DownloadTask) GetUrl() string {
+func (x *GetWebhookDeliveryListRequest) GetOffset() uint64 {
 	if x != nil {
-		return x.Url
+		return x.Offset
 	}
-	return ""
+	return 0
 }
 
-func (x *DownloadTask) GetDownloadStatus() DownloadStatus {
+func (x *GetWebhookDeliveryListRequest) GetLimit() uint64 {
 	if x != nil {
-		return x.DownloadStatus
+		return x.Limit
 	}
-	return DownloadStatus_UndefinedStatus
+	return 0
 }
 
-type CreateAccountRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccountName   string                 `protobuf:"bytes,1,opt,name=account_name,json=accountName,proto3" json:"account_name,omitempty"`
-	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type GetWebhookDeliveryListResponse struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	WebhookDeliveryList       []*WebhookDelivery     `protobuf:"bytes,1,rep,name=webhook_delivery_list,json=webhookDeliveryList,proto3" json:"webhook_delivery_list,omitempty"`
+	TotalWebhookDeliveryCount uint64                 `protobuf:"varint,2,opt,name=total_webhook_delivery_count,json=totalWebhookDeliveryCount,proto3" json:"total_webhook_delivery_count,omitempty"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
 }
 
-func (x *CreateAccountRequest) Reset() {
-	*x = CreateAccountRequest{}
-	mi := &file_api_go_load_proto_msgTypes[2]
+func (x *GetWebhookDeliveryListResponse) Reset() {
+	*x = GetWebhookDeliveryListResponse{}
+	mi := &file_api_go_load_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateAccountRequest) String() string {
+func (x *GetWebhookDeliveryListResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateAccountRequest) ProtoMessage() {}
+func (*GetWebhookDeliveryListResponse) ProtoMessage() {}
 
-func (x *CreateAccountRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_go_load_proto_msgTypes[2]
+func (x *GetWebhookDeliveryListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -283,47 +4243,47 @@ func (x *CreateAccountRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateAccountRequest.ProtoReflect.Descriptor instead.
-func (*CreateAccountRequest) Descriptor() ([]byte, []int) {
-	return file_api_go_load_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use GetWebhookDeliveryListResponse.ProtoReflect.Descriptor instead.
+func (*GetWebhookDeliveryListResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{59}
 }
 
-func (x *CreateAccountRequest) GetAccountName() string {
+func (x *GetWebhookDeliveryListResponse) GetWebhookDeliveryList() []*WebhookDelivery {
 	if x != nil {
-		return x.AccountName
+		return x.WebhookDeliveryList
 	}
-	return ""
+	return nil
 }
 
-func (x *CreateAccountRequest) GetPassword() string {
+func (x *GetWebhookDeliveryListResponse) GetTotalWebhookDeliveryCount() uint64 {
 	if x != nil {
-		return x.Password
+		return x.TotalWebhookDeliveryCount
 	}
-	return ""
+	return 0
 }
 
-type CreateAccountResponse struct {
+type GetAccountStorageUsageRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccountId     uint64                 `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateAccountResponse) Reset() {
-	*x = CreateAccountResponse{}
-	mi := &file_api_go_load_proto_msgTypes[3]
+func (x *GetAccountStorageUsageRequest) Reset() {
+	*x = GetAccountStorageUsageRequest{}
+	mi := &file_api_go_load_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateAccountResponse) String() string {
+func (x *GetAccountStorageUsageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateAccountResponse) ProtoMessage() {}
+func (*GetAccountStorageUsageRequest) ProtoMessage() {}
 
-func (x *CreateAccountResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_go_load_proto_msgTypes[3]
+func (x *GetAccountStorageUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -334,41 +4294,41 @@ func (x *CreateAccountResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateAccountResponse.ProtoReflect.Descriptor instead.
-func (*CreateAccountResponse) Descriptor() ([]byte, []int) {
-	return file_api_go_load_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use GetAccountStorageUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetAccountStorageUsageRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{60}
 }
 
-func (x *CreateAccountResponse) GetAccountId() uint64 {
+func (x *GetAccountStorageUsageRequest) GetToken() string {
 	if x != nil {
-		return x.AccountId
+		return x.Token
 	}
-	return 0
+	return ""
 }
 
-type CreateSessionRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccountName   string                 `protobuf:"bytes,1,opt,name=account_name,json=accountName,proto3" json:"account_name,omitempty"`
-	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type GetAccountStorageUsageResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	LogicalByteCount  uint64                 `protobuf:"varint,1,opt,name=logical_byte_count,json=logicalByteCount,proto3" json:"logical_byte_count,omitempty"`
+	PhysicalByteCount uint64                 `protobuf:"varint,2,opt,name=physical_byte_count,json=physicalByteCount,proto3" json:"physical_byte_count,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
-func (x *CreateSessionRequest) Reset() {
-	*x = CreateSessionRequest{}
-	mi := &file_api_go_load_proto_msgTypes[4]
+func (x *GetAccountStorageUsageResponse) Reset() {
+	*x = GetAccountStorageUsageResponse{}
+	mi := &file_api_go_load_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateSessionRequest) String() string {
+func (x *GetAccountStorageUsageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateSessionRequest) ProtoMessage() {}
+func (*GetAccountStorageUsageResponse) ProtoMessage() {}
 
-func (x *CreateSessionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_go_load_proto_msgTypes[4]
+func (x *GetAccountStorageUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -379,48 +4339,50 @@ func (x *CreateSessionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateSessionRequest.ProtoReflect.Descriptor instead.
-func (*CreateSessionRequest) Descriptor() ([]byte, []int) {
-	return file_api_go_load_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use GetAccountStorageUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetAccountStorageUsageResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{61}
 }
 
-func (x *CreateSessionRequest) GetAccountName() string {
+func (x *GetAccountStorageUsageResponse) GetLogicalByteCount() uint64 {
 	if x != nil {
-		return x.AccountName
+		return x.LogicalByteCount
 	}
-	return ""
+	return 0
 }
 
-func (x *CreateSessionRequest) GetPassword() string {
+func (x *GetAccountStorageUsageResponse) GetPhysicalByteCount() uint64 {
 	if x != nil {
-		return x.Password
+		return x.PhysicalByteCount
 	}
-	return ""
+	return 0
 }
 
-type CreateSessionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Account       *Account               `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
-	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type DuplicateDownload struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	DownloadTaskId uint64                 `protobuf:"varint,1,opt,name=download_task_id,json=downloadTaskId,proto3" json:"download_task_id,omitempty"`
+	FileName       string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	Size           uint64                 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Sha256         string                 `protobuf:"bytes,4,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *CreateSessionResponse) Reset() {
-	*x = CreateSessionResponse{}
-	mi := &file_api_go_load_proto_msgTypes[5]
+func (x *DuplicateDownload) Reset() {
+	*x = DuplicateDownload{}
+	mi := &file_api_go_load_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateSessionResponse) String() string {
+func (x *DuplicateDownload) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateSessionResponse) ProtoMessage() {}
+func (*DuplicateDownload) ProtoMessage() {}
 
-func (x *CreateSessionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_go_load_proto_msgTypes[5]
+func (x *DuplicateDownload) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -431,49 +4393,62 @@ func (x *CreateSessionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateSessionResponse.ProtoReflect.Descriptor instead.
-func (*CreateSessionResponse) Descriptor() ([]byte, []int) {
-	return file_api_go_load_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use DuplicateDownload.ProtoReflect.Descriptor instead.
+func (*DuplicateDownload) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{62}
 }
 
-func (x *CreateSessionResponse) GetAccount() *Account {
+func (x *DuplicateDownload) GetDownloadTaskId() uint64 {
 	if x != nil {
-		return x.Account
+		return x.DownloadTaskId
 	}
-	return nil
+	return 0
 }
 
-func (x *CreateSessionResponse) GetToken() string {
+func (x *DuplicateDownload) GetFileName() string {
 	if x != nil {
-		return x.Token
+		return x.FileName
 	}
 	return ""
 }
 
-type CreateDownloadTaskRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
-	DownloadType  DownloadType           `protobuf:"varint,2,opt,name=download_type,json=downloadType,proto3,enum=go_load.DownloadType" json:"download_type,omitempty"`
-	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *DuplicateDownload) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
 }
 
-func (x *CreateDownloadTaskRequest) Reset() {
-	*x = CreateDownloadTaskRequest{}
-	mi := &file_api_go_load_proto_msgTypes[6]
+func (x *DuplicateDownload) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+type WatchOptions struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	CheckIntervalSec uint32                 `protobuf:"varint,1,opt,name=check_interval_sec,json=checkIntervalSec,proto3" json:"check_interval_sec,omitempty"`
+	RetentionCount   uint32                 `protobuf:"varint,2,opt,name=retention_count,json=retentionCount,proto3" json:"retention_count,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *WatchOptions) Reset() {
+	*x = WatchOptions{}
+	mi := &file_api_go_load_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateDownloadTaskRequest) String() string {
+func (x *WatchOptions) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateDownloadTaskRequest) ProtoMessage() {}
+func (*WatchOptions) ProtoMessage() {}
 
-func (x *CreateDownloadTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_go_load_proto_msgTypes[6]
+func (x *WatchOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -484,54 +4459,52 @@ func (x *CreateDownloadTaskRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateDownloadTaskRequest.ProtoReflect.Descriptor instead.
-func (*CreateDownloadTaskRequest) Descriptor() ([]byte, []int) {
-	return file_api_go_load_proto_rawDescGZIP(), []int{6}
-}
-
-func (x *CreateDownloadTaskRequest) GetToken() string {
-	if x != nil {
-		return x.Token
-	}
-	return ""
+// Deprecated: Use WatchOptions.ProtoReflect.Descriptor instead.
+func (*WatchOptions) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{63}
 }
 
-func (x *CreateDownloadTaskRequest) GetDownloadType() DownloadType {
+func (x *WatchOptions) GetCheckIntervalSec() uint32 {
 	if x != nil {
-		return x.DownloadType
+		return x.CheckIntervalSec
 	}
-	return DownloadType_UndefinedType
+	return 0
 }
 
-func (x *CreateDownloadTaskRequest) GetUrl() string {
+func (x *WatchOptions) GetRetentionCount() uint32 {
 	if x != nil {
-		return x.Url
+		return x.RetentionCount
 	}
-	return ""
+	return 0
 }
 
-type CreateDownloadTaskResponse struct {
+type DownloadTaskVersion struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DownloadTask  *DownloadTask          `protobuf:"bytes,1,opt,name=download_task,json=downloadTask,proto3" json:"download_task,omitempty"`
+	VersionNumber uint32                 `protobuf:"varint,1,opt,name=version_number,json=versionNumber,proto3" json:"version_number,omitempty"`
+	Size          uint64                 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Sha256        string                 `protobuf:"bytes,3,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	Etag          string                 `protobuf:"bytes,4,opt,name=etag,proto3" json:"etag,omitempty"`
+	LastModified  string                 `protobuf:"bytes,5,opt,name=last_modified,json=lastModified,proto3" json:"last_modified,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateDownloadTaskResponse) Reset() {
-	*x = CreateDownloadTaskResponse{}
-	mi := &file_api_go_load_proto_msgTypes[7]
+func (x *DownloadTaskVersion) Reset() {
+	*x = DownloadTaskVersion{}
+	mi := &file_api_go_load_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateDownloadTaskResponse) String() string {
+func (x *DownloadTaskVersion) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateDownloadTaskResponse) ProtoMessage() {}
+func (*DownloadTaskVersion) ProtoMessage() {}
 
-func (x *CreateDownloadTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_go_load_proto_msgTypes[7]
+func (x *DownloadTaskVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -542,42 +4515,76 @@ func (x *CreateDownloadTaskResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateDownloadTaskResponse.ProtoReflect.Descriptor instead.
-func (*CreateDownloadTaskResponse) Descriptor() ([]byte, []int) {
-	return file_api_go_load_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use DownloadTaskVersion.ProtoReflect.Descriptor instead.
+func (*DownloadTaskVersion) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{64}
 }
 
-func (x *CreateDownloadTaskResponse) GetDownloadTask() *DownloadTask {
+func (x *DownloadTaskVersion) GetVersionNumber() uint32 {
+	if x != nil {
+		return x.VersionNumber
+	}
+	return 0
+}
+
+func (x *DownloadTaskVersion) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DownloadTaskVersion) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *DownloadTaskVersion) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+func (x *DownloadTaskVersion) GetLastModified() string {
+	if x != nil {
+		return x.LastModified
+	}
+	return ""
+}
+
+func (x *DownloadTaskVersion) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.DownloadTask
+		return x.CreatedAt
 	}
 	return nil
 }
 
-type GetDownloadTaskListRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
-	Offset        uint64                 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
-	Limit         uint64                 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type GetDownloadTaskVersionListRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Token          string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	DownloadTaskId uint64                 `protobuf:"varint,2,opt,name=download_task_id,json=downloadTaskId,proto3" json:"download_task_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *GetDownloadTaskListRequest) Reset() {
-	*x = GetDownloadTaskListRequest{}
-	mi := &file_api_go_load_proto_msgTypes[8]
+func (x *GetDownloadTaskVersionListRequest) Reset() {
+	*x = GetDownloadTaskVersionListRequest{}
+	mi := &file_api_go_load_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDownloadTaskListRequest) String() string {
+func (x *GetDownloadTaskVersionListRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDownloadTaskListRequest) ProtoMessage() {}
+func (*GetDownloadTaskVersionListRequest) ProtoMessage() {}
 
-func (x *GetDownloadTaskListRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_go_load_proto_msgTypes[8]
+func (x *GetDownloadTaskVersionListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -588,55 +4595,47 @@ func (x *GetDownloadTaskListRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDownloadTaskListRequest.ProtoReflect.Descriptor instead.
-func (*GetDownloadTaskListRequest) Descriptor() ([]byte, []int) {
-	return file_api_go_load_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use GetDownloadTaskVersionListRequest.ProtoReflect.Descriptor instead.
+func (*GetDownloadTaskVersionListRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{65}
 }
 
-func (x *GetDownloadTaskListRequest) GetToken() string {
+func (x *GetDownloadTaskVersionListRequest) GetToken() string {
 	if x != nil {
 		return x.Token
 	}
 	return ""
 }
 
-func (x *GetDownloadTaskListRequest) GetOffset() uint64 {
-	if x != nil {
-		return x.Offset
-	}
-	return 0
-}
-
-func (x *GetDownloadTaskListRequest) GetLimit() uint64 {
+func (x *GetDownloadTaskVersionListRequest) GetDownloadTaskId() uint64 {
 	if x != nil {
-		return x.Limit
+		return x.DownloadTaskId
 	}
 	return 0
 }
 
-type GetDownloadTaskListResponse struct {
-	state                  protoimpl.MessageState `protogen:"open.v1"`
-	DownloadTaskList       []*DownloadTask        `protobuf:"bytes,1,rep,name=download_task_list,json=downloadTaskList,proto3" json:"download_task_list,omitempty"`
-	TotalDownloadTaskCount uint64                 `protobuf:"varint,2,opt,name=total_download_task_count,json=totalDownloadTaskCount,proto3" json:"total_download_task_count,omitempty"`
-	unknownFields          protoimpl.UnknownFields
-	sizeCache              protoimpl.SizeCache
+type GetDownloadTaskVersionListResponse struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	DownloadTaskVersionList []*DownloadTaskVersion `protobuf:"bytes,1,rep,name=download_task_version_list,json=downloadTaskVersionList,proto3" json:"download_task_version_list,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
 }
 
-func (x *GetDownloadTaskListResponse) Reset() {
-	*x = GetDownloadTaskListResponse{}
-	mi := &file_api_go_load_proto_msgTypes[9]
+func (x *GetDownloadTaskVersionListResponse) Reset() {
+	*x = GetDownloadTaskVersionListResponse{}
+	mi := &file_api_go_load_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDownloadTaskListResponse) String() string {
+func (x *GetDownloadTaskVersionListResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDownloadTaskListResponse) ProtoMessage() {}
+func (*GetDownloadTaskVersionListResponse) ProtoMessage() {}
 
-func (x *GetDownloadTaskListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_go_load_proto_msgTypes[9]
+func (x *GetDownloadTaskVersionListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[66]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -647,49 +4646,45 @@ func (x *GetDownloadTaskListResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDownloadTaskListResponse.ProtoReflect.Descriptor instead.
-func (*GetDownloadTaskListResponse) Descriptor() ([]byte, []int) {
-	return file_api_go_load_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use GetDownloadTaskVersionListResponse.ProtoReflect.Descriptor instead.
+func (*GetDownloadTaskVersionListResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{66}
 }
 
-func (x *GetDownloadTaskListResponse) GetDownloadTaskList() []*DownloadTask {
+func (x *GetDownloadTaskVersionListResponse) GetDownloadTaskVersionList() []*DownloadTaskVersion {
 	if x != nil {
-		return x.DownloadTaskList
+		return x.DownloadTaskVersionList
 	}
 	return nil
 }
 
-func (x *GetDownloadTaskListResponse) GetTotalDownloadTaskCount() uint64 {
-	if x != nil {
-		return x.TotalDownloadTaskCount
-	}
-	return 0
-}
-
-type UpdateDownloadTaskRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Token          string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
-	DownloadTaskId uint64                 `protobuf:"varint,2,opt,name=download_task_id,json=downloadTaskId,proto3" json:"download_task_id,omitempty"`
-	Url            string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+type CrawlOptions struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	MaxDepth        uint32                 `protobuf:"varint,1,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`
+	AllowedDomains  []string               `protobuf:"bytes,2,rep,name=allowed_domains,json=allowedDomains,proto3" json:"allowed_domains,omitempty"`
+	PathPrefix      string                 `protobuf:"bytes,3,opt,name=path_prefix,json=pathPrefix,proto3" json:"path_prefix,omitempty"`
+	IncludePatterns []string               `protobuf:"bytes,4,rep,name=include_patterns,json=includePatterns,proto3" json:"include_patterns,omitempty"`
+	ExcludePatterns []string               `protobuf:"bytes,5,rep,name=exclude_patterns,json=excludePatterns,proto3" json:"exclude_patterns,omitempty"`
+	RewriteLinks    bool                   `protobuf:"varint,6,opt,name=rewrite_links,json=rewriteLinks,proto3" json:"rewrite_links,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *UpdateDownloadTaskRequest) Reset() {
-	*x = UpdateDownloadTaskRequest{}
-	mi := &file_api_go_load_proto_msgTypes[10]
+func (x *CrawlOptions) Reset() {
+	*x = CrawlOptions{}
+	mi := &file_api_go_load_proto_msgTypes[67]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateDownloadTaskRequest) String() string {
+func (x *CrawlOptions) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateDownloadTaskRequest) ProtoMessage() {}
+func (*CrawlOptions) ProtoMessage() {}
 
-func (x *UpdateDownloadTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_go_load_proto_msgTypes[10]
+func (x *CrawlOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[67]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -700,54 +4695,80 @@ func (x *UpdateDownloadTaskRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateDownloadTaskRequest.ProtoReflect.Descriptor instead.
-func (*UpdateDownloadTaskRequest) Descriptor() ([]byte, []int) {
-	return file_api_go_load_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use CrawlOptions.ProtoReflect.Descriptor instead.
+func (*CrawlOptions) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{67}
 }
 
-func (x *UpdateDownloadTaskRequest) GetToken() string {
+func (x *CrawlOptions) GetMaxDepth() uint32 {
 	if x != nil {
-		return x.Token
+		return x.MaxDepth
 	}
-	return ""
+	return 0
 }
 
-func (x *UpdateDownloadTaskRequest) GetDownloadTaskId() uint64 {
+func (x *CrawlOptions) GetAllowedDomains() []string {
 	if x != nil {
-		return x.DownloadTaskId
+		return x.AllowedDomains
 	}
-	return 0
+	return nil
 }
 
-func (x *UpdateDownloadTaskRequest) GetUrl() string {
+func (x *CrawlOptions) GetPathPrefix() string {
 	if x != nil {
-		return x.Url
+		return x.PathPrefix
 	}
 	return ""
 }
 
-type UpdateDownloadTaskResponse struct {
+func (x *CrawlOptions) GetIncludePatterns() []string {
+	if x != nil {
+		return x.IncludePatterns
+	}
+	return nil
+}
+
+func (x *CrawlOptions) GetExcludePatterns() []string {
+	if x != nil {
+		return x.ExcludePatterns
+	}
+	return nil
+}
+
+func (x *CrawlOptions) GetRewriteLinks() bool {
+	if x != nil {
+		return x.RewriteLinks
+	}
+	return false
+}
+
+type GrabbedLink struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DownloadTask  *DownloadTask          `protobuf:"bytes,1,opt,name=download_task,json=downloadTask,proto3" json:"download_task,omitempty"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	DownloadType  DownloadType           `protobuf:"varint,2,opt,name=download_type,json=downloadType,proto3,enum=go_load.DownloadType" json:"download_type,omitempty"`
+	FileName      string                 `protobuf:"bytes,3,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	Size          uint64                 `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	ContentType   string                 `protobuf:"bytes,5,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	FileCategory  FileCategory           `protobuf:"varint,6,opt,name=file_category,json=fileCategory,proto3,enum=go_load.FileCategory" json:"file_category,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateDownloadTaskResponse) Reset() {
-	*x = UpdateDownloadTaskResponse{}
-	mi := &file_api_go_load_proto_msgTypes[11]
+func (x *GrabbedLink) Reset() {
+	*x = GrabbedLink{}
+	mi := &file_api_go_load_proto_msgTypes[68]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateDownloadTaskResponse) String() string {
+func (x *GrabbedLink) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateDownloadTaskResponse) ProtoMessage() {}
+func (*GrabbedLink) ProtoMessage() {}
 
-func (x *UpdateDownloadTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_go_load_proto_msgTypes[11]
+func (x *GrabbedLink) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[68]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -758,41 +4779,79 @@ func (x *UpdateDownloadTaskResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateDownloadTaskResponse.ProtoReflect.Descriptor instead.
-func (*UpdateDownloadTaskResponse) Descriptor() ([]byte, []int) {
-	return file_api_go_load_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use GrabbedLink.ProtoReflect.Descriptor instead.
+func (*GrabbedLink) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{68}
 }
 
-func (x *UpdateDownloadTaskResponse) GetDownloadTask() *DownloadTask {
+func (x *GrabbedLink) GetUrl() string {
 	if x != nil {
-		return x.DownloadTask
+		return x.Url
 	}
-	return nil
+	return ""
 }
 
-type DeleteDownloadTaskRequest struct {
+func (x *GrabbedLink) GetDownloadType() DownloadType {
+	if x != nil {
+		return x.DownloadType
+	}
+	return DownloadType_UndefinedType
+}
+
+func (x *GrabbedLink) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *GrabbedLink) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *GrabbedLink) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *GrabbedLink) GetFileCategory() FileCategory {
+	if x != nil {
+		return x.FileCategory
+	}
+	return FileCategory_UndefinedFileCategory
+}
+
+type GrabLinksRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
-	DownloadTask  *DownloadTask          `protobuf:"bytes,2,opt,name=download_task,json=downloadTask,proto3" json:"download_task,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	Extensions    []string               `protobuf:"bytes,4,rep,name=extensions,proto3" json:"extensions,omitempty"`
+	Pattern       string                 `protobuf:"bytes,5,opt,name=pattern,proto3" json:"pattern,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteDownloadTaskRequest) Reset() {
-	*x = DeleteDownloadTaskRequest{}
-	mi := &file_api_go_load_proto_msgTypes[12]
+func (x *GrabLinksRequest) Reset() {
+	*x = GrabLinksRequest{}
+	mi := &file_api_go_load_proto_msgTypes[69]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteDownloadTaskRequest) String() string {
+func (x *GrabLinksRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteDownloadTaskRequest) ProtoMessage() {}
+func (*GrabLinksRequest) ProtoMessage() {}
 
-func (x *DeleteDownloadTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_go_load_proto_msgTypes[12]
+func (x *GrabLinksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[69]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -803,46 +4862,68 @@ func (x *DeleteDownloadTaskRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteDownloadTaskRequest.ProtoReflect.Descriptor instead.
-func (*DeleteDownloadTaskRequest) Descriptor() ([]byte, []int) {
-	return file_api_go_load_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use GrabLinksRequest.ProtoReflect.Descriptor instead.
+func (*GrabLinksRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{69}
 }
 
-func (x *DeleteDownloadTaskRequest) GetToken() string {
+func (x *GrabLinksRequest) GetToken() string {
 	if x != nil {
 		return x.Token
 	}
 	return ""
 }
 
-func (x *DeleteDownloadTaskRequest) GetDownloadTask() *DownloadTask {
+func (x *GrabLinksRequest) GetUrl() string {
 	if x != nil {
-		return x.DownloadTask
+		return x.Url
+	}
+	return ""
+}
+
+func (x *GrabLinksRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *GrabLinksRequest) GetExtensions() []string {
+	if x != nil {
+		return x.Extensions
 	}
 	return nil
 }
 
-type DeleteDownloadTaskResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *GrabLinksRequest) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
 }
 
-func (x *DeleteDownloadTaskResponse) Reset() {
-	*x = DeleteDownloadTaskResponse{}
-	mi := &file_api_go_load_proto_msgTypes[13]
+type GrabLinksResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	GrabbedLinkList []*GrabbedLink         `protobuf:"bytes,1,rep,name=grabbed_link_list,json=grabbedLinkList,proto3" json:"grabbed_link_list,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GrabLinksResponse) Reset() {
+	*x = GrabLinksResponse{}
+	mi := &file_api_go_load_proto_msgTypes[70]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteDownloadTaskResponse) String() string {
+func (x *GrabLinksResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteDownloadTaskResponse) ProtoMessage() {}
+func (*GrabLinksResponse) ProtoMessage() {}
 
-func (x *DeleteDownloadTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_go_load_proto_msgTypes[13]
+func (x *GrabLinksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[70]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -853,34 +4934,42 @@ func (x *DeleteDownloadTaskResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteDownloadTaskResponse.ProtoReflect.Descriptor instead.
-func (*DeleteDownloadTaskResponse) Descriptor() ([]byte, []int) {
-	return file_api_go_load_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use GrabLinksResponse.ProtoReflect.Descriptor instead.
+func (*GrabLinksResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{70}
 }
 
-type GetDownloadTaskFileRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Token          string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
-	DownloadTaskId uint64                 `protobuf:"varint,2,opt,name=download_task_id,json=downloadTaskId,proto3" json:"download_task_id,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *GrabLinksResponse) GetGrabbedLinkList() []*GrabbedLink {
+	if x != nil {
+		return x.GrabbedLinkList
+	}
+	return nil
 }
 
-func (x *GetDownloadTaskFileRequest) Reset() {
-	*x = GetDownloadTaskFileRequest{}
-	mi := &file_api_go_load_proto_msgTypes[14]
+type CreateDownloadTasksFromLinksRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Token           string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Urls            []string               `protobuf:"bytes,2,rep,name=urls,proto3" json:"urls,omitempty"`
+	DownloadQueueId uint64                 `protobuf:"varint,3,opt,name=download_queue_id,json=downloadQueueId,proto3" json:"download_queue_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CreateDownloadTasksFromLinksRequest) Reset() {
+	*x = CreateDownloadTasksFromLinksRequest{}
+	mi := &file_api_go_load_proto_msgTypes[71]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDownloadTaskFileRequest) String() string {
+func (x *CreateDownloadTasksFromLinksRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDownloadTaskFileRequest) ProtoMessage() {}
+func (*CreateDownloadTasksFromLinksRequest) ProtoMessage() {}
 
-func (x *GetDownloadTaskFileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_go_load_proto_msgTypes[14]
+func (x *CreateDownloadTasksFromLinksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[71]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -891,47 +4980,54 @@ func (x *GetDownloadTaskFileRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDownloadTaskFileRequest.ProtoReflect.Descriptor instead.
-func (*GetDownloadTaskFileRequest) Descriptor() ([]byte, []int) {
-	return file_api_go_load_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use CreateDownloadTasksFromLinksRequest.ProtoReflect.Descriptor instead.
+func (*CreateDownloadTasksFromLinksRequest) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{71}
 }
 
-func (x *GetDownloadTaskFileRequest) GetToken() string {
+func (x *CreateDownloadTasksFromLinksRequest) GetToken() string {
 	if x != nil {
 		return x.Token
 	}
 	return ""
 }
 
-func (x *GetDownloadTaskFileRequest) GetDownloadTaskId() uint64 {
+func (x *CreateDownloadTasksFromLinksRequest) GetUrls() []string {
 	if x != nil {
-		return x.DownloadTaskId
+		return x.Urls
+	}
+	return nil
+}
+
+func (x *CreateDownloadTasksFromLinksRequest) GetDownloadQueueId() uint64 {
+	if x != nil {
+		return x.DownloadQueueId
 	}
 	return 0
 }
 
-type GetDownloadTaskFileResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type CreateDownloadTasksFromLinksResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	DownloadTaskList []*DownloadTask        `protobuf:"bytes,1,rep,name=download_task_list,json=downloadTaskList,proto3" json:"download_task_list,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *GetDownloadTaskFileResponse) Reset() {
-	*x = GetDownloadTaskFileResponse{}
-	mi := &file_api_go_load_proto_msgTypes[15]
+func (x *CreateDownloadTasksFromLinksResponse) Reset() {
+	*x = CreateDownloadTasksFromLinksResponse{}
+	mi := &file_api_go_load_proto_msgTypes[72]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDownloadTaskFileResponse) String() string {
+func (x *CreateDownloadTasksFromLinksResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDownloadTaskFileResponse) ProtoMessage() {}
+func (*CreateDownloadTasksFromLinksResponse) ProtoMessage() {}
 
-func (x *GetDownloadTaskFileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_go_load_proto_msgTypes[15]
+func (x *CreateDownloadTasksFromLinksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_go_load_proto_msgTypes[72]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -942,14 +5038,14 @@ func (x *GetDownloadTaskFileResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDownloadTaskFileResponse.ProtoReflect.Descriptor instead.
-func (*GetDownloadTaskFileResponse) Descriptor() ([]byte, []int) {
-	return file_api_go_load_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use CreateDownloadTasksFromLinksResponse.ProtoReflect.Descriptor instead.
+func (*CreateDownloadTasksFromLinksResponse) Descriptor() ([]byte, []int) {
+	return file_api_go_load_proto_rawDescGZIP(), []int{72}
 }
 
-func (x *GetDownloadTaskFileResponse) GetData() []byte {
+func (x *CreateDownloadTasksFromLinksResponse) GetDownloadTaskList() []*DownloadTask {
 	if x != nil {
-		return x.Data
+		return x.DownloadTaskList
 	}
 	return nil
 }
@@ -958,17 +5054,48 @@ var File_api_go_load_proto protoreflect.FileDescriptor
 
 const file_api_go_load_proto_rawDesc = "" +
 	"\n" +
-	"\x11api/go_load.proto\x12\ago_load\"<\n" +
+	"\x11api/go_load.proto\x12\ago_load\x1a\x1fgoogle/protobuf/timestamp.proto\"v\n" +
 	"\aAccount\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x04R\x02id\x12!\n" +
-	"\faccount_name\x18\x02 \x01(\tR\vaccountName\"\xdf\x01\n" +
+	"\faccount_name\x18\x02 \x01(\tR\vaccountName\x128\n" +
+	"\x19speed_limit_bytes_per_sec\x18\x03 \x01(\x04R\x15speedLimitBytesPerSec\"\x80\v\n" +
 	"\fDownloadTask\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x04R\x02id\x12/\n" +
 	"\n" +
 	"of_account\x18\x02 \x01(\v2\x10.go_load.AccountR\tofAccount\x12:\n" +
 	"\rdownload_type\x18\x03 \x01(\x0e2\x15.go_load.DownloadTypeR\fdownloadType\x12\x10\n" +
 	"\x03url\x18\x04 \x01(\tR\x03url\x12@\n" +
-	"\x0fdownload_status\x18\x05 \x01(\x0e2\x17.go_load.DownloadStatusR\x0edownloadStatus\"U\n" +
+	"\x0fdownload_status\x18\x05 \x01(\x0e2\x17.go_load.DownloadStatusR\x0edownloadStatus\x122\n" +
+	"\x15downloaded_byte_count\x18\x06 \x01(\x04R\x13downloadedByteCount\x12(\n" +
+	"\x10total_byte_count\x18\a \x01(\x04R\x0etotalByteCount\x128\n" +
+	"\x18downloaded_segment_count\x18\b \x01(\rR\x16downloadedSegmentCount\x12.\n" +
+	"\x13total_segment_count\x18\t \x01(\rR\x11totalSegmentCount\x12\x1d\n" +
+	"\n" +
+	"file_paths\x18\n" +
+	" \x03(\tR\tfilePaths\x12\x16\n" +
+	"\x06sha256\x18\v \x01(\tR\x06sha256\x12%\n" +
+	"\x0efailure_reason\x18\f \x01(\tR\rfailureReason\x128\n" +
+	"\x19speed_limit_bytes_per_sec\x18\r \x01(\x04R\x15speedLimitBytesPerSec\x12*\n" +
+	"\x11download_queue_id\x18\x0e \x01(\x04R\x0fdownloadQueueId\x12:\n" +
+	"\vnext_run_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\tnextRunAt\x12'\n" +
+	"\x0fcron_expression\x18\x10 \x01(\tR\x0ecronExpression\x12\x1b\n" +
+	"\ttime_zone\x18\x11 \x01(\tR\btimeZone\x12#\n" +
+	"\rattempt_count\x18\x12 \x01(\rR\fattemptCount\x12*\n" +
+	"\x11max_attempt_count\x18\x13 \x01(\rR\x0fmaxAttemptCount\x12>\n" +
+	"\rnext_retry_at\x18\x14 \x01(\v2\x1a.google.protobuf.TimestampR\vnextRetryAt\x12\x1f\n" +
+	"\vmirror_urls\x18\x15 \x03(\tR\n" +
+	"mirrorUrls\x12\\\n" +
+	"\x1apost_download_step_results\x18\x16 \x03(\v2\x1f.go_load.PostDownloadStepResultR\x17postDownloadStepResults\x12'\n" +
+	"\x0fextract_archive\x18\x17 \x01(\bR\x0eextractArchive\x12?\n" +
+	"\x0fextracted_files\x18\x18 \x03(\v2\x16.go_load.ExtractedFileR\x0eextractedFiles\x128\n" +
+	"\x18reused_existing_download\x18\x19 \x01(\bR\x16reusedExistingDownload\x12:\n" +
+	"\rwatch_options\x18\x1a \x01(\v2\x15.go_load.WatchOptionsR\fwatchOptions\x12%\n" +
+	"\x0eversion_number\x18\x1b \x01(\rR\rversionNumber\x12>\n" +
+	"\rnext_check_at\x18\x1c \x01(\v2\x1a.google.protobuf.TimestampR\vnextCheckAt\x12:\n" +
+	"\rcrawl_options\x18\x1d \x01(\v2\x15.go_load.CrawlOptionsR\fcrawlOptions\"V\n" +
+	"\x06Digest\x126\n" +
+	"\talgorithm\x18\x01 \x01(\x0e2\x18.go_load.DigestAlgorithmR\talgorithm\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"U\n" +
 	"\x14CreateAccountRequest\x12!\n" +
 	"\faccount_name\x18\x01 \x01(\tR\vaccountName\x12\x1a\n" +
 	"\bpassword\x18\x02 \x01(\tR\bpassword\"6\n" +
@@ -980,45 +5107,359 @@ const file_api_go_load_proto_rawDesc = "" +
 	"\bpassword\x18\x02 \x01(\tR\bpassword\"Y\n" +
 	"\x15CreateSessionResponse\x12*\n" +
 	"\aaccount\x18\x01 \x01(\v2\x10.go_load.AccountR\aaccount\x12\x14\n" +
-	"\x05token\x18\x02 \x01(\tR\x05token\"\x7f\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\"\x9b\x01\n" +
+	"\vSFTPOptions\x12\x1a\n" +
+	"\bpassword\x18\x01 \x01(\tR\bpassword\x12\x1f\n" +
+	"\vprivate_key\x18\x02 \x01(\tR\n" +
+	"privateKey\x124\n" +
+	"\x16private_key_passphrase\x18\x03 \x01(\tR\x14privateKeyPassphrase\x12\x19\n" +
+	"\bhost_key\x18\x04 \x01(\tR\ahostKey\"-\n" +
+	"\rStreamOptions\x12\x1c\n" +
+	"\tbandwidth\x18\x01 \x01(\x04R\tbandwidth\"M\n" +
+	"\x0eTorrentOptions\x12\x18\n" +
+	"\atorrent\x18\x01 \x01(\fR\atorrent\x12!\n" +
+	"\ffile_indexes\x18\x02 \x03(\rR\vfileIndexes\"\xce\x01\n" +
+	"\tS3Options\x12\x18\n" +
+	"\aprofile\x18\x01 \x01(\tR\aprofile\x12\x1a\n" +
+	"\bendpoint\x18\x02 \x01(\tR\bendpoint\x12\x16\n" +
+	"\x06region\x18\x03 \x01(\tR\x06region\x12\"\n" +
+	"\raccess_key_id\x18\x04 \x01(\tR\vaccessKeyId\x12*\n" +
+	"\x11secret_access_key\x18\x05 \x01(\tR\x0fsecretAccessKey\x12#\n" +
+	"\rsession_token\x18\x06 \x01(\tR\fsessionToken\"\xf0\b\n" +
 	"\x19CreateDownloadTaskRequest\x12\x14\n" +
 	"\x05token\x18\x01 \x01(\tR\x05token\x12:\n" +
 	"\rdownload_type\x18\x02 \x01(\x0e2\x15.go_load.DownloadTypeR\fdownloadType\x12\x10\n" +
-	"\x03url\x18\x03 \x01(\tR\x03url\"X\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12#\n" +
+	"\rsegment_count\x18\x04 \x01(\rR\fsegmentCount\x12(\n" +
+	"\x10min_segment_size\x18\x05 \x01(\x04R\x0eminSegmentSize\x127\n" +
+	"\fsftp_options\x18\x06 \x01(\v2\x14.go_load.SFTPOptionsR\vsftpOptions\x12=\n" +
+	"\x0estream_options\x18\a \x01(\v2\x16.go_load.StreamOptionsR\rstreamOptions\x12@\n" +
+	"\x0ftorrent_options\x18\b \x01(\v2\x17.go_load.TorrentOptionsR\x0etorrentOptions\x121\n" +
+	"\n" +
+	"s3_options\x18\t \x01(\v2\x12.go_load.S3OptionsR\ts3Options\x128\n" +
+	"\x0fexpected_digest\x18\n" +
+	" \x01(\v2\x0f.go_load.DigestR\x0eexpectedDigest\x128\n" +
+	"\x19speed_limit_bytes_per_sec\x18\v \x01(\x04R\x15speedLimitBytesPerSec\x12*\n" +
+	"\x11download_queue_id\x18\f \x01(\x04R\x0fdownloadQueueId\x125\n" +
+	"\bstart_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\astartAt\x12'\n" +
+	"\x0fcron_expression\x18\x0e \x01(\tR\x0ecronExpression\x12\x1b\n" +
+	"\ttime_zone\x18\x0f \x01(\tR\btimeZone\x12*\n" +
+	"\x11max_attempt_count\x18\x10 \x01(\rR\x0fmaxAttemptCount\x12\x1f\n" +
+	"\vmirror_urls\x18\x11 \x03(\tR\n" +
+	"mirrorUrls\x12#\n" +
+	"\rexpected_size\x18\x12 \x01(\x04R\fexpectedSize\x12:\n" +
+	"\rpiece_digests\x18\x13 \x01(\v2\x15.go_load.PieceDigestsR\fpieceDigests\x12'\n" +
+	"\x0fextract_archive\x18\x14 \x01(\bR\x0eextractArchive\x12G\n" +
+	"\x12duplicate_url_mode\x18\x15 \x01(\x0e2\x19.go_load.DuplicateURLModeR\x10duplicateUrlMode\x12:\n" +
+	"\rwatch_options\x18\x16 \x01(\v2\x15.go_load.WatchOptionsR\fwatchOptions\x12:\n" +
+	"\rcrawl_options\x18\x17 \x01(\v2\x15.go_load.CrawlOptionsR\fcrawlOptions\"\xa3\x01\n" +
 	"\x1aCreateDownloadTaskResponse\x12:\n" +
-	"\rdownload_task\x18\x01 \x01(\v2\x15.go_load.DownloadTaskR\fdownloadTask\"`\n" +
+	"\rdownload_task\x18\x01 \x01(\v2\x15.go_load.DownloadTaskR\fdownloadTask\x12I\n" +
+	"\x12duplicate_download\x18\x02 \x01(\v2\x1a.go_load.DuplicateDownloadR\x11duplicateDownload\"`\n" +
 	"\x1aGetDownloadTaskListRequest\x12\x14\n" +
 	"\x05token\x18\x01 \x01(\tR\x05token\x12\x16\n" +
 	"\x06offset\x18\x02 \x01(\x04R\x06offset\x12\x14\n" +
 	"\x05limit\x18\x03 \x01(\x04R\x05limit\"\x9d\x01\n" +
 	"\x1bGetDownloadTaskListResponse\x12C\n" +
 	"\x12download_task_list\x18\x01 \x03(\v2\x15.go_load.DownloadTaskR\x10downloadTaskList\x129\n" +
-	"\x19total_download_task_count\x18\x02 \x01(\x04R\x16totalDownloadTaskCount\"m\n" +
+	"\x19total_download_task_count\x18\x02 \x01(\x04R\x16totalDownloadTaskCount\"\x8a\x02\n" +
 	"\x19UpdateDownloadTaskRequest\x12\x14\n" +
 	"\x05token\x18\x01 \x01(\tR\x05token\x12(\n" +
 	"\x10download_task_id\x18\x02 \x01(\x04R\x0edownloadTaskId\x12\x10\n" +
-	"\x03url\x18\x03 \x01(\tR\x03url\"X\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12=\n" +
+	"\x19speed_limit_bytes_per_sec\x18\x04 \x01(\x04H\x00R\x15speedLimitBytesPerSec\x88\x01\x01\x12>\n" +
+	"\x0fmirror_url_list\x18\x05 \x01(\v2\x16.go_load.MirrorURLListR\rmirrorUrlListB\x1c\n" +
+	"\x1a_speed_limit_bytes_per_sec\"X\n" +
 	"\x1aUpdateDownloadTaskResponse\x12:\n" +
 	"\rdownload_task\x18\x01 \x01(\v2\x15.go_load.DownloadTaskR\fdownloadTask\"m\n" +
 	"\x19DeleteDownloadTaskRequest\x12\x14\n" +
 	"\x05token\x18\x01 \x01(\tR\x05token\x12:\n" +
 	"\rdownload_task\x18\x02 \x01(\v2\x15.go_load.DownloadTaskR\fdownloadTask\"\x1c\n" +
-	"\x1aDeleteDownloadTaskResponse\"\\\n" +
+	"\x1aDeleteDownloadTaskResponse\"\xa0\x01\n" +
 	"\x1aGetDownloadTaskFileRequest\x12\x14\n" +
 	"\x05token\x18\x01 \x01(\tR\x05token\x12(\n" +
-	"\x10download_task_id\x18\x02 \x01(\x04R\x0edownloadTaskId\"1\n" +
+	"\x10download_task_id\x18\x02 \x01(\x04R\x0edownloadTaskId\x12\x1b\n" +
+	"\tfile_path\x18\x03 \x01(\tR\bfilePath\x12%\n" +
+	"\x0eversion_number\x18\x04 \x01(\rR\rversionNumber\"1\n" +
 	"\x1bGetDownloadTaskFileResponse\x12\x12\n" +
-	"\x04data\x18\x01 \x01(\fR\x04data*+\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"\xb6\x01\n" +
+	"\x1dGetDownloadTaskDigestsRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12(\n" +
+	"\x10download_task_id\x18\x02 \x01(\x04R\x0edownloadTaskId\x12\x1b\n" +
+	"\tfile_path\x18\x03 \x01(\tR\bfilePath\x128\n" +
+	"\n" +
+	"algorithms\x18\x04 \x03(\x0e2\x18.go_load.DigestAlgorithmR\n" +
+	"algorithms\"K\n" +
+	"\x1eGetDownloadTaskDigestsResponse\x12)\n" +
+	"\adigests\x18\x01 \x03(\v2\x0f.go_load.DigestR\adigests\"p\n" +
+	"\x1eUpdateAccountSpeedLimitRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x128\n" +
+	"\x19speed_limit_bytes_per_sec\x18\x02 \x01(\x04R\x15speedLimitBytesPerSec\"M\n" +
+	"\x1fUpdateAccountSpeedLimitResponse\x12*\n" +
+	"\aaccount\x18\x01 \x01(\v2\x10.go_load.AccountR\aaccount\"\x92\x02\n" +
+	"\rDownloadQueue\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bpriority\x18\x03 \x01(\x05R\bpriority\x12A\n" +
+	"\x1dmax_concurrent_download_count\x18\x04 \x01(\rR\x1amaxConcurrentDownloadCount\x12*\n" +
+	"\x11active_start_time\x18\x05 \x01(\tR\x0factiveStartTime\x12&\n" +
+	"\x0factive_end_time\x18\x06 \x01(\tR\ractiveEndTime\x12*\n" +
+	"\x11download_task_ids\x18\a \x03(\x04R\x0fdownloadTaskIds\"\xf9\x01\n" +
+	"\x1aCreateDownloadQueueRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bpriority\x18\x03 \x01(\x05R\bpriority\x12A\n" +
+	"\x1dmax_concurrent_download_count\x18\x04 \x01(\rR\x1amaxConcurrentDownloadCount\x12*\n" +
+	"\x11active_start_time\x18\x05 \x01(\tR\x0factiveStartTime\x12&\n" +
+	"\x0factive_end_time\x18\x06 \x01(\tR\ractiveEndTime\"\\\n" +
+	"\x1bCreateDownloadQueueResponse\x12=\n" +
+	"\x0edownload_queue\x18\x01 \x01(\v2\x16.go_load.DownloadQueueR\rdownloadQueue\"3\n" +
+	"\x1bGetDownloadQueueListRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"f\n" +
+	"\x1cGetDownloadQueueListResponse\x12F\n" +
+	"\x13download_queue_list\x18\x01 \x03(\v2\x16.go_load.DownloadQueueR\x11downloadQueueList\"\xa5\x02\n" +
+	"\x1aUpdateDownloadQueueRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12*\n" +
+	"\x11download_queue_id\x18\x02 \x01(\x04R\x0fdownloadQueueId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1a\n" +
+	"\bpriority\x18\x04 \x01(\x05R\bpriority\x12A\n" +
+	"\x1dmax_concurrent_download_count\x18\x05 \x01(\rR\x1amaxConcurrentDownloadCount\x12*\n" +
+	"\x11active_start_time\x18\x06 \x01(\tR\x0factiveStartTime\x12&\n" +
+	"\x0factive_end_time\x18\a \x01(\tR\ractiveEndTime\"\\\n" +
+	"\x1bUpdateDownloadQueueResponse\x12=\n" +
+	"\x0edownload_queue\x18\x01 \x01(\v2\x16.go_load.DownloadQueueR\rdownloadQueue\"^\n" +
+	"\x1aDeleteDownloadQueueRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12*\n" +
+	"\x11download_queue_id\x18\x02 \x01(\x04R\x0fdownloadQueueId\"\x1d\n" +
+	"\x1bDeleteDownloadQueueResponse\"\xa1\x01\n" +
+	"\x17MoveDownloadTaskRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12(\n" +
+	"\x10download_task_id\x18\x02 \x01(\x04R\x0edownloadTaskId\x12*\n" +
+	"\x11download_queue_id\x18\x03 \x01(\x04R\x0fdownloadQueueId\x12\x1a\n" +
+	"\bposition\x18\x04 \x01(\rR\bposition\"V\n" +
+	"\x18MoveDownloadTaskResponse\x12:\n" +
+	"\rdownload_task\x18\x01 \x01(\v2\x15.go_load.DownloadTaskR\fdownloadTask\"\xa1\x02\n" +
+	"\fAccountShare\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\x04R\taccountId\x12!\n" +
+	"\faccount_name\x18\x02 \x01(\tR\vaccountName\x12\x16\n" +
+	"\x06weight\x18\x03 \x01(\rR\x06weight\x12\x14\n" +
+	"\x05share\x18\x04 \x01(\x01R\x05share\x12=\n" +
+	"\x1brunning_download_task_count\x18\x05 \x01(\x04R\x18runningDownloadTaskCount\x12=\n" +
+	"\x1bpending_download_task_count\x18\x06 \x01(\x04R\x18pendingDownloadTaskCount\x12#\n" +
+	"\rrunning_share\x18\a \x01(\x01R\frunningShare\"2\n" +
+	"\x1aGetAccountShareListRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"b\n" +
+	"\x1bGetAccountShareListResponse\x12C\n" +
+	"\x12account_share_list\x18\x01 \x03(\v2\x15.go_load.AccountShareR\x10accountShareList\"\xdf\x02\n" +
+	"\x13DownloadTaskAttempt\x12%\n" +
+	"\x0eattempt_number\x18\x01 \x01(\rR\rattemptNumber\x129\n" +
+	"\n" +
+	"started_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12;\n" +
+	"\vfinished_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\x12\x1c\n" +
+	"\tsucceeded\x18\x04 \x01(\bR\tsucceeded\x12<\n" +
+	"\verror_class\x18\x05 \x01(\x0e2\x1b.go_load.DownloadErrorClassR\n" +
+	"errorClass\x12(\n" +
+	"\x10http_status_code\x18\x06 \x01(\rR\x0ehttpStatusCode\x12#\n" +
+	"\rerror_message\x18\a \x01(\tR\ferrorMessage\"c\n" +
+	"!GetDownloadTaskAttemptListRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12(\n" +
+	"\x10download_task_id\x18\x02 \x01(\x04R\x0edownloadTaskId\"\x7f\n" +
+	"\"GetDownloadTaskAttemptListResponse\x12Y\n" +
+	"\x1adownload_task_attempt_list\x18\x01 \x03(\v2\x1c.go_load.DownloadTaskAttemptR\x17downloadTaskAttemptList\"#\n" +
+	"\rMirrorURLList\x12\x12\n" +
+	"\x04urls\x18\x01 \x03(\tR\x04urls\"\x81\x01\n" +
+	"\fPieceDigests\x126\n" +
+	"\talgorithm\x18\x01 \x01(\x0e2\x18.go_load.DigestAlgorithmR\talgorithm\x12!\n" +
+	"\fpiece_length\x18\x02 \x01(\x04R\vpieceLength\x12\x16\n" +
+	"\x06values\x18\x03 \x03(\tR\x06values\"u\n" +
+	"\x15ImportMetalinkRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1a\n" +
+	"\bmetalink\x18\x02 \x01(\fR\bmetalink\x12*\n" +
+	"\x11download_queue_id\x18\x03 \x01(\x04R\x0fdownloadQueueId\"]\n" +
+	"\x16ImportMetalinkResponse\x12C\n" +
+	"\x12download_task_list\x18\x01 \x03(\v2\x15.go_load.DownloadTaskR\x10downloadTaskList\"c\n" +
+	"!ExportDownloadTaskMetalinkRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12(\n" +
+	"\x10download_task_id\x18\x02 \x01(\x04R\x0edownloadTaskId\"@\n" +
+	"\"ExportDownloadTaskMetalinkResponse\x12\x1a\n" +
+	"\bmetalink\x18\x01 \x01(\fR\bmetalink\"x\n" +
+	"\x16PostDownloadStepResult\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1c\n" +
+	"\tsucceeded\x18\x03 \x01(\bR\tsucceeded\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"@\n" +
+	"\rExtractedFile\x12\x1b\n" +
+	"\tfile_path\x18\x01 \x01(\tR\bfilePath\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x04R\x04size\"g\n" +
+	"\aWebhook\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12:\n" +
+	"\vevent_types\x18\x03 \x03(\x0e2\x19.go_load.WebhookEventTypeR\n" +
+	"eventTypes\"z\n" +
+	"\x14CreateWebhookRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12:\n" +
+	"\vevent_types\x18\x03 \x03(\x0e2\x19.go_load.WebhookEventTypeR\n" +
+	"eventTypes\"[\n" +
+	"\x15CreateWebhookResponse\x12*\n" +
+	"\awebhook\x18\x01 \x01(\v2\x10.go_load.WebhookR\awebhook\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\"-\n" +
+	"\x15GetWebhookListRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"M\n" +
+	"\x16GetWebhookListResponse\x123\n" +
+	"\fwebhook_list\x18\x01 \x03(\v2\x10.go_load.WebhookR\vwebhookList\"K\n" +
+	"\x14DeleteWebhookRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\x04R\twebhookId\"\x17\n" +
+	"\x15DeleteWebhookResponse\"\xc2\x04\n" +
+	"\x0fWebhookDelivery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\x04R\twebhookId\x128\n" +
+	"\n" +
+	"event_type\x18\x03 \x01(\x0e2\x19.go_load.WebhookEventTypeR\teventType\x12(\n" +
+	"\x10download_task_id\x18\x04 \x01(\x04R\x0edownloadTaskId\x12G\n" +
+	"\x0fdelivery_status\x18\x05 \x01(\x0e2\x1e.go_load.WebhookDeliveryStatusR\x0edeliveryStatus\x12#\n" +
+	"\rattempt_count\x18\x06 \x01(\rR\fattemptCount\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12B\n" +
+	"\x0fnext_attempt_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\rnextAttemptAt\x12F\n" +
+	"\x11last_attempted_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\x0flastAttemptedAt\x12(\n" +
+	"\x10http_status_code\x18\n" +
+	" \x01(\rR\x0ehttpStatusCode\x12#\n" +
+	"\rerror_message\x18\v \x01(\tR\ferrorMessage\x12\x18\n" +
+	"\apayload\x18\f \x01(\tR\apayload\"\x82\x01\n" +
+	"\x1dGetWebhookDeliveryListRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\x04R\twebhookId\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x04R\x06offset\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x04R\x05limit\"\xaf\x01\n" +
+	"\x1eGetWebhookDeliveryListResponse\x12L\n" +
+	"\x15webhook_delivery_list\x18\x01 \x03(\v2\x18.go_load.WebhookDeliveryR\x13webhookDeliveryList\x12?\n" +
+	"\x1ctotal_webhook_delivery_count\x18\x02 \x01(\x04R\x19totalWebhookDeliveryCount\"5\n" +
+	"\x1dGetAccountStorageUsageRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"~\n" +
+	"\x1eGetAccountStorageUsageResponse\x12,\n" +
+	"\x12logical_byte_count\x18\x01 \x01(\x04R\x10logicalByteCount\x12.\n" +
+	"\x13physical_byte_count\x18\x02 \x01(\x04R\x11physicalByteCount\"\x86\x01\n" +
+	"\x11DuplicateDownload\x12(\n" +
+	"\x10download_task_id\x18\x01 \x01(\x04R\x0edownloadTaskId\x12\x1b\n" +
+	"\tfile_name\x18\x02 \x01(\tR\bfileName\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x04R\x04size\x12\x16\n" +
+	"\x06sha256\x18\x04 \x01(\tR\x06sha256\"e\n" +
+	"\fWatchOptions\x12,\n" +
+	"\x12check_interval_sec\x18\x01 \x01(\rR\x10checkIntervalSec\x12'\n" +
+	"\x0fretention_count\x18\x02 \x01(\rR\x0eretentionCount\"\xdc\x01\n" +
+	"\x13DownloadTaskVersion\x12%\n" +
+	"\x0eversion_number\x18\x01 \x01(\rR\rversionNumber\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x04R\x04size\x12\x16\n" +
+	"\x06sha256\x18\x03 \x01(\tR\x06sha256\x12\x12\n" +
+	"\x04etag\x18\x04 \x01(\tR\x04etag\x12#\n" +
+	"\rlast_modified\x18\x05 \x01(\tR\flastModified\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"c\n" +
+	"!GetDownloadTaskVersionListRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12(\n" +
+	"\x10download_task_id\x18\x02 \x01(\x04R\x0edownloadTaskId\"\x7f\n" +
+	"\"GetDownloadTaskVersionListResponse\x12Y\n" +
+	"\x1adownload_task_version_list\x18\x01 \x03(\v2\x1c.go_load.DownloadTaskVersionR\x17downloadTaskVersionList\"\xf0\x01\n" +
+	"\fCrawlOptions\x12\x1b\n" +
+	"\tmax_depth\x18\x01 \x01(\rR\bmaxDepth\x12'\n" +
+	"\x0fallowed_domains\x18\x02 \x03(\tR\x0eallowedDomains\x12\x1f\n" +
+	"\vpath_prefix\x18\x03 \x01(\tR\n" +
+	"pathPrefix\x12)\n" +
+	"\x10include_patterns\x18\x04 \x03(\tR\x0fincludePatterns\x12)\n" +
+	"\x10exclude_patterns\x18\x05 \x03(\tR\x0fexcludePatterns\x12#\n" +
+	"\rrewrite_links\x18\x06 \x01(\bR\frewriteLinks\"\xeb\x01\n" +
+	"\vGrabbedLink\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12:\n" +
+	"\rdownload_type\x18\x02 \x01(\x0e2\x15.go_load.DownloadTypeR\fdownloadType\x12\x1b\n" +
+	"\tfile_name\x18\x03 \x01(\tR\bfileName\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x04R\x04size\x12!\n" +
+	"\fcontent_type\x18\x05 \x01(\tR\vcontentType\x12:\n" +
+	"\rfile_category\x18\x06 \x01(\x0e2\x15.go_load.FileCategoryR\ffileCategory\"\x8e\x01\n" +
+	"\x10GrabLinksRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12\x1e\n" +
+	"\n" +
+	"extensions\x18\x04 \x03(\tR\n" +
+	"extensions\x12\x18\n" +
+	"\apattern\x18\x05 \x01(\tR\apattern\"U\n" +
+	"\x11GrabLinksResponse\x12@\n" +
+	"\x11grabbed_link_list\x18\x01 \x03(\v2\x14.go_load.GrabbedLinkR\x0fgrabbedLinkList\"{\n" +
+	"#CreateDownloadTasksFromLinksRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x12\n" +
+	"\x04urls\x18\x02 \x03(\tR\x04urls\x12*\n" +
+	"\x11download_queue_id\x18\x03 \x01(\x04R\x0fdownloadQueueId\"k\n" +
+	"$CreateDownloadTasksFromLinksResponse\x12C\n" +
+	"\x12download_task_list\x18\x01 \x03(\v2\x15.go_load.DownloadTaskR\x10downloadTaskList*b\n" +
 	"\fDownloadType\x12\x11\n" +
 	"\rUndefinedType\x10\x00\x12\b\n" +
-	"\x04HTTP\x10\x01*\\\n" +
+	"\x04HTTP\x10\x01\x12\a\n" +
+	"\x03FTP\x10\x02\x12\b\n" +
+	"\x04SFTP\x10\x03\x12\n" +
+	"\n" +
+	"\x06Stream\x10\x04\x12\x0e\n" +
+	"\n" +
+	"BITTORRENT\x10\x05\x12\x06\n" +
+	"\x02S3\x10\x06*k\n" +
 	"\x0eDownloadStatus\x12\x13\n" +
 	"\x0fUndefinedStatus\x10\x00\x12\v\n" +
 	"\aPending\x10\x01\x12\x0f\n" +
 	"\vDownloading\x10\x02\x12\n" +
 	"\n" +
 	"\x06Failed\x10\x03\x12\v\n" +
-	"\aSuccess\x10\x042\xa0\x05\n" +
+	"\aSuccess\x10\x04\x12\r\n" +
+	"\tScheduled\x10\x05*\x9a\x01\n" +
+	"\x12DownloadErrorClass\x12\x17\n" +
+	"\x13UndefinedErrorClass\x10\x00\x12\x10\n" +
+	"\fTimeoutError\x10\x01\x12\x13\n" +
+	"\x0fConnectionError\x10\x02\x12\x0f\n" +
+	"\vServerError\x10\x03\x12\x0f\n" +
+	"\vClientError\x10\x04\x12\x12\n" +
+	"\x0eIntegrityError\x10\x05\x12\x0e\n" +
+	"\n" +
+	"OtherError\x10\x06*_\n" +
+	"\x0fDigestAlgorithm\x12\x16\n" +
+	"\x12UndefinedAlgorithm\x10\x00\x12\a\n" +
+	"\x03MD5\x10\x01\x12\b\n" +
+	"\x04SHA1\x10\x02\x12\n" +
+	"\n" +
+	"\x06SHA256\x10\x03\x12\n" +
+	"\n" +
+	"\x06SHA512\x10\x04\x12\t\n" +
+	"\x05CRC32\x10\x05*\xb2\x01\n" +
+	"\x10WebhookEventType\x12\x1d\n" +
+	"\x19UndefinedWebhookEventType\x10\x00\x12\x17\n" +
+	"\x13DownloadTaskCreated\x10\x01\x12\x17\n" +
+	"\x13DownloadTaskStarted\x10\x02\x12\x1a\n" +
+	"\x16DownloadTaskProgressed\x10\x03\x12\x19\n" +
+	"\x15DownloadTaskSucceeded\x10\x04\x12\x16\n" +
+	"\x12DownloadTaskFailed\x10\x05*x\n" +
+	"\x10DuplicateURLMode\x12\x1d\n" +
+	"\x19UndefinedDuplicateURLMode\x10\x00\x12\x17\n" +
+	"\x13RefetchDuplicateURL\x10\x01\x12\x15\n" +
+	"\x11AskOnDuplicateURL\x10\x02\x12\x15\n" +
+	"\x11ReuseDuplicateURL\x10\x03*q\n" +
+	"\x15WebhookDeliveryStatus\x12\"\n" +
+	"\x1eUndefinedWebhookDeliveryStatus\x10\x00\x12\x13\n" +
+	"\x0fDeliveryPending\x10\x01\x12\r\n" +
+	"\tDelivered\x10\x02\x12\x10\n" +
+	"\fDeadLettered\x10\x03*\x9c\x01\n" +
+	"\fFileCategory\x12\x19\n" +
+	"\x15UndefinedFileCategory\x10\x00\x12\x12\n" +
+	"\x0eCompressedFile\x10\x01\x12\x10\n" +
+	"\fDocumentFile\x10\x02\x12\r\n" +
+	"\tMusicFile\x10\x03\x12\r\n" +
+	"\tVideoFile\x10\x04\x12\r\n" +
+	"\tImageFile\x10\x05\x12\x0f\n" +
+	"\vProgramFile\x10\x06\x12\r\n" +
+	"\tOtherFile\x10\a2\xa7\x14\n" +
 	"\rGoLoadService\x12P\n" +
 	"\rCreateAccount\x12\x1d.go_load.CreateAccountRequest\x1a\x1e.go_load.CreateAccountResponse\"\x00\x12P\n" +
 	"\rCreateSession\x12\x1d.go_load.CreateSessionRequest\x1a\x1e.go_load.CreateSessionResponse\"\x00\x12_\n" +
@@ -1026,7 +5467,26 @@ const file_api_go_load_proto_rawDesc = "" +
 	"\x13GetDownloadTaskList\x12#.go_load.GetDownloadTaskListRequest\x1a$.go_load.GetDownloadTaskListResponse\"\x00\x12_\n" +
 	"\x12UpdateDownloadTask\x12\".go_load.UpdateDownloadTaskRequest\x1a#.go_load.UpdateDownloadTaskResponse\"\x00\x12_\n" +
 	"\x12DeleteDownloadTask\x12\".go_load.DeleteDownloadTaskRequest\x1a#.go_load.DeleteDownloadTaskResponse\"\x00\x12d\n" +
-	"\x13GetDownloadTaskFile\x12#.go_load.GetDownloadTaskFileRequest\x1a$.go_load.GetDownloadTaskFileResponse\"\x000\x01B\x0eZ\fgrpc/go_loadb\x06proto3"
+	"\x13GetDownloadTaskFile\x12#.go_load.GetDownloadTaskFileRequest\x1a$.go_load.GetDownloadTaskFileResponse\"\x000\x01\x12k\n" +
+	"\x16GetDownloadTaskDigests\x12&.go_load.GetDownloadTaskDigestsRequest\x1a'.go_load.GetDownloadTaskDigestsResponse\"\x00\x12n\n" +
+	"\x17UpdateAccountSpeedLimit\x12'.go_load.UpdateAccountSpeedLimitRequest\x1a(.go_load.UpdateAccountSpeedLimitResponse\"\x00\x12b\n" +
+	"\x13CreateDownloadQueue\x12#.go_load.CreateDownloadQueueRequest\x1a$.go_load.CreateDownloadQueueResponse\"\x00\x12e\n" +
+	"\x14GetDownloadQueueList\x12$.go_load.GetDownloadQueueListRequest\x1a%.go_load.GetDownloadQueueListResponse\"\x00\x12b\n" +
+	"\x13UpdateDownloadQueue\x12#.go_load.UpdateDownloadQueueRequest\x1a$.go_load.UpdateDownloadQueueResponse\"\x00\x12b\n" +
+	"\x13DeleteDownloadQueue\x12#.go_load.DeleteDownloadQueueRequest\x1a$.go_load.DeleteDownloadQueueResponse\"\x00\x12Y\n" +
+	"\x10MoveDownloadTask\x12 .go_load.MoveDownloadTaskRequest\x1a!.go_load.MoveDownloadTaskResponse\"\x00\x12b\n" +
+	"\x13GetAccountShareList\x12#.go_load.GetAccountShareListRequest\x1a$.go_load.GetAccountShareListResponse\"\x00\x12w\n" +
+	"\x1aGetDownloadTaskAttemptList\x12*.go_load.GetDownloadTaskAttemptListRequest\x1a+.go_load.GetDownloadTaskAttemptListResponse\"\x00\x12S\n" +
+	"\x0eImportMetalink\x12\x1e.go_load.ImportMetalinkRequest\x1a\x1f.go_load.ImportMetalinkResponse\"\x00\x12w\n" +
+	"\x1aExportDownloadTaskMetalink\x12*.go_load.ExportDownloadTaskMetalinkRequest\x1a+.go_load.ExportDownloadTaskMetalinkResponse\"\x00\x12P\n" +
+	"\rCreateWebhook\x12\x1d.go_load.CreateWebhookRequest\x1a\x1e.go_load.CreateWebhookResponse\"\x00\x12S\n" +
+	"\x0eGetWebhookList\x12\x1e.go_load.GetWebhookListRequest\x1a\x1f.go_load.GetWebhookListResponse\"\x00\x12P\n" +
+	"\rDeleteWebhook\x12\x1d.go_load.DeleteWebhookRequest\x1a\x1e.go_load.DeleteWebhookResponse\"\x00\x12k\n" +
+	"\x16GetWebhookDeliveryList\x12&.go_load.GetWebhookDeliveryListRequest\x1a'.go_load.GetWebhookDeliveryListResponse\"\x00\x12k\n" +
+	"\x16GetAccountStorageUsage\x12&.go_load.GetAccountStorageUsageRequest\x1a'.go_load.GetAccountStorageUsageResponse\"\x00\x12w\n" +
+	"\x1aGetDownloadTaskVersionList\x12*.go_load.GetDownloadTaskVersionListRequest\x1a+.go_load.GetDownloadTaskVersionListResponse\"\x00\x12D\n" +
+	"\tGrabLinks\x12\x19.go_load.GrabLinksRequest\x1a\x1a.go_load.GrabLinksResponse\"\x00\x12}\n" +
+	"\x1cCreateDownloadTasksFromLinks\x12,.go_load.CreateDownloadTasksFromLinksRequest\x1a-.go_load.CreateDownloadTasksFromLinksResponse\"\x00B\x0eZ\fgrpc/go_loadb\x06proto3"
 
 var (
 	file_api_go_load_proto_rawDescOnce sync.Once
@@ -1040,57 +5500,209 @@ func file_api_go_load_proto_rawDescGZIP() []byte {
 	return file_api_go_load_proto_rawDescData
 }
 
-var file_api_go_load_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_api_go_load_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_api_go_load_proto_enumTypes = make([]protoimpl.EnumInfo, 8)
+var file_api_go_load_proto_msgTypes = make([]protoimpl.MessageInfo, 73)
 var file_api_go_load_proto_goTypes = []any{
-	(DownloadType)(0),                   // 0: go_load.DownloadType
-	(DownloadStatus)(0),                 // 1: go_load.DownloadStatus
-	(*Account)(nil),                     // 2: go_load.Account
-	(*DownloadTask)(nil),                // 3: go_load.DownloadTask
-	(*CreateAccountRequest)(nil),        // 4: go_load.CreateAccountRequest
-	(*CreateAccountResponse)(nil),       // 5: go_load.CreateAccountResponse
-	(*CreateSessionRequest)(nil),        // 6: go_load.CreateSessionRequest
-	(*CreateSessionResponse)(nil),       // 7: go_load.CreateSessionResponse
-	(*CreateDownloadTaskRequest)(nil),   // 8: go_load.CreateDownloadTaskRequest
-	(*CreateDownloadTaskResponse)(nil),  // 9: go_load.CreateDownloadTaskResponse
-	(*GetDownloadTaskListRequest)(nil),  // 10: go_load.GetDownloadTaskListRequest
-	(*GetDownloadTaskListResponse)(nil), // 11: go_load.GetDownloadTaskListResponse
-	(*UpdateDownloadTaskRequest)(nil),   // 12: go_load.UpdateDownloadTaskRequest
-	(*UpdateDownloadTaskResponse)(nil),  // 13: go_load.UpdateDownloadTaskResponse
-	(*DeleteDownloadTaskRequest)(nil),   // 14: go_load.DeleteDownloadTaskRequest
-	(*DeleteDownloadTaskResponse)(nil),  // 15: go_load.DeleteDownloadTaskResponse
-	(*GetDownloadTaskFileRequest)(nil),  // 16: go_load.GetDownloadTaskFileRequest
-	(*GetDownloadTaskFileResponse)(nil), // 17: go_load.GetDownloadTaskFileResponse
+	(DownloadType)(0),                            // 0: go_load.DownloadType
+	(DownloadStatus)(0),                          // 1: go_load.DownloadStatus
+	(DownloadErrorClass)(0),                      // 2: go_load.DownloadErrorClass
+	(DigestAlgorithm)(0),                         // 3: go_load.DigestAlgorithm
+	(WebhookEventType)(0),                        // 4: go_load.WebhookEventType
+	(DuplicateURLMode)(0),                        // 5: go_load.DuplicateURLMode
+	(WebhookDeliveryStatus)(0),                   // 6: go_load.WebhookDeliveryStatus
+	(FileCategory)(0),                            // 7: go_load.FileCategory
+	(*Account)(nil),                              // 8: go_load.Account
+	(*DownloadTask)(nil),                         // 9: go_load.DownloadTask
+	(*Digest)(nil),                               // 10: go_load.Digest
+	(*CreateAccountRequest)(nil),                 // 11: go_load.CreateAccountRequest
+	(*CreateAccountResponse)(nil),                // 12: go_load.CreateAccountResponse
+	(*CreateSessionRequest)(nil),                 // 13: go_load.CreateSessionRequest
+	(*CreateSessionResponse)(nil),                // 14: go_load.CreateSessionResponse
+	(*SFTPOptions)(nil),                          // 15: go_load.SFTPOptions
+	(*StreamOptions)(nil),                        // 16: go_load.StreamOptions
+	(*TorrentOptions)(nil),                       // 17: go_load.TorrentOptions
+	(*S3Options)(nil),                            // 18: go_load.S3Options
+	(*CreateDownloadTaskRequest)(nil),            // 19: go_load.CreateDownloadTaskRequest
+	(*CreateDownloadTaskResponse)(nil),           // 20: go_load.CreateDownloadTaskResponse
+	(*GetDownloadTaskListRequest)(nil),           // 21: go_load.GetDownloadTaskListRequest
+	(*GetDownloadTaskListResponse)(nil),          // 22: go_load.GetDownloadTaskListResponse
+	(*UpdateDownloadTaskRequest)(nil),            // 23: go_load.UpdateDownloadTaskRequest
+	(*UpdateDownloadTaskResponse)(nil),           // 24: go_load.UpdateDownloadTaskResponse
+	(*DeleteDownloadTaskRequest)(nil),            // 25: go_load.DeleteDownloadTaskRequest
+	(*DeleteDownloadTaskResponse)(nil),           // 26: go_load.DeleteDownloadTaskResponse
+	(*GetDownloadTaskFileRequest)(nil),           // 27: go_load.GetDownloadTaskFileRequest
+	(*GetDownloadTaskFileResponse)(nil),          // 28: go_load.GetDownloadTaskFileResponse
+	(*GetDownloadTaskDigestsRequest)(nil),        // 29: go_load.GetDownloadTaskDigestsRequest
+	(*GetDownloadTaskDigestsResponse)(nil),       // 30: go_load.GetDownloadTaskDigestsResponse
+	(*UpdateAccountSpeedLimitRequest)(nil),       // 31: go_load.UpdateAccountSpeedLimitRequest
+	(*UpdateAccountSpeedLimitResponse)(nil),      // 32: go_load.UpdateAccountSpeedLimitResponse
+	(*DownloadQueue)(nil),                        // 33: go_load.DownloadQueue
+	(*CreateDownloadQueueRequest)(nil),           // 34: go_load.CreateDownloadQueueRequest
+	(*CreateDownloadQueueResponse)(nil),          // 35: go_load.CreateDownloadQueueResponse
+	(*GetDownloadQueueListRequest)(nil),          // 36: go_load.GetDownloadQueueListRequest
+	(*GetDownloadQueueListResponse)(nil),         // 37: go_load.GetDownloadQueueListResponse
+	(*UpdateDownloadQueueRequest)(nil),           // 38: go_load.UpdateDownloadQueueRequest
+	(*UpdateDownloadQueueResponse)(nil),          // 39: go_load.UpdateDownloadQueueResponse
+	(*DeleteDownloadQueueRequest)(nil),           // 40: go_load.DeleteDownloadQueueRequest
+	(*DeleteDownloadQueueResponse)(nil),          // 41: go_load.DeleteDownloadQueueResponse
+	(*MoveDownloadTaskRequest)(nil),              // 42: go_load.MoveDownloadTaskRequest
+	(*MoveDownloadTaskResponse)(nil),             // 43: go_load.MoveDownloadTaskResponse
+	(*AccountShare)(nil),                         // 44: go_load.AccountShare
+	(*GetAccountShareListRequest)(nil),           // 45: go_load.GetAccountShareListRequest
+	(*GetAccountShareListResponse)(nil),          // 46: go_load.GetAccountShareListResponse
+	(*DownloadTaskAttempt)(nil),                  // 47: go_load.DownloadTaskAttempt
+	(*GetDownloadTaskAttemptListRequest)(nil),    // 48: go_load.GetDownloadTaskAttemptListRequest
+	(*GetDownloadTaskAttemptListResponse)(nil),   // 49: go_load.GetDownloadTaskAttemptListResponse
+	(*MirrorURLList)(nil),                        // 50: go_load.MirrorURLList
+	(*PieceDigests)(nil),                         // 51: go_load.PieceDigests
+	(*ImportMetalinkRequest)(nil),                // 52: go_load.ImportMetalinkRequest
+	(*ImportMetalinkResponse)(nil),               // 53: go_load.ImportMetalinkResponse
+	(*ExportDownloadTaskMetalinkRequest)(nil),    // 54: go_load.ExportDownloadTaskMetalinkRequest
+	(*ExportDownloadTaskMetalinkResponse)(nil),   // 55: go_load.ExportDownloadTaskMetalinkResponse
+	(*PostDownloadStepResult)(nil),               // 56: go_load.PostDownloadStepResult
+	(*ExtractedFile)(nil),                        // 57: go_load.ExtractedFile
+	(*Webhook)(nil),                              // 58: go_load.Webhook
+	(*CreateWebhookRequest)(nil),                 // 59: go_load.CreateWebhookRequest
+	(*CreateWebhookResponse)(nil),                // 60: go_load.CreateWebhookResponse
+	(*GetWebhookListRequest)(nil),                // 61: go_load.GetWebhookListRequest
+	(*GetWebhookListResponse)(nil),               // 62: go_load.GetWebhookListResponse
+	(*DeleteWebhookRequest)(nil),                 // 63: go_load.DeleteWebhookRequest
+	(*DeleteWebhookResponse)(nil),                // 64: go_load.DeleteWebhookResponse
+	(*WebhookDelivery)(nil),                      // 65: go_load.WebhookDelivery
+	(*GetWebhookDeliveryListRequest)(nil),        // 66: go_load.GetWebhookDeliveryListRequest
+	(*GetWebhookDeliveryListResponse)(nil),       // 67: go_load.GetWebhookDeliveryListResponse
+	(*GetAccountStorageUsageRequest)(nil),        // 68: go_load.GetAccountStorageUsageRequest
+	(*GetAccountStorageUsageResponse)(nil),       // 69: go_load.GetAccountStorageUsageResponse
+	(*DuplicateDownload)(nil),                    // 70: go_load.DuplicateDownload
+	(*WatchOptions)(nil),                         // 71: go_load.WatchOptions
+	(*DownloadTaskVersion)(nil),                  // 72: go_load.DownloadTaskVersion
+	(*GetDownloadTaskVersionListRequest)(nil),    // 73: go_load.GetDownloadTaskVersionListRequest
+	(*GetDownloadTaskVersionListResponse)(nil),   // 74: go_load.GetDownloadTaskVersionListResponse
+	(*CrawlOptions)(nil),                         // 75: go_load.CrawlOptions
+	(*GrabbedLink)(nil),                          // 76: go_load.GrabbedLink
+	(*GrabLinksRequest)(nil),                     // 77: go_load.GrabLinksRequest
+	(*GrabLinksResponse)(nil),                    // 78: go_load.GrabLinksResponse
+	(*CreateDownloadTasksFromLinksRequest)(nil),  // 79: go_load.CreateDownloadTasksFromLinksRequest
+	(*CreateDownloadTasksFromLinksResponse)(nil), // 80: go_load.CreateDownloadTasksFromLinksResponse
+	(*timestamppb.Timestamp)(nil),                // 81: google.protobuf.Timestamp
 }
 var file_api_go_load_proto_depIdxs = []int32{
-	2,  // 0: go_load.DownloadTask.of_account:type_name -> go_load.Account
+	8,  // 0: go_load.DownloadTask.of_account:type_name -> go_load.Account
 	0,  // 1: go_load.DownloadTask.download_type:type_name -> go_load.DownloadType
 	1,  // 2: go_load.DownloadTask.download_status:type_name -> go_load.DownloadStatus
-	2,  // 3: go_load.CreateSessionResponse.account:type_name -> go_load.Account
-	0,  // 4: go_load.CreateDownloadTaskRequest.download_type:type_name -> go_load.DownloadType
-	3,  // 5: go_load.CreateDownloadTaskResponse.download_task:type_name -> go_load.DownloadTask
-	3,  // 6: go_load.GetDownloadTaskListResponse.download_task_list:type_name -> go_load.DownloadTask
-	3,  // 7: go_load.UpdateDownloadTaskResponse.download_task:type_name -> go_load.DownloadTask
-	3,  // 8: go_load.DeleteDownloadTaskRequest.download_task:type_name -> go_load.DownloadTask
-	4,  // 9: go_load.GoLoadService.CreateAccount:input_type -> go_load.CreateAccountRequest
-	6,  // 10: go_load.GoLoadService.CreateSession:input_type -> go_load.CreateSessionRequest
-	8,  // 11: go_load.GoLoadService.CreateDownloadTask:input_type -> go_load.CreateDownloadTaskRequest
-	10, // 12: go_load.GoLoadService.GetDownloadTaskList:input_type -> go_load.GetDownloadTaskListRequest
-	12, // 13: go_load.GoLoadService.UpdateDownloadTask:input_type -> go_load.UpdateDownloadTaskRequest
-	14, // 14: go_load.GoLoadService.DeleteDownloadTask:input_type -> go_load.DeleteDownloadTaskRequest
-	16, // 15: go_load.GoLoadService.GetDownloadTaskFile:input_type -> go_load.GetDownloadTaskFileRequest
-	5,  // 16: go_load.GoLoadService.CreateAccount:output_type -> go_load.CreateAccountResponse
-	7,  // 17: go_load.GoLoadService.CreateSession:output_type -> go_load.CreateSessionResponse
-	9,  // 18: go_load.GoLoadService.CreateDownloadTask:output_type -> go_load.CreateDownloadTaskResponse
-	11, // 19: go_load.GoLoadService.GetDownloadTaskList:output_type -> go_load.GetDownloadTaskListResponse
-	13, // 20: go_load.GoLoadService.UpdateDownloadTask:output_type -> go_load.UpdateDownloadTaskResponse
-	15, // 21: go_load.GoLoadService.DeleteDownloadTask:output_type -> go_load.DeleteDownloadTaskResponse
-	17, // 22: go_load.GoLoadService.GetDownloadTaskFile:output_type -> go_load.GetDownloadTaskFileResponse
-	16, // [16:23] is the sub-list for method output_type
-	9,  // [9:16] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	81, // 3: go_load.DownloadTask.next_run_at:type_name -> google.protobuf.Timestamp
+	81, // 4: go_load.DownloadTask.next_retry_at:type_name -> google.protobuf.Timestamp
+	56, // 5: go_load.DownloadTask.post_download_step_results:type_name -> go_load.PostDownloadStepResult
+	57, // 6: go_load.DownloadTask.extracted_files:type_name -> go_load.ExtractedFile
+	71, // 7: go_load.DownloadTask.watch_options:type_name -> go_load.WatchOptions
+	81, // 8: go_load.DownloadTask.next_check_at:type_name -> google.protobuf.Timestamp
+	75, // 9: go_load.DownloadTask.crawl_options:type_name -> go_load.CrawlOptions
+	3,  // 10: go_load.Digest.algorithm:type_name -> go_load.DigestAlgorithm
+	8,  // 11: go_load.CreateSessionResponse.account:type_name -> go_load.Account
+	0,  // 12: go_load.CreateDownloadTaskRequest.download_type:type_name -> go_load.DownloadType
+	15, // 13: go_load.CreateDownloadTaskRequest.sftp_options:type_name -> go_load.SFTPOptions
+	16, // 14: go_load.CreateDownloadTaskRequest.stream_options:type_name -> go_load.StreamOptions
+	17, // 15: go_load.CreateDownloadTaskRequest.torrent_options:type_name -> go_load.TorrentOptions
+	18, // 16: go_load.CreateDownloadTaskRequest.s3_options:type_name -> go_load.S3Options
+	10, // 17: go_load.CreateDownloadTaskRequest.expected_digest:type_name -> go_load.Digest
+	81, // 18: go_load.CreateDownloadTaskRequest.start_at:type_name -> google.protobuf.Timestamp
+	51, // 19: go_load.CreateDownloadTaskRequest.piece_digests:type_name -> go_load.PieceDigests
+	5,  // 20: go_load.CreateDownloadTaskRequest.duplicate_url_mode:type_name -> go_load.DuplicateURLMode
+	71, // 21: go_load.CreateDownloadTaskRequest.watch_options:type_name -> go_load.WatchOptions
+	75, // 22: go_load.CreateDownloadTaskRequest.crawl_options:type_name -> go_load.CrawlOptions
+	9,  // 23: go_load.CreateDownloadTaskResponse.download_task:type_name -> go_load.DownloadTask
+	70, // 24: go_load.CreateDownloadTaskResponse.duplicate_download:type_name -> go_load.DuplicateDownload
+	9,  // 25: go_load.GetDownloadTaskListResponse.download_task_list:type_name -> go_load.DownloadTask
+	50, // 26: go_load.UpdateDownloadTaskRequest.mirror_url_list:type_name -> go_load.MirrorURLList
+	9,  // 27: go_load.UpdateDownloadTaskResponse.download_task:type_name -> go_load.DownloadTask
+	9,  // 28: go_load.DeleteDownloadTaskRequest.download_task:type_name -> go_load.DownloadTask
+	3,  // 29: go_load.GetDownloadTaskDigestsRequest.algorithms:type_name -> go_load.DigestAlgorithm
+	10, // 30: go_load.GetDownloadTaskDigestsResponse.digests:type_name -> go_load.Digest
+	8,  // 31: go_load.UpdateAccountSpeedLimitResponse.account:type_name -> go_load.Account
+	33, // 32: go_load.CreateDownloadQueueResponse.download_queue:type_name -> go_load.DownloadQueue
+	33, // 33: go_load.GetDownloadQueueListResponse.download_queue_list:type_name -> go_load.DownloadQueue
+	33, // 34: go_load.UpdateDownloadQueueResponse.download_queue:type_name -> go_load.DownloadQueue
+	9,  // 35: go_load.MoveDownloadTaskResponse.download_task:type_name -> go_load.DownloadTask
+	44, // 36: go_load.GetAccountShareListResponse.account_share_list:type_name -> go_load.AccountShare
+	81, // 37: go_load.DownloadTaskAttempt.started_at:type_name -> google.protobuf.Timestamp
+	81, // 38: go_load.DownloadTaskAttempt.finished_at:type_name -> google.protobuf.Timestamp
+	2,  // 39: go_load.DownloadTaskAttempt.error_class:type_name -> go_load.DownloadErrorClass
+	47, // 40: go_load.GetDownloadTaskAttemptListResponse.download_task_attempt_list:type_name -> go_load.DownloadTaskAttempt
+	3,  // 41: go_load.PieceDigests.algorithm:type_name -> go_load.DigestAlgorithm
+	9,  // 42: go_load.ImportMetalinkResponse.download_task_list:type_name -> go_load.DownloadTask
+	4,  // 43: go_load.Webhook.event_types:type_name -> go_load.WebhookEventType
+	4,  // 44: go_load.CreateWebhookRequest.event_types:type_name -> go_load.WebhookEventType
+	58, // 45: go_load.CreateWebhookResponse.webhook:type_name -> go_load.Webhook
+	58, // 46: go_load.GetWebhookListResponse.webhook_list:type_name -> go_load.Webhook
+	4,  // 47: go_load.WebhookDelivery.event_type:type_name -> go_load.WebhookEventType
+	6,  // 48: go_load.WebhookDelivery.delivery_status:type_name -> go_load.WebhookDeliveryStatus
+	81, // 49: go_load.WebhookDelivery.created_at:type_name -> google.protobuf.Timestamp
+	81, // 50: go_load.WebhookDelivery.next_attempt_at:type_name -> google.protobuf.Timestamp
+	81, // 51: go_load.WebhookDelivery.last_attempted_at:type_name -> google.protobuf.Timestamp
+	65, // 52: go_load.GetWebhookDeliveryListResponse.webhook_delivery_list:type_name -> go_load.WebhookDelivery
+	81, // 53: go_load.DownloadTaskVersion.created_at:type_name -> google.protobuf.Timestamp
+	72, // 54: go_load.GetDownloadTaskVersionListResponse.download_task_version_list:type_name -> go_load.DownloadTaskVersion
+	0,  // 55: go_load.GrabbedLink.download_type:type_name -> go_load.DownloadType
+	7,  // 56: go_load.GrabbedLink.file_category:type_name -> go_load.FileCategory
+	76, // 57: go_load.GrabLinksResponse.grabbed_link_list:type_name -> go_load.GrabbedLink
+	9,  // 58: go_load.CreateDownloadTasksFromLinksResponse.download_task_list:type_name -> go_load.DownloadTask
+	11, // 59: go_load.GoLoadService.CreateAccount:input_type -> go_load.CreateAccountRequest
+	13, // 60: go_load.GoLoadService.CreateSession:input_type -> go_load.CreateSessionRequest
+	19, // 61: go_load.GoLoadService.CreateDownloadTask:input_type -> go_load.CreateDownloadTaskRequest
+	21, // 62: go_load.GoLoadService.GetDownloadTaskList:input_type -> go_load.GetDownloadTaskListRequest
+	23, // 63: go_load.GoLoadService.UpdateDownloadTask:input_type -> go_load.UpdateDownloadTaskRequest
+	25, // 64: go_load.GoLoadService.DeleteDownloadTask:input_type -> go_load.DeleteDownloadTaskRequest
+	27, // 65: go_load.GoLoadService.GetDownloadTaskFile:input_type -> go_load.GetDownloadTaskFileRequest
+	29, // 66: go_load.GoLoadService.GetDownloadTaskDigests:input_type -> go_load.GetDownloadTaskDigestsRequest
+	31, // 67: go_load.GoLoadService.UpdateAccountSpeedLimit:input_type -> go_load.UpdateAccountSpeedLimitRequest
+	34, // 68: go_load.GoLoadService.CreateDownloadQueue:input_type -> go_load.CreateDownloadQueueRequest
+	36, // 69: go_load.GoLoadService.GetDownloadQueueList:input_type -> go_load.GetDownloadQueueListRequest
+	38, // 70: go_load.GoLoadService.UpdateDownloadQueue:input_type -> go_load.UpdateDownloadQueueRequest
+	40, // 71: go_load.GoLoadService.DeleteDownloadQueue:input_type -> go_load.DeleteDownloadQueueRequest
+	42, // 72: go_load.GoLoadService.MoveDownloadTask:input_type -> go_load.MoveDownloadTaskRequest
+	45, // 73: go_load.GoLoadService.GetAccountShareList:input_type -> go_load.GetAccountShareListRequest
+	48, // 74: go_load.GoLoadService.GetDownloadTaskAttemptList:input_type -> go_load.GetDownloadTaskAttemptListRequest
+	52, // 75: go_load.GoLoadService.ImportMetalink:input_type -> go_load.ImportMetalinkRequest
+	54, // 76: go_load.GoLoadService.ExportDownloadTaskMetalink:input_type -> go_load.ExportDownloadTaskMetalinkRequest
+	59, // 77: go_load.GoLoadService.CreateWebhook:input_type -> go_load.CreateWebhookRequest
+	61, // 78: go_load.GoLoadService.GetWebhookList:input_type -> go_load.GetWebhookListRequest
+	63, // 79: go_load.GoLoadService.DeleteWebhook:input_type -> go_load.DeleteWebhookRequest
+	66, // 80: go_load.GoLoadService.GetWebhookDeliveryList:input_type -> go_load.GetWebhookDeliveryListRequest
+	68, // 81: go_load.GoLoadService.GetAccountStorageUsage:input_type -> go_load.GetAccountStorageUsageRequest
+	73, // 82: go_load.GoLoadService.GetDownloadTaskVersionList:input_type -> go_load.GetDownloadTaskVersionListRequest
+	77, // 83: go_load.GoLoadService.GrabLinks:input_type -> go_load.GrabLinksRequest
+	79, // 84: go_load.GoLoadService.CreateDownloadTasksFromLinks:input_type -> go_load.CreateDownloadTasksFromLinksRequest
+	12, // 85: go_load.GoLoadService.CreateAccount:output_type -> go_load.CreateAccountResponse
+	14, // 86: go_load.GoLoadService.CreateSession:output_type -> go_load.CreateSessionResponse
+	20, // 87: go_load.GoLoadService.CreateDownloadTask:output_type -> go_load.CreateDownloadTaskResponse
+	22, // 88: go_load.GoLoadService.GetDownloadTaskList:output_type -> go_load.GetDownloadTaskListResponse
+	24, // 89: go_load.GoLoadService.UpdateDownloadTask:output_type -> go_load.UpdateDownloadTaskResponse
+	26, // 90: go_load.GoLoadService.DeleteDownloadTask:output_type -> go_load.DeleteDownloadTaskResponse
+	28, // 91: go_load.GoLoadService.GetDownloadTaskFile:output_type -> go_load.GetDownloadTaskFileResponse
+	30, // 92: go_load.GoLoadService.GetDownloadTaskDigests:output_type -> go_load.GetDownloadTaskDigestsResponse
+	32, // 93: go_load.GoLoadService.UpdateAccountSpeedLimit:output_type -> go_load.UpdateAccountSpeedLimitResponse
+	35, // 94: go_load.GoLoadService.CreateDownloadQueue:output_type -> go_load.CreateDownloadQueueResponse
+	37, // 95: go_load.GoLoadService.GetDownloadQueueList:output_type -> go_load.GetDownloadQueueListResponse
+	39, // 96: go_load.GoLoadService.UpdateDownloadQueue:output_type -> go_load.UpdateDownloadQueueResponse
+	41, // 97: go_load.GoLoadService.DeleteDownloadQueue:output_type -> go_load.DeleteDownloadQueueResponse
+	43, // 98: go_load.GoLoadService.MoveDownloadTask:output_type -> go_load.MoveDownloadTaskResponse
+	46, // 99: go_load.GoLoadService.GetAccountShareList:output_type -> go_load.GetAccountShareListResponse
+	49, // 100: go_load.GoLoadService.GetDownloadTaskAttemptList:output_type -> go_load.GetDownloadTaskAttemptListResponse
+	53, // 101: go_load.GoLoadService.ImportMetalink:output_type -> go_load.ImportMetalinkResponse
+	55, // 102: go_load.GoLoadService.ExportDownloadTaskMetalink:output_type -> go_load.ExportDownloadTaskMetalinkResponse
+	60, // 103: go_load.GoLoadService.CreateWebhook:output_type -> go_load.CreateWebhookResponse
+	62, // 104: go_load.GoLoadService.GetWebhookList:output_type -> go_load.GetWebhookListResponse
+	64, // 105: go_load.GoLoadService.DeleteWebhook:output_type -> go_load.DeleteWebhookResponse
+	67, // 106: go_load.GoLoadService.GetWebhookDeliveryList:output_type -> go_load.GetWebhookDeliveryListResponse
+	69, // 107: go_load.GoLoadService.GetAccountStorageUsage:output_type -> go_load.GetAccountStorageUsageResponse
+	74, // 108: go_load.GoLoadService.GetDownloadTaskVersionList:output_type -> go_load.GetDownloadTaskVersionListResponse
+	78, // 109: go_load.GoLoadService.GrabLinks:output_type -> go_load.GrabLinksResponse
+	80, // 110: go_load.GoLoadService.CreateDownloadTasksFromLinks:output_type -> go_load.CreateDownloadTasksFromLinksResponse
+	85, // [85:111] is the sub-list for method output_type
+	59, // [59:85] is the sub-list for method input_type
+	59, // [59:59] is the sub-list for extension type_name
+	59, // [59:59] is the sub-list for extension extendee
+	0,  // [0:59] is the sub-list for field type_name
 }
 
 func init() { file_api_go_load_proto_init() }
@@ -1098,13 +5710,14 @@ func file_api_go_load_proto_init() {
 	if File_api_go_load_proto != nil {
 		return
 	}
+	file_api_go_load_proto_msgTypes[15].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_go_load_proto_rawDesc), len(file_api_go_load_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   16,
+			NumEnums:      8,
+			NumMessages:   73,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,13 +19,32 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	GoLoadService_CreateAccount_FullMethodName       = "/go_load.GoLoadService/CreateAccount"
-	GoLoadService_CreateSession_FullMethodName       = "/go_load.GoLoadService/CreateSession"
-	GoLoadService_CreateDownloadTask_FullMethodName  = "/go_load.GoLoadService/CreateDownloadTask"
-	GoLoadService_GetDownloadTaskList_FullMethodName = "/go_load.GoLoadService/GetDownloadTaskList"
-	GoLoadService_UpdateDownloadTask_FullMethodName  = "/go_load.GoLoadService/UpdateDownloadTask"
-	GoLoadService_DeleteDownloadTask_FullMethodName  = "/go_load.GoLoadService/DeleteDownloadTask"
-	GoLoadService_GetDownloadTaskFile_FullMethodName = "/go_load.GoLoadService/GetDownloadTaskFile"
+	GoLoadService_CreateAccount_FullMethodName                = "/go_load.GoLoadService/CreateAccount"
+	GoLoadService_CreateSession_FullMethodName                = "/go_load.GoLoadService/CreateSession"
+	GoLoadService_CreateDownloadTask_FullMethodName           = "/go_load.GoLoadService/CreateDownloadTask"
+	GoLoadService_GetDownloadTaskList_FullMethodName          = "/go_load.GoLoadService/GetDownloadTaskList"
+	GoLoadService_UpdateDownloadTask_FullMethodName           = "/go_load.GoLoadService/UpdateDownloadTask"
+	GoLoadService_DeleteDownloadTask_FullMethodName           = "/go_load.GoLoadService/DeleteDownloadTask"
+	GoLoadService_GetDownloadTaskFile_FullMethodName          = "/go_load.GoLoadService/GetDownloadTaskFile"
+	GoLoadService_GetDownloadTaskDigests_FullMethodName       = "/go_load.GoLoadService/GetDownloadTaskDigests"
+	GoLoadService_UpdateAccountSpeedLimit_FullMethodName      = "/go_load.GoLoadService/UpdateAccountSpeedLimit"
+	GoLoadService_CreateDownloadQueue_FullMethodName          = "/go_load.GoLoadService/CreateDownloadQueue"
+	GoLoadService_GetDownloadQueueList_FullMethodName         = "/go_load.GoLoadService/GetDownloadQueueList"
+	GoLoadService_UpdateDownloadQueue_FullMethodName          = "/go_load.GoLoadService/UpdateDownloadQueue"
+	GoLoadService_DeleteDownloadQueue_FullMethodName          = "/go_load.GoLoadService/DeleteDownloadQueue"
+	GoLoadService_MoveDownloadTask_FullMethodName             = "/go_load.GoLoadService/MoveDownloadTask"
+	GoLoadService_GetAccountShareList_FullMethodName          = "/go_load.GoLoadService/GetAccountShareList"
+	GoLoadService_GetDownloadTaskAttemptList_FullMethodName   = "/go_load.GoLoadService/GetDownloadTaskAttemptList"
+	GoLoadService_ImportMetalink_FullMethodName               = "/go_load.GoLoadService/ImportMetalink"
+	GoLoadService_ExportDownloadTaskMetalink_FullMethodName   = "/go_load.GoLoadService/ExportDownloadTaskMetalink"
+	GoLoadService_CreateWebhook_FullMethodName                = "/go_load.GoLoadService/CreateWebhook"
+	GoLoadService_GetWebhookList_FullMethodName               = "/go_load.GoLoadService/GetWebhookList"
+	GoLoadService_DeleteWebhook_FullMethodName                = "/go_load.GoLoadService/DeleteWebhook"
+	GoLoadService_GetWebhookDeliveryList_FullMethodName       = "/go_load.GoLoadService/GetWebhookDeliveryList"
+	GoLoadService_GetAccountStorageUsage_FullMethodName       = "/go_load.GoLoadService/GetAccountStorageUsage"
+	GoLoadService_GetDownloadTaskVersionList_FullMethodName   = "/go_load.GoLoadService/GetDownloadTaskVersionList"
+	GoLoadService_GrabLinks_FullMethodName                    = "/go_load.GoLoadService/GrabLinks"
+	GoLoadService_CreateDownloadTasksFromLinks_FullMethodName = "/go_load.GoLoadService/CreateDownloadTasksFromLinks"
 )
 
 // GoLoadServiceClient is the client API for GoLoadService service.
@@ -39,6 +58,25 @@ type GoLoadServiceClient interface {
 	UpdateDownloadTask(ctx context.Context, in *UpdateDownloadTaskRequest, opts ...grpc.CallOption) (*UpdateDownloadTaskResponse, error)
 	DeleteDownloadTask(ctx context.Context, in *DeleteDownloadTaskRequest, opts ...grpc.CallOption) (*DeleteDownloadTaskResponse, error)
 	GetDownloadTaskFile(ctx context.Context, in *GetDownloadTaskFileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetDownloadTaskFileResponse], error)
+	GetDownloadTaskDigests(ctx context.Context, in *GetDownloadTaskDigestsRequest, opts ...grpc.CallOption) (*GetDownloadTaskDigestsResponse, error)
+	UpdateAccountSpeedLimit(ctx context.Context, in *UpdateAccountSpeedLimitRequest, opts ...grpc.CallOption) (*UpdateAccountSpeedLimitResponse, error)
+	CreateDownloadQueue(ctx context.Context, in *CreateDownloadQueueRequest, opts ...grpc.CallOption) (*CreateDownloadQueueResponse, error)
+	GetDownloadQueueList(ctx context.Context, in *GetDownloadQueueListRequest, opts ...grpc.CallOption) (*GetDownloadQueueListResponse, error)
+	UpdateDownloadQueue(ctx context.Context, in *UpdateDownloadQueueRequest, opts ...grpc.CallOption) (*UpdateDownloadQueueResponse, error)
+	DeleteDownloadQueue(ctx context.Context, in *DeleteDownloadQueueRequest, opts ...grpc.CallOption) (*DeleteDownloadQueueResponse, error)
+	MoveDownloadTask(ctx context.Context, in *MoveDownloadTaskRequest, opts ...grpc.CallOption) (*MoveDownloadTaskResponse, error)
+	GetAccountShareList(ctx context.Context, in *GetAccountShareListRequest, opts ...grpc.CallOption) (*GetAccountShareListResponse, error)
+	GetDownloadTaskAttemptList(ctx context.Context, in *GetDownloadTaskAttemptListRequest, opts ...grpc.CallOption) (*GetDownloadTaskAttemptListResponse, error)
+	ImportMetalink(ctx context.Context, in *ImportMetalinkRequest, opts ...grpc.CallOption) (*ImportMetalinkResponse, error)
+	ExportDownloadTaskMetalink(ctx context.Context, in *ExportDownloadTaskMetalinkRequest, opts ...grpc.CallOption) (*ExportDownloadTaskMetalinkResponse, error)
+	CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*CreateWebhookResponse, error)
+	GetWebhookList(ctx context.Context, in *GetWebhookListRequest, opts ...grpc.CallOption) (*GetWebhookListResponse, error)
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error)
+	GetWebhookDeliveryList(ctx context.Context, in *GetWebhookDeliveryListRequest, opts ...grpc.CallOption) (*GetWebhookDeliveryListResponse, error)
+	GetAccountStorageUsage(ctx context.Context, in *GetAccountStorageUsageRequest, opts ...grpc.CallOption) (*GetAccountStorageUsageResponse, error)
+	GetDownloadTaskVersionList(ctx context.Context, in *GetDownloadTaskVersionListRequest, opts ...grpc.CallOption) (*GetDownloadTaskVersionListResponse, error)
+	GrabLinks(ctx context.Context, in *GrabLinksRequest, opts ...grpc.CallOption) (*GrabLinksResponse, error)
+	CreateDownloadTasksFromLinks(ctx context.Context, in *CreateDownloadTasksFromLinksRequest, opts ...grpc.CallOption) (*CreateDownloadTasksFromLinksResponse, error)
 }
 
 type goLoadServiceClient struct {
@@ -128,6 +166,196 @@ func (c *goLoadServiceClient) GetDownloadTaskFile(ctx context.Context, in *GetDo
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type GoLoadService_GetDownloadTaskFileClient = grpc.ServerStreamingClient[GetDownloadTaskFileResponse]
 
+func (c *goLoadServiceClient) GetDownloadTaskDigests(ctx context.Context, in *GetDownloadTaskDigestsRequest, opts ...grpc.CallOption) (*GetDownloadTaskDigestsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDownloadTaskDigestsResponse)
+	err := c.cc.Invoke(ctx, GoLoadService_GetDownloadTaskDigests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goLoadServiceClient) UpdateAccountSpeedLimit(ctx context.Context, in *UpdateAccountSpeedLimitRequest, opts ...grpc.CallOption) (*UpdateAccountSpeedLimitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateAccountSpeedLimitResponse)
+	err := c.cc.Invoke(ctx, GoLoadService_UpdateAccountSpeedLimit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goLoadServiceClient) CreateDownloadQueue(ctx context.Context, in *CreateDownloadQueueRequest, opts ...grpc.CallOption) (*CreateDownloadQueueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateDownloadQueueResponse)
+	err := c.cc.Invoke(ctx, GoLoadService_CreateDownloadQueue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goLoadServiceClient) GetDownloadQueueList(ctx context.Context, in *GetDownloadQueueListRequest, opts ...grpc.CallOption) (*GetDownloadQueueListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDownloadQueueListResponse)
+	err := c.cc.Invoke(ctx, GoLoadService_GetDownloadQueueList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goLoadServiceClient) UpdateDownloadQueue(ctx context.Context, in *UpdateDownloadQueueRequest, opts ...grpc.CallOption) (*UpdateDownloadQueueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateDownloadQueueResponse)
+	err := c.cc.Invoke(ctx, GoLoadService_UpdateDownloadQueue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goLoadServiceClient) DeleteDownloadQueue(ctx context.Context, in *DeleteDownloadQueueRequest, opts ...grpc.CallOption) (*DeleteDownloadQueueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteDownloadQueueResponse)
+	err := c.cc.Invoke(ctx, GoLoadService_DeleteDownloadQueue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goLoadServiceClient) MoveDownloadTask(ctx context.Context, in *MoveDownloadTaskRequest, opts ...grpc.CallOption) (*MoveDownloadTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MoveDownloadTaskResponse)
+	err := c.cc.Invoke(ctx, GoLoadService_MoveDownloadTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goLoadServiceClient) GetAccountShareList(ctx context.Context, in *GetAccountShareListRequest, opts ...grpc.CallOption) (*GetAccountShareListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAccountShareListResponse)
+	err := c.cc.Invoke(ctx, GoLoadService_GetAccountShareList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goLoadServiceClient) GetDownloadTaskAttemptList(ctx context.Context, in *GetDownloadTaskAttemptListRequest, opts ...grpc.CallOption) (*GetDownloadTaskAttemptListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDownloadTaskAttemptListResponse)
+	err := c.cc.Invoke(ctx, GoLoadService_GetDownloadTaskAttemptList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goLoadServiceClient) ImportMetalink(ctx context.Context, in *ImportMetalinkRequest, opts ...grpc.CallOption) (*ImportMetalinkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportMetalinkResponse)
+	err := c.cc.Invoke(ctx, GoLoadService_ImportMetalink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goLoadServiceClient) ExportDownloadTaskMetalink(ctx context.Context, in *ExportDownloadTaskMetalinkRequest, opts ...grpc.CallOption) (*ExportDownloadTaskMetalinkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportDownloadTaskMetalinkResponse)
+	err := c.cc.Invoke(ctx, GoLoadService_ExportDownloadTaskMetalink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goLoadServiceClient) CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*CreateWebhookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateWebhookResponse)
+	err := c.cc.Invoke(ctx, GoLoadService_CreateWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goLoadServiceClient) GetWebhookList(ctx context.Context, in *GetWebhookListRequest, opts ...grpc.CallOption) (*GetWebhookListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetWebhookListResponse)
+	err := c.cc.Invoke(ctx, GoLoadService_GetWebhookList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goLoadServiceClient) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteWebhookResponse)
+	err := c.cc.Invoke(ctx, GoLoadService_DeleteWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goLoadServiceClient) GetWebhookDeliveryList(ctx context.Context, in *GetWebhookDeliveryListRequest, opts ...grpc.CallOption) (*GetWebhookDeliveryListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetWebhookDeliveryListResponse)
+	err := c.cc.Invoke(ctx, GoLoadService_GetWebhookDeliveryList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goLoadServiceClient) GetAccountStorageUsage(ctx context.Context, in *GetAccountStorageUsageRequest, opts ...grpc.CallOption) (*GetAccountStorageUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAccountStorageUsageResponse)
+	err := c.cc.Invoke(ctx, GoLoadService_GetAccountStorageUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goLoadServiceClient) GetDownloadTaskVersionList(ctx context.Context, in *GetDownloadTaskVersionListRequest, opts ...grpc.CallOption) (*GetDownloadTaskVersionListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDownloadTaskVersionListResponse)
+	err := c.cc.Invoke(ctx, GoLoadService_GetDownloadTaskVersionList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goLoadServiceClient) GrabLinks(ctx context.Context, in *GrabLinksRequest, opts ...grpc.CallOption) (*GrabLinksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GrabLinksResponse)
+	err := c.cc.Invoke(ctx, GoLoadService_GrabLinks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goLoadServiceClient) CreateDownloadTasksFromLinks(ctx context.Context, in *CreateDownloadTasksFromLinksRequest, opts ...grpc.CallOption) (*CreateDownloadTasksFromLinksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateDownloadTasksFromLinksResponse)
+	err := c.cc.Invoke(ctx, GoLoadService_CreateDownloadTasksFromLinks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GoLoadServiceServer is the server API for GoLoadService service.
 // All implementations must embed UnimplementedGoLoadServiceServer
 // for forward compatibility.
@@ -139,6 +367,25 @@ type GoLoadServiceServer interface {
 	UpdateDownloadTask(context.Context, *UpdateDownloadTaskRequest) (*UpdateDownloadTaskResponse, error)
 	DeleteDownloadTask(context.Context, *DeleteDownloadTaskRequest) (*DeleteDownloadTaskResponse, error)
 	GetDownloadTaskFile(*GetDownloadTaskFileRequest, grpc.ServerStreamingServer[GetDownloadTaskFileResponse]) error
+	GetDownloadTaskDigests(context.Context, *GetDownloadTaskDigestsRequest) (*GetDownloadTaskDigestsResponse, error)
+	UpdateAccountSpeedLimit(context.Context, *UpdateAccountSpeedLimitRequest) (*UpdateAccountSpeedLimitResponse, error)
+	CreateDownloadQueue(context.Context, *CreateDownloadQueueRequest) (*CreateDownloadQueueResponse, error)
+	GetDownloadQueueList(context.Context, *GetDownloadQueueListRequest) (*GetDownloadQueueListResponse, error)
+	UpdateDownloadQueue(context.Context, *UpdateDownloadQueueRequest) (*UpdateDownloadQueueResponse, error)
+	DeleteDownloadQueue(context.Context, *DeleteDownloadQueueRequest) (*DeleteDownloadQueueResponse, error)
+	MoveDownloadTask(context.Context, *MoveDownloadTaskRequest) (*MoveDownloadTaskResponse, error)
+	GetAccountShareList(context.Context, *GetAccountShareListRequest) (*GetAccountShareListResponse, error)
+	GetDownloadTaskAttemptList(context.Context, *GetDownloadTaskAttemptListRequest) (*GetDownloadTaskAttemptListResponse, error)
+	ImportMetalink(context.Context, *ImportMetalinkRequest) (*ImportMetalinkResponse, error)
+	ExportDownloadTaskMetalink(context.Context, *ExportDownloadTaskMetalinkRequest) (*ExportDownloadTaskMetalinkResponse, error)
+	CreateWebhook(context.Context, *CreateWebhookRequest) (*CreateWebhookResponse, error)
+	GetWebhookList(context.Context, *GetWebhookListRequest) (*GetWebhookListResponse, error)
+	DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error)
+	GetWebhookDeliveryList(context.Context, *GetWebhookDeliveryListRequest) (*GetWebhookDeliveryListResponse, error)
+	GetAccountStorageUsage(context.Context, *GetAccountStorageUsageRequest) (*GetAccountStorageUsageResponse, error)
+	GetDownloadTaskVersionList(context.Context, *GetDownloadTaskVersionListRequest) (*GetDownloadTaskVersionListResponse, error)
+	GrabLinks(context.Context, *GrabLinksRequest) (*GrabLinksResponse, error)
+	CreateDownloadTasksFromLinks(context.Context, *CreateDownloadTasksFromLinksRequest) (*CreateDownloadTasksFromLinksResponse, error)
 	mustEmbedUnimplementedGoLoadServiceServer()
 }
 
@@ -170,6 +417,63 @@ func (UnimplementedGoLoadServiceServer) DeleteDownloadTask(context.Context, *Del
 func (UnimplementedGoLoadServiceServer) GetDownloadTaskFile(*GetDownloadTaskFileRequest, grpc.ServerStreamingServer[GetDownloadTaskFileResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method GetDownloadTaskFile not implemented")
 }
+func (UnimplementedGoLoadServiceServer) GetDownloadTaskDigests(context.Context, *GetDownloadTaskDigestsRequest) (*GetDownloadTaskDigestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDownloadTaskDigests not implemented")
+}
+func (UnimplementedGoLoadServiceServer) UpdateAccountSpeedLimit(context.Context, *UpdateAccountSpeedLimitRequest) (*UpdateAccountSpeedLimitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAccountSpeedLimit not implemented")
+}
+func (UnimplementedGoLoadServiceServer) CreateDownloadQueue(context.Context, *CreateDownloadQueueRequest) (*CreateDownloadQueueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDownloadQueue not implemented")
+}
+func (UnimplementedGoLoadServiceServer) GetDownloadQueueList(context.Context, *GetDownloadQueueListRequest) (*GetDownloadQueueListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDownloadQueueList not implemented")
+}
+func (UnimplementedGoLoadServiceServer) UpdateDownloadQueue(context.Context, *UpdateDownloadQueueRequest) (*UpdateDownloadQueueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDownloadQueue not implemented")
+}
+func (UnimplementedGoLoadServiceServer) DeleteDownloadQueue(context.Context, *DeleteDownloadQueueRequest) (*DeleteDownloadQueueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDownloadQueue not implemented")
+}
+func (UnimplementedGoLoadServiceServer) MoveDownloadTask(context.Context, *MoveDownloadTaskRequest) (*MoveDownloadTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveDownloadTask not implemented")
+}
+func (UnimplementedGoLoadServiceServer) GetAccountShareList(context.Context, *GetAccountShareListRequest) (*GetAccountShareListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountShareList not implemented")
+}
+func (UnimplementedGoLoadServiceServer) GetDownloadTaskAttemptList(context.Context, *GetDownloadTaskAttemptListRequest) (*GetDownloadTaskAttemptListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDownloadTaskAttemptList not implemented")
+}
+func (UnimplementedGoLoadServiceServer) ImportMetalink(context.Context, *ImportMetalinkRequest) (*ImportMetalinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportMetalink not implemented")
+}
+func (UnimplementedGoLoadServiceServer) ExportDownloadTaskMetalink(context.Context, *ExportDownloadTaskMetalinkRequest) (*ExportDownloadTaskMetalinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportDownloadTaskMetalink not implemented")
+}
+func (UnimplementedGoLoadServiceServer) CreateWebhook(context.Context, *CreateWebhookRequest) (*CreateWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (UnimplementedGoLoadServiceServer) GetWebhookList(context.Context, *GetWebhookListRequest) (*GetWebhookListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWebhookList not implemented")
+}
+func (UnimplementedGoLoadServiceServer) DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedGoLoadServiceServer) GetWebhookDeliveryList(context.Context, *GetWebhookDeliveryListRequest) (*GetWebhookDeliveryListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWebhookDeliveryList not implemented")
+}
+func (UnimplementedGoLoadServiceServer) GetAccountStorageUsage(context.Context, *GetAccountStorageUsageRequest) (*GetAccountStorageUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountStorageUsage not implemented")
+}
+func (UnimplementedGoLoadServiceServer) GetDownloadTaskVersionList(context.Context, *GetDownloadTaskVersionListRequest) (*GetDownloadTaskVersionListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDownloadTaskVersionList not implemented")
+}
+func (UnimplementedGoLoadServiceServer) GrabLinks(context.Context, *GrabLinksRequest) (*GrabLinksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GrabLinks not implemented")
+}
+func (UnimplementedGoLoadServiceServer) CreateDownloadTasksFromLinks(context.Context, *CreateDownloadTasksFromLinksRequest) (*CreateDownloadTasksFromLinksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDownloadTasksFromLinks not implemented")
+}
 func (UnimplementedGoLoadServiceServer) mustEmbedUnimplementedGoLoadServiceServer() {}
 func (UnimplementedGoLoadServiceServer) testEmbeddedByValue()                       {}
 
@@ -310,6 +614,348 @@ func _GoLoadService_GetDownloadTaskFile_Handler(srv interface{}, stream grpc.Ser
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type GoLoadService_GetDownloadTaskFileServer = grpc.ServerStreamingServer[GetDownloadTaskFileResponse]
 
+func _GoLoadService_GetDownloadTaskDigests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDownloadTaskDigestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoLoadServiceServer).GetDownloadTaskDigests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoLoadService_GetDownloadTaskDigests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoLoadServiceServer).GetDownloadTaskDigests(ctx, req.(*GetDownloadTaskDigestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoLoadService_UpdateAccountSpeedLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAccountSpeedLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoLoadServiceServer).UpdateAccountSpeedLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoLoadService_UpdateAccountSpeedLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoLoadServiceServer).UpdateAccountSpeedLimit(ctx, req.(*UpdateAccountSpeedLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoLoadService_CreateDownloadQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDownloadQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoLoadServiceServer).CreateDownloadQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoLoadService_CreateDownloadQueue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoLoadServiceServer).CreateDownloadQueue(ctx, req.(*CreateDownloadQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoLoadService_GetDownloadQueueList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDownloadQueueListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoLoadServiceServer).GetDownloadQueueList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoLoadService_GetDownloadQueueList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoLoadServiceServer).GetDownloadQueueList(ctx, req.(*GetDownloadQueueListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoLoadService_UpdateDownloadQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDownloadQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoLoadServiceServer).UpdateDownloadQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoLoadService_UpdateDownloadQueue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoLoadServiceServer).UpdateDownloadQueue(ctx, req.(*UpdateDownloadQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoLoadService_DeleteDownloadQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDownloadQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoLoadServiceServer).DeleteDownloadQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoLoadService_DeleteDownloadQueue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoLoadServiceServer).DeleteDownloadQueue(ctx, req.(*DeleteDownloadQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoLoadService_MoveDownloadTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveDownloadTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoLoadServiceServer).MoveDownloadTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoLoadService_MoveDownloadTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoLoadServiceServer).MoveDownloadTask(ctx, req.(*MoveDownloadTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoLoadService_GetAccountShareList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountShareListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoLoadServiceServer).GetAccountShareList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoLoadService_GetAccountShareList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoLoadServiceServer).GetAccountShareList(ctx, req.(*GetAccountShareListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoLoadService_GetDownloadTaskAttemptList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDownloadTaskAttemptListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoLoadServiceServer).GetDownloadTaskAttemptList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoLoadService_GetDownloadTaskAttemptList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoLoadServiceServer).GetDownloadTaskAttemptList(ctx, req.(*GetDownloadTaskAttemptListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoLoadService_ImportMetalink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportMetalinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoLoadServiceServer).ImportMetalink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoLoadService_ImportMetalink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoLoadServiceServer).ImportMetalink(ctx, req.(*ImportMetalinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoLoadService_ExportDownloadTaskMetalink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportDownloadTaskMetalinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoLoadServiceServer).ExportDownloadTaskMetalink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoLoadService_ExportDownloadTaskMetalink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoLoadServiceServer).ExportDownloadTaskMetalink(ctx, req.(*ExportDownloadTaskMetalinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoLoadService_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoLoadServiceServer).CreateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoLoadService_CreateWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoLoadServiceServer).CreateWebhook(ctx, req.(*CreateWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoLoadService_GetWebhookList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWebhookListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoLoadServiceServer).GetWebhookList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoLoadService_GetWebhookList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoLoadServiceServer).GetWebhookList(ctx, req.(*GetWebhookListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoLoadService_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoLoadServiceServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoLoadService_DeleteWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoLoadServiceServer).DeleteWebhook(ctx, req.(*DeleteWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoLoadService_GetWebhookDeliveryList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWebhookDeliveryListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoLoadServiceServer).GetWebhookDeliveryList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoLoadService_GetWebhookDeliveryList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoLoadServiceServer).GetWebhookDeliveryList(ctx, req.(*GetWebhookDeliveryListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoLoadService_GetAccountStorageUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountStorageUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoLoadServiceServer).GetAccountStorageUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoLoadService_GetAccountStorageUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoLoadServiceServer).GetAccountStorageUsage(ctx, req.(*GetAccountStorageUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoLoadService_GetDownloadTaskVersionList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDownloadTaskVersionListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoLoadServiceServer).GetDownloadTaskVersionList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoLoadService_GetDownloadTaskVersionList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoLoadServiceServer).GetDownloadTaskVersionList(ctx, req.(*GetDownloadTaskVersionListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoLoadService_GrabLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrabLinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoLoadServiceServer).GrabLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoLoadService_GrabLinks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoLoadServiceServer).GrabLinks(ctx, req.(*GrabLinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoLoadService_CreateDownloadTasksFromLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDownloadTasksFromLinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoLoadServiceServer).CreateDownloadTasksFromLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoLoadService_CreateDownloadTasksFromLinks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoLoadServiceServer).CreateDownloadTasksFromLinks(ctx, req.(*CreateDownloadTasksFromLinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // GoLoadService_ServiceDesc is the grpc.ServiceDesc for GoLoadService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -341,6 +987,82 @@ var GoLoadService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteDownloadTask",
 			Handler:    _GoLoadService_DeleteDownloadTask_Handler,
 		},
+		{
+			MethodName: "GetDownloadTaskDigests",
+			Handler:    _GoLoadService_GetDownloadTaskDigests_Handler,
+		},
+		{
+			MethodName: "UpdateAccountSpeedLimit",
+			Handler:    _GoLoadService_UpdateAccountSpeedLimit_Handler,
+		},
+		{
+			MethodName: "CreateDownloadQueue",
+			Handler:    _GoLoadService_CreateDownloadQueue_Handler,
+		},
+		{
+			MethodName: "GetDownloadQueueList",
+			Handler:    _GoLoadService_GetDownloadQueueList_Handler,
+		},
+		{
+			MethodName: "UpdateDownloadQueue",
+			Handler:    _GoLoadService_UpdateDownloadQueue_Handler,
+		},
+		{
+			MethodName: "DeleteDownloadQueue",
+			Handler:    _GoLoadService_DeleteDownloadQueue_Handler,
+		},
+		{
+			MethodName: "MoveDownloadTask",
+			Handler:    _GoLoadService_MoveDownloadTask_Handler,
+		},
+		{
+			MethodName: "GetAccountShareList",
+			Handler:    _GoLoadService_GetAccountShareList_Handler,
+		},
+		{
+			MethodName: "GetDownloadTaskAttemptList",
+			Handler:    _GoLoadService_GetDownloadTaskAttemptList_Handler,
+		},
+		{
+			MethodName: "ImportMetalink",
+			Handler:    _GoLoadService_ImportMetalink_Handler,
+		},
+		{
+			MethodName: "ExportDownloadTaskMetalink",
+			Handler:    _GoLoadService_ExportDownloadTaskMetalink_Handler,
+		},
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _GoLoadService_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "GetWebhookList",
+			Handler:    _GoLoadService_GetWebhookList_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _GoLoadService_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "GetWebhookDeliveryList",
+			Handler:    _GoLoadService_GetWebhookDeliveryList_Handler,
+		},
+		{
+			MethodName: "GetAccountStorageUsage",
+			Handler:    _GoLoadService_GetAccountStorageUsage_Handler,
+		},
+		{
+			MethodName: "GetDownloadTaskVersionList",
+			Handler:    _GoLoadService_GetDownloadTaskVersionList_Handler,
+		},
+		{
+			MethodName: "GrabLinks",
+			Handler:    _GoLoadService_GrabLinks_Handler,
+		},
+		{
+			MethodName: "CreateDownloadTasksFromLinks",
+			Handler:    _GoLoadService_CreateDownloadTasksFromLinks_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
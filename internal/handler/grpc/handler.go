@@ -2,20 +2,41 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"io"
+	"time"
 
 	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
 	"github.com/quockhanhcao/my-internet-download-manager/internal/logic"
 	"google.golang.org/grpc"
 )
 
+const (
+	downloadTaskFileChunkSize = 1024 * 32
+)
+
 type Handler struct {
 	go_load.UnimplementedGoLoadServiceServer
-	accountHandler logic.AccountHandler
+	accountHandler       logic.AccountHandler
+	downloadTaskHandler  logic.DownloadTaskHandler
+	downloadQueueHandler logic.DownloadQueueHandler
+	fairShareHandler     logic.FairShareHandler
+	webhookHandler       logic.WebhookHandler
 }
 
-func NewHandler(accountHandler logic.AccountHandler) go_load.GoLoadServiceServer {
+func NewHandler(
+	accountHandler logic.AccountHandler,
+	downloadTaskHandler logic.DownloadTaskHandler,
+	downloadQueueHandler logic.DownloadQueueHandler,
+	fairShareHandler logic.FairShareHandler,
+	webhookHandler logic.WebhookHandler,
+) go_load.GoLoadServiceServer {
 	return &Handler{
-		accountHandler: accountHandler,
+		accountHandler:       accountHandler,
+		downloadTaskHandler:  downloadTaskHandler,
+		downloadQueueHandler: downloadQueueHandler,
+		fairShareHandler:     fairShareHandler,
+		webhookHandler:       webhookHandler,
 	}
 }
 
@@ -33,29 +54,426 @@ func (h Handler) CreateAccount(ctx context.Context, request *go_load.CreateAccou
 }
 
 // CreateDownloadTask implements go_load.GoLoadServiceServer.
-func (h *Handler) CreateDownloadTask(context.Context, *go_load.CreateDownloadTaskRequest) (*go_load.CreateDownloadTaskResponse, error) {
-	panic("unimplemented")
+func (h *Handler) CreateDownloadTask(ctx context.Context, request *go_load.CreateDownloadTaskRequest) (*go_load.CreateDownloadTaskResponse, error) {
+	var startAt time.Time
+	if request.GetStartAt() != nil {
+		startAt = request.GetStartAt().AsTime()
+	}
+
+	output, err := h.downloadTaskHandler.CreateDownloadTask(ctx, logic.CreateDownloadTaskParams{
+		Token:                 request.GetToken(),
+		DownloadType:          request.GetDownloadType(),
+		URL:                   request.GetUrl(),
+		SegmentCount:          request.GetSegmentCount(),
+		MinSegmentSize:        request.GetMinSegmentSize(),
+		SFTPOptions:           request.GetSftpOptions(),
+		StreamOptions:         request.GetStreamOptions(),
+		TorrentOptions:        request.GetTorrentOptions(),
+		S3Options:             request.GetS3Options(),
+		ExpectedDigest:        request.GetExpectedDigest(),
+		SpeedLimitBytesPerSec: request.GetSpeedLimitBytesPerSec(),
+		DownloadQueueID:       request.GetDownloadQueueId(),
+		StartAt:               startAt,
+		CronExpression:        request.GetCronExpression(),
+		TimeZone:              request.GetTimeZone(),
+		MaxAttemptCount:       request.GetMaxAttemptCount(),
+		MirrorURLs:            request.GetMirrorUrls(),
+		ExpectedSize:          request.GetExpectedSize(),
+		PieceDigests:          request.GetPieceDigests(),
+		ExtractArchive:        request.GetExtractArchive(),
+		DuplicateURLMode:      request.GetDuplicateUrlMode(),
+		WatchOptions:          request.GetWatchOptions(),
+		CrawlOptions:          request.GetCrawlOptions(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.CreateDownloadTaskResponse{
+		DownloadTask:      output.DownloadTask,
+		DuplicateDownload: output.DuplicateDownload,
+	}, nil
 }
 
 // CreateSession implements go_load.GoLoadServiceServer.
-func (h *Handler) CreateSession(context.Context, *go_load.CreateSessionRequest) (*go_load.CreateSessionResponse, error) {
-	panic("unimplemented")
+func (h *Handler) CreateSession(ctx context.Context, request *go_load.CreateSessionRequest) (*go_load.CreateSessionResponse, error) {
+	token, err := h.accountHandler.CreateSession(ctx, logic.CreateSessionParams{
+		AccountName: request.GetAccountName(),
+		Password:    request.GetPassword(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.CreateSessionResponse{
+		Token: token,
+	}, nil
 }
 
 // DeleteDownloadTask implements go_load.GoLoadServiceServer.
-func (h *Handler) DeleteDownloadTask(context.Context, *go_load.DeleteDownloadTaskRequest) (*go_load.DeleteDownloadTaskResponse, error) {
-	panic("unimplemented")
+func (h *Handler) DeleteDownloadTask(ctx context.Context, request *go_load.DeleteDownloadTaskRequest) (*go_load.DeleteDownloadTaskResponse, error) {
+	err := h.downloadTaskHandler.DeleteDownloadTask(ctx, logic.DeleteDownloadTaskParams{
+		Token:          request.GetToken(),
+		DownloadTaskID: request.GetDownloadTask().GetId(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.DeleteDownloadTaskResponse{}, nil
 }
-func (h *Handler) GetDownloadTaskFile(*go_load.GetDownloadTaskFileRequest, grpc.ServerStreamingServer[go_load.GetDownloadTaskFileResponse]) error {
-	panic("unimplemented")
+
+// GetDownloadTaskFile implements go_load.GoLoadServiceServer.
+func (h *Handler) GetDownloadTaskFile(request *go_load.GetDownloadTaskFileRequest, stream grpc.ServerStreamingServer[go_load.GetDownloadTaskFileResponse]) error {
+	reader, err := h.downloadTaskHandler.GetDownloadTaskFile(stream.Context(), logic.GetDownloadTaskFileParams{
+		Token:          request.GetToken(),
+		DownloadTaskID: request.GetDownloadTaskId(),
+		FilePath:       request.GetFilePath(),
+		VersionNumber:  request.GetVersionNumber(),
+	})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	buffer := make([]byte, downloadTaskFileChunkSize)
+	for {
+		readByteCount, readErr := reader.Read(buffer)
+		if readByteCount > 0 {
+			err = stream.Send(&go_load.GetDownloadTaskFileResponse{
+				Data: buffer[:readByteCount],
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if readErr != nil {
+			if errors.Is(readErr, io.EOF) {
+				return nil
+			}
+			return readErr
+		}
+	}
 }
 
 // GetDownloadTaskList implements go_load.GoLoadServiceServer.
-func (h *Handler) GetDownloadTaskList(context.Context, *go_load.GetDownloadTaskListRequest) (*go_load.GetDownloadTaskListResponse, error) {
-	panic("unimplemented")
+func (h *Handler) GetDownloadTaskList(ctx context.Context, request *go_load.GetDownloadTaskListRequest) (*go_load.GetDownloadTaskListResponse, error) {
+	output, err := h.downloadTaskHandler.GetDownloadTaskList(ctx, logic.GetDownloadTaskListParams{
+		Token:  request.GetToken(),
+		Offset: request.GetOffset(),
+		Limit:  request.GetLimit(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.GetDownloadTaskListResponse{
+		DownloadTaskList:       output.DownloadTaskList,
+		TotalDownloadTaskCount: output.TotalDownloadTaskCount,
+	}, nil
 }
 
 // UpdateDownloadTask implements go_load.GoLoadServiceServer.
-func (h *Handler) UpdateDownloadTask(context.Context, *go_load.UpdateDownloadTaskRequest) (*go_load.UpdateDownloadTaskResponse, error) {
-	panic("unimplemented")
+func (h *Handler) UpdateDownloadTask(ctx context.Context, request *go_load.UpdateDownloadTaskRequest) (*go_load.UpdateDownloadTaskResponse, error) {
+	var mirrorURLs []string
+	if request.GetMirrorUrlList() != nil {
+		// an empty list removes all mirrors, so it has to be told apart from no list
+		mirrorURLs = append([]string{}, request.GetMirrorUrlList().GetUrls()...)
+	}
+
+	downloadTask, err := h.downloadTaskHandler.UpdateDownloadTask(ctx, logic.UpdateDownloadTaskParams{
+		Token:                 request.GetToken(),
+		DownloadTaskID:        request.GetDownloadTaskId(),
+		URL:                   request.GetUrl(),
+		SpeedLimitBytesPerSec: request.SpeedLimitBytesPerSec,
+		MirrorURLs:            mirrorURLs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.UpdateDownloadTaskResponse{
+		DownloadTask: downloadTask,
+	}, nil
+}
+
+// GetDownloadTaskDigests implements go_load.GoLoadServiceServer.
+func (h *Handler) GetDownloadTaskDigests(ctx context.Context, request *go_load.GetDownloadTaskDigestsRequest) (*go_load.GetDownloadTaskDigestsResponse, error) {
+	digests, err := h.downloadTaskHandler.GetDownloadTaskDigests(ctx, logic.GetDownloadTaskDigestsParams{
+		Token:          request.GetToken(),
+		DownloadTaskID: request.GetDownloadTaskId(),
+		FilePath:       request.GetFilePath(),
+		Algorithms:     request.GetAlgorithms(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.GetDownloadTaskDigestsResponse{
+		Digests: digests,
+	}, nil
+}
+
+// UpdateAccountSpeedLimit implements go_load.GoLoadServiceServer.
+func (h *Handler) UpdateAccountSpeedLimit(ctx context.Context, request *go_load.UpdateAccountSpeedLimitRequest) (*go_load.UpdateAccountSpeedLimitResponse, error) {
+	account, err := h.accountHandler.UpdateAccountSpeedLimit(ctx, logic.UpdateAccountSpeedLimitParams{
+		Token:                 request.GetToken(),
+		SpeedLimitBytesPerSec: request.GetSpeedLimitBytesPerSec(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.UpdateAccountSpeedLimitResponse{
+		Account: &go_load.Account{
+			Id:                    account.AccountID,
+			AccountName:           account.AccountName,
+			SpeedLimitBytesPerSec: account.SpeedLimitBytesPerSec,
+		},
+	}, nil
+}
+
+// CreateDownloadQueue implements go_load.GoLoadServiceServer.
+func (h *Handler) CreateDownloadQueue(ctx context.Context, request *go_load.CreateDownloadQueueRequest) (*go_load.CreateDownloadQueueResponse, error) {
+	downloadQueue, err := h.downloadQueueHandler.CreateDownloadQueue(ctx, logic.CreateDownloadQueueParams{
+		Token:                      request.GetToken(),
+		Name:                       request.GetName(),
+		Priority:                   request.GetPriority(),
+		MaxConcurrentDownloadCount: request.GetMaxConcurrentDownloadCount(),
+		ActiveStartTime:            request.GetActiveStartTime(),
+		ActiveEndTime:              request.GetActiveEndTime(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.CreateDownloadQueueResponse{
+		DownloadQueue: downloadQueue,
+	}, nil
+}
+
+// GetDownloadQueueList implements go_load.GoLoadServiceServer.
+func (h *Handler) GetDownloadQueueList(ctx context.Context, request *go_load.GetDownloadQueueListRequest) (*go_load.GetDownloadQueueListResponse, error) {
+	downloadQueueList, err := h.downloadQueueHandler.GetDownloadQueueList(ctx, logic.GetDownloadQueueListParams{
+		Token: request.GetToken(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.GetDownloadQueueListResponse{
+		DownloadQueueList: downloadQueueList,
+	}, nil
+}
+
+// UpdateDownloadQueue implements go_load.GoLoadServiceServer.
+func (h *Handler) UpdateDownloadQueue(ctx context.Context, request *go_load.UpdateDownloadQueueRequest) (*go_load.UpdateDownloadQueueResponse, error) {
+	downloadQueue, err := h.downloadQueueHandler.UpdateDownloadQueue(ctx, logic.UpdateDownloadQueueParams{
+		Token:                      request.GetToken(),
+		DownloadQueueID:            request.GetDownloadQueueId(),
+		Name:                       request.GetName(),
+		Priority:                   request.GetPriority(),
+		MaxConcurrentDownloadCount: request.GetMaxConcurrentDownloadCount(),
+		ActiveStartTime:            request.GetActiveStartTime(),
+		ActiveEndTime:              request.GetActiveEndTime(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.UpdateDownloadQueueResponse{
+		DownloadQueue: downloadQueue,
+	}, nil
+}
+
+// DeleteDownloadQueue implements go_load.GoLoadServiceServer.
+func (h *Handler) DeleteDownloadQueue(ctx context.Context, request *go_load.DeleteDownloadQueueRequest) (*go_load.DeleteDownloadQueueResponse, error) {
+	err := h.downloadQueueHandler.DeleteDownloadQueue(ctx, logic.DeleteDownloadQueueParams{
+		Token:           request.GetToken(),
+		DownloadQueueID: request.GetDownloadQueueId(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.DeleteDownloadQueueResponse{}, nil
+}
+
+// MoveDownloadTask implements go_load.GoLoadServiceServer.
+func (h *Handler) MoveDownloadTask(ctx context.Context, request *go_load.MoveDownloadTaskRequest) (*go_load.MoveDownloadTaskResponse, error) {
+	downloadTask, err := h.downloadTaskHandler.MoveDownloadTask(ctx, logic.MoveDownloadTaskParams{
+		Token:           request.GetToken(),
+		DownloadTaskID:  request.GetDownloadTaskId(),
+		DownloadQueueID: request.GetDownloadQueueId(),
+		Position:        request.GetPosition(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.MoveDownloadTaskResponse{
+		DownloadTask: downloadTask,
+	}, nil
+}
+
+// GetAccountShareList implements go_load.GoLoadServiceServer.
+func (h *Handler) GetAccountShareList(ctx context.Context, request *go_load.GetAccountShareListRequest) (*go_load.GetAccountShareListResponse, error) {
+	accountShareList, err := h.fairShareHandler.GetAccountShareList(ctx, logic.GetAccountShareListParams{
+		Token: request.GetToken(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.GetAccountShareListResponse{
+		AccountShareList: accountShareList,
+	}, nil
+}
+
+// GetDownloadTaskAttemptList implements go_load.GoLoadServiceServer.
+func (h *Handler) GetDownloadTaskAttemptList(ctx context.Context, request *go_load.GetDownloadTaskAttemptListRequest) (*go_load.GetDownloadTaskAttemptListResponse, error) {
+	downloadTaskAttemptList, err := h.downloadTaskHandler.GetDownloadTaskAttemptList(ctx, logic.GetDownloadTaskAttemptListParams{
+		Token:          request.GetToken(),
+		DownloadTaskID: request.GetDownloadTaskId(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.GetDownloadTaskAttemptListResponse{
+		DownloadTaskAttemptList: downloadTaskAttemptList,
+	}, nil
+}
+
+// ImportMetalink implements go_load.GoLoadServiceServer.
+func (h *Handler) ImportMetalink(ctx context.Context, request *go_load.ImportMetalinkRequest) (*go_load.ImportMetalinkResponse, error) {
+	downloadTaskList, err := h.downloadTaskHandler.ImportMetalink(ctx, logic.ImportMetalinkParams{
+		Token:           request.GetToken(),
+		Metalink:        request.GetMetalink(),
+		DownloadQueueID: request.GetDownloadQueueId(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.ImportMetalinkResponse{
+		DownloadTaskList: downloadTaskList,
+	}, nil
+}
+
+// ExportDownloadTaskMetalink implements go_load.GoLoadServiceServer.
+func (h *Handler) ExportDownloadTaskMetalink(ctx context.Context, request *go_load.ExportDownloadTaskMetalinkRequest) (*go_load.ExportDownloadTaskMetalinkResponse, error) {
+	metalink, err := h.downloadTaskHandler.ExportDownloadTaskMetalink(ctx, logic.ExportDownloadTaskMetalinkParams{
+		Token:          request.GetToken(),
+		DownloadTaskID: request.GetDownloadTaskId(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.ExportDownloadTaskMetalinkResponse{
+		Metalink: metalink,
+	}, nil
+}
+
+// CreateWebhook implements go_load.GoLoadServiceServer.
+func (h *Handler) CreateWebhook(ctx context.Context, request *go_load.CreateWebhookRequest) (*go_load.CreateWebhookResponse, error) {
+	output, err := h.webhookHandler.CreateWebhook(ctx, logic.CreateWebhookParams{
+		Token:      request.GetToken(),
+		URL:        request.GetUrl(),
+		EventTypes: request.GetEventTypes(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.CreateWebhookResponse{
+		Webhook: output.Webhook,
+		Secret:  output.Secret,
+	}, nil
+}
+
+// GetWebhookList implements go_load.GoLoadServiceServer.
+func (h *Handler) GetWebhookList(ctx context.Context, request *go_load.GetWebhookListRequest) (*go_load.GetWebhookListResponse, error) {
+	webhookList, err := h.webhookHandler.GetWebhookList(ctx, logic.GetWebhookListParams{
+		Token: request.GetToken(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.GetWebhookListResponse{
+		WebhookList: webhookList,
+	}, nil
+}
+
+// DeleteWebhook implements go_load.GoLoadServiceServer.
+func (h *Handler) DeleteWebhook(ctx context.Context, request *go_load.DeleteWebhookRequest) (*go_load.DeleteWebhookResponse, error) {
+	err := h.webhookHandler.DeleteWebhook(ctx, logic.DeleteWebhookParams{
+		Token:     request.GetToken(),
+		WebhookID: request.GetWebhookId(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.DeleteWebhookResponse{}, nil
+}
+
+// GetAccountStorageUsage implements go_load.GoLoadServiceServer.
+func (h *Handler) GetAccountStorageUsage(ctx context.Context, request *go_load.GetAccountStorageUsageRequest) (*go_load.GetAccountStorageUsageResponse, error) {
+	output, err := h.downloadTaskHandler.GetAccountStorageUsage(ctx, logic.GetAccountStorageUsageParams{
+		Token: request.GetToken(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.GetAccountStorageUsageResponse{
+		LogicalByteCount:  output.LogicalByteCount,
+		PhysicalByteCount: output.PhysicalByteCount,
+	}, nil
+}
+
+// GetWebhookDeliveryList implements go_load.GoLoadServiceServer.
+func (h *Handler) GetWebhookDeliveryList(ctx context.Context, request *go_load.GetWebhookDeliveryListRequest) (*go_load.GetWebhookDeliveryListResponse, error) {
+	output, err := h.webhookHandler.GetWebhookDeliveryList(ctx, logic.GetWebhookDeliveryListParams{
+		Token:     request.GetToken(),
+		WebhookID: request.GetWebhookId(),
+		Offset:    request.GetOffset(),
+		Limit:     request.GetLimit(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.GetWebhookDeliveryListResponse{
+		WebhookDeliveryList:       output.WebhookDeliveryList,
+		TotalWebhookDeliveryCount: output.TotalWebhookDeliveryCount,
+	}, nil
+}
+
+// GetDownloadTaskVersionList implements go_load.GoLoadServiceServer.
+func (h *Handler) GetDownloadTaskVersionList(ctx context.Context, request *go_load.GetDownloadTaskVersionListRequest) (*go_load.GetDownloadTaskVersionListResponse, error) {
+	downloadTaskVersionList, err := h.downloadTaskHandler.GetDownloadTaskVersionList(ctx, logic.GetDownloadTaskVersionListParams{
+		Token:          request.GetToken(),
+		DownloadTaskID: request.GetDownloadTaskId(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.GetDownloadTaskVersionListResponse{
+		DownloadTaskVersionList: downloadTaskVersionList,
+	}, nil
+}
+
+// GrabLinks implements go_load.GoLoadServiceServer.
+func (h *Handler) GrabLinks(ctx context.Context, request *go_load.GrabLinksRequest) (*go_load.GrabLinksResponse, error) {
+	grabbedLinkList, err := h.downloadTaskHandler.GrabLinks(ctx, logic.GrabLinksParams{
+		Token:      request.GetToken(),
+		URL:        request.GetUrl(),
+		Content:    request.GetContent(),
+		Extensions: request.GetExtensions(),
+		Pattern:    request.GetPattern(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.GrabLinksResponse{
+		GrabbedLinkList: grabbedLinkList,
+	}, nil
+}
+
+// CreateDownloadTasksFromLinks implements go_load.GoLoadServiceServer.
+func (h *Handler) CreateDownloadTasksFromLinks(ctx context.Context, request *go_load.CreateDownloadTasksFromLinksRequest) (*go_load.CreateDownloadTasksFromLinksResponse, error) {
+	downloadTaskList, err := h.downloadTaskHandler.CreateDownloadTasksFromLinks(ctx, logic.CreateDownloadTasksFromLinksParams{
+		Token:           request.GetToken(),
+		URLs:            request.GetUrls(),
+		DownloadQueueID: request.GetDownloadQueueId(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &go_load.CreateDownloadTasksFromLinksResponse{
+		DownloadTaskList: downloadTaskList,
+	}, nil
 }
@@ -30,5 +30,9 @@ func (s *server) Start(ctx context.Context) error {
 
 	grpcServer := grpc.NewServer()
 	go_load.RegisterGoLoadServiceServer(grpcServer, s.handler)
+	go func() {
+		<-ctx.Done()
+		grpcServer.GracefulStop()
+	}()
 	return grpcServer.Serve(listener)
 }
@@ -29,5 +29,10 @@ func (s *server) Start(ctx context.Context) error {
 		return err
 	}
 
-	return http.ListenAndServe(":8081", mux)
+	httpServer := &http.Server{Addr: ":8081", Handler: mux}
+	go func() {
+		<-ctx.Done()
+		httpServer.Shutdown(context.WithoutCancel(ctx))
+	}()
+	return httpServer.ListenAndServe()
 }
@@ -12,8 +12,9 @@ import (
 )
 
 type Account struct {
-	AccountID   uint64
-	AccountName string
+	AccountID             uint64
+	AccountName           string
+	SpeedLimitBytesPerSec uint64
 }
 
 type CreateAccountParams struct {
@@ -26,9 +27,16 @@ type CreateSessionParams struct {
 	Password    string
 }
 
+type UpdateAccountSpeedLimitParams struct {
+	Token string
+	// SpeedLimitBytesPerSec limits all downloads of the account together, 0 means unlimited
+	SpeedLimitBytesPerSec uint64
+}
+
 type AccountHandler interface {
 	CreateAccount(ctx context.Context, params CreateAccountParams) (Account, error)
 	CreateSession(ctx context.Context, params CreateSessionParams) (token string, err error)
+	UpdateAccountSpeedLimit(ctx context.Context, params UpdateAccountSpeedLimitParams) (Account, error)
 }
 
 type accountHandler struct {
@@ -173,3 +181,29 @@ func (a accountHandler) CreateSession(ctx context.Context, params CreateSessionP
 	a.logger.With(zap.Uint64("accountID", existingAccount.ID), zap.String("accountName", params.AccountName)).Info("session created successfully")
 	return token, nil
 }
+
+// UpdateAccountSpeedLimit changes the speed limit of the token's account. Running downloads of the account pick it up
+// the next time their progress is saved.
+func (a accountHandler) UpdateAccountSpeedLimit(ctx context.Context, params UpdateAccountSpeedLimitParams) (Account, error) {
+	accountID, _, err := a.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		a.logger.With(zap.Error(err)).Error("failed to verify token")
+		return Account{}, err
+	}
+
+	err = a.accountDataAccessor.UpdateAccountSpeedLimit(ctx, accountID, params.SpeedLimitBytesPerSec)
+	if err != nil {
+		return Account{}, err
+	}
+
+	account, err := a.accountDataAccessor.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return Account{}, err
+	}
+
+	return Account{
+		AccountID:             account.ID,
+		AccountName:           account.AccountName,
+		SpeedLimitBytesPerSec: account.SpeedLimitBytesPerSec,
+	}, nil
+}
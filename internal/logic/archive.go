@@ -0,0 +1,321 @@
+package logic
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMaxExtractedByteCount = 10 * 1024 * 1024 * 1024
+	defaultMaxExtractedFileCount = 10000
+	defaultMaxCompressionRatio   = 100
+)
+
+var (
+	errArchiveExtractionFailed = errors.New("archive extraction failed")
+	errUnsupportedArchive      = errors.New("file is not a .zip, .tar, .tar.gz, .tar.bz2 or .gz archive")
+	errUnsafeArchiveEntry      = errors.New("archive entry path escapes the extraction directory")
+	errTooManyExtractedFiles   = errors.New("archive has too many files")
+	errExtractedSizeTooLarge   = errors.New("archive extracts to too many bytes")
+)
+
+type archiveFormat int
+
+const (
+	archiveFormatUnknown archiveFormat = iota
+	archiveFormatZip
+	archiveFormatTar
+	archiveFormatTarGzip
+	archiveFormatTarBzip2
+	archiveFormatGzip
+)
+
+// archiveExtensions are matched in order, so that .tar.gz is not taken for .gz.
+var archiveExtensions = []struct {
+	extension string
+	format    archiveFormat
+}{
+	{".tar.gz", archiveFormatTarGzip},
+	{".tgz", archiveFormatTarGzip},
+	{".tar.bz2", archiveFormatTarBzip2},
+	{".tbz2", archiveFormatTarBzip2},
+	{".tar", archiveFormatTar},
+	{".zip", archiveFormatZip},
+	{".gz", archiveFormatGzip},
+}
+
+// getArchiveFormat returns the format of an archive from its file name, together with the name without the extension.
+func getArchiveFormat(fileName string) (archiveFormat, string) {
+	lowerFileName := strings.ToLower(fileName)
+	for _, archiveExtension := range archiveExtensions {
+		if strings.HasSuffix(lowerFileName, archiveExtension.extension) {
+			return archiveExtension.format, fileName[:len(fileName)-len(archiveExtension.extension)]
+		}
+	}
+	return archiveFormatUnknown, fileName
+}
+
+// extractedFile is a file extracted from the archive of a download task, FilePath is relative to the extraction
+// directory.
+type extractedFile struct {
+	FilePath string `json:"file_path"`
+	Size     int64  `json:"size"`
+}
+
+// archiveExtractor extracts downloaded archives into a directory next to them. Only regular files and directories are
+// extracted, links and devices are skipped. Entries whose path would leave the directory fail the whole extraction, and
+// so does an archive extracting to more files or bytes than allowed, bytes being counted as they are written rather
+// than trusted from the archive headers.
+type archiveExtractor struct {
+	fileClient            file.Client
+	maxExtractedByteCount int64
+	maxExtractedFileCount int
+	maxCompressionRatio   int64
+	logger                *zap.Logger
+}
+
+func newArchiveExtractor(configs configs.ArchiveExtractionConfig, fileClient file.Client, logger *zap.Logger) archiveExtractor {
+	extractor := archiveExtractor{
+		fileClient:            fileClient,
+		maxExtractedByteCount: configs.MaxExtractedByteCount,
+		maxExtractedFileCount: configs.MaxExtractedFileCount,
+		maxCompressionRatio:   configs.MaxCompressionRatio,
+		logger:                logger,
+	}
+	if extractor.maxExtractedByteCount <= 0 {
+		extractor.maxExtractedByteCount = defaultMaxExtractedByteCount
+	}
+	if extractor.maxExtractedFileCount <= 0 {
+		extractor.maxExtractedFileCount = defaultMaxExtractedFileCount
+	}
+	if extractor.maxCompressionRatio <= 0 {
+		extractor.maxCompressionRatio = defaultMaxCompressionRatio
+	}
+	return extractor
+}
+
+// archiveExtraction is the state of extracting one archive.
+type archiveExtraction struct {
+	directory          string
+	remainingByteCount int64
+	files              []extractedFile
+	fileIndexes        map[string]int
+}
+
+// extract extracts the downloaded archive of a task, recording the directory and the extracted files in metadata. The
+// directory is emptied first, and removed again if the extraction fails.
+func (e archiveExtractor) extract(ctx context.Context, metadata *downloadTaskMetadata) error {
+	metadata.ExtractedDirectory = ""
+	metadata.ExtractedFiles = nil
+	if len(metadata.Files) > 0 {
+		return fmt.Errorf("%w: downloads made of several files cannot be extracted", errArchiveExtractionFailed)
+	}
+
+	format, directoryName := getArchiveFormat(path.Base(metadata.FileName))
+	if format == archiveFormatUnknown {
+		return fmt.Errorf("%w: %w", errArchiveExtractionFailed, errUnsupportedArchive)
+	}
+	if directoryName == "" {
+		directoryName = "extracted"
+	}
+
+	archiveSize, err := e.fileClient.Size(ctx, metadata.FileName)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errArchiveExtractionFailed, err)
+	}
+
+	extraction := &archiveExtraction{
+		directory:          path.Join(path.Dir(metadata.FileName), directoryName),
+		remainingByteCount: e.maxExtractedByteCount,
+		fileIndexes:        make(map[string]int),
+	}
+	if archiveSize < e.maxExtractedByteCount/e.maxCompressionRatio {
+		extraction.remainingByteCount = max(archiveSize*e.maxCompressionRatio, 1)
+	}
+
+	err = e.fileClient.Delete(ctx, extraction.directory)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errArchiveExtractionFailed, err)
+	}
+
+	err = e.extractArchive(ctx, metadata.FileName, format, archiveSize, directoryName, extraction)
+	if err != nil {
+		deleteErr := e.fileClient.Delete(ctx, extraction.directory)
+		if deleteErr != nil {
+			e.logger.With(zap.Error(deleteErr), zap.String("directory", extraction.directory)).Warn("failed to delete partially extracted archive")
+		}
+		return fmt.Errorf("%w: %w", errArchiveExtractionFailed, err)
+	}
+
+	metadata.ExtractedDirectory = extraction.directory
+	metadata.ExtractedFiles = extraction.files
+	return nil
+}
+
+func (e archiveExtractor) extractArchive(
+	ctx context.Context,
+	fileName string,
+	format archiveFormat,
+	archiveSize int64,
+	directoryName string,
+	extraction *archiveExtraction,
+) error {
+	reader, err := e.fileClient.Read(ctx, fileName)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	switch format {
+	case archiveFormatZip:
+		readerAt, ok := reader.(io.ReaderAt)
+		if !ok {
+			return errors.New("zip archives can only be read from files supporting random access")
+		}
+		return e.extractZip(ctx, readerAt, archiveSize, extraction)
+	case archiveFormatTar:
+		return e.extractTar(ctx, reader, extraction)
+	case archiveFormatTarGzip:
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		return e.extractTar(ctx, gzipReader, extraction)
+	case archiveFormatTarBzip2:
+		return e.extractTar(ctx, bzip2.NewReader(reader), extraction)
+	case archiveFormatGzip:
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+
+		// the name in the gzip header is the original one, the download may have been saved under another
+		entryName := path.Base(gzipReader.Name)
+		if !isSafePathElement(entryName) {
+			entryName = directoryName
+		}
+		return e.extractFile(ctx, entryName, gzipReader, extraction)
+	default:
+		return errUnsupportedArchive
+	}
+}
+
+func (e archiveExtractor) extractZip(ctx context.Context, readerAt io.ReaderAt, size int64, extraction *archiveExtraction) error {
+	zipReader, err := zip.NewReader(readerAt, size)
+	if err != nil {
+		return err
+	}
+
+	for _, zipFile := range zipReader.File {
+		if !zipFile.Mode().IsRegular() {
+			continue
+		}
+
+		err = e.extractZipFile(ctx, zipFile, extraction)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e archiveExtractor) extractZipFile(ctx context.Context, zipFile *zip.File, extraction *archiveExtraction) error {
+	reader, err := zipFile.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	return e.extractFile(ctx, zipFile.Name, reader, extraction)
+}
+
+func (e archiveExtractor) extractTar(ctx context.Context, reader io.Reader, extraction *archiveExtraction) error {
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		err = e.extractFile(ctx, header.Name, tarReader, extraction)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// extractFile writes one entry of the archive into the extraction directory. A later entry with the same path
+// replaces the earlier one, like extracting with common tools does.
+func (e archiveExtractor) extractFile(ctx context.Context, entryName string, reader io.Reader, extraction *archiveExtraction) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	filePath, err := getExtractedFilePath(entryName)
+	if err != nil {
+		return err
+	}
+
+	fileIndex, replaced := extraction.fileIndexes[filePath]
+	if !replaced {
+		if len(extraction.files) >= e.maxExtractedFileCount {
+			return errTooManyExtractedFiles
+		}
+		fileIndex = len(extraction.files)
+		extraction.fileIndexes[filePath] = fileIndex
+		extraction.files = append(extraction.files, extractedFile{FilePath: filePath})
+	}
+
+	writer, err := e.fileClient.Write(ctx, path.Join(extraction.directory, filePath))
+	if err != nil {
+		return err
+	}
+
+	// one byte past the limit is read to tell an entry filling the limit exactly from one going over it
+	writtenByteCount, err := io.Copy(writer, io.LimitReader(reader, extraction.remainingByteCount+1))
+	closeErr := writer.Close()
+	if err = errors.Join(err, closeErr); err != nil {
+		return err
+	}
+
+	if writtenByteCount > extraction.remainingByteCount {
+		return errExtractedSizeTooLarge
+	}
+	extraction.remainingByteCount -= writtenByteCount
+	extraction.files[fileIndex].Size = writtenByteCount
+	return nil
+}
+
+// getExtractedFilePath returns the path an archive entry is extracted to, relative to the extraction directory.
+// Leading "./" elements and trailing slashes are accepted, anything that could end up outside of the directory is not.
+func getExtractedFilePath(entryName string) (string, error) {
+	filePath := strings.TrimSuffix(entryName, "/")
+	for strings.HasPrefix(filePath, "./") {
+		filePath = strings.TrimPrefix(filePath, "./")
+	}
+	if !isSafeRelativePath(filePath) {
+		return "", fmt.Errorf("%w: %q", errUnsafeArchiveEntry, entryName)
+	}
+	return filePath, nil
+}
@@ -0,0 +1,214 @@
+package logic
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"go.uber.org/zap"
+)
+
+func TestGetExtractedFilePath(t *testing.T) {
+	testCases := []struct {
+		entryName    string
+		wantFilePath string
+		wantErr      error
+	}{
+		{entryName: "a.txt", wantFilePath: "a.txt"},
+		{entryName: "dir/a.txt", wantFilePath: "dir/a.txt"},
+		{entryName: "./dir/a.txt", wantFilePath: "dir/a.txt"},
+		{entryName: "././a.txt", wantFilePath: "a.txt"},
+		{entryName: "dir/", wantFilePath: "dir"},
+		{entryName: "", wantErr: errUnsafeArchiveEntry},
+		{entryName: "./", wantErr: errUnsafeArchiveEntry},
+		{entryName: "../evil", wantErr: errUnsafeArchiveEntry},
+		{entryName: "dir/../../evil", wantErr: errUnsafeArchiveEntry},
+		{entryName: "dir/./a.txt", wantErr: errUnsafeArchiveEntry},
+		{entryName: "/etc/passwd", wantErr: errUnsafeArchiveEntry},
+		{entryName: "dir//a.txt", wantErr: errUnsafeArchiveEntry},
+		{entryName: `..\evil`, wantErr: errUnsafeArchiveEntry},
+		{entryName: "a\x00.txt", wantErr: errUnsafeArchiveEntry},
+	}
+
+	for _, testCase := range testCases {
+		filePath, err := getExtractedFilePath(testCase.entryName)
+		if !errors.Is(err, testCase.wantErr) || filePath != testCase.wantFilePath {
+			t.Errorf(
+				"getExtractedFilePath(%q) = %q, %v, want %q, %v",
+				testCase.entryName, filePath, err, testCase.wantFilePath, testCase.wantErr,
+			)
+		}
+	}
+}
+
+type testArchiveEntry struct {
+	name    string
+	content []byte
+}
+
+func newTestTarArchive(t *testing.T, entries []testArchiveEntry, compress bool) []byte {
+	t.Helper()
+
+	var archive bytes.Buffer
+	var gzipWriter *gzip.Writer
+	tarWriter := tar.NewWriter(&archive)
+	if compress {
+		gzipWriter = gzip.NewWriter(&archive)
+		tarWriter = tar.NewWriter(gzipWriter)
+	}
+
+	for _, entry := range entries {
+		err := tarWriter.WriteHeader(&tar.Header{Name: entry.name, Mode: 0o644, Size: int64(len(entry.content)), Typeflag: tar.TypeReg})
+		if err == nil {
+			_, err = tarWriter.Write(entry.content)
+		}
+		if err != nil {
+			t.Fatalf("failed to write tar entry %s: %v", entry.name, err)
+		}
+	}
+
+	err := tarWriter.Close()
+	if err == nil && gzipWriter != nil {
+		err = gzipWriter.Close()
+	}
+	if err != nil {
+		t.Fatalf("failed to close tar archive: %v", err)
+	}
+	return archive.Bytes()
+}
+
+func TestArchiveExtractorExtractGuards(t *testing.T) {
+	testCases := []struct {
+		name      string
+		fileName  string
+		entries   []testArchiveEntry
+		configs   configs.ArchiveExtractionConfig
+		wantErr   error
+		wantFiles []extractedFile
+	}{
+		{
+			name:     "within limits",
+			fileName: "files.tar",
+			entries: []testArchiveEntry{
+				{name: "./a.txt", content: []byte("hello")},
+				{name: "dir/b.txt", content: bytes.Repeat([]byte("b"), 5)},
+			},
+			configs: configs.ArchiveExtractionConfig{MaxExtractedFileCount: 2, MaxExtractedByteCount: 10},
+			wantFiles: []extractedFile{
+				{FilePath: "a.txt", Size: 5},
+				{FilePath: "dir/b.txt", Size: 5},
+			},
+		},
+		{
+			name:     "later entry with the same path replaces the earlier one",
+			fileName: "files.tar",
+			entries: []testArchiveEntry{
+				{name: "a.txt", content: []byte("first")},
+				{name: "./a.txt", content: []byte("second")},
+			},
+			configs:   configs.ArchiveExtractionConfig{MaxExtractedFileCount: 1},
+			wantFiles: []extractedFile{{FilePath: "a.txt", Size: 6}},
+		},
+		{
+			name:     "entry escaping the directory",
+			fileName: "files.tar",
+			entries: []testArchiveEntry{
+				{name: "a.txt", content: []byte("fine")},
+				{name: "../evil.txt", content: []byte("evil")},
+			},
+			wantErr: errUnsafeArchiveEntry,
+		},
+		{
+			name:     "too many files",
+			fileName: "files.tar",
+			entries: []testArchiveEntry{
+				{name: "a.txt", content: []byte("a")},
+				{name: "b.txt", content: []byte("b")},
+				{name: "c.txt", content: []byte("c")},
+			},
+			configs: configs.ArchiveExtractionConfig{MaxExtractedFileCount: 2},
+			wantErr: errTooManyExtractedFiles,
+		},
+		{
+			name:     "too many bytes",
+			fileName: "files.tar",
+			entries: []testArchiveEntry{
+				{name: "a.txt", content: bytes.Repeat([]byte("a"), 6)},
+				{name: "b.txt", content: bytes.Repeat([]byte("b"), 5)},
+			},
+			configs: configs.ArchiveExtractionConfig{MaxExtractedByteCount: 10},
+			wantErr: errExtractedSizeTooLarge,
+		},
+		{
+			name:     "compression ratio",
+			fileName: "bomb.tar.gz",
+			entries: []testArchiveEntry{
+				{name: "zeros", content: make([]byte, 1024*1024)},
+			},
+			configs: configs.ArchiveExtractionConfig{MaxCompressionRatio: 10},
+			wantErr: errExtractedSizeTooLarge,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			directory := t.TempDir()
+			logger := zap.NewNop()
+			fileClient, err := file.NewLocalClient(configs.DownloadConfig{DownloadDirectory: directory}, logger)
+			if err != nil {
+				t.Fatalf("failed to create file client: %v", err)
+			}
+
+			archive := newTestTarArchive(t, testCase.entries, filepath.Ext(testCase.fileName) == ".gz")
+			err = os.WriteFile(filepath.Join(directory, testCase.fileName), archive, 0o644)
+			if err != nil {
+				t.Fatalf("failed to write archive: %v", err)
+			}
+
+			_, extractionDirectory := getArchiveFormat(testCase.fileName)
+			metadata := downloadTaskMetadata{FileName: testCase.fileName}
+			err = newArchiveExtractor(testCase.configs, fileClient, logger).extract(context.Background(), &metadata)
+			if testCase.wantErr != nil {
+				if !errors.Is(err, errArchiveExtractionFailed) || !errors.Is(err, testCase.wantErr) {
+					t.Fatalf("extract() error = %v, want %v", err, testCase.wantErr)
+				}
+				// nothing of a failed extraction is left behind, not even the files before the failing entry
+				if _, err := os.Stat(filepath.Join(directory, extractionDirectory)); !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("extraction directory left behind, stat error = %v", err)
+				}
+				if _, err := os.Stat(filepath.Join(directory, "evil.txt")); !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("entry written outside of the extraction directory, stat error = %v", err)
+				}
+				if metadata.ExtractedDirectory != "" || metadata.ExtractedFiles != nil {
+					t.Errorf("failed extraction recorded %q, %v", metadata.ExtractedDirectory, metadata.ExtractedFiles)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("extract() error = %v", err)
+			}
+			if metadata.ExtractedDirectory != extractionDirectory {
+				t.Errorf("ExtractedDirectory = %q, want %q", metadata.ExtractedDirectory, extractionDirectory)
+			}
+			if len(metadata.ExtractedFiles) != len(testCase.wantFiles) {
+				t.Fatalf("ExtractedFiles = %v, want %v", metadata.ExtractedFiles, testCase.wantFiles)
+			}
+			for i, wantFile := range testCase.wantFiles {
+				if metadata.ExtractedFiles[i] != wantFile {
+					t.Errorf("ExtractedFiles[%d] = %v, want %v", i, metadata.ExtractedFiles[i], wantFile)
+				}
+				if _, err := os.Stat(filepath.Join(directory, extractionDirectory, wantFile.FilePath)); err != nil {
+					t.Errorf("extracted file %s missing: %v", wantFile.FilePath, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+const (
+	minSpeedLimitBurst = 1024
+	maxSpeedLimitBurst = 64 * 1024
+)
+
+// BandwidthLimiter throttles downloads with token buckets at three levels: the whole server, each account and each
+// download task. The server limit follows the schedule of the config, the other limits can be changed while
+// downloads are running.
+type BandwidthLimiter interface {
+	// NewDownloadThrottle returns the throttle of a download of the account, it has to be closed once the download
+	// stops. Waits for bandwidth end early when ctx is done.
+	NewDownloadThrottle(ctx context.Context, accountID uint64, taskSpeedLimit, accountSpeedLimit uint64) *DownloadThrottle
+}
+
+type speedLimitPeriod struct {
+	timeWindow  timeWindow
+	bytesPerSec uint64
+}
+
+// accountSpeedLimiter is shared by the running downloads of an account.
+type accountSpeedLimiter struct {
+	limiter     *rate.Limiter
+	bytesPerSec uint64
+	// throttleCount is the number of open throttles using the limiter, it is dropped when none is left
+	throttleCount int
+}
+
+type bandwidthLimiter struct {
+	lock                     sync.Mutex
+	serverLimiter            *rate.Limiter
+	serverBytesPerSec        uint64
+	defaultServerBytesPerSec uint64
+	maxAccountBytesPerSec    uint64
+	schedule                 []speedLimitPeriod
+	location                 *time.Location
+	accountLimiters          map[uint64]*accountSpeedLimiter
+	now                      func() time.Time
+	logger                   *zap.Logger
+}
+
+func NewBandwidthLimiter(configs configs.DownloadConfig, logger *zap.Logger) (BandwidthLimiter, error) {
+	speedLimitConfig := configs.SpeedLimitConfig
+
+	location, err := configs.GetTimeZoneLocation()
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := make([]speedLimitPeriod, 0, len(speedLimitConfig.Schedule))
+	for _, periodConfig := range speedLimitConfig.Schedule {
+		timeWindow, err := parseTimeWindow(periodConfig.Start, periodConfig.End, periodConfig.Weekdays)
+		if err != nil {
+			return nil, fmt.Errorf("invalid speed limit period: %w", err)
+		}
+		schedule = append(schedule, speedLimitPeriod{timeWindow: timeWindow, bytesPerSec: periodConfig.BytesPerSec})
+	}
+
+	limiter := &bandwidthLimiter{
+		defaultServerBytesPerSec: speedLimitConfig.ServerBytesPerSec,
+		maxAccountBytesPerSec:    speedLimitConfig.AccountBytesPerSec,
+		schedule:                 schedule,
+		location:                 location,
+		accountLimiters:          make(map[uint64]*accountSpeedLimiter),
+		now:                      time.Now,
+		logger:                   logger,
+	}
+	limiter.serverBytesPerSec = limiter.getScheduledServerSpeedLimit()
+	limiter.serverLimiter = newSpeedLimiter(limiter.serverBytesPerSec)
+	return limiter, nil
+}
+
+// getScheduledServerSpeedLimit returns the limit of the first period of the schedule that applies now.
+func (l *bandwidthLimiter) getScheduledServerSpeedLimit() uint64 {
+	now := l.now().In(l.location)
+	for _, period := range l.schedule {
+		if period.timeWindow.includes(now) {
+			return period.bytesPerSec
+		}
+	}
+	return l.defaultServerBytesPerSec
+}
+
+// refreshServerSpeedLimit moves the server limiter to the limit the schedule sets for now. It is called before every
+// wait, so there is nothing to keep running in the background.
+func (l *bandwidthLimiter) refreshServerSpeedLimit() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	bytesPerSec := l.getScheduledServerSpeedLimit()
+	if bytesPerSec == l.serverBytesPerSec {
+		return
+	}
+
+	l.logger.With(zap.Uint64("from", l.serverBytesPerSec), zap.Uint64("to", bytesPerSec)).Info("changing server speed limit")
+	l.serverBytesPerSec = bytesPerSec
+	setSpeedLimit(l.serverLimiter, bytesPerSec)
+}
+
+// NewDownloadThrottle implements BandwidthLimiter.
+func (l *bandwidthLimiter) NewDownloadThrottle(
+	ctx context.Context,
+	accountID uint64,
+	taskSpeedLimit uint64,
+	accountSpeedLimit uint64,
+) *DownloadThrottle {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	accountLimiter, ok := l.accountLimiters[accountID]
+	if !ok {
+		bytesPerSec := minSpeedLimit(l.maxAccountBytesPerSec, accountSpeedLimit)
+		accountLimiter = &accountSpeedLimiter{limiter: newSpeedLimiter(bytesPerSec), bytesPerSec: bytesPerSec}
+		l.accountLimiters[accountID] = accountLimiter
+	}
+	accountLimiter.throttleCount++
+	l.updateAccountSpeedLimit(accountLimiter, accountID, accountSpeedLimit)
+
+	return &DownloadThrottle{
+		ctx:              ctx,
+		bandwidthLimiter: l,
+		accountID:        accountID,
+		taskLimiter:      newSpeedLimiter(taskSpeedLimit),
+		taskBytesPerSec:  taskSpeedLimit,
+		accountLimiter:   accountLimiter,
+	}
+}
+
+func (l *bandwidthLimiter) setAccountSpeedLimit(accountLimiter *accountSpeedLimiter, accountID uint64, accountSpeedLimit uint64) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.updateAccountSpeedLimit(accountLimiter, accountID, accountSpeedLimit)
+}
+
+// updateAccountSpeedLimit is setAccountSpeedLimit for callers already holding the lock.
+func (l *bandwidthLimiter) updateAccountSpeedLimit(accountLimiter *accountSpeedLimiter, accountID uint64, accountSpeedLimit uint64) {
+	bytesPerSec := minSpeedLimit(l.maxAccountBytesPerSec, accountSpeedLimit)
+	if bytesPerSec == accountLimiter.bytesPerSec {
+		return
+	}
+
+	l.logger.With(zap.Uint64("accountID", accountID), zap.Uint64("bytesPerSec", bytesPerSec)).Info("changing account speed limit")
+	accountLimiter.bytesPerSec = bytesPerSec
+	setSpeedLimit(accountLimiter.limiter, bytesPerSec)
+}
+
+func (l *bandwidthLimiter) closeDownloadThrottle(accountLimiter *accountSpeedLimiter, accountID uint64) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	accountLimiter.throttleCount--
+	if accountLimiter.throttleCount == 0 {
+		delete(l.accountLimiters, accountID)
+	}
+}
+
+// DownloadThrottle slows down a single download to the limits of its task, its account and the server.
+type DownloadThrottle struct {
+	ctx              context.Context
+	bandwidthLimiter *bandwidthLimiter
+	accountID        uint64
+	lock             sync.Mutex
+	taskLimiter      *rate.Limiter
+	taskBytesPerSec  uint64
+	accountLimiter   *accountSpeedLimiter
+	closeOnce        sync.Once
+}
+
+// Wait blocks until byteCount more bytes fit into every limit, or until the context of the throttle is done.
+func (t *DownloadThrottle) Wait(byteCount int64) error {
+	t.bandwidthLimiter.refreshServerSpeedLimit()
+
+	for _, limiter := range []*rate.Limiter{t.taskLimiter, t.accountLimiter.limiter, t.bandwidthLimiter.serverLimiter} {
+		err := waitSpeedLimiter(t.ctx, limiter, byteCount)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetTaskSpeedLimit changes the limit of the task, downloads already waiting get the new limit within a fraction of a
+// second.
+func (t *DownloadThrottle) SetTaskSpeedLimit(bytesPerSec uint64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if bytesPerSec == t.taskBytesPerSec {
+		return
+	}
+	t.taskBytesPerSec = bytesPerSec
+	setSpeedLimit(t.taskLimiter, bytesPerSec)
+}
+
+// SetAccountSpeedLimit changes the limit the account set for itself, for every running download of the account.
+func (t *DownloadThrottle) SetAccountSpeedLimit(bytesPerSec uint64) {
+	t.bandwidthLimiter.setAccountSpeedLimit(t.accountLimiter, t.accountID, bytesPerSec)
+}
+
+func (t *DownloadThrottle) Close() {
+	t.closeOnce.Do(func() {
+		t.bandwidthLimiter.closeDownloadThrottle(t.accountLimiter, t.accountID)
+	})
+}
+
+// waitSpeedLimiter waits for byteCount tokens in chunks no larger than the burst of the limiter, which is read again
+// for every chunk since the limit may change in the meantime.
+func waitSpeedLimiter(ctx context.Context, limiter *rate.Limiter, byteCount int64) error {
+	for byteCount > 0 {
+		chunkSize := min(byteCount, int64(limiter.Burst()))
+		err := limiter.WaitN(ctx, int(chunkSize))
+		if err != nil {
+			if ctx.Err() != nil || chunkSize <= int64(limiter.Burst()) {
+				return err
+			}
+			// the burst shrank between reading it and waiting
+			continue
+		}
+		byteCount -= chunkSize
+	}
+	return nil
+}
+
+func newSpeedLimiter(bytesPerSec uint64) *rate.Limiter {
+	return rate.NewLimiter(getSpeedLimit(bytesPerSec), getSpeedLimitBurst(bytesPerSec))
+}
+
+func setSpeedLimit(limiter *rate.Limiter, bytesPerSec uint64) {
+	limiter.SetLimit(getSpeedLimit(bytesPerSec))
+	limiter.SetBurst(getSpeedLimitBurst(bytesPerSec))
+}
+
+func getSpeedLimit(bytesPerSec uint64) rate.Limit {
+	if bytesPerSec == 0 {
+		return rate.Inf
+	}
+	return rate.Limit(bytesPerSec)
+}
+
+// getSpeedLimitBurst allows about a tenth of a second worth of bytes at once, short waits let a changed limit take
+// effect quickly.
+func getSpeedLimitBurst(bytesPerSec uint64) int {
+	if bytesPerSec == 0 {
+		return maxSpeedLimitBurst
+	}
+	return int(min(max(bytesPerSec/10, minSpeedLimitBurst), maxSpeedLimitBurst))
+}
+
+// minSpeedLimit returns the stricter of two limits, where 0 is unlimited.
+func minSpeedLimit(a, b uint64) uint64 {
+	if a == 0 || b != 0 && b < a {
+		return b
+	}
+	return a
+}
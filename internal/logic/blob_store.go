@@ -0,0 +1,154 @@
+package logic
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path"
+	"slices"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/database"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
+	"go.uber.org/zap"
+)
+
+const (
+	blobDirectory = ".blobs"
+)
+
+// getBlobFileName returns the name of the file a blob is stored in, blobs being spread over directories by the first
+// byte of their digest.
+func getBlobFileName(sha256 string) string {
+	return path.Join(blobDirectory, sha256[:2], sha256)
+}
+
+// blobStore keeps a single copy on disk of the files completed download tasks have in common. The file of a task
+// stays where it is, it becomes a hard link to the blob of its content, and the blob is removed with the last task
+// referencing it. Tasks sharing a blob share the file itself, including its permissions, so files post-download steps
+// may have changed for their task alone are left out.
+type blobStore struct {
+	goquDatabase             *goqu.Database
+	downloadTaskDataAccessor database.DownloadTaskDataAccessor
+	blobDataAccessor         database.BlobDataAccessor
+	fileClient               file.Client
+	logger                   *zap.Logger
+}
+
+func newBlobStore(
+	goquDatabase *goqu.Database,
+	downloadTaskDataAccessor database.DownloadTaskDataAccessor,
+	blobDataAccessor database.BlobDataAccessor,
+	fileClient file.Client,
+	logger *zap.Logger,
+) blobStore {
+	return blobStore{
+		goquDatabase:             goquDatabase,
+		downloadTaskDataAccessor: downloadTaskDataAccessor,
+		blobDataAccessor:         blobDataAccessor,
+		fileClient:               fileClient,
+		logger:                   logger,
+	}
+}
+
+// store stores the downloaded file of a completed task as a blob, replacing it with a link to the existing blob when
+// another task already downloaded the same content. Only completed tasks are stored, their file is never written to
+// again. Downloads made of several files, watched downloads whose file is replaced by every new version, and files
+// changed by post-download steps are left as they are.
+func (b blobStore) store(ctx context.Context, taskID uint64, metadata downloadTaskMetadata) error {
+	if len(metadata.Files) > 0 || metadata.FileName == "" || metadata.WatchOptions != nil ||
+		hasFileChangingPostDownloadStep(metadata) {
+		return nil
+	}
+
+	sha256 := metadata.SHA256
+	if sha256 == "" {
+		digests, err := computeFileDigests(ctx, b.fileClient, metadata.FileName, []go_load.DigestAlgorithm{go_load.DigestAlgorithm_SHA256})
+		if err != nil {
+			return err
+		}
+		sha256 = digests[0].Value
+	}
+
+	size, err := b.fileClient.Size(ctx, metadata.FileName)
+	if err != nil {
+		return err
+	}
+
+	return b.goquDatabase.WithTx(func(tx *goqu.TxDatabase) error {
+		// the task is locked so that it is not deleted while its blob reference is being taken
+		task, err := b.downloadTaskDataAccessor.WithDatabase(tx).GetDownloadTaskByIDWithXLock(ctx, taskID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if task.DownloadStatus != uint16(go_load.DownloadStatus_Success) || task.BlobSHA256 != "" {
+			return nil
+		}
+
+		blobDataAccessor := b.blobDataAccessor.WithDatabase(tx)
+		blob, err := blobDataAccessor.GetBlobBySHA256WithXLock(ctx, sha256)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			err = b.fileClient.Link(ctx, metadata.FileName, getBlobFileName(sha256))
+			if err != nil {
+				return err
+			}
+
+			err = blobDataAccessor.CreateBlob(ctx, database.Blob{SHA256: sha256, Size: size, ReferenceCount: 1})
+		case err != nil:
+			return err
+		default:
+			// the copy of the task is dropped for a link to the blob, which frees its space
+			err = b.fileClient.Link(ctx, getBlobFileName(sha256), metadata.FileName)
+			if err != nil {
+				return err
+			}
+
+			err = blobDataAccessor.UpdateBlobReferenceCount(ctx, sha256, blob.ReferenceCount+1)
+		}
+		if err != nil {
+			return err
+		}
+
+		b.logger.With(zap.Uint64("taskID", taskID), zap.String("sha256", sha256)).Info("stored downloaded file as blob")
+		return b.downloadTaskDataAccessor.WithDatabase(tx).UpdateDownloadTaskBlobSHA256(ctx, taskID, sha256)
+	})
+}
+
+// hasFileChangingPostDownloadStep reports whether a post-download step ran that may have changed the file itself rather
+// than its name, which a link to a blob would share with every task of the blob. Failed steps count, as they may have
+// changed the file before failing.
+func hasFileChangingPostDownloadStep(metadata downloadTaskMetadata) bool {
+	return slices.ContainsFunc(metadata.PostDownloadStepResults, func(result postDownloadStepResult) bool {
+		return result.Type == postDownloadStepChmod || result.Type == postDownloadStepExec
+	})
+}
+
+// release drops the reference of a task being deleted to its blob, removing the blob once nothing references it. It
+// runs in the transaction deleting the task, the blob file being removed while the blob is locked keeps it from being
+// removed after another task stored the same content again.
+func (b blobStore) release(ctx context.Context, tx *goqu.TxDatabase, sha256 string) error {
+	blobDataAccessor := b.blobDataAccessor.WithDatabase(tx)
+	blob, err := blobDataAccessor.GetBlobBySHA256WithXLock(ctx, sha256)
+	if errors.Is(err, sql.ErrNoRows) {
+		b.logger.With(zap.String("sha256", sha256)).Warn("blob of download task not found")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if blob.ReferenceCount > 1 {
+		return blobDataAccessor.UpdateBlobReferenceCount(ctx, sha256, blob.ReferenceCount-1)
+	}
+
+	err = blobDataAccessor.DeleteBlob(ctx, sha256)
+	if err != nil {
+		return err
+	}
+	return b.fileClient.Delete(ctx, getBlobFileName(sha256))
+}
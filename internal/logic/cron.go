@@ -0,0 +1,213 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxCronSearchYears bounds the search for the next run, schedules like "0 0 30 2 *" never run
+	maxCronSearchYears = 5
+)
+
+var (
+	errInvalidCronExpression = errors.New("invalid cron expression")
+	errCronNeverRuns         = errors.New("cron expression never runs")
+
+	cronMonthNames   = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	cronWeekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+	cronDescriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// cronSchedule is a parsed standard five field cron expression: minute, hour, day of month, month and day of week.
+// Each field is a bitset of the values it matches.
+type cronSchedule struct {
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+	// like in cron, when both day fields are restricted a day matching either of them matches
+	daysOfMonthRestricted bool
+	daysOfWeekRestricted  bool
+}
+
+type cronField struct {
+	min   int
+	max   int
+	names []string
+}
+
+func parseCronExpression(expression string) (cronSchedule, error) {
+	expression = strings.TrimSpace(expression)
+	if descriptor, ok := cronDescriptors[strings.ToLower(expression)]; ok {
+		expression = descriptor
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return cronSchedule{}, fmt.Errorf("%w: expected 5 fields, got %d", errInvalidCronExpression, len(fields))
+	}
+
+	minutes, err := parseCronField(fields[0], cronField{min: 0, max: 59})
+	if err != nil {
+		return cronSchedule{}, err
+	}
+
+	hours, err := parseCronField(fields[1], cronField{min: 0, max: 23})
+	if err != nil {
+		return cronSchedule{}, err
+	}
+
+	daysOfMonth, err := parseCronField(fields[2], cronField{min: 1, max: 31})
+	if err != nil {
+		return cronSchedule{}, err
+	}
+
+	months, err := parseCronField(fields[3], cronField{min: 1, max: 12, names: cronMonthNames})
+	if err != nil {
+		return cronSchedule{}, err
+	}
+
+	// 7 is Sunday as well
+	daysOfWeek, err := parseCronField(fields[4], cronField{min: 0, max: 7, names: cronWeekdayNames})
+	if err != nil {
+		return cronSchedule{}, err
+	}
+	if daysOfWeek&(1<<7) != 0 {
+		daysOfWeek |= 1
+	}
+
+	return cronSchedule{
+		minutes:               minutes,
+		hours:                 hours,
+		daysOfMonth:           daysOfMonth,
+		months:                months,
+		daysOfWeek:            daysOfWeek,
+		daysOfMonthRestricted: !strings.HasPrefix(fields[2], "*"),
+		daysOfWeekRestricted:  !strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+// parseCronField parses a comma separated list of values, ranges like "1-5" and steps like "*/15" or "10-40/10".
+func parseCronField(value string, field cronField) (uint64, error) {
+	var result uint64
+	for _, part := range strings.Split(value, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("%w: invalid step %q", errInvalidCronExpression, part)
+			}
+		}
+
+		var start, end int
+		if rangePart == "*" {
+			start, end = field.min, field.max
+		} else {
+			startPart, endPart, isRange := strings.Cut(rangePart, "-")
+
+			var err error
+			start, err = parseCronValue(startPart, field)
+			if err != nil {
+				return 0, err
+			}
+
+			end = start
+			if isRange {
+				end, err = parseCronValue(endPart, field)
+				if err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				// "5/15" runs from 5 to the end of the field
+				end = field.max
+			}
+		}
+		if start > end {
+			return 0, fmt.Errorf("%w: invalid range %q", errInvalidCronExpression, part)
+		}
+
+		for i := start; i <= end; i += step {
+			result |= 1 << i
+		}
+	}
+	return result, nil
+}
+
+func parseCronValue(value string, field cronField) (int, error) {
+	for i, name := range field.names {
+		if strings.EqualFold(value, name) {
+			// months are named from 1, weekdays from 0
+			return i + field.min, nil
+		}
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < field.min || number > field.max {
+		return 0, fmt.Errorf("%w: value %q out of range %d-%d", errInvalidCronExpression, value, field.min, field.max)
+	}
+	return number, nil
+}
+
+func (c cronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonthMatches := c.daysOfMonth&(1<<t.Day()) != 0
+	dayOfWeekMatches := c.daysOfWeek&(1<<t.Weekday()) != 0
+	if c.daysOfMonthRestricted && c.daysOfWeekRestricted {
+		return dayOfMonthMatches || dayOfWeekMatches
+	}
+	return dayOfMonthMatches && dayOfWeekMatches
+}
+
+// next returns the first time after after the schedule runs, in the time zone of after. Times skipped by a daylight
+// saving change are not run.
+func (c cronSchedule) next(after time.Time) (time.Time, error) {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxCronSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if c.months&(1<<t.Month()) == 0 {
+			t = startOfDay(t, t.Month()+1, 1)
+			continue
+		}
+		if !c.matchesDay(t) {
+			t = startOfDay(t, t.Month(), t.Day()+1)
+			continue
+		}
+		if c.hours&(1<<t.Hour()) == 0 {
+			t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+			continue
+		}
+		if c.minutes&(1<<t.Minute()) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t, nil
+	}
+	return time.Time{}, errCronNeverRuns
+}
+
+// startOfDay returns the midnight starting the given day of the year of t. When a daylight saving change skips that
+// midnight the time may come out before t, an hour after t is returned then so the search keeps going forward.
+func startOfDay(t time.Time, month time.Month, day int) time.Time {
+	start := time.Date(t.Year(), month, day, 0, 0, 0, 0, t.Location())
+	if !start.After(t) {
+		return t.Add(time.Hour).Truncate(time.Hour)
+	}
+	return start
+}
@@ -0,0 +1,205 @@
+package logic
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func getCronBits(values ...int) uint64 {
+	var bits uint64
+	for _, value := range values {
+		bits |= 1 << value
+	}
+	return bits
+}
+
+func getCronRangeBits(start, end, step int) uint64 {
+	var bits uint64
+	for value := start; value <= end; value += step {
+		bits |= 1 << value
+	}
+	return bits
+}
+
+func TestParseCronExpression(t *testing.T) {
+	testCases := []struct {
+		name       string
+		expression string
+		want       cronSchedule
+	}{
+		{
+			name:       "every quarter hour",
+			expression: "*/15 * * * *",
+			want: cronSchedule{
+				minutes:     getCronBits(0, 15, 30, 45),
+				hours:       getCronRangeBits(0, 23, 1),
+				daysOfMonth: getCronRangeBits(1, 31, 1),
+				months:      getCronRangeBits(1, 12, 1),
+				daysOfWeek:  getCronRangeBits(0, 7, 1),
+			},
+		},
+		{
+			name:       "working hours on weekdays by name",
+			expression: "0 9-17 * * MON-fri",
+			want: cronSchedule{
+				minutes:              getCronBits(0),
+				hours:                getCronRangeBits(9, 17, 1),
+				daysOfMonth:          getCronRangeBits(1, 31, 1),
+				months:               getCronRangeBits(1, 12, 1),
+				daysOfWeek:           getCronRangeBits(1, 5, 1),
+				daysOfWeekRestricted: true,
+			},
+		},
+		{
+			name:       "lists, stepped ranges and month names",
+			expression: "5/20 0,12 1,15 jan-jun/2 *",
+			want: cronSchedule{
+				minutes:               getCronBits(5, 25, 45),
+				hours:                 getCronBits(0, 12),
+				daysOfMonth:           getCronBits(1, 15),
+				months:                getCronBits(1, 3, 5),
+				daysOfWeek:            getCronRangeBits(0, 7, 1),
+				daysOfMonthRestricted: true,
+			},
+		},
+		{
+			name:       "7 is Sunday",
+			expression: "0 0 * * 7",
+			want: cronSchedule{
+				minutes:              getCronBits(0),
+				hours:                getCronBits(0),
+				daysOfMonth:          getCronRangeBits(1, 31, 1),
+				months:               getCronRangeBits(1, 12, 1),
+				daysOfWeek:           getCronBits(0, 7),
+				daysOfWeekRestricted: true,
+			},
+		},
+		{
+			name:       "descriptor",
+			expression: " @Daily ",
+			want: cronSchedule{
+				minutes:     getCronBits(0),
+				hours:       getCronBits(0),
+				daysOfMonth: getCronRangeBits(1, 31, 1),
+				months:      getCronRangeBits(1, 12, 1),
+				daysOfWeek:  getCronRangeBits(0, 7, 1),
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := parseCronExpression(testCase.expression)
+			if err != nil {
+				t.Fatalf("parseCronExpression(%q) error = %v", testCase.expression, err)
+			}
+			if got != testCase.want {
+				t.Errorf("parseCronExpression(%q) = %+v, want %+v", testCase.expression, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestParseCronExpressionInvalid(t *testing.T) {
+	testCases := []struct {
+		name       string
+		expression string
+	}{
+		{name: "too few fields", expression: "* * * *"},
+		{name: "too many fields", expression: "* * * * * *"},
+		{name: "empty", expression: ""},
+		{name: "minute out of range", expression: "60 * * * *"},
+		{name: "day of month zero", expression: "0 0 0 * *"},
+		{name: "zero step", expression: "*/0 * * * *"},
+		{name: "negative step", expression: "*/-5 * * * *"},
+		{name: "reversed range", expression: "30-10 * * * *"},
+		{name: "unknown month name", expression: "0 0 1 foo *"},
+		{name: "weekday name in the month field", expression: "0 0 1 mon *"},
+		{name: "unknown descriptor", expression: "@fortnightly"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := parseCronExpression(testCase.expression)
+			if !errors.Is(err, errInvalidCronExpression) {
+				t.Errorf("parseCronExpression(%q) error = %v, want %v", testCase.expression, err, errInvalidCronExpression)
+			}
+		})
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+
+	testCases := []struct {
+		name       string
+		expression string
+		after      time.Time
+		want       time.Time
+		wantErr    error
+	}{
+		{
+			name:       "next quarter hour",
+			expression: "*/15 * * * *",
+			after:      time.Date(2024, 1, 1, 10, 7, 30, 0, time.UTC),
+			want:       time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			name:       "strictly after a matching time",
+			expression: "*/15 * * * *",
+			after:      time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC),
+			want:       time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "weekend skipped",
+			expression: "0 9 * * mon-fri",
+			after:      time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC),
+			want:       time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "either restricted day field matches",
+			expression: "0 0 1 * mon",
+			after:      time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			want:       time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "next leap day",
+			expression: "0 0 29 2 *",
+			after:      time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			want:       time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "time skipped by daylight saving is not run",
+			expression: "30 2 * * *",
+			after:      time.Date(2024, 3, 9, 3, 0, 0, 0, newYork),
+			want:       time.Date(2024, 3, 11, 2, 30, 0, 0, newYork),
+		},
+		{
+			name:       "never runs",
+			expression: "0 0 30 2 *",
+			after:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantErr:    errCronNeverRuns,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			schedule, err := parseCronExpression(testCase.expression)
+			if err != nil {
+				t.Fatalf("parseCronExpression(%q) error = %v", testCase.expression, err)
+			}
+
+			got, err := schedule.next(testCase.after)
+			if !errors.Is(err, testCase.wantErr) {
+				t.Fatalf("next() error = %v, want %v", err, testCase.wantErr)
+			}
+			if !got.Equal(testCase.want) {
+				t.Errorf("next(%v) = %v, want %v", testCase.after, got, testCase.want)
+			}
+		})
+	}
+}
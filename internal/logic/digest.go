@@ -0,0 +1,189 @@
+package logic
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"strings"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
+)
+
+var (
+	errUnsupportedDigestAlgorithm = errors.New("unsupported digest algorithm")
+	errInvalidDigestValue         = errors.New("digest value is not a hex string of the length its algorithm produces")
+	errDigestMismatch             = errors.New("downloaded file does not match the expected digest")
+	errInvalidPieceDigests        = errors.New("piece digests need a piece length and at least one digest")
+	errPieceDigestMismatch        = errors.New("pieces of the downloaded file do not match their expected digest")
+	errSizeMismatch               = errors.New("downloaded file does not have the expected size")
+)
+
+// digest is a hex encoded digest of a file, Value is always lowercase.
+type digest struct {
+	Algorithm go_load.DigestAlgorithm `json:"algorithm"`
+	Value     string                  `json:"value"`
+}
+
+// digestWriter is the part of hash.Hash that computing a digest needs.
+type digestWriter interface {
+	io.Writer
+	Sum(data []byte) []byte
+	Size() int
+}
+
+func newDigestWriter(algorithm go_load.DigestAlgorithm) (digestWriter, error) {
+	switch algorithm {
+	case go_load.DigestAlgorithm_MD5:
+		return md5.New(), nil
+	case go_load.DigestAlgorithm_SHA1:
+		return sha1.New(), nil
+	case go_load.DigestAlgorithm_SHA256:
+		return sha256.New(), nil
+	case go_load.DigestAlgorithm_SHA512:
+		return sha512.New(), nil
+	case go_load.DigestAlgorithm_CRC32:
+		// CRC32 is written big endian, the way crc32 and cksum style tools print it
+		return crc32.NewIEEE(), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedDigestAlgorithm, algorithm)
+	}
+}
+
+func digestFromProto(protoDigest *go_load.Digest) (*digest, error) {
+	if protoDigest == nil {
+		return nil, nil
+	}
+
+	digestWriter, err := newDigestWriter(protoDigest.GetAlgorithm())
+	if err != nil {
+		return nil, err
+	}
+
+	value := strings.ToLower(strings.TrimSpace(protoDigest.GetValue()))
+	decodedValue, err := hex.DecodeString(value)
+	if err != nil || len(decodedValue) != digestWriter.Size() {
+		return nil, errInvalidDigestValue
+	}
+
+	return &digest{
+		Algorithm: protoDigest.GetAlgorithm(),
+		Value:     value,
+	}, nil
+}
+
+// pieceDigests are the digests of the consecutive pieces of a file, which are all PieceLength long except for the last
+// one. Unlike a digest of the whole file they tell which parts of a broken download have to be fetched again.
+type pieceDigests struct {
+	Algorithm   go_load.DigestAlgorithm `json:"algorithm"`
+	PieceLength int64                   `json:"piece_length"`
+	Values      []string                `json:"values"`
+}
+
+func pieceDigestsFromProto(protoPieceDigests *go_load.PieceDigests) (*pieceDigests, error) {
+	if protoPieceDigests == nil {
+		return nil, nil
+	}
+
+	if protoPieceDigests.GetPieceLength() == 0 || len(protoPieceDigests.GetValues()) == 0 {
+		return nil, errInvalidPieceDigests
+	}
+
+	values := make([]string, 0, len(protoPieceDigests.GetValues()))
+	for _, value := range protoPieceDigests.GetValues() {
+		pieceDigest, err := digestFromProto(&go_load.Digest{Algorithm: protoPieceDigests.GetAlgorithm(), Value: value})
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, pieceDigest.Value)
+	}
+
+	return &pieceDigests{
+		Algorithm:   protoPieceDigests.GetAlgorithm(),
+		PieceLength: int64(protoPieceDigests.GetPieceLength()),
+		Values:      values,
+	}, nil
+}
+
+// getPieceCount returns how many pieces a file of the given size is made of.
+func (p pieceDigests) getPieceCount(size int64) int {
+	return int((size + p.PieceLength - 1) / p.PieceLength)
+}
+
+// getBrokenPieces reads the reader to its end once and returns the indexes of the pieces not matching their digest.
+func (p pieceDigests) getBrokenPieces(reader io.Reader) ([]int, error) {
+	brokenPieces := make([]int, 0)
+	for pieceIndex, value := range p.Values {
+		digestWriter, err := newDigestWriter(p.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = io.CopyN(digestWriter, reader, p.PieceLength)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+
+		if hex.EncodeToString(digestWriter.Sum(nil)) != value {
+			brokenPieces = append(brokenPieces, pieceIndex)
+		}
+	}
+	return brokenPieces, nil
+}
+
+// computeDigests reads the reader to its end once and returns its digest for each of the algorithms.
+func computeDigests(reader io.Reader, algorithms []go_load.DigestAlgorithm) ([]digest, error) {
+	digestWriters := make([]digestWriter, 0, len(algorithms))
+	writers := make([]io.Writer, 0, len(algorithms))
+	for _, algorithm := range algorithms {
+		digestWriter, err := newDigestWriter(algorithm)
+		if err != nil {
+			return nil, err
+		}
+		digestWriters = append(digestWriters, digestWriter)
+		writers = append(writers, digestWriter)
+	}
+
+	_, err := io.Copy(io.MultiWriter(writers...), reader)
+	if err != nil {
+		return nil, err
+	}
+
+	digests := make([]digest, 0, len(algorithms))
+	for i, algorithm := range algorithms {
+		digests = append(digests, digest{
+			Algorithm: algorithm,
+			Value:     hex.EncodeToString(digestWriters[i].Sum(nil)),
+		})
+	}
+	return digests, nil
+}
+
+func computeFileDigests(
+	ctx context.Context,
+	fileClient file.Client,
+	fileName string,
+	algorithms []go_load.DigestAlgorithm,
+) ([]digest, error) {
+	reader, err := fileClient.Read(ctx, fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return computeDigests(reader, algorithms)
+}
+
+func (d digest) toProto() *go_load.Digest {
+	return &go_load.Digest{
+		Algorithm: d.Algorithm,
+		Value:     d.Value,
+	}
+}
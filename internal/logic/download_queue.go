@@ -0,0 +1,328 @@
+package logic
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/database"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
+	"go.uber.org/zap"
+)
+
+const (
+	maxDownloadQueueNameLength = 100
+)
+
+var (
+	errDownloadQueueNotFound    = errors.New("download queue not found")
+	errInvalidDownloadQueueName = errors.New("download queue name must not be empty or longer than 100 characters")
+	errIncompleteTimeWindow     = errors.New("active start and end time must be given together")
+)
+
+type CreateDownloadQueueParams struct {
+	Token    string
+	Name     string
+	Priority int32
+	// MaxConcurrentDownloadCount is how many tasks of the queue may download at once, 0 means no limit
+	MaxConcurrentDownloadCount uint32
+	// ActiveStartTime and ActiveEndTime are times of day like "09:00", tasks of the queue only start in between. The
+	// queue is always active when both are empty.
+	ActiveStartTime string
+	ActiveEndTime   string
+}
+
+type GetDownloadQueueListParams struct {
+	Token string
+}
+
+type UpdateDownloadQueueParams struct {
+	Token                      string
+	DownloadQueueID            uint64
+	Name                       string
+	Priority                   int32
+	MaxConcurrentDownloadCount uint32
+	ActiveStartTime            string
+	ActiveEndTime              string
+}
+
+type DeleteDownloadQueueParams struct {
+	Token           string
+	DownloadQueueID uint64
+}
+
+// DownloadQueueHandler manages the named download queues of accounts. Pending tasks of queues with a higher priority
+// start first, a queue never runs more tasks at once than it allows, and outside of its active time window none of its
+// tasks start. Downloads already running when the window closes are left to finish.
+type DownloadQueueHandler interface {
+	CreateDownloadQueue(ctx context.Context, params CreateDownloadQueueParams) (*go_load.DownloadQueue, error)
+	GetDownloadQueueList(ctx context.Context, params GetDownloadQueueListParams) ([]*go_load.DownloadQueue, error)
+	UpdateDownloadQueue(ctx context.Context, params UpdateDownloadQueueParams) (*go_load.DownloadQueue, error)
+	DeleteDownloadQueue(ctx context.Context, params DeleteDownloadQueueParams) error
+}
+
+type downloadQueueHandler struct {
+	tokenHandler              TokenHandler
+	downloadQueueDataAccessor database.DownloadQueueDataAccessor
+	downloadTaskDataAccessor  database.DownloadTaskDataAccessor
+	goquDatabase              *goqu.Database
+	logger                    *zap.Logger
+}
+
+func NewDownloadQueueHandler(
+	tokenHandler TokenHandler,
+	downloadQueueDataAccessor database.DownloadQueueDataAccessor,
+	downloadTaskDataAccessor database.DownloadTaskDataAccessor,
+	goquDatabase *goqu.Database,
+	logger *zap.Logger,
+) DownloadQueueHandler {
+	return &downloadQueueHandler{
+		tokenHandler:              tokenHandler,
+		downloadQueueDataAccessor: downloadQueueDataAccessor,
+		downloadTaskDataAccessor:  downloadTaskDataAccessor,
+		goquDatabase:              goquDatabase,
+		logger:                    logger,
+	}
+}
+
+// getDownloadQueueTimeWindow returns nil for queues that are always active.
+func getDownloadQueueTimeWindow(queue database.DownloadQueue) (*timeWindow, error) {
+	if queue.ActiveStartTime == "" && queue.ActiveEndTime == "" {
+		return nil, nil
+	}
+	if queue.ActiveStartTime == "" || queue.ActiveEndTime == "" {
+		return nil, errIncompleteTimeWindow
+	}
+
+	window, err := parseTimeWindow(queue.ActiveStartTime, queue.ActiveEndTime, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &window, nil
+}
+
+func validateDownloadQueue(queue database.DownloadQueue) error {
+	if queue.Name == "" || len(queue.Name) > maxDownloadQueueNameLength {
+		return errInvalidDownloadQueueName
+	}
+
+	_, err := getDownloadQueueTimeWindow(queue)
+	return err
+}
+
+// getOwnedDownloadQueue returns the download queue only if it belongs to the account.
+func getOwnedDownloadQueue(
+	ctx context.Context,
+	downloadQueueDataAccessor database.DownloadQueueDataAccessor,
+	accountID uint64,
+	downloadQueueID uint64,
+	lock bool,
+) (database.DownloadQueue, error) {
+	var (
+		queue database.DownloadQueue
+		err   error
+	)
+	if lock {
+		queue, err = downloadQueueDataAccessor.GetDownloadQueueByIDWithXLock(ctx, downloadQueueID)
+	} else {
+		queue, err = downloadQueueDataAccessor.GetDownloadQueueByID(ctx, downloadQueueID)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.DownloadQueue{}, errDownloadQueueNotFound
+		}
+		return database.DownloadQueue{}, err
+	}
+
+	if queue.OfAccountID != accountID {
+		return database.DownloadQueue{}, errDownloadQueueNotFound
+	}
+
+	return queue, nil
+}
+
+// placeDownloadTaskInQueue puts the task at the position of the queue, 1 being the front and 0 the end, and renumbers
+// the tasks after it. The queue has to be locked by the caller.
+func placeDownloadTaskInQueue(
+	ctx context.Context,
+	downloadTaskDataAccessor database.DownloadTaskDataAccessor,
+	downloadQueueID uint64,
+	downloadTaskID uint64,
+	position uint32,
+) error {
+	tasks, err := downloadTaskDataAccessor.GetDownloadTasksByQueueID(ctx, downloadQueueID)
+	if err != nil {
+		return err
+	}
+
+	currentPositions := make(map[uint64]uint64, len(tasks))
+	taskIDs := make([]uint64, 0, len(tasks)+1)
+	for _, task := range tasks {
+		if task.ID != downloadTaskID {
+			currentPositions[task.ID] = task.QueuePosition
+			taskIDs = append(taskIDs, task.ID)
+		}
+	}
+
+	index := len(taskIDs)
+	if position > 0 && int(position) <= len(taskIDs) {
+		index = int(position) - 1
+	}
+	taskIDs = slices.Insert(taskIDs, index, downloadTaskID)
+
+	for newPosition, taskID := range taskIDs {
+		currentPosition, ok := currentPositions[taskID]
+		if ok && currentPosition == uint64(newPosition) {
+			continue
+		}
+
+		err = downloadTaskDataAccessor.UpdateDownloadTaskQueue(ctx, taskID, downloadQueueID, uint64(newPosition))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d downloadQueueHandler) databaseDownloadQueueToProto(ctx context.Context, queue database.DownloadQueue) (*go_load.DownloadQueue, error) {
+	tasks, err := d.downloadTaskDataAccessor.GetDownloadTasksByQueueID(ctx, queue.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	taskIDs := make([]uint64, 0, len(tasks))
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+
+	return &go_load.DownloadQueue{
+		Id:                         queue.ID,
+		Name:                       queue.Name,
+		Priority:                   queue.Priority,
+		MaxConcurrentDownloadCount: queue.MaxConcurrentDownloadCount,
+		ActiveStartTime:            queue.ActiveStartTime,
+		ActiveEndTime:              queue.ActiveEndTime,
+		DownloadTaskIds:            taskIDs,
+	}, nil
+}
+
+func (d downloadQueueHandler) CreateDownloadQueue(ctx context.Context, params CreateDownloadQueueParams) (*go_load.DownloadQueue, error) {
+	accountID, _, err := d.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to verify token")
+		return nil, err
+	}
+
+	queue := database.DownloadQueue{
+		OfAccountID:                accountID,
+		Name:                       strings.TrimSpace(params.Name),
+		Priority:                   params.Priority,
+		MaxConcurrentDownloadCount: params.MaxConcurrentDownloadCount,
+		ActiveStartTime:            params.ActiveStartTime,
+		ActiveEndTime:              params.ActiveEndTime,
+	}
+	err = validateDownloadQueue(queue)
+	if err != nil {
+		return nil, err
+	}
+
+	queue.ID, err = d.downloadQueueDataAccessor.CreateDownloadQueue(ctx, queue)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("accountID", accountID)).Error("failed to create download queue")
+		return nil, err
+	}
+
+	d.logger.With(zap.Uint64("accountID", accountID), zap.Uint64("queueID", queue.ID)).Info("download queue created")
+	return d.databaseDownloadQueueToProto(ctx, queue)
+}
+
+func (d downloadQueueHandler) GetDownloadQueueList(ctx context.Context, params GetDownloadQueueListParams) ([]*go_load.DownloadQueue, error) {
+	accountID, _, err := d.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to verify token")
+		return nil, err
+	}
+
+	queues, err := d.downloadQueueDataAccessor.GetDownloadQueuesByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	downloadQueueList := make([]*go_load.DownloadQueue, 0, len(queues))
+	for _, queue := range queues {
+		downloadQueue, err := d.databaseDownloadQueueToProto(ctx, queue)
+		if err != nil {
+			return nil, err
+		}
+		downloadQueueList = append(downloadQueueList, downloadQueue)
+	}
+	return downloadQueueList, nil
+}
+
+func (d downloadQueueHandler) UpdateDownloadQueue(ctx context.Context, params UpdateDownloadQueueParams) (*go_load.DownloadQueue, error) {
+	accountID, _, err := d.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to verify token")
+		return nil, err
+	}
+
+	var queue database.DownloadQueue
+	txErr := d.goquDatabase.WithTx(func(tx *goqu.TxDatabase) error {
+		downloadQueueDataAccessor := d.downloadQueueDataAccessor.WithDatabase(tx)
+		queue, err = getOwnedDownloadQueue(ctx, downloadQueueDataAccessor, accountID, params.DownloadQueueID, true)
+		if err != nil {
+			return err
+		}
+
+		queue.Name = strings.TrimSpace(params.Name)
+		queue.Priority = params.Priority
+		queue.MaxConcurrentDownloadCount = params.MaxConcurrentDownloadCount
+		queue.ActiveStartTime = params.ActiveStartTime
+		queue.ActiveEndTime = params.ActiveEndTime
+		err = validateDownloadQueue(queue)
+		if err != nil {
+			return err
+		}
+
+		return downloadQueueDataAccessor.UpdateDownloadQueue(ctx, queue)
+	})
+	if txErr != nil {
+		d.logger.With(zap.Error(txErr), zap.Uint64("queueID", params.DownloadQueueID)).Error("failed to update download queue")
+		return nil, txErr
+	}
+
+	return d.databaseDownloadQueueToProto(ctx, queue)
+}
+
+// DeleteDownloadQueue deletes the queue, its tasks are kept outside of any queue.
+func (d downloadQueueHandler) DeleteDownloadQueue(ctx context.Context, params DeleteDownloadQueueParams) error {
+	accountID, _, err := d.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to verify token")
+		return err
+	}
+
+	txErr := d.goquDatabase.WithTx(func(tx *goqu.TxDatabase) error {
+		downloadQueueDataAccessor := d.downloadQueueDataAccessor.WithDatabase(tx)
+		queue, err := getOwnedDownloadQueue(ctx, downloadQueueDataAccessor, accountID, params.DownloadQueueID, true)
+		if err != nil {
+			return err
+		}
+
+		err = d.downloadTaskDataAccessor.WithDatabase(tx).RemoveDownloadTasksFromQueue(ctx, queue.ID)
+		if err != nil {
+			return err
+		}
+
+		return downloadQueueDataAccessor.DeleteDownloadQueue(ctx, queue.ID)
+	})
+	if txErr != nil {
+		d.logger.With(zap.Error(txErr), zap.Uint64("queueID", params.DownloadQueueID)).Error("failed to delete download queue")
+		return txErr
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/textproto"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
+)
+
+const (
+	defaultMaxAttemptCount = 3
+	defaultInitialBackoff  = 10 * time.Second
+	defaultMaxBackoff      = 10 * time.Minute
+)
+
+// classifyDownloadError tells what kind of failure ended a download attempt, and the HTTP status code when the attempt
+// failed on an unexpected HTTP response.
+func classifyDownloadError(err error) (go_load.DownloadErrorClass, int) {
+	// the download itself went fine, and running the steps again would repeat those that already succeeded, and an
+	// archive that cannot be extracted will not extract on the next attempt either
+	if errors.Is(err, errPostDownloadStepFailed) || errors.Is(err, errArchiveExtractionFailed) {
+		return go_load.DownloadErrorClass_OtherError, 0
+	}
+
+	var statusError httpStatusError
+	if errors.As(err, &statusError) {
+		if statusError.statusCode >= http.StatusInternalServerError {
+			return go_load.DownloadErrorClass_ServerError, statusError.statusCode
+		}
+		return go_load.DownloadErrorClass_ClientError, statusError.statusCode
+	}
+
+	// FTP replies starting with 4 are transient by definition, the server expects the command to work later
+	var ftpError *textproto.Error
+	if errors.As(err, &ftpError) {
+		if ftpError.Code >= 400 && ftpError.Code < 500 {
+			return go_load.DownloadErrorClass_ServerError, 0
+		}
+		return go_load.DownloadErrorClass_ClientError, 0
+	}
+
+	// a host that does not exist will not exist on the next attempt either, other lookup failures may be the resolver
+	var dnsError *net.DNSError
+	if errors.As(err, &dnsError) && !dnsError.IsTimeout {
+		if dnsError.IsNotFound {
+			return go_load.DownloadErrorClass_ClientError, 0
+		}
+		return go_load.DownloadErrorClass_ConnectionError, 0
+	}
+
+	var netError net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) ||
+		errors.As(err, &netError) && netError.Timeout() {
+		return go_load.DownloadErrorClass_TimeoutError, 0
+	}
+
+	// dropped and refused connections are worth retrying, other network errors such as an unreachable network or a
+	// failed TLS handshake are not fixed by trying again
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return go_load.DownloadErrorClass_ConnectionError, 0
+	}
+
+	if errors.Is(err, errDigestMismatch) || errors.Is(err, errPieceDigestMismatch) || errors.Is(err, errSizeMismatch) ||
+		errors.Is(err, errTorrentPieceMismatch) {
+		return go_load.DownloadErrorClass_IntegrityError, 0
+	}
+
+	return go_load.DownloadErrorClass_OtherError, 0
+}
+
+// isTransientDownloadError tells whether a failed attempt is worth retrying, which are timeouts, dropped connections,
+// server errors and responses asking the client to slow down.
+func isTransientDownloadError(errorClass go_load.DownloadErrorClass, httpStatusCode int) bool {
+	switch errorClass {
+	case go_load.DownloadErrorClass_TimeoutError, go_load.DownloadErrorClass_ConnectionError, go_load.DownloadErrorClass_ServerError:
+		return true
+	case go_load.DownloadErrorClass_ClientError:
+		return httpStatusCode == http.StatusRequestTimeout || httpStatusCode == http.StatusTooManyRequests
+	default:
+		return false
+	}
+}
+
+// downloadRetryPolicy decides whether and when a failed download task is attempted again.
+type downloadRetryPolicy struct {
+	maxAttemptCount uint32
+	initialBackoff  time.Duration
+	maxBackoff      time.Duration
+}
+
+func newDownloadRetryPolicy(configs configs.RetryConfig) (downloadRetryPolicy, error) {
+	policy := downloadRetryPolicy{
+		maxAttemptCount: configs.MaxAttemptCount,
+		initialBackoff:  defaultInitialBackoff,
+		maxBackoff:      defaultMaxBackoff,
+	}
+	if policy.maxAttemptCount == 0 {
+		policy.maxAttemptCount = defaultMaxAttemptCount
+	}
+
+	var err error
+	if configs.InitialBackoff != "" {
+		policy.initialBackoff, err = configs.GetInitialBackoffDuration()
+		if err != nil {
+			return downloadRetryPolicy{}, err
+		}
+	}
+
+	if configs.MaxBackoff != "" {
+		policy.maxBackoff, err = configs.GetMaxBackoffDuration()
+		if err != nil {
+			return downloadRetryPolicy{}, err
+		}
+	}
+
+	return policy, nil
+}
+
+// getMaxAttemptCount returns the attempts a task gets, its own value overriding the configured one.
+func (p downloadRetryPolicy) getMaxAttemptCount(taskMaxAttemptCount uint32) uint32 {
+	if taskMaxAttemptCount > 0 {
+		return taskMaxAttemptCount
+	}
+	return p.maxAttemptCount
+}
+
+// getBackoff returns how long to wait before the attempt following attemptCount ended attempts. The exponential
+// backoff is capped at the maximum, then a random half of it is taken off so that tasks failing together spread out.
+func (p downloadRetryPolicy) getBackoff(attemptCount uint32) time.Duration {
+	backoff := p.initialBackoff
+	for i := uint32(1); i < attemptCount && backoff < p.maxBackoff; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, p.maxBackoff)
+
+	if backoff <= 1 {
+		return backoff
+	}
+	return backoff/2 + rand.N(backoff/2)
+}
@@ -0,0 +1,135 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
+)
+
+func TestClassifyDownloadError(t *testing.T) {
+	testCases := []struct {
+		name               string
+		err                error
+		wantErrorClass     go_load.DownloadErrorClass
+		wantHTTPStatusCode int
+	}{
+		{
+			name:               "server error status",
+			err:                newHTTPStatusError(503, "unexpected response status: 503 Service Unavailable"),
+			wantErrorClass:     go_load.DownloadErrorClass_ServerError,
+			wantHTTPStatusCode: 503,
+		},
+		{
+			name:               "client error status",
+			err:                newHTTPStatusError(404, "unexpected response status: 404 Not Found"),
+			wantErrorClass:     go_load.DownloadErrorClass_ClientError,
+			wantHTTPStatusCode: 404,
+		},
+		{
+			name:               "wrapped status",
+			err:                fmt.Errorf("mirror failed: %w", newHTTPStatusError(429, "unexpected response status: 429 Too Many Requests")),
+			wantErrorClass:     go_load.DownloadErrorClass_ClientError,
+			wantHTTPStatusCode: 429,
+		},
+		{
+			name:           "transient FTP reply",
+			err:            &textproto.Error{Code: 421, Msg: "too many connections"},
+			wantErrorClass: go_load.DownloadErrorClass_ServerError,
+		},
+		{
+			name:           "permanent FTP reply",
+			err:            &textproto.Error{Code: 530, Msg: "login incorrect"},
+			wantErrorClass: go_load.DownloadErrorClass_ClientError,
+		},
+		{
+			name:           "host not found",
+			err:            &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "nope.invalid", IsNotFound: true}},
+			wantErrorClass: go_load.DownloadErrorClass_ClientError,
+		},
+		{
+			name:           "resolver failure",
+			err:            &net.DNSError{Err: "server misbehaving", Name: "example.com", IsTemporary: true},
+			wantErrorClass: go_load.DownloadErrorClass_ConnectionError,
+		},
+		{
+			name:           "resolver timeout",
+			err:            &net.DNSError{Err: "i/o timeout", Name: "example.com", IsTimeout: true},
+			wantErrorClass: go_load.DownloadErrorClass_TimeoutError,
+		},
+		{
+			name:           "context deadline",
+			err:            fmt.Errorf("download: %w", context.DeadlineExceeded),
+			wantErrorClass: go_load.DownloadErrorClass_TimeoutError,
+		},
+		{
+			name:           "read deadline",
+			err:            &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded},
+			wantErrorClass: go_load.DownloadErrorClass_TimeoutError,
+		},
+		{
+			name:           "connection reset",
+			err:            &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)},
+			wantErrorClass: go_load.DownloadErrorClass_ConnectionError,
+		},
+		{
+			name:           "connection refused",
+			err:            &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)},
+			wantErrorClass: go_load.DownloadErrorClass_ConnectionError,
+		},
+		{
+			name:           "body cut short",
+			err:            fmt.Errorf("range 0-99 ended early after 10 bytes: %w", io.ErrUnexpectedEOF),
+			wantErrorClass: go_load.DownloadErrorClass_ConnectionError,
+		},
+		{
+			name:           "unreachable network",
+			err:            &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ENETUNREACH)},
+			wantErrorClass: go_load.DownloadErrorClass_OtherError,
+		},
+		{
+			name:           "digest mismatch",
+			err:            errDigestMismatch,
+			wantErrorClass: go_load.DownloadErrorClass_IntegrityError,
+		},
+		{
+			name:           "torrent piece mismatch",
+			err:            fmt.Errorf("%w: piece 3", errTorrentPieceMismatch),
+			wantErrorClass: go_load.DownloadErrorClass_IntegrityError,
+		},
+		{
+			name:           "failed post-download step hides the cause",
+			err:            fmt.Errorf("%w: %w", errPostDownloadStepFailed, newHTTPStatusError(503, "unexpected response status: 503")),
+			wantErrorClass: go_load.DownloadErrorClass_OtherError,
+		},
+		{
+			name:           "failed extraction",
+			err:            fmt.Errorf("%w: %w", errArchiveExtractionFailed, errUnsafeArchiveEntry),
+			wantErrorClass: go_load.DownloadErrorClass_OtherError,
+		},
+		{
+			name:           "anything else",
+			err:            errors.New("disk full"),
+			wantErrorClass: go_load.DownloadErrorClass_OtherError,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			errorClass, httpStatusCode := classifyDownloadError(testCase.err)
+			if errorClass != testCase.wantErrorClass || httpStatusCode != testCase.wantHTTPStatusCode {
+				t.Errorf(
+					"classifyDownloadError(%v) = %v, %d, want %v, %d",
+					testCase.err, errorClass, httpStatusCode, testCase.wantErrorClass, testCase.wantHTTPStatusCode,
+				)
+			}
+		})
+	}
+}
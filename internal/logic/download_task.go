@@ -0,0 +1,1295 @@
+package logic
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/database"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	errStartAtWithCronExpression = errors.New("a download task cannot have both a start time and a cron expression")
+	errTimeZoneWithoutCron       = errors.New("a time zone can only be given with a cron expression")
+	errMirrorURLsNotSupported    = errors.New("mirror URLs are only supported for HTTP download tasks")
+	errInvalidMirrorURL          = errors.New("mirror URLs must be absolute HTTP or HTTPS URLs")
+	errTooManyMirrorURLs         = errors.New("too many mirror URLs")
+	errPieceCountMismatch        = errors.New("piece digests do not cover the expected size")
+)
+
+const (
+	maxMirrorURLCount = 16
+	// defaultListPageLimit is the page size of a list request that does not give a limit
+	defaultListPageLimit = 50
+	maxListPageLimit     = 500
+)
+
+type CreateDownloadTaskParams struct {
+	Token          string
+	DownloadType   go_load.DownloadType
+	URL            string
+	SegmentCount   uint32
+	MinSegmentSize uint64
+	SFTPOptions    *go_load.SFTPOptions
+	StreamOptions  *go_load.StreamOptions
+	TorrentOptions *go_load.TorrentOptions
+	S3Options      *go_load.S3Options
+	ExpectedDigest *go_load.Digest
+	// SpeedLimitBytesPerSec limits the download of the task alone, 0 means unlimited
+	SpeedLimitBytesPerSec uint64
+	// DownloadQueueID adds the task to the end of one of the account's download queues, 0 keeps it outside of any
+	DownloadQueueID uint64
+	// StartAt keeps the task Scheduled until the given time, the task starts right away when it is zero or past
+	StartAt time.Time
+	// CronExpression makes the task recurring, every run starts a new task. It is read in TimeZone, or in the time
+	// zone of the download config when TimeZone is empty.
+	CronExpression string
+	TimeZone       string
+	// MaxAttemptCount is how many attempts the task gets when failing with transient errors, 0 uses the configured one
+	MaxAttemptCount uint32
+	// MirrorURLs are other URLs serving the same file as URL, only HTTP download tasks can have them
+	MirrorURLs []string
+	// ExpectedSize and PieceDigests are checked once the download completes like ExpectedDigest, only the pieces not
+	// matching their digest are downloaded again
+	ExpectedSize uint64
+	PieceDigests *go_load.PieceDigests
+	// ExtractArchive extracts the downloaded .zip, .tar, .tar.gz, .tar.bz2 or .gz archive into a directory next to it
+	ExtractArchive bool
+	// DuplicateURLMode selects what happens when the URL was already downloaded and the origin still serves the same
+	// file, the task downloads it again by default
+	DuplicateURLMode go_load.DuplicateURLMode
+	// WatchOptions keeps checking the URL once the task completed, storing every change of the file as a new version
+	WatchOptions *go_load.WatchOptions
+	// CrawlOptions makes the task follow the links of the page at URL and download every file it finds into a
+	// directory, only HTTP download tasks can crawl
+	CrawlOptions *go_load.CrawlOptions
+}
+
+// CreateDownloadTaskOutput has either the created task, or the duplicate download found for a task created with
+// go_load.DuplicateURLMode_AskOnDuplicateURL, in which case no task was created.
+type CreateDownloadTaskOutput struct {
+	DownloadTask      *go_load.DownloadTask
+	DuplicateDownload *go_load.DuplicateDownload
+}
+
+type GetDownloadTaskListParams struct {
+	Token  string
+	Offset uint64
+	Limit  uint64
+}
+
+type GetDownloadTaskListOutput struct {
+	DownloadTaskList       []*go_load.DownloadTask
+	TotalDownloadTaskCount uint64
+}
+
+// UpdateDownloadTaskParams changes the fields that are set, an empty URL, a nil speed limit and nil mirror URLs are
+// left as they are. Empty but not nil mirror URLs remove all mirrors.
+type UpdateDownloadTaskParams struct {
+	Token                 string
+	DownloadTaskID        uint64
+	URL                   string
+	SpeedLimitBytesPerSec *uint64
+	MirrorURLs            []string
+}
+
+type DeleteDownloadTaskParams struct {
+	Token          string
+	DownloadTaskID uint64
+}
+
+type MoveDownloadTaskParams struct {
+	Token          string
+	DownloadTaskID uint64
+	// DownloadQueueID is the queue to move the task to, which may be its current one, 0 takes it out of its queue
+	DownloadQueueID uint64
+	// Position is where the task goes in the queue, 1 being the front and 0 the end
+	Position uint32
+}
+
+type GetDownloadTaskAttemptListParams struct {
+	Token          string
+	DownloadTaskID uint64
+}
+
+type GetDownloadTaskFileParams struct {
+	Token          string
+	DownloadTaskID uint64
+	// FilePath selects one of the files of a download made of several files, or one of the files extracted from the
+	// archive of a download
+	FilePath string
+	// VersionNumber selects a version of the file of a watched task, 0 selects the latest one
+	VersionNumber uint32
+}
+
+type GetDownloadTaskVersionListParams struct {
+	Token          string
+	DownloadTaskID uint64
+}
+
+type GetAccountStorageUsageParams struct {
+	Token string
+}
+
+// GetAccountStorageUsageOutput tells how much the completed downloads of an account take. LogicalByteCount counts every
+// file of every task, PhysicalByteCount splits the size of files stored once for several tasks between those tasks, so
+// that the physical usage of all accounts adds up to what is used on disk.
+type GetAccountStorageUsageOutput struct {
+	LogicalByteCount  uint64
+	PhysicalByteCount uint64
+}
+
+type GetDownloadTaskDigestsParams struct {
+	Token          string
+	DownloadTaskID uint64
+	FilePath       string
+	Algorithms     []go_load.DigestAlgorithm
+}
+
+// downloadTaskMetadata is stored as JSON in the metadata column of a download task.
+type downloadTaskMetadata struct {
+	FileName       string `json:"file_name,omitempty"`
+	SegmentCount   uint32 `json:"segment_count,omitempty"`
+	MinSegmentSize uint64 `json:"min_segment_size,omitempty"`
+	// the fields below describe the remote file the saved segment progress belongs to
+	ValidatedURL string            `json:"validated_url,omitempty"`
+	Size         int64             `json:"size,omitempty"`
+	ETag         string            `json:"etag,omitempty"`
+	LastModified string            `json:"last_modified,omitempty"`
+	Segments     []downloadSegment `json:"segments,omitempty"`
+	// DownloadedByteCount is the total progress across all segments, it is reported to users
+	DownloadedByteCount int64 `json:"downloaded_byte_count,omitempty"`
+	// StreamSegments records which media segments of a stream download are completed
+	StreamSegments []bool `json:"stream_segments,omitempty"`
+	// TorrentPieces is the bitfield of the verified pieces of a BitTorrent download, TorrentPieceCount is how many
+	// pieces overlap with the selected files
+	TorrentPieces     []byte `json:"torrent_pieces,omitempty"`
+	TorrentPieceCount uint32 `json:"torrent_piece_count,omitempty"`
+	// Files lists the paths relative to FileName of a download made of several files, FileName is then a directory
+	Files []string `json:"files,omitempty"`
+	// CompletedFiles records which of Files are completed, for downloads that fetch them one after the other
+	CompletedFiles []bool `json:"completed_files,omitempty"`
+	// CrawledURLs are the URLs Files were downloaded from by a crawl
+	CrawledURLs    []string        `json:"crawled_urls,omitempty"`
+	SFTPOptions    *sftpOptions    `json:"sftp_options,omitempty"`
+	StreamOptions  *streamOptions  `json:"stream_options,omitempty"`
+	TorrentOptions *torrentOptions `json:"torrent_options,omitempty"`
+	S3Options      *s3Options      `json:"s3_options,omitempty"`
+	CrawlOptions   *crawlOptions   `json:"crawl_options,omitempty"`
+	// ExpectedDigest is checked once the download completes, SHA256 is computed for every download of a single file
+	ExpectedDigest *digest       `json:"expected_digest,omitempty"`
+	ExpectedSize   int64         `json:"expected_size,omitempty"`
+	PieceDigests   *pieceDigests `json:"piece_digests,omitempty"`
+	SHA256         string        `json:"sha256,omitempty"`
+	// FailureReason tells users why the task is Failed
+	FailureReason string `json:"failure_reason,omitempty"`
+	// PostDownloadStepResults records the steps run on the file once the download succeeded
+	PostDownloadStepResults []postDownloadStepResult `json:"post_download_step_results,omitempty"`
+	ExtractArchive          bool                     `json:"extract_archive,omitempty"`
+	// ExtractedDirectory is where the archive of the download was extracted to, ExtractedFiles lists the files in it
+	ExtractedDirectory string          `json:"extracted_directory,omitempty"`
+	ExtractedFiles     []extractedFile `json:"extracted_files,omitempty"`
+	// ReusedExistingDownload tells the task was completed with the file of another task of the same URL
+	ReusedExistingDownload bool          `json:"reused_existing_download,omitempty"`
+	WatchOptions           *watchOptions `json:"watch_options,omitempty"`
+	// VersionNumber is the version of the file of a watched task FileName holds, 0 until the first check recorded it
+	VersionNumber uint32 `json:"version_number,omitempty"`
+}
+
+type sftpOptions struct {
+	Password             string `json:"password,omitempty"`
+	PrivateKey           string `json:"private_key,omitempty"`
+	PrivateKeyPassphrase string `json:"private_key_passphrase,omitempty"`
+	// HostKey is either an authorized_keys line or a SHA256 fingerprint. When it is not given, the key the server
+	// presents on the first connection is pinned for the rest of the task.
+	HostKey string `json:"host_key,omitempty"`
+	// KnownHostKeys maps the hosts the task connected to without a pinned host key to the fingerprint of their key
+	KnownHostKeys map[string]string `json:"known_host_keys,omitempty"`
+}
+
+type streamOptions struct {
+	// Bandwidth selects the variant with the highest bandwidth not above it, 0 selects the highest bandwidth variant
+	Bandwidth uint64 `json:"bandwidth,omitempty"`
+}
+
+type torrentOptions struct {
+	// FileIndexes selects the files of a multi-file torrent to download, all files are downloaded when it is empty
+	FileIndexes []uint32 `json:"file_indexes,omitempty"`
+}
+
+type crawlOptions struct {
+	MaxDepth uint32 `json:"max_depth,omitempty"`
+	// AllowedDomains are the hosts links are followed to, with their subdomains, only the host of the URL of the task
+	// is crawled when it is empty
+	AllowedDomains []string `json:"allowed_domains,omitempty"`
+	// PathPrefix is the path links are followed under, the directory of the URL of the task when it is empty
+	PathPrefix      string   `json:"path_prefix,omitempty"`
+	IncludePatterns []string `json:"include_patterns,omitempty"`
+	ExcludePatterns []string `json:"exclude_patterns,omitempty"`
+	RewriteLinks    bool     `json:"rewrite_links,omitempty"`
+}
+
+type watchOptions struct {
+	CheckIntervalSec uint32 `json:"check_interval_sec,omitempty"`
+	// RetentionCount is how many versions of the file are kept, the latest one included
+	RetentionCount uint32 `json:"retention_count,omitempty"`
+}
+
+type s3Options struct {
+	// Profile names an S3 profile of the download config, the fields below override the values of the profile
+	Profile         string `json:"profile,omitempty"`
+	Endpoint        string `json:"endpoint,omitempty"`
+	Region          string `json:"region,omitempty"`
+	AccessKeyID     string `json:"access_key_id,omitempty"`
+	SecretAccessKey string `json:"secret_access_key,omitempty"`
+	SessionToken    string `json:"session_token,omitempty"`
+}
+
+// downloadSegment is an inclusive byte range of the file being downloaded, together with how much of it is already
+// written to disk.
+type downloadSegment struct {
+	Start               int64 `json:"start"`
+	End                 int64 `json:"end"`
+	DownloadedByteCount int64 `json:"downloaded_byte_count"`
+}
+
+func (s downloadSegment) Length() int64 {
+	return s.End - s.Start + 1
+}
+
+func (s downloadSegment) IsCompleted() bool {
+	return s.DownloadedByteCount >= s.Length()
+}
+
+func sftpOptionsFromProto(options *go_load.SFTPOptions) *sftpOptions {
+	if options == nil {
+		return nil
+	}
+	return &sftpOptions{
+		Password:             options.GetPassword(),
+		PrivateKey:           options.GetPrivateKey(),
+		PrivateKeyPassphrase: options.GetPrivateKeyPassphrase(),
+		HostKey:              options.GetHostKey(),
+	}
+}
+
+func streamOptionsFromProto(options *go_load.StreamOptions) *streamOptions {
+	if options == nil {
+		return nil
+	}
+	return &streamOptions{
+		Bandwidth: options.GetBandwidth(),
+	}
+}
+
+func torrentOptionsFromProto(options *go_load.TorrentOptions) *torrentOptions {
+	if options == nil {
+		return nil
+	}
+	return &torrentOptions{
+		FileIndexes: options.GetFileIndexes(),
+	}
+}
+
+func s3OptionsFromProto(options *go_load.S3Options) *s3Options {
+	if options == nil {
+		return nil
+	}
+	return &s3Options{
+		Profile:         options.GetProfile(),
+		Endpoint:        options.GetEndpoint(),
+		Region:          options.GetRegion(),
+		AccessKeyID:     options.GetAccessKeyId(),
+		SecretAccessKey: options.GetSecretAccessKey(),
+		SessionToken:    options.GetSessionToken(),
+	}
+}
+
+// splitMirrorURLs returns the mirror URLs stored in the mirror_urls column of a download task.
+func splitMirrorURLs(mirrorURLs string) []string {
+	if mirrorURLs == "" {
+		return nil
+	}
+	return strings.Split(mirrorURLs, "\n")
+}
+
+// joinMirrorURLs checks the mirror URLs of a download task and returns them as stored in its mirror_urls column.
+// Duplicates and mirrors equal to the URL of the task are dropped.
+func joinMirrorURLs(downloadType go_load.DownloadType, downloadURL string, mirrorURLs []string) (string, error) {
+	if len(mirrorURLs) == 0 {
+		return "", nil
+	}
+
+	if downloadType != go_load.DownloadType_HTTP {
+		return "", errMirrorURLsNotSupported
+	}
+
+	uniqueMirrorURLs := make([]string, 0, len(mirrorURLs))
+	for _, mirrorURL := range mirrorURLs {
+		parsedURL, err := url.Parse(mirrorURL)
+		if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+			return "", errInvalidMirrorURL
+		}
+
+		if mirrorURL != downloadURL && !slices.Contains(uniqueMirrorURLs, mirrorURL) {
+			uniqueMirrorURLs = append(uniqueMirrorURLs, mirrorURL)
+		}
+	}
+
+	if len(uniqueMirrorURLs) > maxMirrorURLCount {
+		return "", errTooManyMirrorURLs
+	}
+	return strings.Join(uniqueMirrorURLs, "\n"), nil
+}
+
+func parseDownloadTaskMetadata(metadata string) (downloadTaskMetadata, error) {
+	result := downloadTaskMetadata{}
+	if metadata == "" {
+		return result, nil
+	}
+	err := json.Unmarshal([]byte(metadata), &result)
+	return result, err
+}
+
+// getSegmentCounts returns how many segments of the download are completed, out of how many. Stream downloads count
+// media segments, BitTorrent downloads count pieces, S3 prefix downloads count objects, other downloads count byte
+// range segments.
+func (m downloadTaskMetadata) getSegmentCounts() (uint32, uint32) {
+	if m.TorrentPieceCount > 0 {
+		return countTorrentPieces(m.TorrentPieces), m.TorrentPieceCount
+	}
+
+	if len(m.CompletedFiles) > 0 {
+		return uint32(countTrue(m.CompletedFiles)), uint32(len(m.CompletedFiles))
+	}
+
+	if len(m.StreamSegments) > 0 {
+		return uint32(countTrue(m.StreamSegments)), uint32(len(m.StreamSegments))
+	}
+
+	completedSegmentCount := uint32(0)
+	for _, segment := range m.Segments {
+		if segment.IsCompleted() {
+			completedSegmentCount++
+		}
+	}
+	return completedSegmentCount, uint32(len(m.Segments))
+}
+
+// withoutProgress returns the metadata with only what the task was created with, dropping the saved progress.
+func (m downloadTaskMetadata) withoutProgress() downloadTaskMetadata {
+	return downloadTaskMetadata{
+		FileName:       m.FileName,
+		SegmentCount:   m.SegmentCount,
+		MinSegmentSize: m.MinSegmentSize,
+		SFTPOptions:    m.SFTPOptions,
+		StreamOptions:  m.StreamOptions,
+		TorrentOptions: m.TorrentOptions,
+		S3Options:      m.S3Options,
+		CrawlOptions:   m.CrawlOptions,
+		ExpectedDigest: m.ExpectedDigest,
+		ExpectedSize:   m.ExpectedSize,
+		PieceDigests:   m.PieceDigests,
+		ExtractArchive: m.ExtractArchive,
+		WatchOptions:   m.WatchOptions,
+	}
+}
+
+func countTrue(values []bool) int {
+	count := 0
+	for _, value := range values {
+		if value {
+			count++
+		}
+	}
+	return count
+}
+
+func (m downloadTaskMetadata) String() string {
+	metadataBytes, err := json.Marshal(m)
+	if err != nil {
+		return "{}"
+	}
+	return string(metadataBytes)
+}
+
+type DownloadTaskHandler interface {
+	CreateDownloadTask(ctx context.Context, params CreateDownloadTaskParams) (CreateDownloadTaskOutput, error)
+	GetDownloadTaskList(ctx context.Context, params GetDownloadTaskListParams) (GetDownloadTaskListOutput, error)
+	UpdateDownloadTask(ctx context.Context, params UpdateDownloadTaskParams) (*go_load.DownloadTask, error)
+	DeleteDownloadTask(ctx context.Context, params DeleteDownloadTaskParams) error
+	MoveDownloadTask(ctx context.Context, params MoveDownloadTaskParams) (*go_load.DownloadTask, error)
+	GetDownloadTaskAttemptList(ctx context.Context, params GetDownloadTaskAttemptListParams) ([]*go_load.DownloadTaskAttempt, error)
+	GetDownloadTaskFile(ctx context.Context, params GetDownloadTaskFileParams) (io.ReadCloser, error)
+	GetDownloadTaskDigests(ctx context.Context, params GetDownloadTaskDigestsParams) ([]*go_load.Digest, error)
+	ImportMetalink(ctx context.Context, params ImportMetalinkParams) ([]*go_load.DownloadTask, error)
+	ExportDownloadTaskMetalink(ctx context.Context, params ExportDownloadTaskMetalinkParams) ([]byte, error)
+	GetAccountStorageUsage(ctx context.Context, params GetAccountStorageUsageParams) (GetAccountStorageUsageOutput, error)
+	GetDownloadTaskVersionList(ctx context.Context, params GetDownloadTaskVersionListParams) ([]*go_load.DownloadTaskVersion, error)
+	GrabLinks(ctx context.Context, params GrabLinksParams) ([]*go_load.GrabbedLink, error)
+	CreateDownloadTasksFromLinks(ctx context.Context, params CreateDownloadTasksFromLinksParams) ([]*go_load.DownloadTask, error)
+}
+
+type downloadTaskHandler struct {
+	tokenHandler                    TokenHandler
+	accountDataAccessor             database.AccountDataAccessor
+	downloadTaskDataAccessor        database.DownloadTaskDataAccessor
+	downloadQueueDataAccessor       database.DownloadQueueDataAccessor
+	downloadTaskAttemptDataAccessor database.DownloadTaskAttemptDataAccessor
+	blobDataAccessor                database.BlobDataAccessor
+	downloadTaskVersionDataAccessor database.DownloadTaskVersionDataAccessor
+	blobStore                       blobStore
+	duplicateURLResolver            duplicateURLResolver
+	linkGrabber                     linkGrabber
+	fileClient                      file.Client
+	downloaderRegistry              DownloaderRegistry
+	webhookEventPublisher           WebhookEventPublisher
+	goquDatabase                    *goqu.Database
+	location                        *time.Location
+	logger                          *zap.Logger
+}
+
+func NewDownloadTaskHandler(
+	configs configs.DownloadConfig,
+	tokenHandler TokenHandler,
+	accountDataAccessor database.AccountDataAccessor,
+	downloadTaskDataAccessor database.DownloadTaskDataAccessor,
+	downloadQueueDataAccessor database.DownloadQueueDataAccessor,
+	downloadTaskAttemptDataAccessor database.DownloadTaskAttemptDataAccessor,
+	blobDataAccessor database.BlobDataAccessor,
+	downloadTaskVersionDataAccessor database.DownloadTaskVersionDataAccessor,
+	fileClient file.Client,
+	downloaderRegistry DownloaderRegistry,
+	webhookEventPublisher WebhookEventPublisher,
+	goquDatabase *goqu.Database,
+	logger *zap.Logger,
+) (DownloadTaskHandler, error) {
+	location, err := configs.GetTimeZoneLocation()
+	if err != nil {
+		return nil, err
+	}
+
+	duplicateURLResolver, err := newDuplicateURLResolver(
+		configs.DuplicateURLConfig, downloadTaskDataAccessor, blobDataAccessor, fileClient, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &downloadTaskHandler{
+		tokenHandler:                    tokenHandler,
+		accountDataAccessor:             accountDataAccessor,
+		downloadTaskDataAccessor:        downloadTaskDataAccessor,
+		downloadQueueDataAccessor:       downloadQueueDataAccessor,
+		downloadTaskAttemptDataAccessor: downloadTaskAttemptDataAccessor,
+		blobDataAccessor:                blobDataAccessor,
+		downloadTaskVersionDataAccessor: downloadTaskVersionDataAccessor,
+		blobStore:                       newBlobStore(goquDatabase, downloadTaskDataAccessor, blobDataAccessor, fileClient, logger),
+		duplicateURLResolver:            duplicateURLResolver,
+		linkGrabber:                     newLinkGrabber(logger),
+		fileClient:                      fileClient,
+		downloaderRegistry:              downloaderRegistry,
+		webhookEventPublisher:           webhookEventPublisher,
+		goquDatabase:                    goquDatabase,
+		location:                        location,
+		logger:                          logger,
+	}, nil
+}
+
+func (d downloadTaskHandler) databaseDownloadTaskToProto(ctx context.Context, task database.DownloadTask) (*go_load.DownloadTask, error) {
+	account, err := d.accountDataAccessor.GetAccountByID(ctx, task.OfAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := parseDownloadTaskMetadata(task.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	var nextRunAt, nextRetryAt, nextCheckAt *timestamppb.Timestamp
+	if task.NextRunAt.Valid {
+		nextRunAt = timestamppb.New(task.NextRunAt.Time)
+	}
+	if task.NextRetryAt.Valid {
+		nextRetryAt = timestamppb.New(task.NextRetryAt.Time)
+	}
+	if task.NextCheckAt.Valid {
+		nextCheckAt = timestamppb.New(task.NextCheckAt.Time)
+	}
+
+	var protoCrawlOptions *go_load.CrawlOptions
+	if metadata.CrawlOptions != nil {
+		protoCrawlOptions = &go_load.CrawlOptions{
+			MaxDepth:        metadata.CrawlOptions.MaxDepth,
+			AllowedDomains:  metadata.CrawlOptions.AllowedDomains,
+			PathPrefix:      metadata.CrawlOptions.PathPrefix,
+			IncludePatterns: metadata.CrawlOptions.IncludePatterns,
+			ExcludePatterns: metadata.CrawlOptions.ExcludePatterns,
+			RewriteLinks:    metadata.CrawlOptions.RewriteLinks,
+		}
+	}
+
+	var protoWatchOptions *go_load.WatchOptions
+	if metadata.WatchOptions != nil {
+		protoWatchOptions = &go_load.WatchOptions{
+			CheckIntervalSec: metadata.WatchOptions.CheckIntervalSec,
+			RetentionCount:   metadata.WatchOptions.RetentionCount,
+		}
+	}
+
+	extractedFiles := make([]*go_load.ExtractedFile, 0, len(metadata.ExtractedFiles))
+	for _, extracted := range metadata.ExtractedFiles {
+		extractedFiles = append(extractedFiles, &go_load.ExtractedFile{
+			FilePath: extracted.FilePath,
+			Size:     uint64(extracted.Size),
+		})
+	}
+
+	postDownloadStepResults := make([]*go_load.PostDownloadStepResult, 0, len(metadata.PostDownloadStepResults))
+	for _, result := range metadata.PostDownloadStepResults {
+		postDownloadStepResults = append(postDownloadStepResults, result.toProto())
+	}
+
+	downloadedSegmentCount, totalSegmentCount := metadata.getSegmentCounts()
+	return &go_load.DownloadTask{
+		Id: task.ID,
+		OfAccount: &go_load.Account{
+			Id:                    account.ID,
+			AccountName:           account.AccountName,
+			SpeedLimitBytesPerSec: account.SpeedLimitBytesPerSec,
+		},
+		DownloadType:            go_load.DownloadType(task.DownloadType),
+		Url:                     task.URL,
+		DownloadStatus:          go_load.DownloadStatus(task.DownloadStatus),
+		DownloadedByteCount:     uint64(metadata.DownloadedByteCount),
+		TotalByteCount:          uint64(metadata.Size),
+		DownloadedSegmentCount:  downloadedSegmentCount,
+		TotalSegmentCount:       totalSegmentCount,
+		FilePaths:               metadata.Files,
+		Sha256:                  metadata.SHA256,
+		FailureReason:           metadata.FailureReason,
+		SpeedLimitBytesPerSec:   task.SpeedLimitBytesPerSec,
+		DownloadQueueId:         task.OfQueueID,
+		NextRunAt:               nextRunAt,
+		CronExpression:          task.CronExpression,
+		TimeZone:                task.TimeZone,
+		AttemptCount:            task.AttemptCount,
+		MaxAttemptCount:         task.MaxAttemptCount,
+		NextRetryAt:             nextRetryAt,
+		MirrorUrls:              splitMirrorURLs(task.MirrorURLs),
+		PostDownloadStepResults: postDownloadStepResults,
+		ExtractArchive:          metadata.ExtractArchive,
+		ExtractedFiles:          extractedFiles,
+		ReusedExistingDownload:  metadata.ReusedExistingDownload,
+		WatchOptions:            protoWatchOptions,
+		VersionNumber:           metadata.VersionNumber,
+		NextCheckAt:             nextCheckAt,
+		CrawlOptions:            protoCrawlOptions,
+	}, nil
+}
+
+// scheduleDownloadTask sets the status and next run time of a new task from its start time or cron expression.
+func (d downloadTaskHandler) scheduleDownloadTask(task *database.DownloadTask, params CreateDownloadTaskParams) error {
+	now := time.Now()
+	switch {
+	case params.CronExpression != "":
+		if !params.StartAt.IsZero() {
+			return errStartAtWithCronExpression
+		}
+
+		nextRunAt, err := getDownloadTaskNextRunTime(params.CronExpression, params.TimeZone, d.location, now)
+		if err != nil {
+			return err
+		}
+
+		task.DownloadStatus = uint16(go_load.DownloadStatus_Scheduled)
+		task.NextRunAt = sql.NullTime{Time: nextRunAt, Valid: true}
+		task.CronExpression = params.CronExpression
+		task.TimeZone = params.TimeZone
+	case params.TimeZone != "":
+		return errTimeZoneWithoutCron
+	case params.StartAt.After(now):
+		task.DownloadStatus = uint16(go_load.DownloadStatus_Scheduled)
+		task.NextRunAt = sql.NullTime{Time: params.StartAt, Valid: true}
+	}
+	return nil
+}
+
+// getOwnedDownloadTask returns the download task only if it belongs to the account the token was issued for.
+func (d downloadTaskHandler) getOwnedDownloadTask(
+	ctx context.Context,
+	downloadTaskDataAccessor database.DownloadTaskDataAccessor,
+	accountID uint64,
+	downloadTaskID uint64,
+	lock bool,
+) (database.DownloadTask, error) {
+	var (
+		task database.DownloadTask
+		err  error
+	)
+	if lock {
+		task, err = downloadTaskDataAccessor.GetDownloadTaskByIDWithXLock(ctx, downloadTaskID)
+	} else {
+		task, err = downloadTaskDataAccessor.GetDownloadTaskByID(ctx, downloadTaskID)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.DownloadTask{}, errors.New("download task not found")
+		}
+		return database.DownloadTask{}, err
+	}
+
+	if task.OfAccountID != accountID {
+		d.logger.With(zap.Uint64("accountID", accountID), zap.Uint64("taskID", downloadTaskID)).Warn("account does not own download task")
+		return database.DownloadTask{}, errors.New("download task not found")
+	}
+
+	return task, nil
+}
+
+func (d downloadTaskHandler) CreateDownloadTask(ctx context.Context, params CreateDownloadTaskParams) (CreateDownloadTaskOutput, error) {
+	accountID, _, err := d.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to verify token")
+		return CreateDownloadTaskOutput{}, err
+	}
+
+	return d.createDownloadTask(ctx, accountID, params)
+}
+
+// createDownloadTask creates a download task for the account, the token of params is not checked. A task allowed to
+// reuse the file of a duplicate is created completed with it.
+func (d downloadTaskHandler) createDownloadTask(ctx context.Context, accountID uint64, params CreateDownloadTaskParams) (CreateDownloadTaskOutput, error) {
+	_, err := d.downloaderRegistry.GetDownloader(params.DownloadType)
+	if err != nil {
+		d.logger.With(zap.String("downloadType", params.DownloadType.String())).Warn("unsupported download type")
+		return CreateDownloadTaskOutput{}, err
+	}
+
+	expectedDigest, err := digestFromProto(params.ExpectedDigest)
+	if err != nil {
+		return CreateDownloadTaskOutput{}, err
+	}
+
+	pieceDigests, err := pieceDigestsFromProto(params.PieceDigests)
+	if err != nil {
+		return CreateDownloadTaskOutput{}, err
+	}
+
+	if pieceDigests != nil && params.ExpectedSize > 0 && pieceDigests.getPieceCount(int64(params.ExpectedSize)) != len(pieceDigests.Values) {
+		return CreateDownloadTaskOutput{}, errPieceCountMismatch
+	}
+
+	downloadURL := params.URL
+	if params.DownloadType == go_load.DownloadType_BITTORRENT {
+		// the torrent itself is what gets downloaded, the URL of the task only identifies it
+		metainfo, err := parseTorrentMetainfo(params.TorrentOptions.GetTorrent())
+		if err != nil {
+			d.logger.With(zap.Error(err)).Warn("invalid torrent file")
+			return CreateDownloadTaskOutput{}, err
+		}
+
+		_, err = metainfo.getSelectedFiles(params.TorrentOptions.GetFileIndexes())
+		if err != nil {
+			return CreateDownloadTaskOutput{}, err
+		}
+
+		downloadURL = metainfo.getMagnetURI()
+	}
+
+	mirrorURLs, err := joinMirrorURLs(params.DownloadType, downloadURL, params.MirrorURLs)
+	if err != nil {
+		return CreateDownloadTaskOutput{}, err
+	}
+
+	watchOptions, err := watchOptionsFromProto(params)
+	if err != nil {
+		return CreateDownloadTaskOutput{}, err
+	}
+
+	crawlOptions, err := crawlOptionsFromProto(params)
+	if err != nil {
+		return CreateDownloadTaskOutput{}, err
+	}
+
+	task := database.DownloadTask{
+		OfAccountID:    accountID,
+		DownloadType:   uint16(params.DownloadType),
+		URL:            downloadURL,
+		DownloadStatus: uint16(go_load.DownloadStatus_Pending),
+		Metadata: downloadTaskMetadata{
+			SegmentCount:   params.SegmentCount,
+			MinSegmentSize: params.MinSegmentSize,
+			SFTPOptions:    sftpOptionsFromProto(params.SFTPOptions),
+			StreamOptions:  streamOptionsFromProto(params.StreamOptions),
+			TorrentOptions: torrentOptionsFromProto(params.TorrentOptions),
+			S3Options:      s3OptionsFromProto(params.S3Options),
+			CrawlOptions:   crawlOptions,
+			ExpectedDigest: expectedDigest,
+			ExpectedSize:   int64(params.ExpectedSize),
+			PieceDigests:   pieceDigests,
+			ExtractArchive: params.ExtractArchive,
+			WatchOptions:   watchOptions,
+		}.String(),
+		SpeedLimitBytesPerSec: params.SpeedLimitBytesPerSec,
+		MaxAttemptCount:       params.MaxAttemptCount,
+		MirrorURLs:            mirrorURLs,
+	}
+	err = d.scheduleDownloadTask(&task, params)
+	if err != nil {
+		return CreateDownloadTaskOutput{}, err
+	}
+	if watchOptions != nil {
+		// the first check, once the task completed, records the downloaded file as the first version
+		task.NextCheckAt = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+
+	var duplicateTask database.DownloadTask
+	duplicateFound := false
+	if canReuseDownload(params, expectedDigest) {
+		var duplicateMetadata downloadTaskMetadata
+		duplicateTask, duplicateMetadata, duplicateFound = d.duplicateURLResolver.findReusableDownloadTask(
+			ctx, accountID, downloadURL, params.ExpectedSize, expectedDigest)
+		if duplicateFound && params.DuplicateURLMode == go_load.DuplicateURLMode_AskOnDuplicateURL {
+			return CreateDownloadTaskOutput{
+				DuplicateDownload: getDuplicateDownload(accountID, duplicateTask, duplicateMetadata),
+			}, nil
+		}
+	}
+
+	reused := false
+
+	txErr := d.goquDatabase.WithTx(func(tx *goqu.TxDatabase) error {
+		downloadTaskDataAccessor := d.downloadTaskDataAccessor.WithDatabase(tx)
+		if params.DownloadQueueID != 0 {
+			_, err = getOwnedDownloadQueue(ctx, d.downloadQueueDataAccessor.WithDatabase(tx), accountID, params.DownloadQueueID, true)
+			if err != nil {
+				return err
+			}
+		}
+
+		task.ID, err = downloadTaskDataAccessor.CreateDownloadTask(ctx, task)
+		if err != nil {
+			return err
+		}
+
+		if params.DownloadQueueID != 0 {
+			err = placeDownloadTaskInQueue(ctx, downloadTaskDataAccessor, params.DownloadQueueID, task.ID, 0)
+			if err != nil {
+				return err
+			}
+		}
+
+		if duplicateFound {
+			reused, err = d.duplicateURLResolver.reuseDownloadTask(ctx, tx, &task, duplicateTask.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		if params.DownloadType != go_load.DownloadType_BITTORRENT {
+			return nil
+		}
+
+		// the torrent is written before the task is committed, so that the executor never picks up a task without it
+		return d.writeTorrentFile(ctx, task.ID, params.TorrentOptions.GetTorrent())
+	})
+	if txErr != nil {
+		d.logger.With(zap.Error(txErr), zap.Uint64("accountID", accountID)).Error("failed to create download task")
+		return CreateDownloadTaskOutput{}, txErr
+	}
+
+	d.logger.With(zap.Uint64("accountID", accountID), zap.Uint64("taskID", task.ID)).Info("download task created")
+	d.webhookEventPublisher.Publish(ctx, WebhookEvent{
+		EventType:    go_load.WebhookEventType_DownloadTaskCreated,
+		DownloadTask: task,
+	})
+	if reused {
+		d.webhookEventPublisher.Publish(ctx, WebhookEvent{
+			EventType:    go_load.WebhookEventType_DownloadTaskSucceeded,
+			DownloadTask: task,
+		})
+	}
+
+	downloadTask, err := d.databaseDownloadTaskToProto(ctx, task)
+	if err != nil {
+		return CreateDownloadTaskOutput{}, err
+	}
+	return CreateDownloadTaskOutput{DownloadTask: downloadTask}, nil
+}
+
+func (d downloadTaskHandler) writeTorrentFile(ctx context.Context, taskID uint64, torrent []byte) error {
+	writer, err := d.fileClient.Write(ctx, getTorrentFileName(taskID))
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(torrent)
+	closeErr := writer.Close()
+	return errors.Join(err, closeErr)
+}
+
+func (d downloadTaskHandler) GetDownloadTaskList(ctx context.Context, params GetDownloadTaskListParams) (GetDownloadTaskListOutput, error) {
+	accountID, _, err := d.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to verify token")
+		return GetDownloadTaskListOutput{}, err
+	}
+
+	totalDownloadTaskCount, err := d.downloadTaskDataAccessor.GetDownloadTaskCountByAccountID(ctx, accountID)
+	if err != nil {
+		return GetDownloadTaskListOutput{}, err
+	}
+
+	limit := getListPageLimit(params.Limit)
+	tasks, err := d.downloadTaskDataAccessor.GetDownloadTasksByAccountID(ctx, accountID, params.Offset, limit)
+	if err != nil {
+		return GetDownloadTaskListOutput{}, err
+	}
+
+	downloadTaskList := make([]*go_load.DownloadTask, 0, len(tasks))
+	for _, task := range tasks {
+		downloadTask, err := d.databaseDownloadTaskToProto(ctx, task)
+		if err != nil {
+			return GetDownloadTaskListOutput{}, err
+		}
+		downloadTaskList = append(downloadTaskList, downloadTask)
+	}
+
+	return GetDownloadTaskListOutput{
+		DownloadTaskList:       downloadTaskList,
+		TotalDownloadTaskCount: totalDownloadTaskCount,
+	}, nil
+}
+
+// getListPageLimit returns the page size a list request is served with, a request without a limit gets the default
+// page size and larger limits are clamped so that no request lists every row of an account.
+func getListPageLimit(limit uint64) uint64 {
+	if limit == 0 {
+		return defaultListPageLimit
+	}
+	return min(limit, maxListPageLimit)
+}
+
+func (d downloadTaskHandler) UpdateDownloadTask(ctx context.Context, params UpdateDownloadTaskParams) (*go_load.DownloadTask, error) {
+	accountID, _, err := d.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to verify token")
+		return nil, err
+	}
+
+	var task database.DownloadTask
+	txErr := d.goquDatabase.WithTx(func(tx *goqu.TxDatabase) error {
+		downloadTaskDataAccessor := d.downloadTaskDataAccessor.WithDatabase(tx)
+		task, err = d.getOwnedDownloadTask(ctx, downloadTaskDataAccessor, accountID, params.DownloadTaskID, true)
+		if err != nil {
+			return err
+		}
+
+		if params.SpeedLimitBytesPerSec != nil {
+			// a running download picks up the new limit the next time its progress is saved
+			task.SpeedLimitBytesPerSec = *params.SpeedLimitBytesPerSec
+		}
+
+		urlChanged := false
+		if params.URL != "" && params.URL != task.URL {
+			if task.DownloadType == uint16(go_load.DownloadType_BITTORRENT) {
+				return errors.New("the URL of a BitTorrent download task cannot be changed")
+			}
+
+			task.URL = params.URL
+			urlChanged = true
+		}
+
+		if params.MirrorURLs != nil || urlChanged {
+			mirrorURLs := params.MirrorURLs
+			if mirrorURLs == nil {
+				mirrorURLs = splitMirrorURLs(task.MirrorURLs)
+			}
+
+			// joined again when only the URL changed, it may now be one of the mirrors
+			joinedMirrorURLs, err := joinMirrorURLs(go_load.DownloadType(task.DownloadType), task.URL, mirrorURLs)
+			if err != nil {
+				return err
+			}
+
+			if joinedMirrorURLs != task.MirrorURLs {
+				task.MirrorURLs = joinedMirrorURLs
+				urlChanged = true
+			}
+		}
+
+		if urlChanged && task.DownloadStatus == uint16(go_load.DownloadStatus_Failed) {
+			// give the task another try from the new URLs, saved progress is reused if it points at the same file. It
+			// gets all of its attempts again.
+			task.DownloadStatus = uint16(go_load.DownloadStatus_Pending)
+			task.AttemptCount = 0
+			task.NextRetryAt = sql.NullTime{}
+		}
+		return downloadTaskDataAccessor.UpdateDownloadTask(ctx, task)
+	})
+	if txErr != nil {
+		d.logger.With(zap.Error(txErr), zap.Uint64("taskID", params.DownloadTaskID)).Error("failed to update download task")
+		return nil, txErr
+	}
+
+	return d.databaseDownloadTaskToProto(ctx, task)
+}
+
+func (d downloadTaskHandler) DeleteDownloadTask(ctx context.Context, params DeleteDownloadTaskParams) error {
+	accountID, _, err := d.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to verify token")
+		return err
+	}
+
+	var (
+		task     database.DownloadTask
+		metadata downloadTaskMetadata
+		versions []database.DownloadTaskVersion
+	)
+	txErr := d.goquDatabase.WithTx(func(tx *goqu.TxDatabase) error {
+		downloadTaskDataAccessor := d.downloadTaskDataAccessor.WithDatabase(tx)
+		task, err = d.getOwnedDownloadTask(ctx, downloadTaskDataAccessor, accountID, params.DownloadTaskID, true)
+		if err != nil {
+			return err
+		}
+
+		metadata, err = parseDownloadTaskMetadata(task.Metadata)
+		if err != nil {
+			return err
+		}
+
+		if metadata.WatchOptions != nil {
+			// the versions go with the task, their files are deleted once it is
+			versions, err = d.downloadTaskVersionDataAccessor.WithDatabase(tx).GetDownloadTaskVersionsByDownloadTaskID(ctx, task.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		if task.BlobSHA256 != "" {
+			err = d.blobStore.release(ctx, tx, task.BlobSHA256)
+			if err != nil {
+				return err
+			}
+		}
+
+		return downloadTaskDataAccessor.DeleteDownloadTask(ctx, task.ID)
+	})
+	if txErr != nil {
+		d.logger.With(zap.Error(txErr), zap.Uint64("taskID", params.DownloadTaskID)).Error("failed to delete download task")
+		return txErr
+	}
+
+	if metadata.FileName != "" {
+		err = d.fileClient.Delete(ctx, metadata.FileName)
+		if err != nil {
+			d.logger.With(zap.Error(err), zap.Uint64("taskID", params.DownloadTaskID)).Warn("failed to delete downloaded file")
+		}
+	}
+
+	for _, version := range versions {
+		if version.FileName == metadata.FileName {
+			continue
+		}
+
+		err = d.fileClient.Delete(ctx, version.FileName)
+		if err != nil {
+			d.logger.With(zap.Error(err), zap.Uint64("taskID", params.DownloadTaskID)).Warn("failed to delete version file")
+		}
+	}
+
+	if metadata.ExtractedDirectory != "" {
+		err = d.fileClient.Delete(ctx, metadata.ExtractedDirectory)
+		if err != nil {
+			d.logger.With(zap.Error(err), zap.Uint64("taskID", params.DownloadTaskID)).Warn("failed to delete extracted files")
+		}
+	}
+
+	if task.DownloadType == uint16(go_load.DownloadType_BITTORRENT) {
+		err = d.fileClient.Delete(ctx, getTorrentFileName(task.ID))
+		if err != nil {
+			d.logger.With(zap.Error(err), zap.Uint64("taskID", params.DownloadTaskID)).Warn("failed to delete torrent file")
+		}
+	}
+
+	return nil
+}
+
+func (d downloadTaskHandler) MoveDownloadTask(ctx context.Context, params MoveDownloadTaskParams) (*go_load.DownloadTask, error) {
+	accountID, _, err := d.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to verify token")
+		return nil, err
+	}
+
+	var task database.DownloadTask
+	txErr := d.goquDatabase.WithTx(func(tx *goqu.TxDatabase) error {
+		downloadTaskDataAccessor := d.downloadTaskDataAccessor.WithDatabase(tx)
+		task, err = d.getOwnedDownloadTask(ctx, downloadTaskDataAccessor, accountID, params.DownloadTaskID, true)
+		if err != nil {
+			return err
+		}
+
+		if params.DownloadQueueID == 0 {
+			return downloadTaskDataAccessor.UpdateDownloadTaskQueue(ctx, task.ID, 0, 0)
+		}
+
+		_, err = getOwnedDownloadQueue(ctx, d.downloadQueueDataAccessor.WithDatabase(tx), accountID, params.DownloadQueueID, true)
+		if err != nil {
+			return err
+		}
+
+		return placeDownloadTaskInQueue(ctx, downloadTaskDataAccessor, params.DownloadQueueID, task.ID, params.Position)
+	})
+	if txErr != nil {
+		d.logger.With(zap.Error(txErr), zap.Uint64("taskID", params.DownloadTaskID)).Error("failed to move download task")
+		return nil, txErr
+	}
+
+	task, err = d.downloadTaskDataAccessor.GetDownloadTaskByID(ctx, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	return d.databaseDownloadTaskToProto(ctx, task)
+}
+
+func (d downloadTaskHandler) GetDownloadTaskAttemptList(ctx context.Context, params GetDownloadTaskAttemptListParams) ([]*go_load.DownloadTaskAttempt, error) {
+	accountID, _, err := d.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to verify token")
+		return nil, err
+	}
+
+	task, err := d.getOwnedDownloadTask(ctx, d.downloadTaskDataAccessor, accountID, params.DownloadTaskID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := d.downloadTaskAttemptDataAccessor.GetDownloadTaskAttemptsByDownloadTaskID(ctx, task.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	downloadTaskAttemptList := make([]*go_load.DownloadTaskAttempt, 0, len(attempts))
+	for _, attempt := range attempts {
+		downloadTaskAttemptList = append(downloadTaskAttemptList, &go_load.DownloadTaskAttempt{
+			AttemptNumber:  attempt.AttemptNumber,
+			StartedAt:      timestamppb.New(attempt.StartedAt),
+			FinishedAt:     timestamppb.New(attempt.FinishedAt),
+			Succeeded:      attempt.Succeeded,
+			ErrorClass:     go_load.DownloadErrorClass(attempt.ErrorClass),
+			HttpStatusCode: attempt.HTTPStatusCode,
+			ErrorMessage:   attempt.ErrorMessage,
+		})
+	}
+	return downloadTaskAttemptList, nil
+}
+
+// getCompletedDownloadTaskFileName returns the name of a downloaded file of a completed download task the token's
+// account owns. filePath selects one of the files of a download made of several files, versionNumber one of the
+// versions kept of the file of a watched task.
+func (d downloadTaskHandler) getCompletedDownloadTaskFileName(
+	ctx context.Context,
+	token string,
+	downloadTaskID uint64,
+	filePath string,
+	versionNumber uint32,
+) (string, error) {
+	accountID, _, err := d.tokenHandler.GetAccountIDAndExpireTime(ctx, token)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to verify token")
+		return "", err
+	}
+
+	task, err := d.getOwnedDownloadTask(ctx, d.downloadTaskDataAccessor, accountID, downloadTaskID, false)
+	if err != nil {
+		return "", err
+	}
+
+	if task.DownloadStatus != uint16(go_load.DownloadStatus_Success) {
+		return "", errors.New("download task is not completed")
+	}
+
+	metadata, err := parseDownloadTaskMetadata(task.Metadata)
+	if err != nil {
+		return "", err
+	}
+
+	if versionNumber != 0 && versionNumber != metadata.VersionNumber {
+		version, err := d.downloadTaskVersionDataAccessor.GetDownloadTaskVersionByVersionNumber(ctx, task.ID, versionNumber)
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", errors.New("download task version not found")
+		}
+		if err != nil {
+			return "", err
+		}
+		return version.FileName, nil
+	}
+
+	if filePath != "" && len(metadata.ExtractedFiles) > 0 {
+		for _, extracted := range metadata.ExtractedFiles {
+			if extracted.FilePath == filePath {
+				return path.Join(metadata.ExtractedDirectory, filePath), nil
+			}
+		}
+		return "", errors.New("file path is not part of the download task")
+	}
+
+	if len(metadata.Files) == 0 {
+		return metadata.FileName, nil
+	}
+
+	// only the listed files can be read, which also keeps the path from escaping the directory of the download
+	if filePath == "" && len(metadata.Files) == 1 {
+		return path.Join(metadata.FileName, metadata.Files[0]), nil
+	}
+	if !slices.Contains(metadata.Files, filePath) {
+		return "", errors.New("file path is not part of the download task")
+	}
+	return path.Join(metadata.FileName, filePath), nil
+}
+
+func (d downloadTaskHandler) GetDownloadTaskFile(ctx context.Context, params GetDownloadTaskFileParams) (io.ReadCloser, error) {
+	fileName, err := d.getCompletedDownloadTaskFileName(ctx, params.Token, params.DownloadTaskID, params.FilePath, params.VersionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.fileClient.Read(ctx, fileName)
+}
+
+func (d downloadTaskHandler) GetDownloadTaskDigests(ctx context.Context, params GetDownloadTaskDigestsParams) ([]*go_load.Digest, error) {
+	if len(params.Algorithms) == 0 {
+		return nil, errors.New("no digest algorithm requested")
+	}
+
+	fileName, err := d.getCompletedDownloadTaskFileName(ctx, params.Token, params.DownloadTaskID, params.FilePath, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	digests, err := computeFileDigests(ctx, d.fileClient, fileName, params.Algorithms)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", params.DownloadTaskID)).Error("failed to compute file digests")
+		return nil, err
+	}
+
+	protoDigests := make([]*go_load.Digest, 0, len(digests))
+	for _, digest := range digests {
+		protoDigests = append(protoDigests, digest.toProto())
+	}
+	return protoDigests, nil
+}
+
+func (d downloadTaskHandler) GetAccountStorageUsage(ctx context.Context, params GetAccountStorageUsageParams) (GetAccountStorageUsageOutput, error) {
+	accountID, _, err := d.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to verify token")
+		return GetAccountStorageUsageOutput{}, err
+	}
+
+	tasks, err := d.downloadTaskDataAccessor.GetDownloadTasksByAccountIDAndStatus(ctx, accountID, uint16(go_load.DownloadStatus_Success))
+	if err != nil {
+		return GetAccountStorageUsageOutput{}, err
+	}
+
+	output := GetAccountStorageUsageOutput{}
+	blobReferenceCounts := make(map[string]uint64)
+	for _, task := range tasks {
+		metadata, err := parseDownloadTaskMetadata(task.Metadata)
+		if err != nil {
+			d.logger.With(zap.Error(err), zap.Uint64("taskID", task.ID)).Warn("failed to parse download task metadata")
+			continue
+		}
+
+		// extracted files are never shared
+		for _, extracted := range metadata.ExtractedFiles {
+			output.LogicalByteCount += uint64(extracted.Size)
+			output.PhysicalByteCount += uint64(extracted.Size)
+		}
+
+		if task.BlobSHA256 != "" {
+			blobReferenceCounts[task.BlobSHA256]++
+			continue
+		}
+
+		if metadata.VersionNumber > 0 {
+			// the versions of a watched task include its current file
+			versions, err := d.downloadTaskVersionDataAccessor.GetDownloadTaskVersionsByDownloadTaskID(ctx, task.ID)
+			if err != nil {
+				return GetAccountStorageUsageOutput{}, err
+			}
+
+			for _, version := range versions {
+				output.LogicalByteCount += uint64(version.Size)
+				output.PhysicalByteCount += uint64(version.Size)
+			}
+			continue
+		}
+		output.LogicalByteCount += uint64(max(metadata.DownloadedByteCount, 0))
+		output.PhysicalByteCount += uint64(max(metadata.DownloadedByteCount, 0))
+	}
+
+	blobs, err := d.blobDataAccessor.GetBlobsBySHA256s(ctx, slices.Collect(maps.Keys(blobReferenceCounts)))
+	if err != nil {
+		return GetAccountStorageUsageOutput{}, err
+	}
+
+	for _, blob := range blobs {
+		referenceCount := blobReferenceCounts[blob.SHA256]
+		output.LogicalByteCount += uint64(blob.Size) * referenceCount
+		output.PhysicalByteCount += uint64(blob.Size) * referenceCount / uint64(max(blob.ReferenceCount, 1))
+	}
+
+	return output, nil
+}
+
+func (d downloadTaskHandler) GetDownloadTaskVersionList(
+	ctx context.Context,
+	params GetDownloadTaskVersionListParams,
+) ([]*go_load.DownloadTaskVersion, error) {
+	accountID, _, err := d.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to verify token")
+		return nil, err
+	}
+
+	task, err := d.getOwnedDownloadTask(ctx, d.downloadTaskDataAccessor, accountID, params.DownloadTaskID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := d.downloadTaskVersionDataAccessor.GetDownloadTaskVersionsByDownloadTaskID(ctx, task.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	downloadTaskVersionList := make([]*go_load.DownloadTaskVersion, 0, len(versions))
+	for _, version := range versions {
+		downloadTaskVersionList = append(downloadTaskVersionList, &go_load.DownloadTaskVersion{
+			VersionNumber: version.VersionNumber,
+			Size:          uint64(version.Size),
+			Sha256:        version.SHA256,
+			Etag:          version.ETag,
+			LastModified:  version.LastModified,
+			CreatedAt:     timestamppb.New(version.CreatedAt),
+		})
+	}
+	return downloadTaskVersionList, nil
+}
@@ -0,0 +1,871 @@
+package logic
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/database"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPollInterval               = 5 * time.Second
+	defaultMaxConcurrentDownloadCount = 4
+	defaultSegmentCount               = 4
+	defaultMinSegmentSize             = 1024 * 1024
+	defaultProgressSaveInterval       = 2 * time.Second
+	defaultLeaseDuration              = time.Minute
+	// maxPieceRepairSegmentCount bounds the segments broken pieces are downloaded again in, each one is a connection
+	maxPieceRepairSegmentCount = 16
+)
+
+var (
+	errDownloadTaskDeleted    = errors.New("download task was deleted")
+	errDownloadTaskURLChanged = errors.New("download task URLs were changed")
+	errDownloadTaskNotOwned   = errors.New("download task is no longer owned by this executor")
+	errLeaseDurationTooShort  = errors.New("the lease duration has to be at least a second")
+)
+
+// DownloadTaskExecutor picks up pending download tasks and downloads them in the background.
+type DownloadTaskExecutor interface {
+	Start(ctx context.Context) error
+}
+
+type downloadTaskExecutor struct {
+	downloadTaskDataAccessor        database.DownloadTaskDataAccessor
+	downloadQueueDataAccessor       database.DownloadQueueDataAccessor
+	accountDataAccessor             database.AccountDataAccessor
+	downloadTaskAttemptDataAccessor database.DownloadTaskAttemptDataAccessor
+	downloaderRegistry              DownloaderRegistry
+	bandwidthLimiter                BandwidthLimiter
+	accountWeights                  accountWeights
+	retryPolicy                     downloadRetryPolicy
+	archiveExtractor                archiveExtractor
+	postDownloadPipeline            postDownloadPipeline
+	blobStore                       blobStore
+	webhookEventPublisher           WebhookEventPublisher
+	progressMilestones              []uint32
+	fileClient                      file.Client
+	pollInterval                    time.Duration
+	maxConcurrentDownloadCount      int
+	segmentCount                    int
+	minSegmentSize                  int64
+	progressSaveInterval            time.Duration
+	leaseDuration                   time.Duration
+	ownerID                         string
+	location                        *time.Location
+	logger                          *zap.Logger
+}
+
+func NewDownloadTaskExecutor(
+	configs configs.DownloadConfig,
+	postDownloadConfig configs.PostDownloadConfig,
+	webhookConfig configs.WebhookConfig,
+	downloadTaskDataAccessor database.DownloadTaskDataAccessor,
+	downloadQueueDataAccessor database.DownloadQueueDataAccessor,
+	accountDataAccessor database.AccountDataAccessor,
+	downloadTaskAttemptDataAccessor database.DownloadTaskAttemptDataAccessor,
+	blobDataAccessor database.BlobDataAccessor,
+	downloaderRegistry DownloaderRegistry,
+	bandwidthLimiter BandwidthLimiter,
+	webhookEventPublisher WebhookEventPublisher,
+	fileClient file.Client,
+	goquDatabase *goqu.Database,
+	logger *zap.Logger,
+) (DownloadTaskExecutor, error) {
+	pollInterval := defaultPollInterval
+	if configs.PollInterval != "" {
+		var err error
+		pollInterval, err = configs.GetPollIntervalDuration()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	maxConcurrentDownloadCount := configs.MaxConcurrentDownloadCount
+	if maxConcurrentDownloadCount <= 0 {
+		maxConcurrentDownloadCount = defaultMaxConcurrentDownloadCount
+	}
+
+	segmentCount := configs.SegmentCount
+	if segmentCount <= 0 {
+		segmentCount = defaultSegmentCount
+	}
+
+	minSegmentSize := configs.MinSegmentSize
+	if minSegmentSize <= 0 {
+		minSegmentSize = defaultMinSegmentSize
+	}
+
+	progressSaveInterval := defaultProgressSaveInterval
+	if configs.ProgressSaveInterval != "" {
+		var err error
+		progressSaveInterval, err = configs.GetProgressSaveIntervalDuration()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	leaseDuration := defaultLeaseDuration
+	if configs.LeaseDuration != "" {
+		var err error
+		leaseDuration, err = configs.GetLeaseDurationDuration()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if leaseDuration < time.Second {
+		return nil, errLeaseDurationTooShort
+	}
+
+	ownerID, err := newExecutorOwnerID()
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := configs.GetTimeZoneLocation()
+	if err != nil {
+		return nil, err
+	}
+
+	retryPolicy, err := newDownloadRetryPolicy(configs.RetryConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	postDownloadPipeline, err := newPostDownloadPipeline(postDownloadConfig, fileClient, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	progressMilestones := slices.DeleteFunc(slices.Clone(webhookConfig.ProgressMilestones), func(milestone uint32) bool {
+		return milestone == 0 || milestone >= 100
+	})
+	slices.Sort(progressMilestones)
+
+	return &downloadTaskExecutor{
+		downloadTaskDataAccessor:        downloadTaskDataAccessor,
+		downloadQueueDataAccessor:       downloadQueueDataAccessor,
+		accountDataAccessor:             accountDataAccessor,
+		downloadTaskAttemptDataAccessor: downloadTaskAttemptDataAccessor,
+		downloaderRegistry:              downloaderRegistry,
+		bandwidthLimiter:                bandwidthLimiter,
+		accountWeights:                  newAccountWeights(configs.FairShareConfig, accountDataAccessor),
+		retryPolicy:                     retryPolicy,
+		archiveExtractor:                newArchiveExtractor(configs.ArchiveExtractionConfig, fileClient, logger),
+		postDownloadPipeline:            postDownloadPipeline,
+		blobStore:                       newBlobStore(goquDatabase, downloadTaskDataAccessor, blobDataAccessor, fileClient, logger),
+		webhookEventPublisher:           webhookEventPublisher,
+		progressMilestones:              slices.Compact(progressMilestones),
+		fileClient:                      fileClient,
+		pollInterval:                    pollInterval,
+		maxConcurrentDownloadCount:      maxConcurrentDownloadCount,
+		segmentCount:                    segmentCount,
+		minSegmentSize:                  minSegmentSize,
+		progressSaveInterval:            progressSaveInterval,
+		leaseDuration:                   leaseDuration,
+		ownerID:                         ownerID,
+		location:                        location,
+		logger:                          logger,
+	}, nil
+}
+
+// newExecutorOwnerID returns an ID made of the host name and a random suffix, so that the tasks of a server can be told
+// apart while two executors on the same host still get different IDs.
+func newExecutorOwnerID() (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "executor"
+	}
+
+	suffix := make([]byte, 8)
+	_, err = rand.Read(suffix)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%.80s-%s", hostname, hex.EncodeToString(suffix)), nil
+}
+
+// getClaimOwnerID returns the owner ID of a new claim. Every claim gets its own, a download of a task this executor
+// lost and claimed again then tells it no longer owns the task either.
+func (d downloadTaskExecutor) getClaimOwnerID() string {
+	return fmt.Sprintf("%s/%d", d.ownerID, time.Now().UnixNano())
+}
+
+func (d downloadTaskExecutor) Start(ctx context.Context) error {
+	d.logger.With(zap.Duration("pollInterval", d.pollInterval), zap.Int("maxConcurrentDownloadCount", d.maxConcurrentDownloadCount),
+		zap.String("ownerID", d.ownerID)).Info("starting download task executor")
+
+	// each slot in the semaphore is one running download
+	semaphore := make(chan struct{}, d.maxConcurrentDownloadCount)
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.recoverAbandonedDownloadTasks(ctx)
+		d.executePendingDownloadTasks(ctx, semaphore)
+
+		select {
+		case <-ctx.Done():
+			// taking every slot waits for the running downloads to requeue their tasks
+			for range cap(semaphore) {
+				semaphore <- struct{}{}
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// recoverAbandonedDownloadTasks queues the tasks left in Downloading by a server that stopped or crashed again, so that
+// they resume. Tasks of servers still renewing their leases are left alone, whichever server runs them.
+func (d downloadTaskExecutor) recoverAbandonedDownloadTasks(ctx context.Context) {
+	recoveredTaskCount, err := d.downloadTaskDataAccessor.UpdateDownloadTaskStatusIfLeaseExpired(
+		ctx, uint16(go_load.DownloadStatus_Downloading), uint16(go_load.DownloadStatus_Pending), time.Now())
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to recover abandoned download tasks")
+		return
+	}
+	if recoveredTaskCount > 0 {
+		d.logger.With(zap.Uint64("recoveredTaskCount", recoveredTaskCount)).Info("recovered abandoned download tasks")
+	}
+}
+
+func (d downloadTaskExecutor) executePendingDownloadTasks(ctx context.Context, semaphore chan struct{}) {
+	freeSlotCount := cap(semaphore) - len(semaphore)
+	if freeSlotCount == 0 {
+		return
+	}
+
+	tasks, err := d.getNextDownloadTasks(ctx, freeSlotCount)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to get pending download tasks")
+		return
+	}
+
+	for _, task := range tasks {
+		ownerID := d.getClaimOwnerID()
+		claimed, err := d.downloadTaskDataAccessor.ClaimDownloadTask(
+			ctx, task.ID, uint16(go_load.DownloadStatus_Pending), uint16(go_load.DownloadStatus_Downloading),
+			ownerID, time.Now().Add(d.leaseDuration))
+		if err != nil {
+			d.logger.With(zap.Error(err), zap.Uint64("taskID", task.ID)).Error("failed to claim download task")
+			continue
+		}
+		if !claimed {
+			// another worker got to the task first
+			continue
+		}
+
+		task.DownloadStatus = uint16(go_load.DownloadStatus_Downloading)
+		task.OwnerID = ownerID
+		semaphore <- struct{}{}
+		go func(task database.DownloadTask) {
+			defer func() { <-semaphore }()
+			d.executeDownloadTask(ctx, task)
+		}(task)
+	}
+}
+
+// getNextDownloadTasks returns up to limit pending tasks to start, shared across accounts by their fair share weight
+// and in priority order within each account. Tasks waiting for a retry, and tasks of download queues outside of their
+// active time window or already running as many downloads as they allow, are left out.
+func (d downloadTaskExecutor) getNextDownloadTasks(ctx context.Context, limit int) ([]database.DownloadTask, error) {
+	queues, err := d.downloadQueueDataAccessor.GetDownloadQueues(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	runningQueueTaskCounts, err := d.downloadTaskDataAccessor.GetDownloadTaskCountsByQueueID(ctx, uint16(go_load.DownloadStatus_Downloading))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	nowInLocation := now.In(d.location)
+	// queue ID 0 stands for tasks outside of any queue, which can always start
+	queueIDs := []uint64{0}
+	freeQueueSlotCounts := make(map[uint64]uint64)
+	for _, queue := range queues {
+		window, err := getDownloadQueueTimeWindow(queue)
+		if err != nil {
+			d.logger.With(zap.Error(err), zap.Uint64("queueID", queue.ID)).Warn("skipping download queue with invalid time window")
+			continue
+		}
+		if window != nil && !window.includes(nowInLocation) {
+			continue
+		}
+
+		if queue.MaxConcurrentDownloadCount > 0 {
+			maxCount := uint64(queue.MaxConcurrentDownloadCount)
+			if runningQueueTaskCounts[queue.ID] >= maxCount {
+				continue
+			}
+			freeQueueSlotCounts[queue.ID] = maxCount - runningQueueTaskCounts[queue.ID]
+		}
+		queueIDs = append(queueIDs, queue.ID)
+	}
+
+	pendingAccountTaskCounts, err := d.downloadTaskDataAccessor.GetDownloadTaskCountsByAccountID(ctx, uint16(go_load.DownloadStatus_Pending))
+	if err != nil {
+		return nil, err
+	}
+
+	runningAccountTaskCounts, err := d.downloadTaskDataAccessor.GetDownloadTaskCountsByAccountID(ctx, uint16(go_load.DownloadStatus_Downloading))
+	if err != nil {
+		return nil, err
+	}
+
+	candidateTasks := make(map[uint64][]database.DownloadTask, len(pendingAccountTaskCounts))
+	accountIDs := make([]uint64, 0, len(pendingAccountTaskCounts))
+	for accountID := range pendingAccountTaskCounts {
+		tasks, err := d.downloadTaskDataAccessor.GetDownloadTasksByStatusInPriorityOrder(
+			ctx, uint16(go_load.DownloadStatus_Pending), accountID, queueIDs, now, uint64(limit))
+		if err != nil {
+			return nil, err
+		}
+
+		// tasks beyond the free slots of their queue wait for a later poll, the queue is left out once it is full
+		for _, task := range tasks {
+			freeSlotCount, limited := freeQueueSlotCounts[task.OfQueueID]
+			if limited {
+				if freeSlotCount == 0 {
+					continue
+				}
+				freeQueueSlotCounts[task.OfQueueID] = freeSlotCount - 1
+			}
+			candidateTasks[accountID] = append(candidateTasks[accountID], task)
+		}
+		accountIDs = append(accountIDs, accountID)
+	}
+
+	weights, _, err := d.accountWeights.getAccountWeights(ctx, accountIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return pickFairShareTasks(candidateTasks, runningAccountTaskCounts, weights, limit), nil
+}
+
+func (d downloadTaskExecutor) executeDownloadTask(ctx context.Context, task database.DownloadTask) {
+	logger := d.logger.With(zap.Uint64("taskID", task.ID), zap.String("url", task.URL))
+	logger.Info("executing download task")
+	startedAt := time.Now()
+
+	metadata, err := parseDownloadTaskMetadata(task.Metadata)
+	if err != nil {
+		logger.With(zap.Error(err)).Warn("failed to parse download task metadata, resetting it")
+		metadata = downloadTaskMetadata{}
+	}
+
+	downloader, err := d.downloaderRegistry.GetDownloader(go_load.DownloadType(task.DownloadType))
+	if err != nil {
+		logger.With(zap.Error(err)).Error("failed to get downloader")
+		d.finishDownloadTaskAttempt(ctx, task, startedAt, err, metadata)
+		return
+	}
+
+	if !downloader.CanResume() {
+		metadata = metadata.withoutProgress()
+	}
+	metadata.FailureReason = ""
+
+	segmentCount := d.segmentCount
+	if metadata.SegmentCount > 0 {
+		segmentCount = int(metadata.SegmentCount)
+	}
+
+	minSegmentSize := d.minSegmentSize
+	if metadata.MinSegmentSize > 0 {
+		minSegmentSize = int64(metadata.MinSegmentSize)
+	}
+
+	// the file name is kept across restarts and URL changes so that a resumed download keeps writing into the same file
+	if metadata.FileName == "" {
+		metadata.FileName, err = getDownloadFileName(task)
+		if err != nil {
+			logger.With(zap.Error(err)).Error("failed to get download file name")
+			d.finishDownloadTaskAttempt(ctx, task, startedAt, err, metadata)
+			return
+		}
+	}
+
+	task.Metadata = metadata.String()
+	d.webhookEventPublisher.Publish(ctx, WebhookEvent{
+		EventType:    go_load.WebhookEventType_DownloadTaskStarted,
+		DownloadTask: task,
+	})
+
+	// the lease is kept until the final status is recorded, as verifying and extracting a large file takes a while
+	leaseCtx, cancelLease := context.WithCancelCause(ctx)
+	leaseDone := make(chan struct{})
+	go func() {
+		defer close(leaseDone)
+		d.keepDownloadTaskLease(leaseCtx, task, cancelLease)
+	}()
+	defer func() {
+		cancelLease(nil)
+		<-leaseDone
+	}()
+
+	downloadCtx, cancel := context.WithCancelCause(leaseCtx)
+	defer cancel(nil)
+
+	account, err := d.accountDataAccessor.GetAccountByID(ctx, task.OfAccountID)
+	if err != nil {
+		logger.With(zap.Error(err)).Warn("failed to get account speed limit, downloading without it")
+	}
+
+	throttle := d.bandwidthLimiter.NewDownloadThrottle(downloadCtx, task.OfAccountID, task.SpeedLimitBytesPerSec, account.SpeedLimitBytesPerSec)
+	defer throttle.Close()
+
+	progress := newDownloadProgress(metadata)
+	progress.SetThrottle(throttle)
+	watchDone := make(chan struct{})
+	go func() {
+		defer close(watchDone)
+		d.watchDownloadTask(downloadCtx, task, progress, throttle, cancel)
+	}()
+
+	err = downloader.Download(downloadCtx, DownloadParams{
+		TaskID:         task.ID,
+		URL:            task.URL,
+		SegmentCount:   segmentCount,
+		MinSegmentSize: minSegmentSize,
+		MirrorURLs:     splitMirrorURLs(task.MirrorURLs),
+		ExpectedSize:   metadata.ExpectedSize,
+	}, progress)
+	cancel(nil)
+	<-watchDone
+
+	if err == nil {
+		metadata := progress.Snapshot()
+		err = d.verifyDownloadedFile(ctx, &metadata)
+		if errors.Is(err, errDigestMismatch) || errors.Is(err, errSizeMismatch) {
+			// the saved progress produced a broken file, a retry has to download everything again
+			metadata = metadata.withoutProgress()
+		}
+
+		if err == nil && metadata.ExtractArchive {
+			err = d.archiveExtractor.extract(ctx, &metadata)
+		}
+		if err == nil {
+			err = d.postDownloadPipeline.run(ctx, task, account.AccountName, &metadata)
+		}
+		progress = newDownloadProgress(metadata)
+	}
+
+	cause := context.Cause(downloadCtx)
+	if errors.Is(cause, errDownloadTaskDeleted) {
+		logger.Info("download task was deleted while downloading")
+		return
+	}
+
+	cancelLease(nil)
+	<-leaseDone
+	if !d.isDownloadTaskStillOwned(ctx, task, context.Cause(leaseCtx)) {
+		// another executor runs the task now, anything recorded here would overwrite what it does
+		logger.Warn("download task was taken over by another executor, leaving it")
+		return
+	}
+
+	switch {
+	case err == nil:
+		logger.Info("download task succeeded")
+		d.finishDownloadTaskAttempt(ctx, task, startedAt, nil, progress.Snapshot())
+
+		// the task keeps its own copy of the file when it cannot be stored as a blob
+		err = d.blobStore.store(context.WithoutCancel(ctx), task.ID, progress.Snapshot())
+		if err != nil {
+			logger.With(zap.Error(err)).Warn("failed to store downloaded file as blob")
+		}
+	case errors.Is(cause, errDownloadTaskURLChanged), ctx.Err() != nil:
+		// put the task back in the queue, it will resume from the saved progress
+		logger.With(zap.Error(cause)).Info("download task interrupted, requeueing it")
+		d.finishDownloadTask(ctx, task.ID, go_load.DownloadStatus_Pending, progress.Snapshot())
+	default:
+		logger.With(zap.Error(err)).Error("download task failed")
+		d.finishDownloadTaskAttempt(ctx, task, startedAt, err, progress.Snapshot())
+	}
+}
+
+// keepDownloadTaskLease renews the lease of a running task until ctx is done. When the task is no longer in Downloading
+// or owned by this executor, its lease expired and it was queued again, ctx is cancelled with errDownloadTaskNotOwned.
+func (d downloadTaskExecutor) keepDownloadTaskLease(ctx context.Context, task database.DownloadTask, cancel context.CancelCauseFunc) {
+	// a few renewals fit in a lease, so that one failing does not lose the task
+	ticker := time.NewTicker(d.leaseDuration / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		renewed, err := d.downloadTaskDataAccessor.RenewDownloadTaskLease(
+			ctx, task.ID, uint16(go_load.DownloadStatus_Downloading), task.OwnerID, time.Now().Add(d.leaseDuration))
+		if err != nil {
+			d.logger.With(zap.Error(err), zap.Uint64("taskID", task.ID)).Warn("failed to renew download task lease")
+			continue
+		}
+		if !renewed {
+			cancel(errDownloadTaskNotOwned)
+			return
+		}
+	}
+}
+
+// isDownloadTaskStillOwned tells whether the task is still owned once its lease stopped being renewed. The lease is
+// renewed one last time, so that it cannot expire while the final status is recorded.
+func (d downloadTaskExecutor) isDownloadTaskStillOwned(ctx context.Context, task database.DownloadTask, leaseCause error) bool {
+	if errors.Is(leaseCause, errDownloadTaskNotOwned) {
+		return false
+	}
+
+	renewed, err := d.downloadTaskDataAccessor.RenewDownloadTaskLease(
+		context.WithoutCancel(ctx), task.ID, uint16(go_load.DownloadStatus_Downloading), task.OwnerID, time.Now().Add(d.leaseDuration))
+	if err != nil {
+		// recording the final status will most likely fail as well, it is attempted anyway
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", task.ID)).Warn("failed to renew download task lease")
+		return true
+	}
+	return renewed
+}
+
+// verifyDownloadedFile computes the SHA-256 digest of the downloaded file and checks it against the expected digest
+// of the task, both in a single pass over the file. The expected size and piece digests of the task are checked before.
+// Downloads made of several files are not hashed.
+func (d downloadTaskExecutor) verifyDownloadedFile(ctx context.Context, metadata *downloadTaskMetadata) error {
+	if len(metadata.Files) > 0 {
+		if metadata.ExpectedDigest != nil || metadata.ExpectedSize > 0 || metadata.PieceDigests != nil {
+			return errors.New("an expected digest or size cannot be verified for a download made of several files")
+		}
+		return nil
+	}
+
+	if metadata.ExpectedSize > 0 {
+		size, err := d.fileClient.Size(ctx, metadata.FileName)
+		if err != nil {
+			return fmt.Errorf("failed to get size of downloaded file: %w", err)
+		}
+
+		if size != metadata.ExpectedSize {
+			return fmt.Errorf("%w: expected %d bytes, got %d", errSizeMismatch, metadata.ExpectedSize, size)
+		}
+	}
+
+	if metadata.PieceDigests != nil {
+		err := d.verifyPieceDigests(ctx, metadata)
+		if err != nil {
+			return err
+		}
+	}
+
+	algorithms := []go_load.DigestAlgorithm{go_load.DigestAlgorithm_SHA256}
+	if metadata.ExpectedDigest != nil && metadata.ExpectedDigest.Algorithm != go_load.DigestAlgorithm_SHA256 {
+		algorithms = append(algorithms, metadata.ExpectedDigest.Algorithm)
+	}
+
+	digests, err := computeFileDigests(ctx, d.fileClient, metadata.FileName, algorithms)
+	if err != nil {
+		return fmt.Errorf("failed to compute digest of downloaded file: %w", err)
+	}
+	metadata.SHA256 = digests[0].Value
+
+	if metadata.ExpectedDigest == nil {
+		return nil
+	}
+
+	actualDigest := digests[len(digests)-1]
+	if actualDigest.Value != metadata.ExpectedDigest.Value {
+		return fmt.Errorf("%w: expected %s %s, got %s",
+			errDigestMismatch, metadata.ExpectedDigest.Algorithm, metadata.ExpectedDigest.Value, actualDigest.Value)
+	}
+	return nil
+}
+
+// verifyPieceDigests checks every piece of the downloaded file. When the download was made in segments, the segments
+// are changed so that only the broken pieces are downloaded again by the next attempt.
+func (d downloadTaskExecutor) verifyPieceDigests(ctx context.Context, metadata *downloadTaskMetadata) error {
+	reader, err := d.fileClient.Read(ctx, metadata.FileName)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	brokenPieces, err := metadata.PieceDigests.getBrokenPieces(reader)
+	if err != nil {
+		return fmt.Errorf("failed to compute piece digests of downloaded file: %w", err)
+	}
+
+	if len(brokenPieces) == 0 {
+		return nil
+	}
+
+	if len(metadata.Segments) == 0 {
+		return fmt.Errorf("%w: %d of %d pieces are broken", errDigestMismatch, len(brokenPieces), len(metadata.PieceDigests.Values))
+	}
+
+	segments := getPieceRepairSegments(metadata.Size, metadata.PieceDigests.PieceLength, brokenPieces)
+	if segments == nil {
+		return fmt.Errorf("%w: %d of %d pieces are broken", errDigestMismatch, len(brokenPieces), len(metadata.PieceDigests.Values))
+	}
+
+	metadata.Segments = segments
+	metadata.DownloadedByteCount = 0
+	for _, segment := range segments {
+		metadata.DownloadedByteCount += segment.DownloadedByteCount
+	}
+	return fmt.Errorf("%w: %d of %d pieces are broken", errPieceDigestMismatch, len(brokenPieces), len(metadata.PieceDigests.Values))
+}
+
+// getPieceRepairSegments returns segments covering a file of the given size where only the broken pieces are left to
+// download, adjacent broken pieces sharing a segment. It returns nil when there are too many of them to download at
+// once, the whole file is downloaded again then.
+func getPieceRepairSegments(size int64, pieceLength int64, brokenPieces []int) []downloadSegment {
+	segments := make([]downloadSegment, 0)
+	brokenSegmentCount := 0
+	start := int64(0)
+	for _, pieceIndex := range brokenPieces {
+		pieceStart := int64(pieceIndex) * pieceLength
+		if pieceStart >= size {
+			break
+		}
+		pieceEnd := min(pieceStart+pieceLength, size) - 1
+
+		if pieceStart > start {
+			segments = append(segments, downloadSegment{Start: start, End: pieceStart - 1, DownloadedByteCount: pieceStart - start})
+		}
+
+		if lastIndex := len(segments) - 1; lastIndex >= 0 && segments[lastIndex].DownloadedByteCount == 0 &&
+			segments[lastIndex].End == pieceStart-1 {
+			segments[lastIndex].End = pieceEnd
+		} else {
+			segments = append(segments, downloadSegment{Start: pieceStart, End: pieceEnd})
+			brokenSegmentCount++
+		}
+		start = pieceEnd + 1
+	}
+
+	if brokenSegmentCount > maxPieceRepairSegmentCount {
+		return nil
+	}
+
+	if start < size {
+		segments = append(segments, downloadSegment{Start: start, End: size - 1, DownloadedByteCount: size - start})
+	}
+	return segments
+}
+
+func (d downloadTaskExecutor) finishDownloadTask(
+	ctx context.Context,
+	taskID uint64,
+	status go_load.DownloadStatus,
+	metadata downloadTaskMetadata,
+) {
+	// the context may already be cancelled on shutdown, but the final status still has to be recorded
+	err := d.downloadTaskDataAccessor.UpdateDownloadTaskStatusAndMetadata(context.WithoutCancel(ctx), taskID, uint16(status), metadata.String())
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("taskID", taskID)).Error("failed to update download task status")
+	}
+}
+
+// finishDownloadTaskAttempt records how an attempt at the task ended, attemptErr being nil when it succeeded. A task
+// failing with a transient error goes back to Pending until its next retry while it has attempts left.
+func (d downloadTaskExecutor) finishDownloadTaskAttempt(
+	ctx context.Context,
+	task database.DownloadTask,
+	startedAt time.Time,
+	attemptErr error,
+	metadata downloadTaskMetadata,
+) {
+	// the context may already be cancelled on shutdown, but how the attempt ended still has to be recorded
+	ctx = context.WithoutCancel(ctx)
+	logger := d.logger.With(zap.Uint64("taskID", task.ID))
+
+	attempt := database.DownloadTaskAttempt{
+		OfDownloadTaskID: task.ID,
+		AttemptNumber:    task.AttemptCount + 1,
+		StartedAt:        startedAt,
+		FinishedAt:       time.Now(),
+		Succeeded:        attemptErr == nil,
+	}
+	status := go_load.DownloadStatus_Success
+	nextRetryAt := sql.NullTime{}
+	if attemptErr != nil {
+		errorClass, httpStatusCode := classifyDownloadError(attemptErr)
+		attempt.ErrorClass = uint16(errorClass)
+		attempt.HTTPStatusCode = uint32(httpStatusCode)
+		attempt.ErrorMessage = attemptErr.Error()
+		metadata.FailureReason = attemptErr.Error()
+
+		status = go_load.DownloadStatus_Failed
+		maxAttemptCount := d.retryPolicy.getMaxAttemptCount(task.MaxAttemptCount)
+		// broken pieces are worth another attempt, only they are downloaded again
+		retryable := isTransientDownloadError(errorClass, httpStatusCode) || errors.Is(attemptErr, errPieceDigestMismatch)
+		if retryable && attempt.AttemptNumber < maxAttemptCount {
+			status = go_load.DownloadStatus_Pending
+			nextRetryAt = sql.NullTime{Time: attempt.FinishedAt.Add(d.retryPolicy.getBackoff(attempt.AttemptNumber)), Valid: true}
+			logger.With(zap.Uint32("attemptNumber", attempt.AttemptNumber), zap.Time("nextRetryAt", nextRetryAt.Time)).
+				Info("download task attempt failed with a transient error, retrying it later")
+		}
+	}
+
+	_, err := d.downloadTaskAttemptDataAccessor.CreateDownloadTaskAttempt(ctx, attempt)
+	if err != nil {
+		logger.With(zap.Error(err)).Warn("failed to record download task attempt")
+	}
+
+	err = d.downloadTaskDataAccessor.UpdateDownloadTaskAfterAttempt(
+		ctx, task.ID, uint16(status), metadata.String(), attempt.AttemptNumber, nextRetryAt)
+	if err != nil {
+		logger.With(zap.Error(err)).Error("failed to update download task status")
+		return
+	}
+
+	task.DownloadStatus = uint16(status)
+	task.Metadata = metadata.String()
+	task.AttemptCount = attempt.AttemptNumber
+	task.NextRetryAt = nextRetryAt
+	switch status {
+	case go_load.DownloadStatus_Success:
+		d.webhookEventPublisher.Publish(ctx, WebhookEvent{EventType: go_load.WebhookEventType_DownloadTaskSucceeded, DownloadTask: task})
+	case go_load.DownloadStatus_Failed:
+		d.webhookEventPublisher.Publish(ctx, WebhookEvent{EventType: go_load.WebhookEventType_DownloadTaskFailed, DownloadTask: task})
+	}
+}
+
+// getProgressPercent returns how much of a download of known size is done, or 0 while the size is not known.
+func getProgressPercent(metadata downloadTaskMetadata) uint32 {
+	if metadata.Size <= 0 {
+		return 0
+	}
+	return uint32(min(metadata.DownloadedByteCount*100/metadata.Size, 100))
+}
+
+// publishProgressMilestones publishes an event for every progress milestone the download passed since the percentage
+// reported last, and returns the percentage reported now.
+func (d downloadTaskExecutor) publishProgressMilestones(
+	ctx context.Context,
+	task database.DownloadTask,
+	metadata downloadTaskMetadata,
+	reportedPercent uint32,
+) uint32 {
+	percent := getProgressPercent(metadata)
+	if percent <= reportedPercent {
+		return reportedPercent
+	}
+
+	task.Metadata = metadata.String()
+	for _, milestone := range d.progressMilestones {
+		if milestone > reportedPercent && milestone <= percent {
+			d.webhookEventPublisher.Publish(ctx, WebhookEvent{
+				EventType:         go_load.WebhookEventType_DownloadTaskProgressed,
+				DownloadTask:      task,
+				ProgressMilestone: milestone,
+			})
+		}
+	}
+	return percent
+}
+
+// watchDownloadTask periodically saves the progress of a running download, and stops the download if the task was
+// deleted, its URL was changed, or it is no longer in Downloading or owned by this executor in the meantime. Changed
+// speed limits of the task and its account are applied to the running download.
+func (d downloadTaskExecutor) watchDownloadTask(
+	ctx context.Context,
+	task database.DownloadTask,
+	progress *DownloadProgress,
+	throttle *DownloadThrottle,
+	cancel context.CancelCauseFunc,
+) {
+	ticker := time.NewTicker(d.progressSaveInterval)
+	defer ticker.Stop()
+
+	// milestones a resumed download passed before are not reported again
+	reportedPercent := getProgressPercent(progress.Snapshot())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// the progress is only saved while the task is owned, whether it still is is told by reloading it
+		metadata := progress.Snapshot()
+		_, err := d.downloadTaskDataAccessor.UpdateDownloadTaskMetadataIfOwner(
+			ctx, task.ID, uint16(go_load.DownloadStatus_Downloading), task.OwnerID, metadata.String())
+		if err != nil {
+			d.logger.With(zap.Error(err), zap.Uint64("taskID", task.ID)).Warn("failed to save download progress")
+		}
+
+		currentTask, err := d.downloadTaskDataAccessor.GetDownloadTaskByID(ctx, task.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				cancel(errDownloadTaskDeleted)
+				return
+			}
+			d.logger.With(zap.Error(err), zap.Uint64("taskID", task.ID)).Warn("failed to reload download task")
+			continue
+		}
+
+		if currentTask.DownloadStatus != uint16(go_load.DownloadStatus_Downloading) || currentTask.OwnerID != task.OwnerID {
+			cancel(errDownloadTaskNotOwned)
+			return
+		}
+
+		if currentTask.URL != task.URL || currentTask.MirrorURLs != task.MirrorURLs {
+			cancel(errDownloadTaskURLChanged)
+			return
+		}
+		reportedPercent = d.publishProgressMilestones(ctx, task, metadata, reportedPercent)
+		throttle.SetTaskSpeedLimit(currentTask.SpeedLimitBytesPerSec)
+
+		account, err := d.accountDataAccessor.GetAccountByID(ctx, task.OfAccountID)
+		if err != nil {
+			d.logger.With(zap.Error(err), zap.Uint64("taskID", task.ID)).Warn("failed to reload account speed limit")
+			continue
+		}
+		throttle.SetAccountSpeedLimit(account.SpeedLimitBytesPerSec)
+	}
+}
+
+// getDownloadFileName returns the name the downloaded file is stored under. It is prefixed with the task ID so that
+// two tasks downloading files with the same name do not overwrite each other.
+func getDownloadFileName(task database.DownloadTask) (string, error) {
+	baseName, err := getDownloadBaseName(task)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d_%s", task.ID, baseName), nil
+}
+
+// getDownloadBaseName returns the last element of the path of the URL of a task, made safe to use in a file name.
+func getDownloadBaseName(task database.DownloadTask) (string, error) {
+	parsedURL, err := url.Parse(task.URL)
+	if err != nil {
+		return "", err
+	}
+
+	baseName := path.Base(parsedURL.Path)
+	if baseName == "." || baseName == "/" {
+		baseName = "download"
+	}
+	return strings.ReplaceAll(baseName, "..", "_"), nil
+}
@@ -0,0 +1,256 @@
+package logic
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/database"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
+	"go.uber.org/zap"
+)
+
+const (
+	scheduledDownloadTaskBatchSize = 100
+)
+
+// getDownloadTaskNextRunTime returns the first time after after a recurring task runs. The cron expression is read in
+// timeZone, or in defaultLocation when it is empty.
+func getDownloadTaskNextRunTime(
+	cronExpression string,
+	timeZone string,
+	defaultLocation *time.Location,
+	after time.Time,
+) (time.Time, error) {
+	schedule, err := parseCronExpression(cronExpression)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	location := defaultLocation
+	if timeZone != "" {
+		location, err = time.LoadLocation(timeZone)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	return schedule.next(after.In(location))
+}
+
+// DownloadTaskScheduler starts Scheduled download tasks once they are due. A recurring task stays Scheduled and a new
+// Pending task is created from it on every run. Runs missed while no server was up are made up for by a single run.
+type DownloadTaskScheduler interface {
+	Start(ctx context.Context) error
+}
+
+type downloadTaskScheduler struct {
+	downloadTaskDataAccessor  database.DownloadTaskDataAccessor
+	downloadQueueDataAccessor database.DownloadQueueDataAccessor
+	fileClient                file.Client
+	webhookEventPublisher     WebhookEventPublisher
+	goquDatabase              *goqu.Database
+	pollInterval              time.Duration
+	location                  *time.Location
+	logger                    *zap.Logger
+}
+
+func NewDownloadTaskScheduler(
+	configs configs.DownloadConfig,
+	downloadTaskDataAccessor database.DownloadTaskDataAccessor,
+	downloadQueueDataAccessor database.DownloadQueueDataAccessor,
+	fileClient file.Client,
+	webhookEventPublisher WebhookEventPublisher,
+	goquDatabase *goqu.Database,
+	logger *zap.Logger,
+) (DownloadTaskScheduler, error) {
+	pollInterval := defaultPollInterval
+	if configs.PollInterval != "" {
+		var err error
+		pollInterval, err = configs.GetPollIntervalDuration()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	location, err := configs.GetTimeZoneLocation()
+	if err != nil {
+		return nil, err
+	}
+
+	return &downloadTaskScheduler{
+		downloadTaskDataAccessor:  downloadTaskDataAccessor,
+		downloadQueueDataAccessor: downloadQueueDataAccessor,
+		fileClient:                fileClient,
+		webhookEventPublisher:     webhookEventPublisher,
+		goquDatabase:              goquDatabase,
+		pollInterval:              pollInterval,
+		location:                  location,
+		logger:                    logger,
+	}, nil
+}
+
+func (d downloadTaskScheduler) Start(ctx context.Context) error {
+	d.logger.With(zap.Duration("pollInterval", d.pollInterval)).Info("starting download task scheduler")
+
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.startDueDownloadTasks(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d downloadTaskScheduler) startDueDownloadTasks(ctx context.Context) {
+	now := time.Now()
+	tasks, err := d.downloadTaskDataAccessor.GetDownloadTasksByStatusDueBefore(
+		ctx, uint16(go_load.DownloadStatus_Scheduled), now, scheduledDownloadTaskBatchSize)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to get due download tasks")
+		return
+	}
+
+	for _, task := range tasks {
+		err = d.startScheduledDownloadTask(ctx, task.ID, now)
+		if err != nil {
+			d.logger.With(zap.Error(err), zap.Uint64("taskID", task.ID)).Error("failed to start scheduled download task")
+		}
+	}
+}
+
+// startScheduledDownloadTask runs a due task while holding its row lock. Servers sharing the database may pick up the
+// same due task, the first one to get the lock moves it out of being due and the others find nothing left to do. The
+// events of what happened are published once it is committed.
+func (d downloadTaskScheduler) startScheduledDownloadTask(ctx context.Context, taskID uint64, now time.Time) error {
+	var events []WebhookEvent
+	txErr := d.goquDatabase.WithTx(func(tx *goqu.TxDatabase) error {
+		downloadTaskDataAccessor := d.downloadTaskDataAccessor.WithDatabase(tx)
+		task, err := downloadTaskDataAccessor.GetDownloadTaskByIDWithXLock(ctx, taskID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+
+		if task.DownloadStatus != uint16(go_load.DownloadStatus_Scheduled) || !task.NextRunAt.Valid || task.NextRunAt.Time.After(now) {
+			return nil
+		}
+
+		if task.CronExpression == "" {
+			d.logger.With(zap.Uint64("taskID", task.ID)).Info("scheduled download task is due")
+			_, err = downloadTaskDataAccessor.UpdateDownloadTaskStatusIfMatch(
+				ctx, task.ID, uint16(go_load.DownloadStatus_Scheduled), uint16(go_load.DownloadStatus_Pending))
+			return err
+		}
+
+		runTask, err := d.createDownloadTaskRun(ctx, tx, task)
+		if err != nil {
+			return err
+		}
+		d.logger.With(zap.Uint64("taskID", task.ID), zap.Uint64("runTaskID", runTask.ID)).Info("recurring download task run created")
+		events = append(events, WebhookEvent{EventType: go_load.WebhookEventType_DownloadTaskCreated, DownloadTask: runTask})
+
+		nextRunAt, err := getDownloadTaskNextRunTime(task.CronExpression, task.TimeZone, d.location, now)
+		if err != nil {
+			// the expression was checked when the task was created, it can only have run out of matching times
+			d.logger.With(zap.Error(err), zap.Uint64("taskID", task.ID)).Warn("recurring download task has no next run")
+			metadata, parseErr := parseDownloadTaskMetadata(task.Metadata)
+			if parseErr != nil {
+				return parseErr
+			}
+			metadata.FailureReason = err.Error()
+			task.DownloadStatus = uint16(go_load.DownloadStatus_Failed)
+			task.Metadata = metadata.String()
+			events = append(events, WebhookEvent{EventType: go_load.WebhookEventType_DownloadTaskFailed, DownloadTask: task})
+			return downloadTaskDataAccessor.UpdateDownloadTaskStatusAndMetadata(ctx, task.ID, task.DownloadStatus, task.Metadata)
+		}
+
+		return downloadTaskDataAccessor.UpdateDownloadTaskNextRunAt(ctx, task.ID, nextRunAt)
+	})
+	if txErr != nil {
+		return txErr
+	}
+
+	for _, event := range events {
+		d.webhookEventPublisher.Publish(ctx, event)
+	}
+	return nil
+}
+
+// createDownloadTaskRun creates a Pending task with the URL and options of a recurring task, at the end of its queue.
+func (d downloadTaskScheduler) createDownloadTaskRun(
+	ctx context.Context,
+	tx *goqu.TxDatabase,
+	task database.DownloadTask,
+) (database.DownloadTask, error) {
+	metadata, err := parseDownloadTaskMetadata(task.Metadata)
+	if err != nil {
+		return database.DownloadTask{}, err
+	}
+
+	runTask := database.DownloadTask{
+		OfAccountID:           task.OfAccountID,
+		DownloadType:          task.DownloadType,
+		URL:                   task.URL,
+		DownloadStatus:        uint16(go_load.DownloadStatus_Pending),
+		Metadata:              metadata.withoutProgress().String(),
+		SpeedLimitBytesPerSec: task.SpeedLimitBytesPerSec,
+		MaxAttemptCount:       task.MaxAttemptCount,
+		MirrorURLs:            task.MirrorURLs,
+	}
+	downloadTaskDataAccessor := d.downloadTaskDataAccessor.WithDatabase(tx)
+	runTask.ID, err = downloadTaskDataAccessor.CreateDownloadTask(ctx, runTask)
+	if err != nil {
+		return database.DownloadTask{}, err
+	}
+
+	if task.OfQueueID != 0 {
+		_, err = d.downloadQueueDataAccessor.WithDatabase(tx).GetDownloadQueueByIDWithXLock(ctx, task.OfQueueID)
+		if err != nil {
+			return database.DownloadTask{}, err
+		}
+
+		err = placeDownloadTaskInQueue(ctx, downloadTaskDataAccessor, task.OfQueueID, runTask.ID, 0)
+		if err != nil {
+			return database.DownloadTask{}, err
+		}
+	}
+
+	if task.DownloadType == uint16(go_load.DownloadType_BITTORRENT) {
+		err = d.copyTorrentFile(ctx, task.ID, runTask.ID)
+		if err != nil {
+			return database.DownloadTask{}, err
+		}
+	}
+
+	return runTask, nil
+}
+
+func (d downloadTaskScheduler) copyTorrentFile(ctx context.Context, fromTaskID uint64, toTaskID uint64) error {
+	reader, err := d.fileClient.Read(ctx, getTorrentFileName(fromTaskID))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	writer, err := d.fileClient.Write(ctx, getTorrentFileName(toTaskID))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(writer, reader)
+	closeErr := writer.Close()
+	return errors.Join(err, closeErr)
+}
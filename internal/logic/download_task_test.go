@@ -0,0 +1,23 @@
+package logic
+
+import "testing"
+
+func TestGetListPageLimit(t *testing.T) {
+	testCases := []struct {
+		limit uint64
+		want  uint64
+	}{
+		{limit: 0, want: defaultListPageLimit},
+		{limit: 1, want: 1},
+		{limit: maxListPageLimit, want: maxListPageLimit},
+		{limit: maxListPageLimit + 1, want: maxListPageLimit},
+		{limit: 1 << 63, want: maxListPageLimit},
+	}
+
+	for _, testCase := range testCases {
+		got := getListPageLimit(testCase.limit)
+		if got != testCase.want {
+			t.Errorf("getListPageLimit(%d) = %d, want %d", testCase.limit, got, testCase.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
+)
+
+var (
+	ErrUnsupportedDownloadType = errors.New("unsupported download type")
+)
+
+// httpStatusError is returned when a server answers with an unexpected status, the status code is kept so that failed
+// attempts can be told apart by it.
+type httpStatusError struct {
+	statusCode int
+	message    string
+}
+
+func newHTTPStatusError(statusCode int, format string, args ...any) error {
+	return httpStatusError{
+		statusCode: statusCode,
+		message:    fmt.Sprintf(format, args...),
+	}
+}
+
+func (e httpStatusError) Error() string {
+	return e.message
+}
+
+type DownloadParams struct {
+	TaskID         uint64
+	URL            string
+	SegmentCount   int
+	MinSegmentSize int64
+	// MirrorURLs are other URLs serving the same file as URL, downloaders that support them spread the download
+	// over all of them
+	MirrorURLs []string
+	// ExpectedSize is the size the file is known to have, 0 when it is not known
+	ExpectedSize int64
+}
+
+// Downloader fetches the file of a download task for one download type. Implementations write into the file named by
+// DownloadProgress.FileName, report written bytes to the progress and stop when ctx is cancelled.
+type Downloader interface {
+	// CanResume reports whether the downloader continues from the progress saved by an interrupted run. If not, the
+	// saved progress is discarded before Download is called again.
+	CanResume() bool
+	Download(ctx context.Context, params DownloadParams, progress *DownloadProgress) error
+}
+
+type DownloaderRegistry interface {
+	GetDownloader(downloadType go_load.DownloadType) (Downloader, error)
+}
+
+type downloaderRegistry struct {
+	downloaders map[go_load.DownloadType]Downloader
+}
+
+func NewDownloaderRegistry(
+	httpDownloader HTTPDownloader,
+	ftpDownloader FTPDownloader,
+	sftpDownloader SFTPDownloader,
+	streamDownloader StreamDownloader,
+	bitTorrentDownloader BitTorrentDownloader,
+	s3Downloader S3Downloader,
+) DownloaderRegistry {
+	return &downloaderRegistry{
+		downloaders: map[go_load.DownloadType]Downloader{
+			go_load.DownloadType_HTTP:       httpDownloader,
+			go_load.DownloadType_FTP:        ftpDownloader,
+			go_load.DownloadType_SFTP:       sftpDownloader,
+			go_load.DownloadType_Stream:     streamDownloader,
+			go_load.DownloadType_BITTORRENT: bitTorrentDownloader,
+			go_load.DownloadType_S3:         s3Downloader,
+		},
+	}
+}
+
+func (d downloaderRegistry) GetDownloader(downloadType go_load.DownloadType) (Downloader, error) {
+	downloader, ok := d.downloaders[downloadType]
+	if !ok {
+		return nil, ErrUnsupportedDownloadType
+	}
+	return downloader, nil
+}
+
+// isPartialFileIntact checks that the partially downloaded file is still on disk, otherwise the saved progress points
+// at data that no longer exists.
+func isPartialFileIntact(ctx context.Context, fileClient file.Client, metadata downloadTaskMetadata) bool {
+	size, err := fileClient.Size(ctx, metadata.FileName)
+	if err != nil {
+		return false
+	}
+	return size == metadata.Size
+}
+
+// canResumeSequentialDownload reports whether the saved progress of a download that writes the file front to back
+// belongs to the remote file. Like for HTTP, a changed URL is treated as a mirror and only the size is compared.
+func canResumeSequentialDownload(metadata downloadTaskMetadata, downloadURL string, size int64, lastModified string) bool {
+	if metadata.DownloadedByteCount <= 0 || metadata.Size != size {
+		return false
+	}
+
+	if metadata.ValidatedURL != downloadURL {
+		return true
+	}
+
+	return metadata.LastModified == lastModified
+}
+
+// isSafePathElement reports whether a remote supplied file or directory name can be used as an element of a local
+// path without escaping the directory of the download.
+func isSafePathElement(element string) bool {
+	return element != "" && element != "." && element != ".." && !strings.ContainsAny(element, "/\\\x00")
+}
+
+// isSafeRelativePath reports whether every element of a slash separated path is safe, see isSafePathElement.
+func isSafeRelativePath(relativePath string) bool {
+	for _, element := range strings.Split(relativePath, "/") {
+		if !isSafePathElement(element) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,385 @@
+package logic
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultFTPDialTimeout    = 30 * time.Second
+	defaultFTPPort           = "21"
+	ftpAnonymousUserName     = "anonymous"
+	ftpAnonymousPassword     = "anonymous@"
+	ftpModificationTimeValue = "20060102150405"
+)
+
+type FTPDownloader Downloader
+
+// ftpDownloader downloads ftp:// URLs in passive mode. ftps:// URLs use explicit TLS (AUTH TLS) on the same port for
+// both the control and the data connection. Credentials come from the URL user info, anonymous login is used
+// otherwise.
+type ftpDownloader struct {
+	dialer     *net.Dialer
+	tlsConfig  *tls.Config
+	fileClient file.Client
+	logger     *zap.Logger
+}
+
+func NewFTPDownloader(configs configs.DownloadConfig, fileClient file.Client, logger *zap.Logger) (FTPDownloader, error) {
+	dialTimeout := defaultFTPDialTimeout
+	if configs.FTPConfig.DialTimeout != "" {
+		var err error
+		dialTimeout, err = configs.FTPConfig.GetDialTimeoutDuration()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ftpDownloader{
+		dialer: &net.Dialer{Timeout: dialTimeout},
+		tlsConfig: &tls.Config{
+			InsecureSkipVerify: configs.FTPConfig.InsecureSkipVerify,
+			// most servers require the data connection to resume the TLS session of the control connection
+			ClientSessionCache: tls.NewLRUClientSessionCache(0),
+		},
+		fileClient: fileClient,
+		logger:     logger,
+	}, nil
+}
+
+// CanResume implements Downloader.
+func (d ftpDownloader) CanResume() bool {
+	return true
+}
+
+// Download implements Downloader.
+func (d ftpDownloader) Download(ctx context.Context, params DownloadParams, progress *DownloadProgress) error {
+	parsedURL, err := url.Parse(params.URL)
+	if err != nil {
+		return err
+	}
+
+	useTLS := false
+	switch parsedURL.Scheme {
+	case "ftp":
+	case "ftps":
+		useTLS = true
+	default:
+		return fmt.Errorf("unsupported FTP URL scheme %q", parsedURL.Scheme)
+	}
+
+	connection, err := d.connect(ctx, parsedURL, useTLS)
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	// Close is also what interrupts blocked reads when the download is cancelled
+	stopCloseOnCancel := context.AfterFunc(ctx, func() { connection.Close() })
+	defer stopCloseOnCancel()
+
+	filePath := parsedURL.Path
+	size, err := connection.Size(filePath)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.String("url", params.URL)).Warn("failed to get file size, the download cannot be resumed")
+		size = 0
+	}
+
+	lastModified, err := connection.ModificationTime(filePath)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.String("url", params.URL)).Debug("failed to get file modification time")
+	}
+
+	metadata := progress.Snapshot()
+	offset := int64(0)
+	if size > 0 && canResumeSequentialDownload(metadata, params.URL, size, lastModified) && isPartialFileIntact(ctx, d.fileClient, metadata) {
+		offset = metadata.DownloadedByteCount
+		d.logger.With(zap.String("url", params.URL), zap.Int64("offset", offset)).Info("resuming FTP download")
+	}
+
+	progress.Update(func(metadata *downloadTaskMetadata) {
+		metadata.ValidatedURL = params.URL
+		metadata.Size = size
+		metadata.LastModified = lastModified
+		metadata.ETag = ""
+		metadata.Segments = nil
+		metadata.DownloadedByteCount = offset
+	})
+
+	var writer io.Writer
+	if size > 0 {
+		writerAt, err := d.fileClient.WriteAt(ctx, metadata.FileName, size)
+		if err != nil {
+			return err
+		}
+		defer writerAt.Close()
+		writer = io.NewOffsetWriter(writerAt, offset)
+	} else {
+		writeCloser, err := d.fileClient.Write(ctx, metadata.FileName)
+		if err != nil {
+			return err
+		}
+		defer writeCloser.Close()
+		writer = writeCloser
+	}
+
+	dataConnection, err := connection.Retrieve(ctx, filePath, offset)
+	if err != nil {
+		return errors.Join(err, ctx.Err())
+	}
+	stopCloseDataOnCancel := context.AfterFunc(ctx, func() { dataConnection.Close() })
+	defer stopCloseDataOnCancel()
+
+	writtenByteCount, err := io.Copy(downloadProgressWriter{writer: writer, progress: progress}, dataConnection)
+	closeErr := dataConnection.Close()
+	if err != nil || closeErr != nil {
+		return errors.Join(err, closeErr, ctx.Err())
+	}
+
+	if size > 0 && offset+writtenByteCount != size {
+		return fmt.Errorf("FTP transfer ended after %d of %d bytes: %w", offset+writtenByteCount, size, io.ErrUnexpectedEOF)
+	}
+
+	return nil
+}
+
+func (d ftpDownloader) connect(ctx context.Context, parsedURL *url.URL, useTLS bool) (*ftpConnection, error) {
+	host := parsedURL.Hostname()
+	port := parsedURL.Port()
+	if port == "" {
+		port = defaultFTPPort
+	}
+
+	conn, err := d.dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, err
+	}
+
+	connection := &ftpConnection{
+		conn:      conn,
+		text:      textproto.NewConn(conn),
+		host:      host,
+		dialer:    d.dialer,
+		tlsConfig: d.tlsConfig.Clone(),
+	}
+	connection.tlsConfig.ServerName = host
+
+	err = connection.login(parsedURL, useTLS)
+	if err != nil {
+		connection.Close()
+		return nil, err
+	}
+
+	return connection, nil
+}
+
+// ftpConnection is an FTP control connection, it supports the subset of RFC 959, 2228, 2389 and 3659 needed to
+// download a single file.
+type ftpConnection struct {
+	conn      net.Conn
+	text      *textproto.Conn
+	host      string
+	dialer    *net.Dialer
+	tlsConfig *tls.Config
+	useTLS    bool
+}
+
+func (c *ftpConnection) Close() error {
+	return c.text.Close()
+}
+
+func (c *ftpConnection) command(expectCode int, format string, args ...any) (int, string, error) {
+	_, err := c.text.Cmd(format, args...)
+	if err != nil {
+		return 0, "", err
+	}
+	return c.text.ReadResponse(expectCode)
+}
+
+func (c *ftpConnection) login(parsedURL *url.URL, useTLS bool) error {
+	_, _, err := c.text.ReadResponse(220)
+	if err != nil {
+		return err
+	}
+
+	if useTLS {
+		_, _, err = c.command(234, "AUTH TLS")
+		if err != nil {
+			return err
+		}
+		c.conn = tls.Client(c.conn, c.tlsConfig)
+		c.text = textproto.NewConn(c.conn)
+		c.useTLS = true
+	}
+
+	userName := ftpAnonymousUserName
+	password := ftpAnonymousPassword
+	if parsedURL.User != nil {
+		userName = parsedURL.User.Username()
+		password, _ = parsedURL.User.Password()
+	}
+
+	code, message, err := c.command(0, "USER %s", userName)
+	if err != nil {
+		return err
+	}
+	switch code {
+	case 230:
+	case 331:
+		_, _, err = c.command(230, "PASS %s", password)
+		if err != nil {
+			return err
+		}
+	default:
+		return &textproto.Error{Code: code, Msg: message}
+	}
+
+	if useTLS {
+		_, _, err = c.command(200, "PBSZ 0")
+		if err != nil {
+			return err
+		}
+		_, _, err = c.command(200, "PROT P")
+		if err != nil {
+			return err
+		}
+	}
+
+	_, _, err = c.command(200, "TYPE I")
+	return err
+}
+
+func (c *ftpConnection) Size(filePath string) (int64, error) {
+	_, message, err := c.command(213, "SIZE %s", filePath)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(message), 10, 64)
+}
+
+// ModificationTime returns the MDTM value of the file, normalized to RFC 3339.
+func (c *ftpConnection) ModificationTime(filePath string) (string, error) {
+	_, message, err := c.command(213, "MDTM %s", filePath)
+	if err != nil {
+		return "", err
+	}
+
+	// the value may carry fractional seconds, which are not needed to detect changes
+	value, _, _ := strings.Cut(strings.TrimSpace(message), ".")
+	modificationTime, err := time.Parse(ftpModificationTimeValue, value)
+	if err != nil {
+		return "", err
+	}
+	return modificationTime.UTC().Format(time.RFC3339), nil
+}
+
+// passiveAddress asks the server for a passive data port. EPSV is tried first, PASV is the fallback for older
+// servers. The address in the PASV reply is ignored in favor of the control connection host, it is often a private
+// address behind NAT.
+func (c *ftpConnection) passiveAddress() (string, error) {
+	_, message, err := c.command(229, "EPSV")
+	if err == nil {
+		start := strings.Index(message, "(|||")
+		end := strings.LastIndex(message, "|)")
+		if start < 0 || end < start+4 {
+			return "", fmt.Errorf("invalid EPSV response %q", message)
+		}
+		return net.JoinHostPort(c.host, message[start+4:end]), nil
+	}
+
+	_, message, err = c.command(227, "PASV")
+	if err != nil {
+		return "", err
+	}
+
+	start := strings.Index(message, "(")
+	end := strings.LastIndex(message, ")")
+	if start < 0 || end < start {
+		return "", fmt.Errorf("invalid PASV response %q", message)
+	}
+
+	fields := strings.Split(message[start+1:end], ",")
+	if len(fields) != 6 {
+		return "", fmt.Errorf("invalid PASV response %q", message)
+	}
+
+	portHigh, err := strconv.Atoi(strings.TrimSpace(fields[4]))
+	if err != nil {
+		return "", err
+	}
+	portLow, err := strconv.Atoi(strings.TrimSpace(fields[5]))
+	if err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(c.host, strconv.Itoa(portHigh<<8|portLow)), nil
+}
+
+// Retrieve starts the transfer of the file from the given offset. Closing the returned reader waits for the server to
+// confirm the transfer.
+func (c *ftpConnection) Retrieve(ctx context.Context, filePath string, offset int64) (io.ReadCloser, error) {
+	address, err := c.passiveAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	dataConn, err := c.dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset > 0 {
+		_, _, err = c.command(350, "REST %d", offset)
+		if err != nil {
+			dataConn.Close()
+			return nil, err
+		}
+	}
+
+	code, message, err := c.command(0, "RETR %s", filePath)
+	if err != nil {
+		dataConn.Close()
+		return nil, err
+	}
+	if code != 125 && code != 150 {
+		dataConn.Close()
+		return nil, &textproto.Error{Code: code, Msg: message}
+	}
+
+	if c.useTLS {
+		dataConn = tls.Client(dataConn, c.tlsConfig)
+	}
+
+	return &ftpDataConnection{Conn: dataConn, control: c}, nil
+}
+
+type ftpDataConnection struct {
+	net.Conn
+	control   *ftpConnection
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func (d *ftpDataConnection) Close() error {
+	d.closeOnce.Do(func() {
+		d.closeErr = d.Conn.Close()
+		if d.closeErr != nil {
+			return
+		}
+		_, _, d.closeErr = d.control.text.ReadResponse(226)
+	})
+	return d.closeErr
+}
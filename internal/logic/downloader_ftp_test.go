@@ -0,0 +1,296 @@
+package logic
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"go.uber.org/zap"
+)
+
+const (
+	testFTPUserName     = "alice"
+	testFTPPassword     = "secret"
+	testFTPLastModified = "20240101120000"
+)
+
+// testFTPServer serves a single file over plain FTP in passive mode, enough of RFC 959 and 3659 for ftpDownloader.
+type testFTPServer struct {
+	listener net.Listener
+	payload  []byte
+
+	mutex sync.Mutex
+	// commands are the commands received, and sentByteCount the bytes sent over data connections, for all sessions
+	commands      []string
+	sentByteCount int
+}
+
+func newTestFTPServer(t *testing.T, payload []byte) *testFTPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	server := &testFTPServer{listener: listener, payload: payload}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+	return server
+}
+
+func (s *testFTPServer) url(userInfo string) string {
+	return fmt.Sprintf("ftp://%s%s/pub/file.bin", userInfo, s.listener.Addr())
+}
+
+func (s *testFTPServer) getCommands() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string(nil), s.commands...)
+}
+
+func (s *testFTPServer) getSentByteCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.sentByteCount
+}
+
+func (s *testFTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.serveSession(conn)
+	}
+}
+
+func (s *testFTPServer) serveSession(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(format string, args ...any) {
+		fmt.Fprintf(conn, format+"\r\n", args...)
+	}
+
+	reply("220 ready")
+	userName := ""
+	loggedIn := false
+	offset := 0
+	var dataListener net.Listener
+	defer func() {
+		if dataListener != nil {
+			dataListener.Close()
+		}
+	}()
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command, argument, _ := strings.Cut(strings.TrimSpace(line), " ")
+		s.mutex.Lock()
+		s.commands = append(s.commands, command)
+		s.mutex.Unlock()
+
+		if !loggedIn && command != "USER" && command != "PASS" && command != "QUIT" {
+			reply("530 not logged in")
+			continue
+		}
+
+		switch command {
+		case "USER":
+			userName = argument
+			reply("331 password required")
+		case "PASS":
+			if userName != testFTPUserName || argument != testFTPPassword {
+				reply("530 login incorrect")
+				continue
+			}
+			loggedIn = true
+			reply("230 logged in")
+		case "TYPE":
+			reply("200 type set")
+		case "SIZE":
+			reply("213 %d", len(s.payload))
+		case "MDTM":
+			reply("213 %s", testFTPLastModified)
+		case "EPSV":
+			dataListener, err = net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				reply("425 cannot open data connection")
+				continue
+			}
+			reply("229 entering extended passive mode (|||%d|)", dataListener.Addr().(*net.TCPAddr).Port)
+		case "REST":
+			offset, err = strconv.Atoi(argument)
+			if err != nil || offset > len(s.payload) {
+				reply("501 invalid offset")
+				continue
+			}
+			reply("350 restarting at %d", offset)
+		case "RETR":
+			if dataListener == nil {
+				reply("425 use EPSV first")
+				continue
+			}
+			reply("150 opening data connection")
+			dataConn, err := dataListener.Accept()
+			if err != nil {
+				return
+			}
+			sentByteCount, _ := dataConn.Write(s.payload[offset:])
+			dataConn.Close()
+			dataListener.Close()
+			dataListener = nil
+			offset = 0
+			s.mutex.Lock()
+			s.sentByteCount += sentByteCount
+			s.mutex.Unlock()
+			reply("226 transfer complete")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 command not implemented")
+		}
+	}
+}
+
+func newTestFTPDownloader(t *testing.T) (FTPDownloader, string) {
+	t.Helper()
+
+	directory := t.TempDir()
+	logger := zap.NewNop()
+	downloadConfig := configs.DownloadConfig{DownloadDirectory: directory}
+	fileClient, err := file.NewLocalClient(downloadConfig, logger)
+	if err != nil {
+		t.Fatalf("failed to create file client: %v", err)
+	}
+
+	downloader, err := NewFTPDownloader(downloadConfig, fileClient, logger)
+	if err != nil {
+		t.Fatalf("failed to create FTP downloader: %v", err)
+	}
+	return downloader, directory
+}
+
+func newTestPayload(size int) []byte {
+	payload := make([]byte, size)
+	for i := range payload {
+		payload[i] = byte(i * 31)
+	}
+	return payload
+}
+
+func TestFTPDownloaderDownload(t *testing.T) {
+	payload := newTestPayload(256*1024 + 7)
+	server := newTestFTPServer(t, payload)
+	downloader, directory := newTestFTPDownloader(t)
+
+	progress := newDownloadProgress(downloadTaskMetadata{FileName: "file.bin"})
+	err := downloader.Download(context.Background(), DownloadParams{URL: server.url(testFTPUserName + ":" + testFTPPassword + "@")}, progress)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(directory, "file.bin"))
+	if err != nil {
+		t.Fatalf("failed to read downloaded file: %v", err)
+	}
+	if !bytes.Equal(content, payload) {
+		t.Fatalf("downloaded file differs from the served file")
+	}
+
+	metadata := progress.Snapshot()
+	if metadata.Size != int64(len(payload)) || metadata.DownloadedByteCount != int64(len(payload)) {
+		t.Errorf("Size = %d, DownloadedByteCount = %d, want both %d", metadata.Size, metadata.DownloadedByteCount, len(payload))
+	}
+	if metadata.LastModified != "2024-01-01T12:00:00Z" {
+		t.Errorf("LastModified = %q, want %q", metadata.LastModified, "2024-01-01T12:00:00Z")
+	}
+	if commands := server.getCommands(); slices.Contains(commands, "REST") {
+		t.Errorf("commands = %v, want no REST for a new download", commands)
+	}
+}
+
+func TestFTPDownloaderDownloadResumes(t *testing.T) {
+	payload := newTestPayload(256*1024 + 7)
+	server := newTestFTPServer(t, payload)
+	downloader, directory := newTestFTPDownloader(t)
+	downloadURL := server.url(testFTPUserName + ":" + testFTPPassword + "@")
+
+	// an interrupted download leaves a file of the full size with only its beginning written
+	const downloadedByteCount = 100 * 1024
+	partialContent := make([]byte, len(payload))
+	copy(partialContent, payload[:downloadedByteCount])
+	err := os.WriteFile(filepath.Join(directory, "file.bin"), partialContent, 0o644)
+	if err != nil {
+		t.Fatalf("failed to write partial file: %v", err)
+	}
+
+	progress := newDownloadProgress(downloadTaskMetadata{
+		FileName:            "file.bin",
+		ValidatedURL:        downloadURL,
+		Size:                int64(len(payload)),
+		LastModified:        "2024-01-01T12:00:00Z",
+		DownloadedByteCount: downloadedByteCount,
+	})
+	err = downloader.Download(context.Background(), DownloadParams{URL: downloadURL}, progress)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(directory, "file.bin"))
+	if err != nil {
+		t.Fatalf("failed to read downloaded file: %v", err)
+	}
+	if !bytes.Equal(content, payload) {
+		t.Fatalf("resumed file differs from the served file")
+	}
+	if sentByteCount := server.getSentByteCount(); sentByteCount != len(payload)-downloadedByteCount {
+		t.Errorf("server sent %d bytes, want only the remaining %d", sentByteCount, len(payload)-downloadedByteCount)
+	}
+	if downloaded := progress.Snapshot().DownloadedByteCount; downloaded != int64(len(payload)) {
+		t.Errorf("DownloadedByteCount = %d, want %d", downloaded, len(payload))
+	}
+
+	commands := server.getCommands()
+	restIndex := slices.Index(commands, "REST")
+	if restIndex < 0 || restIndex > slices.Index(commands, "RETR") {
+		t.Errorf("commands = %v, want REST before RETR", commands)
+	}
+}
+
+func TestFTPDownloaderDownloadLoginFailure(t *testing.T) {
+	server := newTestFTPServer(t, newTestPayload(1024))
+	downloader, directory := newTestFTPDownloader(t)
+
+	progress := newDownloadProgress(downloadTaskMetadata{FileName: "file.bin"})
+	err := downloader.Download(context.Background(), DownloadParams{URL: server.url(testFTPUserName + ":wrong@")}, progress)
+
+	var ftpError *textproto.Error
+	if !errors.As(err, &ftpError) || ftpError.Code != 530 {
+		t.Fatalf("Download() error = %v, want a 530 reply", err)
+	}
+	if _, err := os.Stat(filepath.Join(directory, "file.bin")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("a failed login created the file, stat error = %v", err)
+	}
+	if commands := server.getCommands(); slices.Contains(commands, "RETR") {
+		t.Errorf("commands = %v, want no RETR after a failed login", commands)
+	}
+}
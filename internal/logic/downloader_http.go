@@ -0,0 +1,443 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"go.uber.org/zap"
+)
+
+var (
+	errRemoteFileChanged = errors.New("remote file changed since the download started")
+)
+
+type HTTPDownloader Downloader
+
+type httpDownloader struct {
+	httpClient *http.Client
+	fileClient file.Client
+	logger     *zap.Logger
+}
+
+func NewHTTPDownloader(fileClient file.Client, logger *zap.Logger) HTTPDownloader {
+	return &httpDownloader{
+		httpClient: &http.Client{},
+		fileClient: fileClient,
+		logger:     logger,
+	}
+}
+
+// CanResume implements Downloader.
+func (d httpDownloader) CanResume() bool {
+	return true
+}
+
+type httpFileInfo struct {
+	Size         int64
+	AcceptRanges bool
+	ETag         string
+	LastModified string
+}
+
+// splitIntoSegments splits a file of the given size into at most segmentCount byte ranges, none of them smaller than
+// minSegmentSize except for the last one.
+func splitIntoSegments(size int64, segmentCount int, minSegmentSize int64) []downloadSegment {
+	if size <= 0 {
+		return nil
+	}
+
+	// every segment has at least one byte
+	maxSegmentCount := size
+	if minSegmentSize > 0 {
+		maxSegmentCount = size / minSegmentSize
+	}
+	if int64(segmentCount) > maxSegmentCount {
+		segmentCount = int(maxSegmentCount)
+	}
+	if segmentCount < 1 {
+		segmentCount = 1
+	}
+
+	segmentSize := size / int64(segmentCount)
+	segments := make([]downloadSegment, 0, segmentCount)
+	for i := 0; i < segmentCount; i++ {
+		start := int64(i) * segmentSize
+		end := start + segmentSize - 1
+		if i == segmentCount-1 {
+			end = size - 1
+		}
+		segments = append(segments, downloadSegment{Start: start, End: end})
+	}
+	return segments
+}
+
+// isSameRemoteFile reports whether the saved progress in metadata can be reused for the remote file described by
+// fileInfo. On the same URL the origin's validators have to match. A different URL is treated as a mirror of the same
+// file, and since mirrors generate their own ETag and Last-Modified values only the size can be compared.
+func isSameRemoteFile(metadata downloadTaskMetadata, downloadURL string, fileInfo httpFileInfo) bool {
+	if len(metadata.Segments) == 0 || metadata.Size != fileInfo.Size {
+		return false
+	}
+
+	if metadata.ValidatedURL != downloadURL {
+		return true
+	}
+
+	if metadata.ETag != "" || fileInfo.ETag != "" {
+		return metadata.ETag == fileInfo.ETag
+	}
+
+	return metadata.LastModified == fileInfo.LastModified
+}
+
+func (d httpDownloader) getHTTPFileInfo(ctx context.Context, downloadURL string) (httpFileInfo, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodHead, downloadURL, nil)
+	if err != nil {
+		return httpFileInfo{}, err
+	}
+
+	response, err := d.httpClient.Do(request)
+	if err != nil {
+		return httpFileInfo{}, err
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return httpFileInfo{}, newHTTPStatusError(response.StatusCode, "unexpected response status: %s", response.Status)
+	}
+
+	return httpFileInfo{
+		Size:         response.ContentLength,
+		AcceptRanges: strings.EqualFold(response.Header.Get("Accept-Ranges"), "bytes"),
+		ETag:         response.Header.Get("ETag"),
+		LastModified: response.Header.Get("Last-Modified"),
+	}, nil
+}
+
+// Download implements Downloader.
+func (d httpDownloader) Download(ctx context.Context, params DownloadParams, progress *DownloadProgress) error {
+	downloadURL := params.URL
+	metadata := progress.Snapshot()
+	if metadata.CrawlOptions != nil {
+		return d.downloadHTTPCrawl(ctx, params, progress)
+	}
+
+	logger := d.logger.With(zap.String("url", downloadURL), zap.String("fileName", metadata.FileName))
+
+	downloadURLs := append([]string{downloadURL}, params.MirrorURLs...)
+	mirrors := d.probeHTTPMirrors(ctx, downloadURLs, logger)
+	if len(mirrors) == 0 {
+		// some origins do not implement HEAD, a plain GET may still work
+		logger.Warn("failed to get file info, falling back to a single stream")
+		return d.downloadHTTPSingleStreamFromMirrors(ctx, downloadURLs, progress, logger)
+	}
+
+	if params.ExpectedSize > 0 {
+		mirrors = slices.DeleteFunc(mirrors, func(mirror *httpMirror) bool {
+			if mirror.fileInfo.Size <= 0 || mirror.fileInfo.Size == params.ExpectedSize {
+				return false
+			}
+			logger.With(zap.String("mirrorURL", mirror.url), zap.Int64("size", mirror.fileInfo.Size)).
+				Warn("mirror does not serve a file of the expected size, not using it")
+			return true
+		})
+		if len(mirrors) == 0 {
+			return fmt.Errorf("%w: no URL serves a file of %d bytes", errSizeMismatch, params.ExpectedSize)
+		}
+	}
+
+	// the file is described by the first URL that answered, mirrors serving a different file are not used
+	reference := slices.MinFunc(mirrors, func(a, b *httpMirror) int {
+		return slices.Index(downloadURLs, a.url) - slices.Index(downloadURLs, b.url)
+	})
+	fileInfo := reference.fileInfo
+	if !fileInfo.AcceptRanges || fileInfo.Size <= 0 {
+		logger.With(zap.Int64("size", fileInfo.Size), zap.Bool("acceptRanges", fileInfo.AcceptRanges)).
+			Info("origin does not support ranges, downloading file in a single stream")
+		streamURLs := []string{reference.url}
+		for _, mirror := range mirrors {
+			if mirror != reference {
+				streamURLs = append(streamURLs, mirror.url)
+			}
+		}
+		return d.downloadHTTPSingleStreamFromMirrors(ctx, streamURLs, progress, logger)
+	}
+
+	mirrors = slices.DeleteFunc(mirrors, func(mirror *httpMirror) bool {
+		if mirror.fileInfo.AcceptRanges && mirror.fileInfo.Size == fileInfo.Size {
+			return false
+		}
+		logger.With(zap.String("mirrorURL", mirror.url), zap.Int64("size", mirror.fileInfo.Size)).
+			Warn("mirror does not serve the same file with ranges, not using it")
+		return true
+	})
+	mirrorPool := newHTTPMirrorPool(mirrors)
+
+	if isSameRemoteFile(metadata, reference.url, fileInfo) && isPartialFileIntact(ctx, d.fileClient, metadata) {
+		logger.Info("resuming download from saved progress")
+		setRemoteFileInfo(progress, reference.url, fileInfo, nil)
+		err := d.downloadHTTPSegments(ctx, mirrorPool, reference, progress, logger)
+		if !errors.Is(err, errRemoteFileChanged) {
+			return err
+		}
+		// the file info the progress was validated against is stale, the restart fetches it again
+		logger.Warn("remote file changed, restarting download from the beginning")
+		setRemoteFileInfo(progress, reference.url, httpFileInfo{}, []downloadSegment{})
+		return d.Download(ctx, params, progress)
+	}
+
+	segments := splitIntoSegments(fileInfo.Size, params.SegmentCount, params.MinSegmentSize)
+	setRemoteFileInfo(progress, reference.url, fileInfo, segments)
+	err := d.fileClient.Delete(ctx, metadata.FileName)
+	if err != nil {
+		return err
+	}
+
+	logger.With(zap.Int64("size", fileInfo.Size), zap.Int("segmentCount", len(segments)), zap.Int("mirrorCount", len(mirrors))).
+		Info("downloading file in segments")
+	return d.downloadHTTPSegments(ctx, mirrorPool, reference, progress, logger)
+}
+
+// setRemoteFileInfo records the remote file the progress belongs to. Segments are replaced only when they are not nil.
+func setRemoteFileInfo(
+	progress *DownloadProgress,
+	downloadURL string,
+	fileInfo httpFileInfo,
+	segments []downloadSegment,
+) {
+	progress.Update(func(metadata *downloadTaskMetadata) {
+		metadata.ValidatedURL = downloadURL
+		metadata.Size = fileInfo.Size
+		metadata.ETag = fileInfo.ETag
+		metadata.LastModified = fileInfo.LastModified
+		if segments != nil {
+			metadata.Segments = segments
+		}
+
+		metadata.DownloadedByteCount = 0
+		for _, segment := range metadata.Segments {
+			metadata.DownloadedByteCount += segment.DownloadedByteCount
+		}
+	})
+}
+
+// downloadHTTPSingleStreamFromMirrors downloads the file in a single stream from the first URL that works. A stream
+// cannot be resumed, so moving on to the next URL starts the download over.
+func (d httpDownloader) downloadHTTPSingleStreamFromMirrors(
+	ctx context.Context,
+	downloadURLs []string,
+	progress *DownloadProgress,
+	logger *zap.Logger,
+) error {
+	var err error
+	for _, downloadURL := range downloadURLs {
+		err = d.downloadHTTPSingleStream(ctx, downloadURL, progress)
+		if err == nil || ctx.Err() != nil || !isMirrorError(err) {
+			return err
+		}
+		logger.With(zap.Error(err), zap.String("mirrorURL", downloadURL)).Warn("mirror failed, downloading from the next one")
+	}
+	return err
+}
+
+func (d httpDownloader) downloadHTTPSingleStream(ctx context.Context, downloadURL string, progress *DownloadProgress) error {
+	// without range support there is nothing to resume from
+	setRemoteFileInfo(progress, downloadURL, httpFileInfo{}, []downloadSegment{})
+	metadata := progress.Snapshot()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := d.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return newHTTPStatusError(response.StatusCode, "unexpected response status: %s", response.Status)
+	}
+
+	writer, err := d.fileClient.Write(ctx, metadata.FileName)
+	if err != nil {
+		return err
+	}
+
+	if response.ContentLength > 0 {
+		progress.SetSize(response.ContentLength)
+	}
+	_, err = io.Copy(downloadProgressWriter{writer: writer, progress: progress}, response.Body)
+	closeErr := writer.Close()
+	return errors.Join(err, closeErr)
+}
+
+// downloadHTTPSegments downloads the segments from the mirrors of the pool. A segment whose mirror fails continues
+// where it stopped from another mirror. The reference mirror is the one the saved progress was validated against, a
+// change of its file means the progress is lost, while any other mirror that changed is just left out.
+func (d httpDownloader) downloadHTTPSegments(
+	ctx context.Context,
+	mirrorPool *httpMirrorPool,
+	reference *httpMirror,
+	progress *DownloadProgress,
+	logger *zap.Logger,
+) error {
+	metadata := progress.Snapshot()
+	writer, err := d.fileClient.WriteAt(ctx, metadata.FileName, metadata.Size)
+	if err != nil {
+		return err
+	}
+
+	segmentErr := downloadSegmentsInParallel(ctx, metadata.Segments, func(ctx context.Context, segmentIndex int, segment downloadSegment) error {
+		for {
+			mirror, err := mirrorPool.acquire()
+			if err != nil {
+				return err
+			}
+
+			err = d.downloadHTTPSegment(ctx, mirror.url, mirror.getIfRangeValidator(), writer, progress, segmentIndex, segment)
+			mirrorFailed := err != nil && ctx.Err() == nil &&
+				(isMirrorError(err) || errors.Is(err, errRemoteFileChanged) && mirror != reference)
+			if !mirrorPool.release(mirror, mirrorFailed) {
+				return err
+			}
+
+			logger.With(zap.Error(err), zap.String("mirrorURL", mirror.url), zap.Int("segmentIndex", segmentIndex)).
+				Warn("mirror failed, moving segment to another mirror")
+			segment = progress.Snapshot().Segments[segmentIndex]
+		}
+	})
+
+	closeErr := writer.Close()
+	return errors.Join(segmentErr, closeErr)
+}
+
+// downloadSegmentsInParallel runs download for every segment that is not completed yet. The first failing
+// segment cancels the others, there is no point in finishing a download that is already broken.
+func downloadSegmentsInParallel(
+	ctx context.Context,
+	segments []downloadSegment,
+	download func(ctx context.Context, segmentIndex int, segment downloadSegment) error,
+) error {
+	segmentCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		waitGroup  sync.WaitGroup
+		errorLock  sync.Mutex
+		segmentErr error
+	)
+	for segmentIndex, segment := range segments {
+		if segment.IsCompleted() {
+			continue
+		}
+
+		waitGroup.Add(1)
+		go func(segmentIndex int, segment downloadSegment) {
+			defer waitGroup.Done()
+			err := download(segmentCtx, segmentIndex, segment)
+			if err != nil {
+				errorLock.Lock()
+				if segmentErr == nil {
+					segmentErr = err
+					cancel()
+				}
+				errorLock.Unlock()
+			}
+		}(segmentIndex, segment)
+	}
+	waitGroup.Wait()
+
+	return segmentErr
+}
+
+func (d httpDownloader) downloadHTTPSegment(
+	ctx context.Context,
+	downloadURL string,
+	ifRangeValidator string,
+	writer file.WriterAtCloser,
+	progress *DownloadProgress,
+	segmentIndex int,
+	segment downloadSegment,
+) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return err
+	}
+
+	start := segment.Start + segment.DownloadedByteCount
+	request.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, segment.End))
+	if ifRangeValidator != "" {
+		request.Header.Set("If-Range", ifRangeValidator)
+	}
+
+	response, err := d.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusOK && ifRangeValidator != "" {
+		// If-Range did not match, the origin is sending the whole new file instead of the requested range
+		return errRemoteFileChanged
+	}
+
+	if response.StatusCode != http.StatusPartialContent {
+		return newHTTPStatusError(response.StatusCode, "unexpected response status for range %d-%d: %s", start, segment.End, response.Status)
+	}
+
+	segmentWriter := &downloadSegmentWriter{
+		writer: writer,
+		offset: start,
+		onWrite: func(byteCount int64) {
+			progress.AddSegmentDownloadedByteCount(segmentIndex, byteCount)
+		},
+	}
+	remainingByteCount := segment.End - start + 1
+	writtenByteCount, err := io.Copy(segmentWriter, io.LimitReader(response.Body, remainingByteCount))
+	if err != nil {
+		return err
+	}
+
+	if writtenByteCount != remainingByteCount {
+		return fmt.Errorf("range %d-%d ended early after %d bytes: %w", start, segment.End, writtenByteCount, io.ErrUnexpectedEOF)
+	}
+
+	return nil
+}
+
+// downloadSegmentWriter writes sequentially into a segment of the output file and reports every write, so the
+// progress only ever counts bytes that actually made it to the file.
+type downloadSegmentWriter struct {
+	writer  io.WriterAt
+	offset  int64
+	onWrite func(byteCount int64)
+}
+
+func (w *downloadSegmentWriter) Write(data []byte) (int, error) {
+	writtenByteCount, err := w.writer.WriteAt(data, w.offset)
+	w.offset += int64(writtenByteCount)
+	w.onWrite(int64(writtenByteCount))
+	return writtenByteCount, err
+}
+
+// downloadProgressWriter writes the file of a sequential download that has no segments and counts the bytes that
+// actually made it to the file.
+type downloadProgressWriter struct {
+	writer   io.Writer
+	progress *DownloadProgress
+}
+
+func (w downloadProgressWriter) Write(data []byte) (int, error) {
+	writtenByteCount, err := w.writer.Write(data)
+	w.progress.AddDownloadedByteCount(int64(writtenByteCount))
+	return writtenByteCount, err
+}
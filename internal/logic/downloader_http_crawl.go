@@ -0,0 +1,789 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
+	"go.uber.org/zap"
+	"golang.org/x/net/html"
+)
+
+const (
+	defaultCrawlMaxDepth = 5
+	maxCrawlMaxDepth     = 20
+	maxCrawlPatternCount = 32
+	// crawlMaxFileCount keeps the file list of a crawl small enough for the metadata of its task
+	crawlMaxFileCount = 500
+	// crawlMaxRequestCount bounds the pages fetched only for their links on top of the saved files
+	crawlMaxRequestCount = 2000
+	// crawlMaxPageSize is how much of a page is parsed for links, larger pages are saved without following their links
+	crawlMaxPageSize = 10 * 1024 * 1024
+)
+
+var (
+	errCrawlNotSupported       = errors.New("only HTTP download tasks can crawl")
+	errCrawlWithOtherOptions   = errors.New("a crawl download task cannot have mirror URLs, watch options, archive extraction or an expected digest or size")
+	errCrawlMaxDepthTooHigh    = fmt.Errorf("the max depth of a crawl cannot be more than %d", maxCrawlMaxDepth)
+	errInvalidCrawlPathPrefix  = errors.New("the path prefix of a crawl must start with a slash")
+	errTooManyCrawlPatterns    = fmt.Errorf("a crawl can have at most %d include and %d exclude patterns", maxCrawlPatternCount, maxCrawlPatternCount)
+	errCrawlStartURLDisallowed = errors.New("robots.txt disallows crawling the URL of the download task")
+	errCrawlStartURLOutOfScope = errors.New("the URL of the download task is outside of the scope of the crawl")
+	errCrawlFoundNoFile        = errors.New("the crawl found no file to save")
+)
+
+var (
+	// apacheIndexSortQuery matches the query of the links Apache directory indexes sort their columns with
+	apacheIndexSortQuery = regexp.MustCompile(`^C=[NMSD][;&]O=[AD]$`)
+	// pageExtensions are the extensions of URLs fetched to follow their links even when they are not to be saved
+	pageExtensions = []string{"", ".html", ".htm", ".xhtml", ".shtml", ".php", ".asp", ".aspx", ".jsp", ".cgi"}
+)
+
+// crawlOptionsFromProto checks the crawl options of a new task. A crawl saves many files into a directory, options
+// describing a single file do not apply to it.
+func crawlOptionsFromProto(params CreateDownloadTaskParams) (*crawlOptions, error) {
+	options := params.CrawlOptions
+	if options == nil {
+		return nil, nil
+	}
+
+	switch {
+	case params.DownloadType != go_load.DownloadType_HTTP:
+		return nil, errCrawlNotSupported
+	case len(params.MirrorURLs) > 0 || params.WatchOptions != nil || params.ExtractArchive ||
+		params.ExpectedDigest != nil || params.ExpectedSize > 0 || params.PieceDigests != nil:
+		return nil, errCrawlWithOtherOptions
+	case options.GetMaxDepth() > maxCrawlMaxDepth:
+		return nil, errCrawlMaxDepthTooHigh
+	case options.GetPathPrefix() != "" && !strings.HasPrefix(options.GetPathPrefix(), "/"):
+		return nil, errInvalidCrawlPathPrefix
+	case len(options.GetIncludePatterns()) > maxCrawlPatternCount || len(options.GetExcludePatterns()) > maxCrawlPatternCount:
+		return nil, errTooManyCrawlPatterns
+	}
+
+	for _, pattern := range slices.Concat(options.GetIncludePatterns(), options.GetExcludePatterns()) {
+		_, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid crawl pattern %q: %w", pattern, err)
+		}
+	}
+
+	maxDepth := options.GetMaxDepth()
+	if maxDepth == 0 {
+		maxDepth = defaultCrawlMaxDepth
+	}
+
+	allowedDomains := make([]string, 0, len(options.GetAllowedDomains()))
+	for _, domain := range options.GetAllowedDomains() {
+		allowedDomains = append(allowedDomains, strings.ToLower(strings.TrimPrefix(domain, ".")))
+	}
+
+	return &crawlOptions{
+		MaxDepth:        maxDepth,
+		AllowedDomains:  allowedDomains,
+		PathPrefix:      options.GetPathPrefix(),
+		IncludePatterns: options.GetIncludePatterns(),
+		ExcludePatterns: options.GetExcludePatterns(),
+		RewriteLinks:    options.GetRewriteLinks(),
+	}, nil
+}
+
+// crawlScope decides which of the links found by a crawl are followed and which are saved.
+type crawlScope struct {
+	allowedDomains  []string
+	pathPrefix      string
+	includePatterns []*regexp.Regexp
+	excludePatterns []*regexp.Regexp
+}
+
+func newCrawlScope(startURL *url.URL, options crawlOptions) (crawlScope, error) {
+	scope := crawlScope{
+		allowedDomains: options.AllowedDomains,
+		pathPrefix:     options.PathPrefix,
+	}
+	if len(scope.allowedDomains) == 0 {
+		scope.allowedDomains = []string{strings.ToLower(startURL.Hostname())}
+	}
+	if scope.pathPrefix == "" {
+		// like the parent directory link of directory indexes, nothing above the directory of the URL is crawled
+		scope.pathPrefix = startURL.Path[:strings.LastIndex(startURL.Path, "/")+1]
+	}
+
+	for _, pattern := range options.IncludePatterns {
+		expression, err := regexp.Compile(pattern)
+		if err != nil {
+			return crawlScope{}, err
+		}
+		scope.includePatterns = append(scope.includePatterns, expression)
+	}
+	for _, pattern := range options.ExcludePatterns {
+		expression, err := regexp.Compile(pattern)
+		if err != nil {
+			return crawlScope{}, err
+		}
+		scope.excludePatterns = append(scope.excludePatterns, expression)
+	}
+
+	return scope, nil
+}
+
+// isInScope reports whether the URL is on an allowed domain, under the path prefix and not excluded.
+func (s crawlScope) isInScope(crawlURL *url.URL) bool {
+	if crawlURL.Scheme != "http" && crawlURL.Scheme != "https" {
+		return false
+	}
+
+	host := strings.ToLower(crawlURL.Hostname())
+	if !slices.ContainsFunc(s.allowedDomains, func(domain string) bool {
+		return host == domain || strings.HasSuffix(host, "."+domain)
+	}) {
+		return false
+	}
+
+	if !strings.HasPrefix(crawlURL.Path, s.pathPrefix) && crawlURL.Path+"/" != s.pathPrefix {
+		return false
+	}
+
+	if apacheIndexSortQuery.MatchString(crawlURL.RawQuery) {
+		return false
+	}
+
+	urlString := crawlURL.String()
+	return !slices.ContainsFunc(s.excludePatterns, func(expression *regexp.Regexp) bool {
+		return expression.MatchString(urlString)
+	})
+}
+
+// shouldSave reports whether the file at the URL is saved, which is every file unless include patterns are given.
+func (s crawlScope) shouldSave(crawlURL *url.URL) bool {
+	if len(s.includePatterns) == 0 {
+		return true
+	}
+
+	urlString := crawlURL.String()
+	return slices.ContainsFunc(s.includePatterns, func(expression *regexp.Regexp) bool {
+		return expression.MatchString(urlString)
+	})
+}
+
+// shouldFetch reports whether the URL is fetched, either to be saved or because it looks like a page with links.
+func (s crawlScope) shouldFetch(crawlURL *url.URL) bool {
+	return s.shouldSave(crawlURL) || slices.Contains(pageExtensions, strings.ToLower(path.Ext(crawlURL.Path)))
+}
+
+// getCrawlFilePath returns the path relative to the directory of the crawl a URL is saved at, made of its host and
+// path. Directories are saved as their index.html, queries are kept in the name so that pages differing by their query
+// do not overwrite each other.
+func getCrawlFilePath(crawlURL *url.URL) (string, bool) {
+	urlPath := crawlURL.Path
+	if urlPath == "" {
+		urlPath = "/"
+	}
+
+	filePath := strings.ToLower(crawlURL.Host) + urlPath
+	if strings.HasSuffix(filePath, "/") {
+		filePath += "index.html"
+	}
+	if crawlURL.RawQuery != "" {
+		filePath += "?" + strings.ReplaceAll(crawlURL.RawQuery, "/", "%2F")
+	}
+
+	return filePath, isSafeRelativePath(filePath)
+}
+
+// crawlItem is a URL waiting to be fetched, with how many links away from the URL of the task it was found.
+type crawlItem struct {
+	url   *url.URL
+	depth uint32
+}
+
+// crawledPage is a saved page, kept to rewrite its links once every file of the crawl is known.
+type crawledPage struct {
+	url      *url.URL
+	filePath string
+}
+
+// httpCrawl is the state of one run of a crawl.
+type httpCrawl struct {
+	downloader httpDownloader
+	options    crawlOptions
+	scope      crawlScope
+	robots     *robotsCache
+	progress   *DownloadProgress
+	directory  string
+	// completedFilePaths are the files of the URLs an interrupted run completed, they are not downloaded again
+	completedFilePaths map[string]string
+	// linkedFilePaths are the files saved by this run by the URLs linking to them, including redirected ones
+	linkedFilePaths map[string]string
+	// usedFilePaths and usedDirectories keep a file from being saved where another file or a directory already is
+	usedFilePaths   map[string]bool
+	usedDirectories map[string]bool
+	pages           []crawledPage
+	requestCount    int
+	logger          *zap.Logger
+}
+
+// downloadHTTPCrawl follows the links of the page at the URL of the task, saving every file found under the directory
+// named by the file name of the task. Pages are parsed for href, src and srcset links, which covers directory indexes.
+// Files completed by an interrupted run are kept, pages are fetched again to find their links.
+func (d httpDownloader) downloadHTTPCrawl(ctx context.Context, params DownloadParams, progress *DownloadProgress) error {
+	metadata := progress.Snapshot()
+	logger := d.logger.With(zap.String("url", params.URL), zap.String("fileName", metadata.FileName))
+
+	startURL, err := url.Parse(params.URL)
+	if err != nil {
+		return err
+	}
+	startURL.Fragment = ""
+
+	scope, err := newCrawlScope(startURL, *metadata.CrawlOptions)
+	if err != nil {
+		return err
+	}
+	if !scope.isInScope(startURL) {
+		return errCrawlStartURLOutOfScope
+	}
+
+	crawl := &httpCrawl{
+		downloader:         d,
+		options:            *metadata.CrawlOptions,
+		scope:              scope,
+		robots:             newRobotsCache(d.httpClient, logger),
+		progress:           progress,
+		directory:          metadata.FileName,
+		completedFilePaths: make(map[string]string),
+		linkedFilePaths:    make(map[string]string),
+		usedFilePaths:      make(map[string]bool),
+		usedDirectories:    make(map[string]bool),
+		logger:             logger,
+	}
+
+	if metadata.ValidatedURL == params.URL && len(metadata.CrawledURLs) == len(metadata.Files) {
+		logger.Info("resuming crawl from saved progress")
+		for i, crawledURL := range metadata.CrawledURLs {
+			if i < len(metadata.CompletedFiles) && metadata.CompletedFiles[i] {
+				crawl.completedFilePaths[crawledURL] = metadata.Files[i]
+			}
+		}
+	} else {
+		err = d.fileClient.Delete(ctx, metadata.FileName)
+		if err != nil {
+			return err
+		}
+	}
+
+	progress.Update(func(metadata *downloadTaskMetadata) {
+		metadata.ValidatedURL = params.URL
+		metadata.Size = 0
+		metadata.ETag = ""
+		metadata.LastModified = ""
+		metadata.Segments = nil
+		metadata.Files = []string{}
+		metadata.CompletedFiles = []bool{}
+		metadata.CrawledURLs = []string{}
+		metadata.DownloadedByteCount = 0
+	})
+
+	if !crawl.robots.isAllowed(ctx, startURL) {
+		return errCrawlStartURLDisallowed
+	}
+
+	err = crawl.run(ctx, startURL)
+	if err != nil {
+		return err
+	}
+
+	if len(progress.Snapshot().Files) == 0 {
+		return errCrawlFoundNoFile
+	}
+
+	if crawl.options.RewriteLinks {
+		return crawl.rewriteLinks(ctx)
+	}
+	return nil
+}
+
+// run fetches the URLs breadth first, so that the files closest to the URL of the task are saved first when the crawl
+// runs out of files it may save.
+func (c *httpCrawl) run(ctx context.Context, startURL *url.URL) error {
+	queue := []crawlItem{{url: startURL}}
+	queued := map[string]bool{startURL.String(): true}
+	for len(queue) > 0 {
+		if len(c.usedFilePaths) >= crawlMaxFileCount || c.requestCount >= crawlMaxRequestCount {
+			c.logger.With(zap.Int("fileCount", len(c.usedFilePaths)), zap.Int("requestCount", c.requestCount)).
+				Warn("crawl reached its limit, not following the remaining links")
+			return nil
+		}
+
+		item := queue[0]
+		queue = queue[1:]
+		logger := c.logger.With(zap.String("crawlURL", item.url.String()))
+
+		if !c.robots.isAllowed(ctx, item.url) {
+			logger.Debug("robots.txt disallows crawling URL")
+			continue
+		}
+
+		links, err := c.fetch(ctx, item)
+		if err != nil {
+			// a broken link does not fail the crawl, only the URL of the task itself does
+			if ctx.Err() != nil || !isCrawlFetchError(err) || item.depth == 0 {
+				return err
+			}
+			logger.With(zap.Error(err)).Warn("failed to fetch crawled URL, skipping it")
+			continue
+		}
+
+		if item.depth >= c.options.MaxDepth {
+			continue
+		}
+		for _, link := range links {
+			if queued[link.String()] || !c.scope.isInScope(link) || !c.scope.shouldFetch(link) {
+				continue
+			}
+			queued[link.String()] = true
+			queue = append(queue, crawlItem{url: link, depth: item.depth + 1})
+		}
+	}
+	return nil
+}
+
+// crawlFetchError is a URL of the crawl that could not be fetched, as opposed to a file of the crawl that could not be
+// written.
+type crawlFetchError struct {
+	err error
+}
+
+func (e crawlFetchError) Error() string {
+	return e.err.Error()
+}
+
+func (e crawlFetchError) Unwrap() error {
+	return e.err
+}
+
+func isCrawlFetchError(err error) bool {
+	return errors.As(err, &crawlFetchError{})
+}
+
+// fetch saves the file at the URL of the item when it is to be saved, and returns the links of the page it is.
+func (c *httpCrawl) fetch(ctx context.Context, item crawlItem) ([]*url.URL, error) {
+	filePath, ok := getCrawlFilePath(item.url)
+	if !ok {
+		c.logger.With(zap.String("crawlURL", item.url.String())).Warn("skipping URL that cannot be used as a file path")
+		return nil, nil
+	}
+
+	if completedFilePath, ok := c.completedFilePaths[item.url.String()]; ok && completedFilePath == filePath {
+		kept, err := c.keepCompletedFile(ctx, item.url, filePath)
+		if err != nil || kept {
+			return nil, err
+		}
+	}
+
+	c.requestCount++
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, item.url.String(), nil)
+	if err != nil {
+		return nil, crawlFetchError{err: err}
+	}
+	request.Header.Set("User-Agent", crawlUserAgent)
+
+	response, err := c.downloader.httpClient.Do(request)
+	if err != nil {
+		return nil, crawlFetchError{err: err}
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return nil, crawlFetchError{err: newHTTPStatusError(response.StatusCode, "unexpected response status: %s", response.Status)}
+	}
+
+	// a redirect may lead out of the scope, links of the page are relative to where it ended up
+	pageURL := response.Request.URL
+	if pageURL.String() != item.url.String() {
+		if !c.scope.isInScope(pageURL) {
+			return nil, nil
+		}
+		filePath, ok = getCrawlFilePath(pageURL)
+		if !ok {
+			return nil, nil
+		}
+	}
+
+	save := c.scope.shouldSave(pageURL) && c.reserveFilePath(filePath)
+	if save {
+		c.linkedFilePaths[item.url.String()] = filePath
+		c.linkedFilePaths[pageURL.String()] = filePath
+	}
+	if !isHTMLContentType(response.Header.Get("Content-Type")) {
+		if !save {
+			return nil, nil
+		}
+		err = c.saveFile(ctx, pageURL, filePath, response.Body)
+		if err != nil {
+			c.releaseFilePath(filePath, item.url, pageURL)
+		}
+		return nil, err
+	}
+
+	var page bytes.Buffer
+	_, err = io.Copy(&page, io.LimitReader(response.Body, crawlMaxPageSize+1))
+	if err != nil {
+		if save {
+			c.releaseFilePath(filePath, item.url, pageURL)
+		}
+		return nil, crawlFetchError{err: err}
+	}
+	if save {
+		err = c.saveFile(ctx, pageURL, filePath, io.MultiReader(bytes.NewReader(page.Bytes()), response.Body))
+		if err != nil {
+			c.releaseFilePath(filePath, item.url, pageURL)
+			return nil, err
+		}
+		c.pages = append(c.pages, crawledPage{url: pageURL, filePath: filePath})
+	}
+
+	if page.Len() > crawlMaxPageSize {
+		c.logger.With(zap.String("crawlURL", pageURL.String())).Warn("page is too large to follow its links")
+		return nil, nil
+	}
+	return extractHTMLLinks(pageURL, page.Bytes()), nil
+}
+
+// reserveFilePath takes the file path, unless a file or a directory is already there or a file is where one of its
+// directories would be.
+func (c *httpCrawl) reserveFilePath(filePath string) bool {
+	if c.usedFilePaths[filePath] || c.usedDirectories[filePath] {
+		return false
+	}
+	for directory := path.Dir(filePath); directory != "."; directory = path.Dir(directory) {
+		if c.usedFilePaths[directory] {
+			return false
+		}
+	}
+
+	c.usedFilePaths[filePath] = true
+	for directory := path.Dir(filePath); directory != "."; directory = path.Dir(directory) {
+		c.usedDirectories[directory] = true
+	}
+	return true
+}
+
+// releaseFilePath gives back the file path of a file that could not be saved, so that it does not count towards the
+// files of the crawl and links to it are not rewritten. Its directories stay taken, other files may be in them.
+func (c *httpCrawl) releaseFilePath(filePath string, crawlURLs ...*url.URL) {
+	delete(c.usedFilePaths, filePath)
+	for _, crawlURL := range crawlURLs {
+		delete(c.linkedFilePaths, crawlURL.String())
+	}
+}
+
+// keepCompletedFile keeps a file completed by an interrupted run instead of downloading it again. Pages are not kept,
+// they have to be fetched again for their links.
+func (c *httpCrawl) keepCompletedFile(ctx context.Context, crawlURL *url.URL, filePath string) (bool, error) {
+	fileName := path.Join(c.directory, filePath)
+	reader, err := c.downloader.fileClient.Read(ctx, fileName)
+	if err != nil {
+		return false, nil
+	}
+	defer reader.Close()
+
+	head := make([]byte, 512)
+	headByteCount, _ := io.ReadFull(reader, head)
+	if isHTMLContentType(http.DetectContentType(head[:headByteCount])) || !c.reserveFilePath(filePath) {
+		return false, nil
+	}
+	c.linkedFilePaths[crawlURL.String()] = filePath
+
+	size, err := c.downloader.fileClient.Size(ctx, fileName)
+	if err != nil {
+		return false, err
+	}
+
+	c.progress.Update(func(metadata *downloadTaskMetadata) {
+		metadata.Files = append(metadata.Files, filePath)
+		metadata.CompletedFiles = append(metadata.CompletedFiles, true)
+		metadata.CrawledURLs = append(metadata.CrawledURLs, crawlURL.String())
+		metadata.Size += size
+		metadata.DownloadedByteCount += size
+	})
+	return true, nil
+}
+
+// saveFile writes the file of a crawled URL, recording it in the progress before it is written and as completed after.
+// A file that fails to be written is dropped from the progress again.
+func (c *httpCrawl) saveFile(ctx context.Context, crawlURL *url.URL, filePath string, reader io.Reader) error {
+	index := 0
+	c.progress.Update(func(metadata *downloadTaskMetadata) {
+		index = len(metadata.Files)
+		metadata.Files = append(metadata.Files, filePath)
+		metadata.CompletedFiles = append(metadata.CompletedFiles, false)
+		metadata.CrawledURLs = append(metadata.CrawledURLs, crawlURL.String())
+	})
+
+	size, err := c.writeFile(ctx, filePath, reader)
+	if err != nil {
+		c.progress.Update(func(metadata *downloadTaskMetadata) {
+			metadata.Files = slices.Delete(metadata.Files, index, index+1)
+			metadata.CompletedFiles = slices.Delete(metadata.CompletedFiles, index, index+1)
+			metadata.CrawledURLs = slices.Delete(metadata.CrawledURLs, index, index+1)
+			metadata.DownloadedByteCount -= size
+		})
+		return err
+	}
+
+	c.progress.Update(func(metadata *downloadTaskMetadata) {
+		metadata.CompletedFiles[index] = true
+		metadata.Size += size
+	})
+	return nil
+}
+
+func (c *httpCrawl) writeFile(ctx context.Context, filePath string, reader io.Reader) (int64, error) {
+	writer, err := c.downloader.fileClient.Write(ctx, path.Join(c.directory, filePath))
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := io.Copy(downloadProgressWriter{writer: writer, progress: c.progress}, reader)
+	closeErr := writer.Close()
+	if closeErr != nil {
+		return size, closeErr
+	}
+	if err != nil {
+		// reading the response is what failed, the crawl goes on without the file
+		return size, crawlFetchError{err: err}
+	}
+	return size, nil
+}
+
+// rewriteLinks makes the links of the saved pages to other saved files relative links to those files, so that the
+// crawl can be browsed offline. Links to files that were not saved are made absolute, pointing to the web.
+func (c *httpCrawl) rewriteLinks(ctx context.Context) error {
+	for _, page := range c.pages {
+		fileName := path.Join(c.directory, page.filePath)
+		content, err := readFile(ctx, c.downloader.fileClient, fileName)
+		if err != nil {
+			return err
+		}
+
+		rewritten := rewriteHTMLLinks(page.url, content, func(link *url.URL) string {
+			filePath, ok := c.linkedFilePaths[link.String()]
+			if !ok {
+				return link.String()
+			}
+			return (&url.URL{Path: getRelativePath(path.Dir(page.filePath), filePath)}).String()
+		})
+
+		writer, err := c.downloader.fileClient.Write(ctx, fileName)
+		if err != nil {
+			return err
+		}
+		_, err = writer.Write(rewritten)
+		closeErr := writer.Close()
+		if err = errors.Join(err, closeErr); err != nil {
+			return err
+		}
+
+		sizeChange := int64(len(rewritten) - len(content))
+		c.progress.Update(func(metadata *downloadTaskMetadata) {
+			metadata.Size += sizeChange
+			metadata.DownloadedByteCount += sizeChange
+		})
+	}
+
+	c.logger.With(zap.Int("pageCount", len(c.pages))).Info("rewrote links of crawled pages")
+	return nil
+}
+
+// getRelativePath returns the path leading from a directory to a file, both relative to the same directory.
+func getRelativePath(fromDirectory string, toFilePath string) string {
+	fromElements := strings.Split(fromDirectory, "/")
+	toElements := strings.Split(toFilePath, "/")
+	commonCount := 0
+	for commonCount < len(fromElements) && commonCount < len(toElements)-1 && fromElements[commonCount] == toElements[commonCount] {
+		commonCount++
+	}
+
+	elements := make([]string, 0, len(fromElements)-commonCount+len(toElements)-commonCount)
+	for range fromElements[commonCount:] {
+		elements = append(elements, "..")
+	}
+	return strings.Join(append(elements, toElements[commonCount:]...), "/")
+}
+
+func readFile(ctx context.Context, fileClient file.Client, fileName string) ([]byte, error) {
+	reader, err := fileClient.Read(ctx, fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+func isHTMLContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "text/html" || mediaType == "application/xhtml+xml")
+}
+
+// htmlLinkAttributes are the attributes holding links, srcset holding several of them with their descriptors.
+var htmlLinkAttributes = []string{"href", "src", "srcset"}
+
+// resolveHTMLLink resolves a link of a page, dropping its fragment. Links that are not HTTP or FTP URLs are left out.
+func resolveHTMLLink(baseURL *url.URL, link string) (*url.URL, bool) {
+	link = strings.TrimSpace(link)
+	if link == "" || strings.HasPrefix(link, "#") {
+		return nil, false
+	}
+
+	resolvedURL, err := baseURL.Parse(link)
+	if err != nil || !slices.Contains([]string{"http", "https", "ftp"}, resolvedURL.Scheme) {
+		return nil, false
+	}
+	resolvedURL.Fragment = ""
+	resolvedURL.RawFragment = ""
+	return resolvedURL, true
+}
+
+// splitSrcset returns the URLs of the candidates of a srcset attribute.
+func splitSrcset(srcset string) []string {
+	links := make([]string, 0)
+	for _, candidate := range strings.Split(srcset, ",") {
+		fields := strings.Fields(candidate)
+		if len(fields) > 0 {
+			links = append(links, fields[0])
+		}
+	}
+	return links
+}
+
+// extractHTMLLinks returns the href, src and srcset links of a page, resolved against its base URL, without
+// duplicates.
+func extractHTMLLinks(pageURL *url.URL, page []byte) []*url.URL {
+	baseURL := pageURL
+	links := make([]*url.URL, 0)
+	seen := make(map[string]bool)
+	addLink := func(link string) {
+		resolvedURL, ok := resolveHTMLLink(baseURL, link)
+		if ok && !seen[resolvedURL.String()] {
+			seen[resolvedURL.String()] = true
+			links = append(links, resolvedURL)
+		}
+	}
+
+	tokenizer := html.NewTokenizer(bytes.NewReader(page))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			return links
+		}
+		if tokenType != html.StartTagToken && tokenType != html.SelfClosingTagToken {
+			continue
+		}
+
+		token := tokenizer.Token()
+		for _, attribute := range token.Attr {
+			switch {
+			case token.Data == "base" && attribute.Key == "href":
+				if resolvedURL, ok := resolveHTMLLink(pageURL, attribute.Val); ok {
+					baseURL = resolvedURL
+				}
+			case attribute.Key == "srcset":
+				for _, link := range splitSrcset(attribute.Val) {
+					addLink(link)
+				}
+			case slices.Contains(htmlLinkAttributes, attribute.Key):
+				addLink(attribute.Val)
+			}
+		}
+	}
+}
+
+// rewriteHTMLLinks replaces the HTTP and FTP links of a page with what rewrite returns for them, keeping their fragment. The
+// base element is dropped as rewritten links are relative to the page itself. Everything else is kept byte for byte.
+func rewriteHTMLLinks(pageURL *url.URL, page []byte, rewrite func(link *url.URL) string) []byte {
+	baseURL := pageURL
+	rewriteLink := func(link string) (string, bool) {
+		resolvedURL, ok := resolveHTMLLink(baseURL, link)
+		if !ok {
+			return "", false
+		}
+
+		rewrittenLink := rewrite(resolvedURL)
+		if _, fragment, found := strings.Cut(link, "#"); found {
+			rewrittenLink += "#" + fragment
+		}
+		return rewrittenLink, rewrittenLink != link
+	}
+
+	var output bytes.Buffer
+	tokenizer := html.NewTokenizer(bytes.NewReader(page))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			return output.Bytes()
+		}
+		// the raw bytes have to be copied before the token is read, reading it may change them
+		raw := append([]byte(nil), tokenizer.Raw()...)
+		if tokenType != html.StartTagToken && tokenType != html.SelfClosingTagToken {
+			output.Write(raw)
+			continue
+		}
+
+		token := tokenizer.Token()
+		if token.Data == "base" {
+			for _, attribute := range token.Attr {
+				if attribute.Key != "href" {
+					continue
+				}
+				if resolvedURL, ok := resolveHTMLLink(pageURL, attribute.Val); ok {
+					baseURL = resolvedURL
+				}
+			}
+			continue
+		}
+
+		rewritten := false
+		for i, attribute := range token.Attr {
+			switch {
+			case attribute.Key == "srcset":
+				candidates := strings.Split(attribute.Val, ",")
+				for j, candidate := range candidates {
+					fields := strings.Fields(candidate)
+					if len(fields) == 0 {
+						continue
+					}
+					if rewrittenLink, ok := rewriteLink(fields[0]); ok {
+						fields[0] = rewrittenLink
+						candidates[j] = strings.Join(fields, " ")
+						rewritten = true
+					}
+				}
+				token.Attr[i].Val = strings.Join(candidates, ", ")
+			case slices.Contains(htmlLinkAttributes, attribute.Key):
+				if rewrittenLink, ok := rewriteLink(attribute.Val); ok {
+					token.Attr[i].Val = rewrittenLink
+					rewritten = true
+				}
+			}
+		}
+
+		if rewritten {
+			output.WriteString(token.String())
+		} else {
+			output.Write(raw)
+		}
+	}
+}
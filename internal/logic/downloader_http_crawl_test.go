@@ -0,0 +1,186 @@
+package logic
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestGetCrawlFilePath(t *testing.T) {
+	testCases := []struct {
+		name         string
+		crawlURL     string
+		wantFilePath string
+		wantOK       bool
+	}{
+		{
+			name:         "host without path",
+			crawlURL:     "http://Example.com",
+			wantFilePath: "example.com/index.html",
+			wantOK:       true,
+		},
+		{
+			name:         "directory",
+			crawlURL:     "https://example.com/pub/",
+			wantFilePath: "example.com/pub/index.html",
+			wantOK:       true,
+		},
+		{
+			name:         "file",
+			crawlURL:     "https://example.com/pub/a.zip",
+			wantFilePath: "example.com/pub/a.zip",
+			wantOK:       true,
+		},
+		{
+			name:         "port is part of the host",
+			crawlURL:     "http://example.com:8080/a.txt",
+			wantFilePath: "example.com:8080/a.txt",
+			wantOK:       true,
+		},
+		{
+			name:         "query kept in the name without slashes",
+			crawlURL:     "http://example.com/list?page=2&dir=/pub",
+			wantFilePath: "example.com/list?page=2&dir=%2Fpub",
+			wantOK:       true,
+		},
+		{
+			name:         "query of a directory",
+			crawlURL:     "http://example.com/pub/?C=N;O=D",
+			wantFilePath: "example.com/pub/index.html?C=N;O=D",
+			wantOK:       true,
+		},
+		{
+			name:     "dot dot element",
+			crawlURL: "http://example.com/a/../../etc/passwd",
+			wantOK:   false,
+		},
+		{
+			name:     "empty element",
+			crawlURL: "http://example.com/a//b",
+			wantOK:   false,
+		},
+		{
+			name:     "backslash",
+			crawlURL: "http://example.com/a%5C..%5Cb",
+			wantOK:   false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			crawlURL, err := url.Parse(testCase.crawlURL)
+			if err != nil {
+				t.Fatalf("failed to parse URL: %v", err)
+			}
+
+			filePath, ok := getCrawlFilePath(crawlURL)
+			if ok != testCase.wantOK || ok && filePath != testCase.wantFilePath {
+				t.Errorf("getCrawlFilePath(%s) = %q, %v, want %q, %v", testCase.crawlURL, filePath, ok, testCase.wantFilePath, testCase.wantOK)
+			}
+		})
+	}
+}
+
+func TestGetRelativePath(t *testing.T) {
+	testCases := []struct {
+		fromDirectory string
+		toFilePath    string
+		want          string
+	}{
+		{fromDirectory: "h/pub", toFilePath: "h/pub/index.html", want: "index.html"},
+		{fromDirectory: "h/pub", toFilePath: "h/pub/files/a.zip", want: "files/a.zip"},
+		{fromDirectory: "h/pub/files", toFilePath: "h/pub/index.html", want: "../index.html"},
+		{fromDirectory: "h", toFilePath: "h/pub/index.html", want: "pub/index.html"},
+		{fromDirectory: "h/x/y", toFilePath: "g/z", want: "../../../g/z"},
+		// a file named like the directory is not inside it
+		{fromDirectory: "h/pub", toFilePath: "h/pub", want: "../pub"},
+	}
+
+	for _, testCase := range testCases {
+		got := getRelativePath(testCase.fromDirectory, testCase.toFilePath)
+		if got != testCase.want {
+			t.Errorf("getRelativePath(%q, %q) = %q, want %q", testCase.fromDirectory, testCase.toFilePath, got, testCase.want)
+		}
+	}
+}
+
+func TestRewriteHTMLLinks(t *testing.T) {
+	pageURL, err := url.Parse("http://h/pub/index.html")
+	if err != nil {
+		t.Fatalf("failed to parse URL: %v", err)
+	}
+
+	savedFiles := map[string]string{
+		"http://h/pub/files/a.zip":   "files/a.zip",
+		"http://h/pub/img.png":       "img.png",
+		"http://h/pub/img2.png":      "img2.png",
+		"http://h/other/x.bin":       "../other/x.bin",
+		"http://h/pub/page.html?p=2": "page.html%3Fp=2",
+	}
+	rewrite := func(link *url.URL) string {
+		if filePath, ok := savedFiles[link.String()]; ok {
+			return filePath
+		}
+		return link.String()
+	}
+
+	testCases := []struct {
+		name string
+		page string
+		want string
+	}{
+		{
+			name: "saved file made relative, fragment kept",
+			page: `<a href="files/a.zip#top">a</a>`,
+			want: `<a href="files/a.zip#top">a</a>`,
+		},
+		{
+			name: "absolute link to a saved file",
+			page: `<a href="http://h/pub/files/a.zip">a</a>`,
+			want: `<a href="files/a.zip">a</a>`,
+		},
+		{
+			name: "link to a file that was not saved made absolute",
+			page: `<a href="missing.txt">m</a>`,
+			want: `<a href="http://h/pub/missing.txt">m</a>`,
+		},
+		{
+			name: "unchanged tags kept byte for byte",
+			page: `<A  HREF='http://elsewhere/x' >x</A><a href="#local">l</a><a href="mailto:a@b">m</a>`,
+			want: `<A  HREF='http://elsewhere/x' >x</A><a href="#local">l</a><a href="mailto:a@b">m</a>`,
+		},
+		{
+			name: "srcset candidates keep their descriptors",
+			page: `<img src="img.png" srcset="img.png 1x, img2.png 2x">`,
+			want: `<img src="img.png" srcset="img.png 1x, img2.png 2x">`,
+		},
+		{
+			name: "srcset of absolute links",
+			page: `<img srcset="http://h/pub/img.png 1x,http://h/pub/img2.png 2x">`,
+			want: `<img srcset="img.png 1x, img2.png 2x">`,
+		},
+		{
+			name: "base dropped and links resolved against it",
+			page: `<head><base href="http://h/other/"></head><a href="x.bin">x</a>`,
+			want: `<head></head><a href="../other/x.bin">x</a>`,
+		},
+		{
+			name: "query links",
+			page: `<a href="page.html?p=2">2</a>`,
+			want: `<a href="page.html%3Fp=2">2</a>`,
+		},
+		{
+			name: "text, comments and scripts untouched",
+			page: "<!-- <a href=\"files/a.zip\"> --><script>if (a < b) { x = '<a href=\"y\">' }</script>text &amp; more",
+			want: "<!-- <a href=\"files/a.zip\"> --><script>if (a < b) { x = '<a href=\"y\">' }</script>text &amp; more",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := string(rewriteHTMLLinks(pageURL, []byte(testCase.page), rewrite))
+			if got != testCase.want {
+				t.Errorf("rewriteHTMLLinks() = %s, want %s", got, testCase.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
+	"go.uber.org/zap"
+)
+
+const (
+	// mirrorProbeByteCount is how much of the file is fetched from every mirror to measure how fast it is
+	mirrorProbeByteCount = 256 * 1024
+	mirrorProbeTimeout   = 10 * time.Second
+)
+
+var (
+	errNoMirrorLeft = errors.New("no mirror of the download is left")
+)
+
+// httpMirror is one of the URLs the file of a download task is served from.
+type httpMirror struct {
+	url      string
+	fileInfo httpFileInfo
+	// bytesPerSec is the speed measured by the probe, mirrors are used in proportion to it
+	bytesPerSec        float64
+	activeSegmentCount int
+	failed             bool
+}
+
+// getIfRangeValidator returns the value for the If-Range header. Weak ETags are not allowed there, so Last-Modified is
+// used for them instead. Mirrors generate their own validators, so each mirror is asked with its own.
+func (m *httpMirror) getIfRangeValidator() string {
+	if m.fileInfo.ETag != "" && !strings.HasPrefix(m.fileInfo.ETag, "W/") {
+		return m.fileInfo.ETag
+	}
+	return m.fileInfo.LastModified
+}
+
+// httpMirrorPool hands out the mirrors segments are downloaded from. Mirrors that fail are left out for the rest of
+// the download, the segments they were serving move on to the remaining ones.
+type httpMirrorPool struct {
+	lock    sync.Mutex
+	mirrors []*httpMirror
+}
+
+func newHTTPMirrorPool(mirrors []*httpMirror) *httpMirrorPool {
+	return &httpMirrorPool{
+		mirrors: mirrors,
+	}
+}
+
+// acquire returns the mirror the next segment should be downloaded from, which is the one whose active segments, counting
+// the next one, are the fewest relative to its speed. The fastest mirror gets the first segment and the most of them.
+func (p *httpMirrorPool) acquire() (*httpMirror, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var nextMirror *httpMirror
+	for _, mirror := range p.mirrors {
+		if mirror.failed {
+			continue
+		}
+		if nextMirror == nil || float64(mirror.activeSegmentCount+1)/mirror.bytesPerSec <
+			float64(nextMirror.activeSegmentCount+1)/nextMirror.bytesPerSec {
+			nextMirror = mirror
+		}
+	}
+	if nextMirror == nil {
+		return nil, errNoMirrorLeft
+	}
+
+	nextMirror.activeSegmentCount++
+	return nextMirror, nil
+}
+
+// release gives back a mirror a segment was downloaded from. When the segment failed because of the mirror, the mirror
+// is left out and true is returned if another mirror is left to continue the segment from.
+func (p *httpMirrorPool) release(mirror *httpMirror, mirrorFailed bool) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	mirror.activeSegmentCount--
+	if !mirrorFailed {
+		return false
+	}
+
+	otherMirrorLeft := false
+	for _, otherMirror := range p.mirrors {
+		if otherMirror != mirror && !otherMirror.failed {
+			otherMirrorLeft = true
+		}
+	}
+	if otherMirrorLeft {
+		mirror.failed = true
+	}
+	return otherMirrorLeft
+}
+
+// isMirrorError tells whether a segment failed because of the mirror it was downloaded from, as opposed to failing
+// locally, in which case no other mirror would do better.
+func isMirrorError(err error) bool {
+	errorClass, _ := classifyDownloadError(err)
+	return errorClass != go_load.DownloadErrorClass_OtherError && errorClass != go_load.DownloadErrorClass_IntegrityError
+}
+
+// probeHTTPMirrors gets the file info of every URL at once. When there is more than one, a piece of the file is
+// fetched from each of them to measure their speed, and those that fail are dropped. The mirrors come back fastest
+// first, or in the order of downloadURLs when there was nothing to measure.
+func (d httpDownloader) probeHTTPMirrors(ctx context.Context, downloadURLs []string, logger *zap.Logger) []*httpMirror {
+	mirrors := make([]*httpMirror, len(downloadURLs))
+	var waitGroup sync.WaitGroup
+	for i, downloadURL := range downloadURLs {
+		waitGroup.Add(1)
+		go func(i int, downloadURL string) {
+			defer waitGroup.Done()
+			mirror, err := d.probeHTTPMirror(ctx, downloadURL, len(downloadURLs) > 1)
+			if err != nil {
+				logger.With(zap.Error(err), zap.String("mirrorURL", downloadURL)).Warn("failed to probe mirror")
+				return
+			}
+			mirrors[i] = mirror
+		}(i, downloadURL)
+	}
+	waitGroup.Wait()
+
+	mirrors = slices.DeleteFunc(mirrors, func(mirror *httpMirror) bool {
+		return mirror == nil
+	})
+	slices.SortStableFunc(mirrors, func(a, b *httpMirror) int {
+		switch {
+		case a.bytesPerSec > b.bytesPerSec:
+			return -1
+		case a.bytesPerSec < b.bytesPerSec:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return mirrors
+}
+
+func (d httpDownloader) probeHTTPMirror(ctx context.Context, downloadURL string, measureSpeed bool) (*httpMirror, error) {
+	probeCtx, cancel := context.WithTimeout(ctx, mirrorProbeTimeout)
+	defer cancel()
+
+	fileInfo, err := d.getHTTPFileInfo(probeCtx, downloadURL)
+	if err != nil {
+		return nil, err
+	}
+
+	mirror := &httpMirror{
+		url:         downloadURL,
+		fileInfo:    fileInfo,
+		bytesPerSec: 1,
+	}
+	if !measureSpeed || !fileInfo.AcceptRanges || fileInfo.Size <= 0 {
+		return mirror, nil
+	}
+
+	request, err := http.NewRequestWithContext(probeCtx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	probeByteCount := min(fileInfo.Size, mirrorProbeByteCount)
+	request.Header.Set("Range", fmt.Sprintf("bytes=0-%d", probeByteCount-1))
+
+	startTime := time.Now()
+	response, err := d.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusPartialContent {
+		return nil, newHTTPStatusError(response.StatusCode, "unexpected response status for probe: %s", response.Status)
+	}
+
+	readByteCount, err := io.Copy(io.Discard, io.LimitReader(response.Body, probeByteCount))
+	if err != nil {
+		return nil, err
+	}
+
+	mirror.bytesPerSec = float64(readByteCount) / max(time.Since(startTime).Seconds(), 1e-6)
+	return mirror, nil
+}
@@ -0,0 +1,188 @@
+package logic
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// crawlUserAgent is sent with every request of a crawl, it is also the name rules of robots.txt are matched with
+	crawlUserAgent = "GoLoad"
+	// robotsTxtMaxSize is how much of a robots.txt is read, the rest is ignored as crawlers are allowed to
+	robotsTxtMaxSize = 512 * 1024
+)
+
+// robotsRule allows or disallows the paths matching its pattern, which may contain * wildcards and end with $.
+type robotsRule struct {
+	pattern    string
+	expression *regexp.Regexp
+	allow      bool
+}
+
+func newRobotsRule(pattern string, allow bool) robotsRule {
+	anchored := strings.HasSuffix(pattern, "$")
+	parts := strings.Split(strings.TrimSuffix(pattern, "$"), "*")
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+
+	expression := "^" + strings.Join(parts, ".*")
+	if anchored {
+		expression += "$"
+	}
+	return robotsRule{
+		pattern:    pattern,
+		expression: regexp.MustCompile(expression),
+		allow:      allow,
+	}
+}
+
+// robotsRules are the rules of robots.txt that apply to the crawl on one host.
+type robotsRules struct {
+	rules []robotsRule
+	// disallowAll is set when robots.txt could not be fetched because of a server error
+	disallowAll bool
+}
+
+// isAllowed reports whether the path, with its query, may be crawled. The longest matching rule wins, allowing when an
+// allow and a disallow rule are as long.
+func (r robotsRules) isAllowed(requestPath string) bool {
+	if r.disallowAll {
+		return false
+	}
+
+	allowed := true
+	matchedLength := -1
+	for _, rule := range r.rules {
+		if !rule.expression.MatchString(requestPath) {
+			continue
+		}
+
+		if len(rule.pattern) > matchedLength || len(rule.pattern) == matchedLength && rule.allow {
+			allowed = rule.allow
+			matchedLength = len(rule.pattern)
+		}
+	}
+	return allowed
+}
+
+// parseRobotsTxt returns the rules of the group for userAgent, or of the * group when no group names it. Consecutive
+// user-agent lines share the group that follows them.
+func parseRobotsTxt(reader io.Reader, userAgent string) robotsRules {
+	userAgent = strings.ToLower(userAgent)
+
+	var (
+		specificRules, wildcardRules     []robotsRule
+		specificFound                    bool
+		inSpecificGroup, inWildcardGroup bool
+		previousLineWasUserAgent         bool
+	)
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if !previousLineWasUserAgent {
+				inSpecificGroup, inWildcardGroup = false, false
+			}
+			previousLineWasUserAgent = true
+
+			name := strings.ToLower(value)
+			if name == "*" {
+				inWildcardGroup = true
+			} else if name != "" && strings.Contains(userAgent, name) {
+				inSpecificGroup = true
+				specificFound = true
+			}
+			continue
+		case "allow", "disallow":
+			// an empty disallow allows everything, which is the default anyway
+			if value == "" {
+				break
+			}
+
+			rule := newRobotsRule(value, key == "allow")
+			if inSpecificGroup {
+				specificRules = append(specificRules, rule)
+			}
+			if inWildcardGroup {
+				wildcardRules = append(wildcardRules, rule)
+			}
+		}
+		previousLineWasUserAgent = false
+	}
+
+	if specificFound {
+		return robotsRules{rules: specificRules}
+	}
+	return robotsRules{rules: wildcardRules}
+}
+
+// robotsCache fetches robots.txt once for every host a crawl visits.
+type robotsCache struct {
+	httpClient *http.Client
+	rules      map[string]robotsRules
+	logger     *zap.Logger
+}
+
+func newRobotsCache(httpClient *http.Client, logger *zap.Logger) *robotsCache {
+	return &robotsCache{
+		httpClient: httpClient,
+		rules:      make(map[string]robotsRules),
+		logger:     logger,
+	}
+}
+
+func (c *robotsCache) isAllowed(ctx context.Context, crawlURL *url.URL) bool {
+	origin := crawlURL.Scheme + "://" + crawlURL.Host
+	rules, ok := c.rules[origin]
+	if !ok {
+		rules = c.fetchRobotsRules(ctx, origin)
+		c.rules[origin] = rules
+	}
+
+	return rules.isAllowed(crawlURL.EscapedPath() + strings.TrimSuffix("?"+crawlURL.RawQuery, "?"))
+}
+
+// fetchRobotsRules fetches the robots.txt of an origin. A missing robots.txt allows everything, a server error or an
+// origin that cannot be reached disallows everything, as robots.txt may exist but could not be read.
+func (c *robotsCache) fetchRobotsRules(ctx context.Context, origin string) robotsRules {
+	logger := c.logger.With(zap.String("origin", origin))
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+"/robots.txt", nil)
+	if err != nil {
+		return robotsRules{disallowAll: true}
+	}
+	request.Header.Set("User-Agent", crawlUserAgent)
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		logger.With(zap.Error(err)).Warn("failed to fetch robots.txt, not crawling origin")
+		return robotsRules{disallowAll: true}
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode >= http.StatusInternalServerError:
+		logger.With(zap.Int("statusCode", response.StatusCode)).Warn("failed to fetch robots.txt, not crawling origin")
+		return robotsRules{disallowAll: true}
+	case response.StatusCode != http.StatusOK:
+		return robotsRules{}
+	}
+
+	return parseRobotsTxt(io.LimitReader(response.Body, robotsTxtMaxSize), crawlUserAgent)
+}
@@ -0,0 +1,500 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"go.uber.org/zap"
+)
+
+func TestSplitIntoSegments(t *testing.T) {
+	testCases := []struct {
+		name           string
+		size           int64
+		segmentCount   int
+		minSegmentSize int64
+		want           []downloadSegment
+	}{
+		{
+			name:           "even split",
+			size:           100,
+			segmentCount:   4,
+			minSegmentSize: 10,
+			want:           []downloadSegment{{Start: 0, End: 24}, {Start: 25, End: 49}, {Start: 50, End: 74}, {Start: 75, End: 99}},
+		},
+		{
+			name:           "last segment takes the remainder",
+			size:           10,
+			segmentCount:   3,
+			minSegmentSize: 1,
+			want:           []downloadSegment{{Start: 0, End: 2}, {Start: 3, End: 5}, {Start: 6, End: 9}},
+		},
+		{
+			name:           "segment count reduced to keep the minimum segment size",
+			size:           100,
+			segmentCount:   8,
+			minSegmentSize: 30,
+			want:           []downloadSegment{{Start: 0, End: 32}, {Start: 33, End: 65}, {Start: 66, End: 99}},
+		},
+		{
+			name:           "file smaller than the minimum segment size",
+			size:           5,
+			segmentCount:   4,
+			minSegmentSize: 10,
+			want:           []downloadSegment{{Start: 0, End: 4}},
+		},
+		{
+			name:           "no segment count",
+			size:           5,
+			segmentCount:   0,
+			minSegmentSize: 1,
+			want:           []downloadSegment{{Start: 0, End: 4}},
+		},
+		{
+			name:           "more segments than bytes",
+			size:           3,
+			segmentCount:   8,
+			minSegmentSize: 0,
+			want:           []downloadSegment{{Start: 0, End: 0}, {Start: 1, End: 1}, {Start: 2, End: 2}},
+		},
+		{
+			name:           "empty file",
+			size:           0,
+			segmentCount:   4,
+			minSegmentSize: 10,
+			want:           nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := splitIntoSegments(testCase.size, testCase.segmentCount, testCase.minSegmentSize)
+			if !slices.Equal(got, testCase.want) {
+				t.Errorf(
+					"splitIntoSegments(%d, %d, %d) = %v, want %v",
+					testCase.size, testCase.segmentCount, testCase.minSegmentSize, got, testCase.want,
+				)
+			}
+		})
+	}
+}
+
+// testHTTPRequest is what testHTTPServer recorded of a request.
+type testHTTPRequest struct {
+	method      string
+	rangeHeader string
+	ifRange     string
+}
+
+// testHTTPServer serves a single file. With acceptRanges it answers ranges and If-Range like any file server does,
+// without it the whole file is sent to every request and Accept-Ranges is not announced.
+type testHTTPServer struct {
+	server *httptest.Server
+
+	mutex        sync.Mutex
+	content      []byte
+	etag         string
+	lastModified time.Time
+	acceptRanges bool
+	// replacementContent, when set, replaces the served file as soon as a range of it is requested
+	replacementContent []byte
+	replacementETag    string
+	requests           []testHTTPRequest
+}
+
+func newTestHTTPServer(t *testing.T, content []byte, etag string, acceptRanges bool) *testHTTPServer {
+	t.Helper()
+
+	server := &testHTTPServer{
+		content:      content,
+		etag:         etag,
+		lastModified: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		acceptRanges: acceptRanges,
+	}
+	server.server = httptest.NewServer(http.HandlerFunc(server.serveHTTP))
+	t.Cleanup(server.server.Close)
+	return server
+}
+
+func (s *testHTTPServer) getRequests(method string) []testHTTPRequest {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var requests []testHTTPRequest
+	for _, request := range s.requests {
+		if request.method == method {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
+
+func (s *testHTTPServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	s.requests = append(s.requests, testHTTPRequest{
+		method:      r.Method,
+		rangeHeader: r.Header.Get("Range"),
+		ifRange:     r.Header.Get("If-Range"),
+	})
+	if s.replacementContent != nil && r.Header.Get("Range") != "" {
+		s.content, s.etag = s.replacementContent, s.replacementETag
+		s.replacementContent = nil
+	}
+	content, etag, lastModified, acceptRanges := s.content, s.etag, s.lastModified, s.acceptRanges
+	s.mutex.Unlock()
+
+	if !acceptRanges {
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		if r.Method != http.MethodHead {
+			w.Write(content)
+		}
+		return
+	}
+
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	http.ServeContent(w, r, "", lastModified, bytes.NewReader(content))
+}
+
+func newTestHTTPDownloader(t *testing.T) (HTTPDownloader, string) {
+	t.Helper()
+
+	directory := t.TempDir()
+	logger := zap.NewNop()
+	fileClient, err := file.NewLocalClient(configs.DownloadConfig{DownloadDirectory: directory}, logger)
+	if err != nil {
+		t.Fatalf("failed to create file client: %v", err)
+	}
+	return NewHTTPDownloader(fileClient, logger), directory
+}
+
+func checkTestDownloadedFile(t *testing.T, filePath string, want []byte) {
+	t.Helper()
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("failed to read downloaded file: %v", err)
+	}
+	if !bytes.Equal(content, want) {
+		t.Fatalf("downloaded file differs from the served file")
+	}
+}
+
+func TestHTTPDownloaderDownloadSegments(t *testing.T) {
+	payload := newTestPayload(1000)
+	server := newTestHTTPServer(t, payload, `"v1"`, true)
+	downloader, directory := newTestHTTPDownloader(t)
+
+	progress := newDownloadProgress(downloadTaskMetadata{FileName: "file.bin"})
+	params := DownloadParams{URL: server.server.URL + "/file.bin", SegmentCount: 4, MinSegmentSize: 100}
+	err := downloader.Download(context.Background(), params, progress)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	checkTestDownloadedFile(t, filepath.Join(directory, "file.bin"), payload)
+
+	var rangeHeaders []string
+	for _, request := range server.getRequests(http.MethodGet) {
+		rangeHeaders = append(rangeHeaders, request.rangeHeader)
+	}
+	slices.Sort(rangeHeaders)
+	wantRangeHeaders := []string{"bytes=0-249", "bytes=250-499", "bytes=500-749", "bytes=750-999"}
+	if !slices.Equal(rangeHeaders, wantRangeHeaders) {
+		t.Errorf("requested ranges %v, want %v", rangeHeaders, wantRangeHeaders)
+	}
+
+	metadata := progress.Snapshot()
+	if len(metadata.Segments) != 4 || metadata.DownloadedByteCount != int64(len(payload)) {
+		t.Errorf("Segments = %v, DownloadedByteCount = %d, want 4 segments and %d", metadata.Segments, metadata.DownloadedByteCount, len(payload))
+	}
+	for _, segment := range metadata.Segments {
+		if !segment.IsCompleted() {
+			t.Errorf("segment %v is not completed", segment)
+		}
+	}
+	if metadata.ETag != `"v1"` || metadata.ValidatedURL != params.URL {
+		t.Errorf("ETag = %s, ValidatedURL = %s, want %s, %s", metadata.ETag, metadata.ValidatedURL, `"v1"`, params.URL)
+	}
+}
+
+func TestHTTPDownloaderDownloadWithoutRanges(t *testing.T) {
+	payload := newTestPayload(1000)
+	server := newTestHTTPServer(t, payload, "", false)
+	downloader, directory := newTestHTTPDownloader(t)
+
+	progress := newDownloadProgress(downloadTaskMetadata{FileName: "file.bin"})
+	params := DownloadParams{URL: server.server.URL + "/file.bin", SegmentCount: 4, MinSegmentSize: 100}
+	err := downloader.Download(context.Background(), params, progress)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	checkTestDownloadedFile(t, filepath.Join(directory, "file.bin"), payload)
+
+	getRequests := server.getRequests(http.MethodGet)
+	if len(getRequests) != 1 || getRequests[0].rangeHeader != "" {
+		t.Errorf("GET requests = %v, want a single one without a range", getRequests)
+	}
+
+	metadata := progress.Snapshot()
+	if len(metadata.Segments) != 0 || metadata.DownloadedByteCount != int64(len(payload)) {
+		t.Errorf("Segments = %v, DownloadedByteCount = %d, want none and %d", metadata.Segments, metadata.DownloadedByteCount, len(payload))
+	}
+}
+
+func TestIsSameRemoteFile(t *testing.T) {
+	metadata := downloadTaskMetadata{
+		ValidatedURL: "http://origin.example/file.bin",
+		Size:         100,
+		ETag:         `"v1"`,
+		LastModified: "Mon, 01 Jan 2024 12:00:00 GMT",
+		Segments:     []downloadSegment{{Start: 0, End: 99, DownloadedByteCount: 50}},
+	}
+	metadataWithoutETag := metadata
+	metadataWithoutETag.ETag = ""
+	metadataWithoutSegments := metadata
+	metadataWithoutSegments.Segments = nil
+
+	testCases := []struct {
+		name        string
+		metadata    downloadTaskMetadata
+		downloadURL string
+		fileInfo    httpFileInfo
+		want        bool
+	}{
+		{
+			name:        "same ETag",
+			metadata:    metadata,
+			downloadURL: metadata.ValidatedURL,
+			fileInfo:    httpFileInfo{Size: 100, ETag: `"v1"`, LastModified: "Tue, 02 Jan 2024 12:00:00 GMT"},
+			want:        true,
+		},
+		{
+			name:        "changed ETag",
+			metadata:    metadata,
+			downloadURL: metadata.ValidatedURL,
+			fileInfo:    httpFileInfo{Size: 100, ETag: `"v2"`, LastModified: metadata.LastModified},
+			want:        false,
+		},
+		{
+			name:        "ETag no longer sent",
+			metadata:    metadata,
+			downloadURL: metadata.ValidatedURL,
+			fileInfo:    httpFileInfo{Size: 100, LastModified: metadata.LastModified},
+			want:        false,
+		},
+		{
+			name:        "same Last-Modified without ETags",
+			metadata:    metadataWithoutETag,
+			downloadURL: metadata.ValidatedURL,
+			fileInfo:    httpFileInfo{Size: 100, LastModified: metadata.LastModified},
+			want:        true,
+		},
+		{
+			name:        "changed Last-Modified without ETags",
+			metadata:    metadataWithoutETag,
+			downloadURL: metadata.ValidatedURL,
+			fileInfo:    httpFileInfo{Size: 100, LastModified: "Tue, 02 Jan 2024 12:00:00 GMT"},
+			want:        false,
+		},
+		{
+			name:        "changed size",
+			metadata:    metadata,
+			downloadURL: metadata.ValidatedURL,
+			fileInfo:    httpFileInfo{Size: 101, ETag: `"v1"`, LastModified: metadata.LastModified},
+			want:        false,
+		},
+		{
+			name:        "mirror compared by size only",
+			metadata:    metadata,
+			downloadURL: "http://mirror.example/file.bin",
+			fileInfo:    httpFileInfo{Size: 100, ETag: `"mirror"`, LastModified: "Tue, 02 Jan 2024 12:00:00 GMT"},
+			want:        true,
+		},
+		{
+			name:        "mirror of another size",
+			metadata:    metadata,
+			downloadURL: "http://mirror.example/file.bin",
+			fileInfo:    httpFileInfo{Size: 101, ETag: `"v1"`, LastModified: metadata.LastModified},
+			want:        false,
+		},
+		{
+			name:        "nothing saved",
+			metadata:    metadataWithoutSegments,
+			downloadURL: metadata.ValidatedURL,
+			fileInfo:    httpFileInfo{Size: 100, ETag: `"v1"`, LastModified: metadata.LastModified},
+			want:        false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := isSameRemoteFile(testCase.metadata, testCase.downloadURL, testCase.fileInfo)
+			if got != testCase.want {
+				t.Errorf("isSameRemoteFile() = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+// newTestInterruptedHTTPDownload writes the file of a download of payload in two segments that was interrupted
+// after 200 bytes of the first segment and all of the second one, and returns its saved progress.
+func newTestInterruptedHTTPDownload(t *testing.T, directory string, payload []byte, metadata downloadTaskMetadata) *DownloadProgress {
+	t.Helper()
+
+	partialContent := make([]byte, len(payload))
+	copy(partialContent[:200], payload)
+	copy(partialContent[500:], payload[500:])
+	err := os.WriteFile(filepath.Join(directory, "file.bin"), partialContent, 0o644)
+	if err != nil {
+		t.Fatalf("failed to write partial file: %v", err)
+	}
+
+	metadata.FileName = "file.bin"
+	metadata.Size = int64(len(payload))
+	metadata.Segments = []downloadSegment{{Start: 0, End: 499, DownloadedByteCount: 200}, {Start: 500, End: 999, DownloadedByteCount: 500}}
+	metadata.DownloadedByteCount = 700
+	return newDownloadProgress(metadata)
+}
+
+func TestHTTPDownloaderDownloadResumes(t *testing.T) {
+	const lastModified = "Mon, 01 Jan 2024 12:00:00 GMT"
+
+	testCases := []struct {
+		name string
+		etag string
+		// savedURL and savedETag are what the interrupted download validated, an empty savedURL is the server's URL
+		savedURL         string
+		savedETag        string
+		wantRangeHeaders []string
+		wantIfRange      string
+	}{
+		{
+			name:             "strong ETag",
+			etag:             `"v1"`,
+			savedETag:        `"v1"`,
+			wantRangeHeaders: []string{"bytes=200-499"},
+			wantIfRange:      `"v1"`,
+		},
+		{
+			name:             "weak ETag falls back to Last-Modified",
+			etag:             `W/"v1"`,
+			savedETag:        `W/"v1"`,
+			wantRangeHeaders: []string{"bytes=200-499"},
+			wantIfRange:      lastModified,
+		},
+		{
+			name:             "mirror with its own validators",
+			etag:             `"v1"`,
+			savedURL:         "http://origin.invalid/file.bin",
+			savedETag:        `"origin"`,
+			wantRangeHeaders: []string{"bytes=200-499"},
+			wantIfRange:      `"v1"`,
+		},
+		{
+			name:             "changed file restarts",
+			etag:             `"v1"`,
+			savedETag:        `"v0"`,
+			wantRangeHeaders: []string{"bytes=0-499", "bytes=500-999"},
+			wantIfRange:      `"v1"`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			payload := newTestPayload(1000)
+			server := newTestHTTPServer(t, payload, testCase.etag, true)
+			downloader, directory := newTestHTTPDownloader(t)
+			downloadURL := server.server.URL + "/file.bin"
+
+			savedURL := testCase.savedURL
+			if savedURL == "" {
+				savedURL = downloadURL
+			}
+			progress := newTestInterruptedHTTPDownload(t, directory, payload, downloadTaskMetadata{
+				ValidatedURL: savedURL,
+				ETag:         testCase.savedETag,
+				LastModified: lastModified,
+			})
+			params := DownloadParams{URL: downloadURL, SegmentCount: 2, MinSegmentSize: 100}
+			err := downloader.Download(context.Background(), params, progress)
+			if err != nil {
+				t.Fatalf("Download() error = %v", err)
+			}
+			checkTestDownloadedFile(t, filepath.Join(directory, "file.bin"), payload)
+
+			var rangeHeaders []string
+			for _, request := range server.getRequests(http.MethodGet) {
+				rangeHeaders = append(rangeHeaders, request.rangeHeader)
+				if request.ifRange != testCase.wantIfRange {
+					t.Errorf("If-Range = %s, want %s", request.ifRange, testCase.wantIfRange)
+				}
+			}
+			slices.Sort(rangeHeaders)
+			if !slices.Equal(rangeHeaders, testCase.wantRangeHeaders) {
+				t.Errorf("requested ranges %v, want %v", rangeHeaders, testCase.wantRangeHeaders)
+			}
+
+			metadata := progress.Snapshot()
+			if metadata.DownloadedByteCount != int64(len(payload)) || metadata.ValidatedURL != downloadURL || metadata.ETag != testCase.etag {
+				t.Errorf(
+					"DownloadedByteCount = %d, ValidatedURL = %s, ETag = %s, want %d, %s, %s",
+					metadata.DownloadedByteCount, metadata.ValidatedURL, metadata.ETag, len(payload), downloadURL, testCase.etag,
+				)
+			}
+		})
+	}
+}
+
+func TestHTTPDownloaderDownloadRestartsWhenIfRangeFails(t *testing.T) {
+	payload := newTestPayload(1000)
+	server := newTestHTTPServer(t, payload, `"v1"`, true)
+	downloader, directory := newTestHTTPDownloader(t)
+	downloadURL := server.server.URL + "/file.bin"
+
+	// the file changes after it was checked for the resume, the origin answers the If-Range request with all of the
+	// new file
+	replacement := bytes.Repeat([]byte("x"), len(payload))
+	server.replacementContent, server.replacementETag = replacement, `"v2"`
+
+	progress := newTestInterruptedHTTPDownload(t, directory, payload, downloadTaskMetadata{ValidatedURL: downloadURL, ETag: `"v1"`})
+	params := DownloadParams{URL: downloadURL, SegmentCount: 2, MinSegmentSize: 100}
+	err := downloader.Download(context.Background(), params, progress)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	checkTestDownloadedFile(t, filepath.Join(directory, "file.bin"), replacement)
+
+	getRequests := server.getRequests(http.MethodGet)
+	wantGETRequests := []testHTTPRequest{
+		{method: http.MethodGet, rangeHeader: "bytes=200-499", ifRange: `"v1"`},
+		{method: http.MethodGet, rangeHeader: "bytes=0-499", ifRange: `"v2"`},
+		{method: http.MethodGet, rangeHeader: "bytes=500-999", ifRange: `"v2"`},
+	}
+	if len(getRequests) == len(wantGETRequests) {
+		// the segments of the restart are requested in parallel
+		slices.SortFunc(getRequests[1:], func(a, b testHTTPRequest) int {
+			return strings.Compare(a.rangeHeader, b.rangeHeader)
+		})
+	}
+	if !slices.Equal(getRequests, wantGETRequests) {
+		t.Errorf("GET requests = %v, want %v", getRequests, wantGETRequests)
+	}
+
+	metadata := progress.Snapshot()
+	if metadata.ETag != `"v2"` || metadata.DownloadedByteCount != int64(len(replacement)) {
+		t.Errorf("ETag = %s, DownloadedByteCount = %d, want %s, %d", metadata.ETag, metadata.DownloadedByteCount, `"v2"`, len(replacement))
+	}
+}
@@ -0,0 +1,96 @@
+package logic
+
+import "sync"
+
+// DownloadProgress holds the metadata of a running download. Downloaders report written bytes to it while the
+// executor periodically takes snapshots to persist them. Reporting bytes blocks while the download is over its speed
+// limit, which is how every downloader gets throttled.
+type DownloadProgress struct {
+	lock     sync.Mutex
+	metadata downloadTaskMetadata
+	throttle *DownloadThrottle
+}
+
+func newDownloadProgress(metadata downloadTaskMetadata) *DownloadProgress {
+	return &DownloadProgress{metadata: metadata}
+}
+
+// SetThrottle makes reported bytes wait for the throttle, a nil throttle turns throttling off.
+func (p *DownloadProgress) SetThrottle(throttle *DownloadThrottle) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.throttle = throttle
+}
+
+func (p *DownloadProgress) Snapshot() downloadTaskMetadata {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	metadata := p.metadata
+	metadata.Segments = append([]downloadSegment(nil), p.metadata.Segments...)
+	metadata.StreamSegments = append([]bool(nil), p.metadata.StreamSegments...)
+	metadata.TorrentPieces = append([]byte(nil), p.metadata.TorrentPieces...)
+	metadata.Files = append([]string(nil), p.metadata.Files...)
+	metadata.CompletedFiles = append([]bool(nil), p.metadata.CompletedFiles...)
+	metadata.CrawledURLs = append([]string(nil), p.metadata.CrawledURLs...)
+	return metadata
+}
+
+func (p *DownloadProgress) Update(updateFunc func(metadata *downloadTaskMetadata)) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	updateFunc(&p.metadata)
+}
+
+func (p *DownloadProgress) FileName() string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.metadata.FileName
+}
+
+// SetSize records the total size of the file, 0 means the size is not known up front.
+func (p *DownloadProgress) SetSize(size int64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.metadata.Size = size
+}
+
+// ResetDownloadedByteCount is called when a download starts over from the beginning.
+func (p *DownloadProgress) ResetDownloadedByteCount() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.metadata.DownloadedByteCount = 0
+}
+
+func (p *DownloadProgress) AddDownloadedByteCount(byteCount int64) {
+	p.lock.Lock()
+	p.metadata.DownloadedByteCount += byteCount
+	throttle := p.throttle
+	p.lock.Unlock()
+
+	p.wait(throttle, byteCount)
+}
+
+func (p *DownloadProgress) AddSegmentDownloadedByteCount(segmentIndex int, byteCount int64) {
+	p.lock.Lock()
+	p.metadata.Segments[segmentIndex].DownloadedByteCount += byteCount
+	p.metadata.DownloadedByteCount += byteCount
+	throttle := p.throttle
+	p.lock.Unlock()
+
+	p.wait(throttle, byteCount)
+}
+
+// wait holds back the downloader reporting the bytes, outside of the lock so that snapshots are not held back too.
+func (p *DownloadProgress) wait(throttle *DownloadThrottle, byteCount int64) {
+	if throttle == nil {
+		return
+	}
+	// the only error is the download being stopped, which the downloader notices on its next read
+	_ = throttle.Wait(byteCount)
+}
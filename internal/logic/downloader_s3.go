@@ -0,0 +1,351 @@
+package logic
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"go.uber.org/zap"
+)
+
+const (
+	s3URLScheme = "s3"
+	// s3MaxPrefixObjectCount keeps the file list of a prefix download small enough for the metadata of its task
+	s3MaxPrefixObjectCount = 500
+)
+
+var (
+	errInvalidS3URL     = errors.New("S3 URL must look like s3://bucket/key, or s3://bucket/prefix/ for a whole prefix")
+	errUnknownS3Profile = errors.New("unknown S3 profile")
+)
+
+type S3Downloader Downloader
+
+// s3Downloader downloads objects from S3-compatible object stores. A URL ending with a slash downloads every object
+// under that prefix into a directory. Objects are downloaded with parallel ranged GETs, the same way HTTP files are.
+type s3Downloader struct {
+	httpClient *http.Client
+	profiles   map[string]configs.S3ProfileConfig
+	fileClient file.Client
+	logger     *zap.Logger
+}
+
+func NewS3Downloader(configs configs.DownloadConfig, fileClient file.Client, logger *zap.Logger) S3Downloader {
+	return &s3Downloader{
+		// objects stored with a Content-Encoding have to be saved as they are, not decompressed on the way
+		httpClient: &http.Client{Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, DisableCompression: true}},
+		profiles:   configs.S3Config.Profiles,
+		fileClient: fileClient,
+		logger:     logger,
+	}
+}
+
+// CanResume implements Downloader.
+func (d s3Downloader) CanResume() bool {
+	return true
+}
+
+// parseS3URL returns the bucket and the object key, or the prefix when the key is empty or ends with a slash.
+func parseS3URL(rawURL string) (string, string, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	if parsedURL.Scheme != s3URLScheme || parsedURL.Host == "" {
+		return "", "", errInvalidS3URL
+	}
+	return parsedURL.Host, strings.TrimPrefix(parsedURL.Path, "/"), nil
+}
+
+// getS3Client combines the profile the task refers to with the endpoint and credentials given to the task itself,
+// the latter taking precedence.
+func (d s3Downloader) getS3Client(options *s3Options) (s3Client, error) {
+	if options == nil {
+		options = &s3Options{}
+	}
+
+	profile := configs.S3ProfileConfig{}
+	if options.Profile != "" {
+		var ok bool
+		profile, ok = d.profiles[options.Profile]
+		if !ok {
+			return s3Client{}, fmt.Errorf("%w: %s", errUnknownS3Profile, options.Profile)
+		}
+	}
+
+	override := func(value *string, overrideValue string) {
+		if overrideValue != "" {
+			*value = overrideValue
+		}
+	}
+	override(&profile.Endpoint, options.Endpoint)
+	override(&profile.Region, options.Region)
+	override(&profile.AccessKeyID, options.AccessKeyID)
+	override(&profile.SecretAccessKey, options.SecretAccessKey)
+	override(&profile.SessionToken, options.SessionToken)
+
+	client := s3Client{
+		httpClient:      d.httpClient,
+		region:          profile.Region,
+		accessKeyID:     profile.AccessKeyID,
+		secretAccessKey: profile.SecretAccessKey,
+		sessionToken:    profile.SessionToken,
+		now:             time.Now,
+	}
+	if client.region == "" {
+		client.region = defaultS3Region
+	}
+
+	if profile.Endpoint != "" {
+		endpoint := profile.Endpoint
+		if !strings.Contains(endpoint, "://") {
+			endpoint = "https://" + endpoint
+		}
+
+		var err error
+		client.endpoint, err = url.Parse(endpoint)
+		if err != nil {
+			return s3Client{}, err
+		}
+	}
+
+	return client, nil
+}
+
+// Download implements Downloader.
+func (d s3Downloader) Download(ctx context.Context, params DownloadParams, progress *DownloadProgress) error {
+	bucket, key, err := parseS3URL(params.URL)
+	if err != nil {
+		return err
+	}
+
+	client, err := d.getS3Client(progress.Snapshot().S3Options)
+	if err != nil {
+		return err
+	}
+
+	if key == "" || strings.HasSuffix(key, "/") {
+		return d.downloadS3Prefix(ctx, client, bucket, key, params, progress)
+	}
+	return d.downloadS3Object(ctx, client, bucket, key, params, progress)
+}
+
+func (d s3Downloader) downloadS3Object(
+	ctx context.Context,
+	client s3Client,
+	bucket string,
+	key string,
+	params DownloadParams,
+	progress *DownloadProgress,
+) error {
+	metadata := progress.Snapshot()
+	logger := d.logger.With(zap.String("url", params.URL), zap.String("fileName", metadata.FileName))
+
+	object, err := client.HeadObject(ctx, bucket, key)
+	if err != nil {
+		return err
+	}
+
+	fileInfo := httpFileInfo{
+		Size:         object.Size,
+		AcceptRanges: true,
+		ETag:         object.ETag,
+		LastModified: object.LastModified,
+	}
+	if object.Size == 0 {
+		setRemoteFileInfo(progress, params.URL, fileInfo, []downloadSegment{})
+		return d.createEmptyFile(ctx, metadata.FileName)
+	}
+
+	if isSameRemoteFile(metadata, params.URL, fileInfo) && isPartialFileIntact(ctx, d.fileClient, metadata) {
+		logger.Info("resuming download from saved progress")
+		setRemoteFileInfo(progress, params.URL, fileInfo, nil)
+		err = d.downloadS3Segments(ctx, client, bucket, object, metadata.FileName, progress.Snapshot().Segments, progress.AddSegmentDownloadedByteCount)
+		if !errors.Is(err, errRemoteFileChanged) {
+			return err
+		}
+		logger.Warn("remote object changed, restarting download from the beginning")
+	}
+
+	segments := splitIntoSegments(object.Size, params.SegmentCount, params.MinSegmentSize)
+	setRemoteFileInfo(progress, params.URL, fileInfo, segments)
+	err = d.fileClient.Delete(ctx, metadata.FileName)
+	if err != nil {
+		return err
+	}
+
+	logger.With(zap.Int64("size", object.Size), zap.Int("segmentCount", len(segments))).Info("downloading object in segments")
+	return d.downloadS3Segments(ctx, client, bucket, object, metadata.FileName, segments, progress.AddSegmentDownloadedByteCount)
+}
+
+// downloadS3Prefix downloads every object under the prefix into the directory named by the file name of the task,
+// keeping the part of their keys after the prefix as their path. Objects completed by an interrupted run are kept as
+// long as the listing of the prefix did not change.
+func (d s3Downloader) downloadS3Prefix(
+	ctx context.Context,
+	client s3Client,
+	bucket string,
+	prefix string,
+	params DownloadParams,
+	progress *DownloadProgress,
+) error {
+	metadata := progress.Snapshot()
+	logger := d.logger.With(zap.String("url", params.URL), zap.String("fileName", metadata.FileName))
+
+	listedObjects, err := client.ListObjects(ctx, bucket, prefix, s3MaxPrefixObjectCount)
+	if err != nil {
+		return err
+	}
+
+	objects := make([]s3Object, 0, len(listedObjects))
+	filePaths := make([]string, 0, len(listedObjects))
+	listingHash := sha256.New()
+	size := int64(0)
+	for _, object := range listedObjects {
+		filePath := strings.TrimPrefix(object.Key, prefix)
+		if strings.HasSuffix(filePath, "/") {
+			// zero-byte objects ending with a slash are folder markers created by consoles
+			continue
+		}
+		if !isSafeRelativePath(filePath) {
+			logger.With(zap.String("key", object.Key)).Warn("skipping object whose key cannot be used as a file path")
+			continue
+		}
+
+		objects = append(objects, object)
+		filePaths = append(filePaths, filePath)
+		size += object.Size
+		fmt.Fprintf(listingHash, "%s\n%s\n%d\n", object.Key, object.ETag, object.Size)
+	}
+	listingETag := hex.EncodeToString(listingHash.Sum(nil))
+
+	completedFiles := make([]bool, len(objects))
+	if metadata.ValidatedURL == params.URL && metadata.ETag == listingETag && len(metadata.CompletedFiles) == len(objects) {
+		logger.Info("resuming prefix download from saved progress")
+		for i, completed := range metadata.CompletedFiles {
+			fileSize, err := d.fileClient.Size(ctx, path.Join(metadata.FileName, filePaths[i]))
+			completedFiles[i] = completed && err == nil && fileSize == objects[i].Size
+		}
+	} else {
+		err = d.fileClient.Delete(ctx, metadata.FileName)
+		if err != nil {
+			return err
+		}
+	}
+
+	downloadedByteCount := int64(0)
+	for i, completed := range completedFiles {
+		if completed {
+			downloadedByteCount += objects[i].Size
+		}
+	}
+	progress.Update(func(metadata *downloadTaskMetadata) {
+		metadata.ValidatedURL = params.URL
+		metadata.Size = size
+		metadata.ETag = listingETag
+		metadata.LastModified = ""
+		metadata.Segments = nil
+		metadata.Files = filePaths
+		metadata.CompletedFiles = completedFiles
+		metadata.DownloadedByteCount = downloadedByteCount
+	})
+
+	logger.With(zap.Int("objectCount", len(objects)), zap.Int64("size", size)).Info("downloading objects under prefix")
+	for i, object := range objects {
+		if completedFiles[i] {
+			continue
+		}
+
+		fileName := path.Join(metadata.FileName, filePaths[i])
+		if object.Size == 0 {
+			err = d.createEmptyFile(ctx, fileName)
+		} else {
+			segments := splitIntoSegments(object.Size, params.SegmentCount, params.MinSegmentSize)
+			err = d.downloadS3Segments(ctx, client, bucket, object, fileName, segments, func(_ int, byteCount int64) {
+				progress.AddDownloadedByteCount(byteCount)
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		progress.Update(func(metadata *downloadTaskMetadata) {
+			metadata.CompletedFiles[i] = true
+		})
+	}
+
+	return nil
+}
+
+func (d s3Downloader) createEmptyFile(ctx context.Context, fileName string) error {
+	writer, err := d.fileClient.Write(ctx, fileName)
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+func (d s3Downloader) downloadS3Segments(
+	ctx context.Context,
+	client s3Client,
+	bucket string,
+	object s3Object,
+	fileName string,
+	segments []downloadSegment,
+	onWrite func(segmentIndex int, byteCount int64),
+) error {
+	writer, err := d.fileClient.WriteAt(ctx, fileName, object.Size)
+	if err != nil {
+		return err
+	}
+
+	segmentErr := downloadSegmentsInParallel(ctx, segments, func(ctx context.Context, segmentIndex int, segment downloadSegment) error {
+		return d.downloadS3Segment(ctx, client, bucket, object, writer, segment, func(byteCount int64) {
+			onWrite(segmentIndex, byteCount)
+		})
+	})
+
+	closeErr := writer.Close()
+	return errors.Join(segmentErr, closeErr)
+}
+
+func (d s3Downloader) downloadS3Segment(
+	ctx context.Context,
+	client s3Client,
+	bucket string,
+	object s3Object,
+	writer io.WriterAt,
+	segment downloadSegment,
+	onWrite func(byteCount int64),
+) error {
+	start := segment.Start + segment.DownloadedByteCount
+	response, err := client.GetObject(ctx, bucket, object.Key, object.ETag, start, segment.End)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	segmentWriter := &downloadSegmentWriter{writer: writer, offset: start, onWrite: onWrite}
+	remainingByteCount := segment.End - start + 1
+	writtenByteCount, err := io.Copy(segmentWriter, io.LimitReader(response.Body, remainingByteCount))
+	if err != nil {
+		return err
+	}
+
+	if writtenByteCount != remainingByteCount {
+		return fmt.Errorf("range %d-%d of %s ended early after %d bytes: %w", start, segment.End, object.Key, writtenByteCount, io.ErrUnexpectedEOF)
+	}
+
+	return nil
+}
@@ -0,0 +1,348 @@
+package logic
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultS3Region         = "us-east-1"
+	s3SigningAlgorithm      = "AWS4-HMAC-SHA256"
+	s3SigningService        = "s3"
+	s3SigningTerminator     = "aws4_request"
+	s3DateTimeFormat        = "20060102T150405Z"
+	s3DateFormat            = "20060102"
+	s3ErrorResponseMaxSize  = 64 * 1024
+	s3ListResponseMaxSize   = 16 * 1024 * 1024
+	s3ListObjectsMaxKeys    = 1000
+	s3EmptyPayloadSHA256Hex = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+var (
+	errS3ObjectNotFound = errors.New("S3 object not found")
+)
+
+// s3Client sends requests to an S3-compatible object store, signed with AWS Signature Version 4. Requests are sent
+// unsigned when no access key is given, which works for public buckets.
+type s3Client struct {
+	httpClient *http.Client
+	// endpoint is nil for AWS S3, where buckets are addressed as virtual hosts
+	endpoint        *url.URL
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	now             func() time.Time
+}
+
+// s3Object describes an object of a bucket, ETag keeps the quotes it is sent with.
+type s3Object struct {
+	Key          string
+	Size         int64
+	ETag         string
+	LastModified string
+}
+
+type s3ErrorResponse struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+type s3ListObjectsResponse struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key          string `xml:"Key"`
+		Size         int64  `xml:"Size"`
+		ETag         string `xml:"ETag"`
+		LastModified string `xml:"LastModified"`
+	} `xml:"Contents"`
+}
+
+// getObjectURL returns the URL of an object, or of the bucket when key is empty.
+func (c s3Client) getObjectURL(bucket, key string) *url.URL {
+	objectURL := &url.URL{Scheme: "https", Host: fmt.Sprintf("%s.s3.%s.amazonaws.com", bucket, c.region), Path: "/" + key}
+	// dots in the bucket name would break TLS certificate validation of the virtual host
+	if c.endpoint != nil || strings.Contains(bucket, ".") {
+		objectURL = &url.URL{Scheme: "https", Host: fmt.Sprintf("s3.%s.amazonaws.com", c.region), Path: "/" + bucket + "/" + key}
+		if c.endpoint != nil {
+			objectURL.Scheme, objectURL.Host = c.endpoint.Scheme, c.endpoint.Host
+			objectURL.Path = strings.TrimSuffix(c.endpoint.Path, "/") + objectURL.Path
+		}
+	}
+	objectURL.RawPath = encodeS3URIPath(objectURL.Path)
+	return objectURL
+}
+
+// newRequest returns a signed request without a body. Every header set by configureRequest is signed as well.
+func (c s3Client) newRequest(
+	ctx context.Context,
+	method string,
+	bucket string,
+	key string,
+	query url.Values,
+	configureRequest func(request *http.Request),
+) (*http.Request, error) {
+	requestURL := c.getObjectURL(bucket, key)
+	requestURL.RawQuery = encodeS3Query(query)
+
+	request, err := http.NewRequestWithContext(ctx, method, requestURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if configureRequest != nil {
+		configureRequest(request)
+	}
+
+	if c.accessKeyID != "" {
+		c.sign(request, c.now().UTC())
+	}
+	return request, nil
+}
+
+// sign adds the Authorization header of Signature Version 4 to the request, as described in
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html.
+func (c s3Client) sign(request *http.Request, now time.Time) {
+	request.Header.Set("X-Amz-Date", now.Format(s3DateTimeFormat))
+	request.Header.Set("X-Amz-Content-Sha256", s3EmptyPayloadSHA256Hex)
+	if c.sessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", c.sessionToken)
+	}
+
+	headerValues := map[string]string{"host": request.URL.Host}
+	for name, values := range request.Header {
+		headerValues[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+	headerNames := make([]string, 0, len(headerValues))
+	for name := range headerValues {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+
+	canonicalHeaders := strings.Builder{}
+	for _, name := range headerNames {
+		canonicalHeaders.WriteString(name + ":" + strings.Join(strings.Fields(headerValues[name]), " ") + "\n")
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		request.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		s3EmptyPayloadSHA256Hex,
+	}, "\n")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := strings.Join([]string{now.Format(s3DateFormat), c.region, s3SigningService, s3SigningTerminator}, "/")
+	stringToSign := strings.Join([]string{
+		s3SigningAlgorithm,
+		now.Format(s3DateTimeFormat),
+		scope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	signingKey := []byte("AWS4" + c.secretAccessKey)
+	for _, scopeElement := range strings.Split(scope, "/") {
+		signingKey = hmacSHA256(signingKey, scopeElement)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3SigningAlgorithm, c.accessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// encodeS3URIPath escapes every byte of the path except unreserved characters and slashes, which is the canonical
+// form Signature Version 4 expects.
+func encodeS3URIPath(uriPath string) string {
+	return strings.ReplaceAll(encodeS3URIComponent(uriPath), "%2F", "/")
+}
+
+func encodeS3URIComponent(value string) string {
+	encoded := strings.Builder{}
+	for i := 0; i < len(value); i++ {
+		character := value[i]
+		if 'A' <= character && character <= 'Z' || 'a' <= character && character <= 'z' || '0' <= character && character <= '9' ||
+			character == '-' || character == '.' || character == '_' || character == '~' {
+			encoded.WriteByte(character)
+		} else {
+			fmt.Fprintf(&encoded, "%%%02X", character)
+		}
+	}
+	return encoded.String()
+}
+
+// encodeS3Query returns the canonical query string, sorted by name and escaped like path elements.
+func encodeS3Query(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parameters := make([]string, 0, len(query))
+	for _, name := range names {
+		values := append([]string(nil), query[name]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parameters = append(parameters, encodeS3URIComponent(name)+"="+encodeS3URIComponent(value))
+		}
+	}
+	return strings.Join(parameters, "&")
+}
+
+// getS3ResponseError turns an S3 error response into an error, including the error code S3 sends in the body.
+func getS3ResponseError(response *http.Response) error {
+	if response.StatusCode == http.StatusNotFound {
+		return errS3ObjectNotFound
+	}
+
+	errorResponse := s3ErrorResponse{}
+	body, err := io.ReadAll(io.LimitReader(response.Body, s3ErrorResponseMaxSize))
+	if err == nil && xml.Unmarshal(body, &errorResponse) == nil && errorResponse.Code != "" {
+		return newHTTPStatusError(response.StatusCode, "S3 request failed with %s: %s: %s", response.Status, errorResponse.Code, errorResponse.Message)
+	}
+	return newHTTPStatusError(response.StatusCode, "unexpected S3 response status: %s", response.Status)
+}
+
+func (c s3Client) HeadObject(ctx context.Context, bucket, key string) (s3Object, error) {
+	request, err := c.newRequest(ctx, http.MethodHead, bucket, key, nil, nil)
+	if err != nil {
+		return s3Object{}, err
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return s3Object{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return s3Object{}, getS3ResponseError(response)
+	}
+
+	return s3Object{
+		Key:          key,
+		Size:         response.ContentLength,
+		ETag:         response.Header.Get("ETag"),
+		LastModified: response.Header.Get("Last-Modified"),
+	}, nil
+}
+
+// GetObject returns the response for the whole object, or for the inclusive byte range from start to end when end is
+// not negative. The request fails with errRemoteFileChanged when the object no longer has the given ETag.
+func (c s3Client) GetObject(ctx context.Context, bucket, key, eTag string, start, end int64) (*http.Response, error) {
+	request, err := c.newRequest(ctx, http.MethodGet, bucket, key, nil, func(request *http.Request) {
+		if end >= 0 {
+			request.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+		}
+		if eTag != "" {
+			request.Header.Set("If-Match", eTag)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedStatusCode := http.StatusOK
+	if end >= 0 {
+		expectedStatusCode = http.StatusPartialContent
+	}
+	if response.StatusCode != expectedStatusCode {
+		defer response.Body.Close()
+		if response.StatusCode == http.StatusPreconditionFailed {
+			return nil, errRemoteFileChanged
+		}
+		return nil, getS3ResponseError(response)
+	}
+	return response, nil
+}
+
+// ListObjects returns every object whose key starts with prefix, following the continuation tokens of ListObjectsV2.
+func (c s3Client) ListObjects(ctx context.Context, bucket, prefix string, maxObjectCount int) ([]s3Object, error) {
+	objects := make([]s3Object, 0)
+	continuationToken := ""
+	for {
+		query := url.Values{
+			"list-type": {"2"},
+			"max-keys":  {strconv.Itoa(s3ListObjectsMaxKeys)},
+			"prefix":    {prefix},
+		}
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+
+		listResponse, err := c.listObjectsPage(ctx, bucket, query)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, content := range listResponse.Contents {
+			objects = append(objects, s3Object{
+				Key:          content.Key,
+				Size:         content.Size,
+				ETag:         content.ETag,
+				LastModified: content.LastModified,
+			})
+		}
+		if len(objects) > maxObjectCount {
+			return nil, fmt.Errorf("prefix has more than %d objects", maxObjectCount)
+		}
+
+		if !listResponse.IsTruncated || listResponse.NextContinuationToken == "" {
+			return objects, nil
+		}
+		continuationToken = listResponse.NextContinuationToken
+	}
+}
+
+func (c s3Client) listObjectsPage(ctx context.Context, bucket string, query url.Values) (s3ListObjectsResponse, error) {
+	request, err := c.newRequest(ctx, http.MethodGet, bucket, "", query, nil)
+	if err != nil {
+		return s3ListObjectsResponse{}, err
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return s3ListObjectsResponse{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return s3ListObjectsResponse{}, getS3ResponseError(response)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, s3ListResponseMaxSize))
+	if err != nil {
+		return s3ListObjectsResponse{}, err
+	}
+
+	listResponse := s3ListObjectsResponse{}
+	err = xml.Unmarshal(body, &listResponse)
+	return listResponse, err
+}
@@ -0,0 +1,360 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"go.uber.org/zap"
+)
+
+const (
+	testS3Bucket          = "private-bucket"
+	testS3Key             = "data/object.bin"
+	testS3Region          = "eu-west-1"
+	testS3AccessKeyID     = "AKIDEXAMPLE"
+	testS3SecretAccessKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	testS3ETag            = `"0123456789abcdef"`
+	testS3Profile         = "private"
+)
+
+// testS3Server is an S3 stand-in serving a single object of a private bucket. It checks the Signature Version 4 of
+// every request on its own, rejecting unsigned requests and requests signed with another secret.
+type testS3Server struct {
+	server *httptest.Server
+	object []byte
+
+	mutex sync.Mutex
+	// ranges are the Range headers of the GET requests that were let through
+	ranges           []string
+	servedByteCount  int
+	deniedErrorCodes []string
+}
+
+func newTestS3Server(t *testing.T, object []byte) *testS3Server {
+	t.Helper()
+
+	server := &testS3Server{object: object}
+	server.server = httptest.NewServer(http.HandlerFunc(server.serveHTTP))
+	t.Cleanup(server.server.Close)
+	return server
+}
+
+func (s *testS3Server) getRanges() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return slices.Clone(s.ranges)
+}
+
+func (s *testS3Server) getServedByteCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.servedByteCount
+}
+
+func (s *testS3Server) getDeniedErrorCodes() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return slices.Clone(s.deniedErrorCodes)
+}
+
+func (s *testS3Server) deny(w http.ResponseWriter, code string) {
+	s.mutex.Lock()
+	s.deniedErrorCodes = append(s.deniedErrorCodes, code)
+	s.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusForbidden)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>request denied</Message></Error>", code)
+}
+
+func (s *testS3Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if code := s.authenticate(r); code != "" {
+		s.deny(w, code)
+		return
+	}
+
+	if r.URL.Path != "/"+testS3Bucket+"/"+testS3Key {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("ETag", testS3ETag)
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && ifMatch != testS3ETag {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+
+	content := s.object
+	statusCode := http.StatusOK
+	if rangeHeader := r.Header.Get("Range"); rangeHeader != "" {
+		var start, end int
+		_, err := fmt.Sscanf(rangeHeader, "bytes=%d-%d", &start, &end)
+		if err != nil || start > end || end >= len(s.object) {
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		content = s.object[start : end+1]
+		statusCode = http.StatusPartialContent
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(s.object)))
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.WriteHeader(statusCode)
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	writtenByteCount, _ := w.Write(content)
+	s.mutex.Lock()
+	s.ranges = append(s.ranges, r.Header.Get("Range"))
+	s.servedByteCount += writtenByteCount
+	s.mutex.Unlock()
+}
+
+// authenticate returns the S3 error code a request is denied with, or an empty string when its signature is valid.
+func (s *testS3Server) authenticate(r *http.Request) string {
+	authorization := r.Header.Get("Authorization")
+	if authorization == "" {
+		return "AccessDenied"
+	}
+
+	algorithm, fields, _ := strings.Cut(authorization, " ")
+	values := map[string]string{}
+	for _, field := range strings.Split(fields, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+		values[name] = value
+	}
+	accessKeyID, scope, _ := strings.Cut(values["Credential"], "/")
+	if algorithm != "AWS4-HMAC-SHA256" || accessKeyID != testS3AccessKeyID {
+		return "InvalidAccessKeyId"
+	}
+
+	requestTime, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+	if err != nil || time.Since(requestTime).Abs() > 15*time.Minute {
+		return "RequestTimeTooSkewed"
+	}
+	wantScope := requestTime.Format("20060102") + "/" + testS3Region + "/s3/aws4_request"
+	if scope != wantScope {
+		return "AuthorizationHeaderMalformed"
+	}
+
+	// headers that are required to be signed, the others are only signed when the client chose to
+	signedHeaders := strings.Split(values["SignedHeaders"], ";")
+	for _, name := range []string{"host", "x-amz-date", "x-amz-content-sha256", "range", "if-match"} {
+		if (name == "host" || r.Header.Get(name) != "") && !slices.Contains(signedHeaders, name) {
+			return "AccessDenied"
+		}
+	}
+
+	rawPath, rawQuery, _ := strings.Cut(r.RequestURI, "?")
+	var canonicalHeaders strings.Builder
+	for _, name := range signedHeaders {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		rawPath,
+		rawQuery,
+		canonicalHeaders.String(),
+		values["SignedHeaders"],
+		r.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		algorithm,
+		r.Header.Get("X-Amz-Date"),
+		scope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	signingKey := []byte("AWS4" + testS3SecretAccessKey)
+	for _, scopeElement := range strings.Split(scope, "/") {
+		mac := hmac.New(sha256.New, signingKey)
+		mac.Write([]byte(scopeElement))
+		signingKey = mac.Sum(nil)
+	}
+	mac := hmac.New(sha256.New, signingKey)
+	mac.Write([]byte(stringToSign))
+	if !hmac.Equal([]byte(hex.EncodeToString(mac.Sum(nil))), []byte(values["Signature"])) {
+		return "SignatureDoesNotMatch"
+	}
+	return ""
+}
+
+func newTestS3Downloader(t *testing.T, endpoint, secretAccessKey string) (S3Downloader, string) {
+	t.Helper()
+
+	directory := t.TempDir()
+	logger := zap.NewNop()
+	downloadConfig := configs.DownloadConfig{
+		DownloadDirectory: directory,
+		S3Config: configs.S3Config{Profiles: map[string]configs.S3ProfileConfig{
+			testS3Profile: {
+				Endpoint:        endpoint,
+				Region:          testS3Region,
+				AccessKeyID:     testS3AccessKeyID,
+				SecretAccessKey: secretAccessKey,
+			},
+		}},
+	}
+	fileClient, err := file.NewLocalClient(downloadConfig, logger)
+	if err != nil {
+		t.Fatalf("failed to create file client: %v", err)
+	}
+	return NewS3Downloader(downloadConfig, fileClient, logger), directory
+}
+
+func getTestS3URL() string {
+	return "s3://" + testS3Bucket + "/" + testS3Key
+}
+
+func TestS3DownloaderDownloadPrivateObject(t *testing.T) {
+	object := newTestPayload(3*1024*1024 + 5)
+	server := newTestS3Server(t, object)
+	downloader, directory := newTestS3Downloader(t, server.server.URL, testS3SecretAccessKey)
+
+	progress := newDownloadProgress(downloadTaskMetadata{FileName: "object.bin", S3Options: &s3Options{Profile: testS3Profile}})
+	params := DownloadParams{URL: getTestS3URL(), SegmentCount: 4, MinSegmentSize: 512 * 1024}
+	err := downloader.Download(context.Background(), params, progress)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(directory, "object.bin"))
+	if err != nil {
+		t.Fatalf("failed to read downloaded file: %v", err)
+	}
+	if !bytes.Equal(content, object) {
+		t.Fatalf("downloaded file differs from the object")
+	}
+
+	if deniedErrorCodes := server.getDeniedErrorCodes(); len(deniedErrorCodes) > 0 {
+		t.Errorf("server denied requests with %v, want every request signed correctly", deniedErrorCodes)
+	}
+	if ranges := server.getRanges(); len(ranges) != 4 {
+		t.Errorf("ranges = %v, want one ranged GET per segment", ranges)
+	}
+	metadata := progress.Snapshot()
+	if metadata.ETag != testS3ETag || metadata.DownloadedByteCount != int64(len(object)) {
+		t.Errorf("ETag = %s, DownloadedByteCount = %d, want %s and %d", metadata.ETag, metadata.DownloadedByteCount, testS3ETag, len(object))
+	}
+}
+
+func TestS3DownloaderDownloadResumes(t *testing.T) {
+	object := newTestPayload(2*1024*1024 + 3)
+	server := newTestS3Server(t, object)
+	downloader, directory := newTestS3Downloader(t, server.server.URL, testS3SecretAccessKey)
+
+	// an interrupted download leaves the beginning of every segment written into a file of the full size
+	segments := splitIntoSegments(int64(len(object)), 2, 512*1024)
+	partialContent := make([]byte, len(object))
+	for i := range segments {
+		segments[i].DownloadedByteCount = 1000 * int64(i+1)
+		copy(partialContent[segments[i].Start:], object[segments[i].Start:segments[i].Start+segments[i].DownloadedByteCount])
+	}
+	err := os.WriteFile(filepath.Join(directory, "object.bin"), partialContent, 0o644)
+	if err != nil {
+		t.Fatalf("failed to write partial file: %v", err)
+	}
+
+	progress := newDownloadProgress(downloadTaskMetadata{
+		FileName:     "object.bin",
+		S3Options:    &s3Options{Profile: testS3Profile},
+		ValidatedURL: getTestS3URL(),
+		Size:         int64(len(object)),
+		ETag:         testS3ETag,
+		Segments:     slices.Clone(segments),
+	})
+	err = downloader.Download(context.Background(), DownloadParams{URL: getTestS3URL(), SegmentCount: 2, MinSegmentSize: 512 * 1024}, progress)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(directory, "object.bin"))
+	if err != nil {
+		t.Fatalf("failed to read downloaded file: %v", err)
+	}
+	if !bytes.Equal(content, object) {
+		t.Fatalf("resumed file differs from the object")
+	}
+
+	wantRanges := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		wantRanges = append(wantRanges, fmt.Sprintf("bytes=%d-%d", segment.Start+segment.DownloadedByteCount, segment.End))
+	}
+	ranges := server.getRanges()
+	slices.Sort(ranges)
+	slices.Sort(wantRanges)
+	if !slices.Equal(ranges, wantRanges) {
+		t.Errorf("ranges = %v, want the rest of every segment %v", ranges, wantRanges)
+	}
+	if servedByteCount := server.getServedByteCount(); servedByteCount != len(object)-1000-2000 {
+		t.Errorf("server sent %d bytes, want only the remaining %d", servedByteCount, len(object)-1000-2000)
+	}
+}
+
+func TestS3DownloaderDownloadDenied(t *testing.T) {
+	object := newTestPayload(1024)
+	server := newTestS3Server(t, object)
+
+	testCases := []struct {
+		name            string
+		options         *s3Options
+		secretAccessKey string
+		wantErrorCode   string
+	}{
+		{
+			name:            "wrong secret",
+			options:         &s3Options{Profile: testS3Profile},
+			secretAccessKey: "not-the-secret",
+			wantErrorCode:   "SignatureDoesNotMatch",
+		},
+		{
+			name:          "unsigned request to a private bucket",
+			options:       &s3Options{Endpoint: server.server.URL, Region: testS3Region},
+			wantErrorCode: "AccessDenied",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			downloader, directory := newTestS3Downloader(t, server.server.URL, testCase.secretAccessKey)
+
+			progress := newDownloadProgress(downloadTaskMetadata{FileName: "object.bin", S3Options: testCase.options})
+			err := downloader.Download(context.Background(), DownloadParams{URL: getTestS3URL(), SegmentCount: 1}, progress)
+
+			var statusError httpStatusError
+			if !errors.As(err, &statusError) || statusError.statusCode != http.StatusForbidden {
+				t.Fatalf("Download() error = %v, want a 403", err)
+			}
+			// the response to the HEAD request the download starts with has no body to carry the error code
+			if deniedErrorCodes := server.getDeniedErrorCodes(); !slices.Contains(deniedErrorCodes, testCase.wantErrorCode) {
+				t.Errorf("server denied requests with %v, want %s", deniedErrorCodes, testCase.wantErrorCode)
+			}
+			if _, err := os.Stat(filepath.Join(directory, "object.bin")); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("a denied download created the file, stat error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,742 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	defaultSFTPDialTimeout   = 30 * time.Second
+	defaultSFTPPort          = "22"
+	sftpProtocolVersion      = 3
+	sftpReadChunkSize        = 32 * 1024
+	sftpMaxInflightReadCount = 16
+	// sftpMaxPacketLength bounds the memory a misbehaving server can make us allocate for a single packet
+	sftpMaxPacketLength = 256 * 1024
+)
+
+// SFTP packet types and constants from draft-ietf-secsh-filexfer-02, which is the version 3 of the protocol that
+// OpenSSH implements.
+const (
+	sftpPacketInit    = 1
+	sftpPacketVersion = 2
+	sftpPacketOpen    = 3
+	sftpPacketClose   = 4
+	sftpPacketRead    = 5
+	sftpPacketFstat   = 8
+	sftpPacketStatus  = 101
+	sftpPacketHandle  = 102
+	sftpPacketData    = 103
+	sftpPacketAttrs   = 105
+
+	sftpOpenFlagRead = 0x00000001
+
+	sftpAttrFlagSize        = 0x00000001
+	sftpAttrFlagUIDGID      = 0x00000002
+	sftpAttrFlagPermissions = 0x00000004
+	sftpAttrFlagACModTime   = 0x00000008
+	sftpAttrFlagExtended    = 0x80000000
+
+	sftpStatusOK  = 0
+	sftpStatusEOF = 1
+)
+
+var (
+	errSFTPNoCredentials   = errors.New("SFTP URL has no credentials, a password or a private key is required")
+	errSFTPNoUserName      = errors.New("SFTP URL has no user name")
+	errSFTPHostKeyMismatch = errors.New("SFTP server host key does not match the pinned host key")
+	errSFTPInvalidPacket   = errors.New("invalid SFTP packet")
+)
+
+type SFTPDownloader Downloader
+
+// sftpDownloader downloads sftp:// URLs over SSH. The user name comes from the URL, the password from the URL or the
+// task SFTP options, which may also carry a private key. The server host key is checked against the pinned host key
+// of the task when one is given, otherwise the key seen on the first connection to a host is trusted and pinned.
+type sftpDownloader struct {
+	dialer      *net.Dialer
+	dialTimeout time.Duration
+	fileClient  file.Client
+	logger      *zap.Logger
+}
+
+func NewSFTPDownloader(configs configs.DownloadConfig, fileClient file.Client, logger *zap.Logger) (SFTPDownloader, error) {
+	dialTimeout := defaultSFTPDialTimeout
+	if configs.SFTPConfig.DialTimeout != "" {
+		var err error
+		dialTimeout, err = configs.SFTPConfig.GetDialTimeoutDuration()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &sftpDownloader{
+		dialer:      &net.Dialer{Timeout: dialTimeout},
+		dialTimeout: dialTimeout,
+		fileClient:  fileClient,
+		logger:      logger,
+	}, nil
+}
+
+// CanResume implements Downloader.
+func (d sftpDownloader) CanResume() bool {
+	return true
+}
+
+// Download implements Downloader.
+func (d sftpDownloader) Download(ctx context.Context, params DownloadParams, progress *DownloadProgress) error {
+	parsedURL, err := url.Parse(params.URL)
+	if err != nil {
+		return err
+	}
+
+	if parsedURL.Scheme != "sftp" {
+		return fmt.Errorf("unsupported SFTP URL scheme %q", parsedURL.Scheme)
+	}
+
+	sshClient, err := d.connect(ctx, parsedURL, progress)
+	if err != nil {
+		return errors.Join(err, ctx.Err())
+	}
+	defer sshClient.Close()
+
+	// Close is also what interrupts blocked reads when the download is cancelled
+	stopCloseOnCancel := context.AfterFunc(ctx, func() { sshClient.Close() })
+	defer stopCloseOnCancel()
+
+	client, err := newSFTPClient(sshClient)
+	if err != nil {
+		return errors.Join(err, ctx.Err())
+	}
+
+	handle, err := client.Open(getSFTPFilePath(parsedURL))
+	if err != nil {
+		return errors.Join(err, ctx.Err())
+	}
+	defer client.Close(handle)
+
+	size, lastModified, err := client.Fstat(handle)
+	if err != nil {
+		return errors.Join(err, ctx.Err())
+	}
+
+	metadata := progress.Snapshot()
+	offset := int64(0)
+	if size > 0 && canResumeSequentialDownload(metadata, params.URL, size, lastModified) && isPartialFileIntact(ctx, d.fileClient, metadata) {
+		offset = metadata.DownloadedByteCount
+		d.logger.With(zap.String("url", params.URL), zap.Int64("offset", offset)).Info("resuming SFTP download")
+	}
+
+	progress.Update(func(metadata *downloadTaskMetadata) {
+		metadata.ValidatedURL = params.URL
+		metadata.Size = size
+		metadata.LastModified = lastModified
+		metadata.ETag = ""
+		metadata.Segments = nil
+		metadata.DownloadedByteCount = offset
+	})
+
+	if size <= 0 {
+		// without a size the reads cannot be pipelined, the file is read front to back until the server reports EOF
+		writeCloser, err := d.fileClient.Write(ctx, metadata.FileName)
+		if err != nil {
+			return err
+		}
+		defer writeCloser.Close()
+
+		_, err = io.Copy(
+			downloadProgressWriter{writer: writeCloser, progress: progress},
+			&sftpFileReader{client: client, handle: handle},
+		)
+		return errors.Join(err, ctx.Err())
+	}
+
+	writerAt, err := d.fileClient.WriteAt(ctx, metadata.FileName, size)
+	if err != nil {
+		return err
+	}
+	defer writerAt.Close()
+
+	err = client.ReadFile(handle, offset, size, writerAt, func(readByteCount int) {
+		progress.AddDownloadedByteCount(int64(readByteCount))
+	})
+	return errors.Join(err, ctx.Err())
+}
+
+func (d sftpDownloader) connect(ctx context.Context, parsedURL *url.URL, progress *DownloadProgress) (*ssh.Client, error) {
+	options := progress.Snapshot().SFTPOptions
+	if options == nil {
+		options = &sftpOptions{}
+	}
+
+	clientConfig, err := d.getClientConfig(parsedURL, options, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	port := parsedURL.Port()
+	if port == "" {
+		port = defaultSFTPPort
+	}
+	address := net.JoinHostPort(parsedURL.Hostname(), port)
+
+	conn, err := d.dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	// the SSH handshake has no context support, the deadline keeps it from hanging on an unresponsive server
+	stopCloseOnCancel := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stopCloseOnCancel()
+	err = conn.SetDeadline(time.Now().Add(d.dialTimeout))
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	clientConn, channels, requests, err := ssh.NewClientConn(conn, address, clientConfig)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	err = conn.SetDeadline(time.Time{})
+	if err != nil {
+		clientConn.Close()
+		return nil, err
+	}
+
+	return ssh.NewClient(clientConn, channels, requests), nil
+}
+
+func (d sftpDownloader) getClientConfig(parsedURL *url.URL, options *sftpOptions, progress *DownloadProgress) (*ssh.ClientConfig, error) {
+	if parsedURL.User == nil || parsedURL.User.Username() == "" {
+		return nil, errSFTPNoUserName
+	}
+
+	authMethods := make([]ssh.AuthMethod, 0)
+	if options.PrivateKey != "" {
+		var (
+			signer ssh.Signer
+			err    error
+		)
+		if options.PrivateKeyPassphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(options.PrivateKey), []byte(options.PrivateKeyPassphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey([]byte(options.PrivateKey))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse SFTP private key: %w", err)
+		}
+		authMethods = append(authMethods, ssh.PublicKeys(signer))
+	}
+
+	password := options.Password
+	if urlPassword, ok := parsedURL.User.Password(); ok && password == "" {
+		password = urlPassword
+	}
+	if password != "" {
+		// servers that disable plain password authentication usually still accept it through keyboard-interactive
+		authMethods = append(authMethods, ssh.Password(password), ssh.KeyboardInteractive(
+			func(_, _ string, questions []string, _ []bool) ([]string, error) {
+				answers := make([]string, len(questions))
+				for i := range answers {
+					answers[i] = password
+				}
+				return answers, nil
+			}))
+	}
+
+	if len(authMethods) == 0 {
+		return nil, errSFTPNoCredentials
+	}
+
+	return &ssh.ClientConfig{
+		User:            parsedURL.User.Username(),
+		Auth:            authMethods,
+		HostKeyCallback: d.getHostKeyCallback(options, progress),
+	}, nil
+}
+
+// getHostKeyCallback checks the host key against the host key of the task SFTP options, which is either an
+// authorized_keys line or a SHA256 fingerprint. When there is none, the key is checked against the key the host
+// presented on an earlier attempt of the same task, and the first key of a host is recorded in the task metadata.
+func (d sftpDownloader) getHostKeyCallback(options *sftpOptions, progress *DownloadProgress) ssh.HostKeyCallback {
+	return func(hostname string, _ net.Addr, key ssh.PublicKey) error {
+		fingerprint := ssh.FingerprintSHA256(key)
+
+		if options.HostKey != "" {
+			if strings.HasPrefix(options.HostKey, "SHA256:") {
+				if options.HostKey != fingerprint {
+					return errSFTPHostKeyMismatch
+				}
+				return nil
+			}
+
+			pinnedKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(options.HostKey))
+			if err != nil {
+				return fmt.Errorf("failed to parse SFTP host key: %w", err)
+			}
+			if !bytes.Equal(pinnedKey.Marshal(), key.Marshal()) {
+				return errSFTPHostKeyMismatch
+			}
+			return nil
+		}
+
+		if knownFingerprint, ok := options.KnownHostKeys[hostname]; ok {
+			if knownFingerprint != fingerprint {
+				return errSFTPHostKeyMismatch
+			}
+			return nil
+		}
+
+		d.logger.With(zap.String("host", hostname), zap.String("fingerprint", fingerprint)).Info("trusting SFTP host key on first use")
+		progress.Update(func(metadata *downloadTaskMetadata) {
+			updatedOptions := sftpOptions{}
+			if metadata.SFTPOptions != nil {
+				updatedOptions = *metadata.SFTPOptions
+			}
+			knownHostKeys := make(map[string]string, len(updatedOptions.KnownHostKeys)+1)
+			for knownHost, knownFingerprint := range updatedOptions.KnownHostKeys {
+				knownHostKeys[knownHost] = knownFingerprint
+			}
+			knownHostKeys[hostname] = fingerprint
+			updatedOptions.KnownHostKeys = knownHostKeys
+			metadata.SFTPOptions = &updatedOptions
+		})
+		return nil
+	}
+}
+
+// getSFTPFilePath returns the remote path of the URL. Paths are absolute, except for paths starting with /~/ which
+// are relative to the home directory of the user, the same convention curl follows.
+func getSFTPFilePath(parsedURL *url.URL) string {
+	if filePath, ok := strings.CutPrefix(parsedURL.Path, "/~/"); ok {
+		return filePath
+	}
+	return parsedURL.Path
+}
+
+type sftpStatusError struct {
+	Code    uint32
+	Message string
+}
+
+func (e sftpStatusError) Error() string {
+	return fmt.Sprintf("SFTP status %d: %s", e.Code, e.Message)
+}
+
+// sftpClient is a client for the SFTP subsystem of an SSH connection, it supports the subset of protocol version 3
+// needed to download a single file. Requests are not safe for concurrent use.
+type sftpClient struct {
+	writer        io.Writer
+	reader        io.Reader
+	nextRequestID uint32
+}
+
+func newSFTPClient(sshClient *ssh.Client) (*sftpClient, error) {
+	session, err := sshClient.NewSession()
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := session.StdinPipe()
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+
+	reader, err := session.StdoutPipe()
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+
+	err = session.RequestSubsystem("sftp")
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+
+	client := &sftpClient{
+		writer: writer,
+		reader: reader,
+	}
+
+	err = client.writePacket(sftpPacketInit, binary.BigEndian.AppendUint32(nil, sftpProtocolVersion))
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+
+	packetType, payload, err := client.readPacket()
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+
+	packetReader := &sftpPacketReader{data: payload}
+	version := packetReader.Uint32()
+	if packetType != sftpPacketVersion || packetReader.err != nil {
+		session.Close()
+		return nil, errSFTPInvalidPacket
+	}
+	if version < sftpProtocolVersion {
+		session.Close()
+		return nil, fmt.Errorf("unsupported SFTP protocol version %d", version)
+	}
+
+	return client, nil
+}
+
+func (c *sftpClient) writePacket(packetType byte, payload []byte) error {
+	packet := make([]byte, 0, 5+len(payload))
+	packet = binary.BigEndian.AppendUint32(packet, uint32(1+len(payload)))
+	packet = append(packet, packetType)
+	packet = append(packet, payload...)
+	_, err := c.writer.Write(packet)
+	return err
+}
+
+func (c *sftpClient) readPacket() (byte, []byte, error) {
+	header := make([]byte, 5)
+	_, err := io.ReadFull(c.reader, header)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	length := binary.BigEndian.Uint32(header)
+	if length < 1 || length > sftpMaxPacketLength {
+		return 0, nil, errSFTPInvalidPacket
+	}
+
+	payload := make([]byte, length-1)
+	_, err = io.ReadFull(c.reader, payload)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return header[4], payload, nil
+}
+
+// sendRequest writes a request and returns its ID, the payload must not include the ID.
+func (c *sftpClient) sendRequest(packetType byte, payload []byte) (uint32, error) {
+	c.nextRequestID++
+	requestID := c.nextRequestID
+	err := c.writePacket(packetType, append(binary.BigEndian.AppendUint32(nil, requestID), payload...))
+	return requestID, err
+}
+
+// readResponse reads the next response, the returned reader is positioned after the request ID.
+func (c *sftpClient) readResponse() (uint32, byte, *sftpPacketReader, error) {
+	packetType, payload, err := c.readPacket()
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	packetReader := &sftpPacketReader{data: payload}
+	requestID := packetReader.Uint32()
+	if packetReader.err != nil {
+		return 0, 0, nil, packetReader.err
+	}
+
+	return requestID, packetType, packetReader, nil
+}
+
+// request sends a request and waits for its response, it is only used while no other request is in flight.
+func (c *sftpClient) request(packetType byte, payload []byte) (byte, *sftpPacketReader, error) {
+	requestID, err := c.sendRequest(packetType, payload)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	responseID, responseType, packetReader, err := c.readResponse()
+	if err != nil {
+		return 0, nil, err
+	}
+	if responseID != requestID {
+		return 0, nil, errSFTPInvalidPacket
+	}
+
+	return responseType, packetReader, nil
+}
+
+func (c *sftpClient) Open(filePath string) (string, error) {
+	payload := appendSFTPString(nil, filePath)
+	payload = binary.BigEndian.AppendUint32(payload, sftpOpenFlagRead)
+	// no attributes, they only matter when the file is created
+	payload = binary.BigEndian.AppendUint32(payload, 0)
+
+	responseType, packetReader, err := c.request(sftpPacketOpen, payload)
+	if err != nil {
+		return "", err
+	}
+
+	switch responseType {
+	case sftpPacketHandle:
+		handle := packetReader.String()
+		return handle, packetReader.err
+	case sftpPacketStatus:
+		return "", packetReader.StatusError()
+	default:
+		return "", errSFTPInvalidPacket
+	}
+}
+
+func (c *sftpClient) Close(handle string) error {
+	responseType, packetReader, err := c.request(sftpPacketClose, appendSFTPString(nil, handle))
+	if err != nil {
+		return err
+	}
+	if responseType != sftpPacketStatus {
+		return errSFTPInvalidPacket
+	}
+	return packetReader.StatusError()
+}
+
+// Fstat returns the size of the file, 0 when the server does not report it, and its modification time in RFC 3339,
+// empty when the server does not report it.
+func (c *sftpClient) Fstat(handle string) (int64, string, error) {
+	responseType, packetReader, err := c.request(sftpPacketFstat, appendSFTPString(nil, handle))
+	if err != nil {
+		return 0, "", err
+	}
+
+	switch responseType {
+	case sftpPacketAttrs:
+	case sftpPacketStatus:
+		return 0, "", packetReader.StatusError()
+	default:
+		return 0, "", errSFTPInvalidPacket
+	}
+
+	flags := packetReader.Uint32()
+	size := uint64(0)
+	if flags&sftpAttrFlagSize != 0 {
+		size = packetReader.Uint64()
+	}
+	if flags&sftpAttrFlagUIDGID != 0 {
+		packetReader.Uint32()
+		packetReader.Uint32()
+	}
+	if flags&sftpAttrFlagPermissions != 0 {
+		packetReader.Uint32()
+	}
+	lastModified := ""
+	if flags&sftpAttrFlagACModTime != 0 {
+		packetReader.Uint32()
+		lastModified = time.Unix(int64(packetReader.Uint32()), 0).UTC().Format(time.RFC3339)
+	}
+	if packetReader.err != nil {
+		return 0, "", packetReader.err
+	}
+
+	return int64(size), lastModified, nil
+}
+
+func appendSFTPReadRequest(handle string, offset int64, length uint32) []byte {
+	payload := appendSFTPString(nil, handle)
+	payload = binary.BigEndian.AppendUint64(payload, uint64(offset))
+	return binary.BigEndian.AppendUint32(payload, length)
+}
+
+// Read reads the next chunk of the file at the offset, it returns io.EOF at the end of the file.
+func (c *sftpClient) Read(handle string, offset int64, length uint32) ([]byte, error) {
+	responseType, packetReader, err := c.request(sftpPacketRead, appendSFTPReadRequest(handle, offset, length))
+	if err != nil {
+		return nil, err
+	}
+
+	switch responseType {
+	case sftpPacketData:
+		data := packetReader.String()
+		return []byte(data), packetReader.err
+	case sftpPacketStatus:
+		err = packetReader.StatusError()
+		if statusErr := (sftpStatusError{}); errors.As(err, &statusErr) && statusErr.Code == sftpStatusEOF {
+			return nil, io.EOF
+		}
+		if err == nil {
+			return nil, errSFTPInvalidPacket
+		}
+		return nil, err
+	default:
+		return nil, errSFTPInvalidPacket
+	}
+}
+
+// ReadFile reads the file from the offset up to its size into the writer. Several reads are kept in flight so that the
+// transfer is not bound by the round trip time, and since servers may answer with less data than requested, the rest
+// of a short read is requested again.
+func (c *sftpClient) ReadFile(handle string, offset, size int64, writer io.WriterAt, onRead func(readByteCount int)) error {
+	type readRequest struct {
+		offset int64
+		length uint32
+	}
+
+	inflightRequests := make(map[uint32]readRequest)
+	retryRequests := make([]readRequest, 0)
+	nextOffset := offset
+	for {
+		for len(inflightRequests) < sftpMaxInflightReadCount {
+			var request readRequest
+			if len(retryRequests) > 0 {
+				request = retryRequests[len(retryRequests)-1]
+				retryRequests = retryRequests[:len(retryRequests)-1]
+			} else if nextOffset < size {
+				request = readRequest{offset: nextOffset, length: uint32(min(sftpReadChunkSize, size-nextOffset))}
+				nextOffset += int64(request.length)
+			} else {
+				break
+			}
+
+			requestID, err := c.sendRequest(sftpPacketRead, appendSFTPReadRequest(handle, request.offset, request.length))
+			if err != nil {
+				return err
+			}
+			inflightRequests[requestID] = request
+		}
+
+		if len(inflightRequests) == 0 {
+			return nil
+		}
+
+		requestID, responseType, packetReader, err := c.readResponse()
+		if err != nil {
+			return err
+		}
+
+		request, ok := inflightRequests[requestID]
+		if !ok {
+			return errSFTPInvalidPacket
+		}
+		delete(inflightRequests, requestID)
+
+		switch responseType {
+		case sftpPacketData:
+		case sftpPacketStatus:
+			err = packetReader.StatusError()
+			if statusErr := (sftpStatusError{}); errors.As(err, &statusErr) && statusErr.Code == sftpStatusEOF {
+				return fmt.Errorf("SFTP file ended at offset %d before its size %d, it was probably changed", request.offset, size)
+			}
+			if err == nil {
+				return errSFTPInvalidPacket
+			}
+			return err
+		default:
+			return errSFTPInvalidPacket
+		}
+
+		data := packetReader.String()
+		if packetReader.err != nil {
+			return packetReader.err
+		}
+		if len(data) == 0 || len(data) > int(request.length) {
+			return errSFTPInvalidPacket
+		}
+
+		_, err = writer.WriteAt([]byte(data), request.offset)
+		if err != nil {
+			return err
+		}
+		onRead(len(data))
+
+		if len(data) < int(request.length) {
+			retryRequests = append(retryRequests, readRequest{
+				offset: request.offset + int64(len(data)),
+				length: request.length - uint32(len(data)),
+			})
+		}
+	}
+}
+
+// sftpFileReader reads an SFTP file front to back, one request at a time.
+type sftpFileReader struct {
+	client *sftpClient
+	handle string
+	offset int64
+}
+
+func (r *sftpFileReader) Read(p []byte) (int, error) {
+	data, err := r.client.Read(r.handle, r.offset, uint32(min(len(p), sftpReadChunkSize)))
+	if err != nil {
+		return 0, err
+	}
+
+	readByteCount := copy(p, data)
+	r.offset += int64(readByteCount)
+	return readByteCount, nil
+}
+
+func appendSFTPString(payload []byte, value string) []byte {
+	payload = binary.BigEndian.AppendUint32(payload, uint32(len(value)))
+	return append(payload, value...)
+}
+
+// sftpPacketReader decodes the fields of an SFTP packet. The first decoding error is kept and every later field reads
+// as zero, so that callers only have to check once.
+type sftpPacketReader struct {
+	data []byte
+	err  error
+}
+
+func (r *sftpPacketReader) Uint32() uint32 {
+	if r.err != nil || len(r.data) < 4 {
+		r.err = errSFTPInvalidPacket
+		return 0
+	}
+	value := binary.BigEndian.Uint32(r.data)
+	r.data = r.data[4:]
+	return value
+}
+
+func (r *sftpPacketReader) Uint64() uint64 {
+	if r.err != nil || len(r.data) < 8 {
+		r.err = errSFTPInvalidPacket
+		return 0
+	}
+	value := binary.BigEndian.Uint64(r.data)
+	r.data = r.data[8:]
+	return value
+}
+
+func (r *sftpPacketReader) String() string {
+	length := r.Uint32()
+	if r.err != nil || uint32(len(r.data)) < length {
+		r.err = errSFTPInvalidPacket
+		return ""
+	}
+	value := string(r.data[:length])
+	r.data = r.data[length:]
+	return value
+}
+
+// StatusError decodes a status response, it returns nil for SSH_FX_OK.
+func (r *sftpPacketReader) StatusError() error {
+	code := r.Uint32()
+	message := ""
+	// some servers leave out the message and language tag
+	if len(r.data) > 0 {
+		message = r.String()
+	}
+	if r.err != nil {
+		return r.err
+	}
+	if code == sftpStatusOK {
+		return nil
+	}
+	return sftpStatusError{Code: code, Message: message}
+}
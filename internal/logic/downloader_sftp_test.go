@@ -0,0 +1,488 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	testSFTPUserName = "alice"
+	testSFTPPassword = "secret"
+	// testSFTPMaxReadLength is less than sftpReadChunkSize, so that the downloader has to request the rest of every read
+	testSFTPMaxReadLength = 10000
+)
+
+var testSFTPModTime = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestSSHKey(t *testing.T) (ed25519.PrivateKey, ssh.Signer) {
+	t.Helper()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	if err != nil {
+		t.Fatalf("failed to create signer: %v", err)
+	}
+	return privateKey, signer
+}
+
+// testSFTPServer is an SSH server serving a single file over the SFTP subsystem. It accepts the test password and the
+// client key it was created with.
+type testSFTPServer struct {
+	listener  net.Listener
+	payload   []byte
+	hostKey   ssh.Signer
+	clientKey ssh.PublicKey
+
+	mutex sync.Mutex
+	// readOffsets are the offsets of the read requests and sentByteCount the bytes sent for them, for all sessions
+	readOffsets   []int64
+	sentByteCount int
+}
+
+func newTestSFTPServer(t *testing.T, payload []byte, clientKey ssh.PublicKey) *testSFTPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	_, hostKey := newTestSSHKey(t)
+	server := &testSFTPServer{listener: listener, payload: payload, hostKey: hostKey, clientKey: clientKey}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+	return server
+}
+
+func (s *testSFTPServer) url(userInfo string) string {
+	return fmt.Sprintf("sftp://%s%s/data/file.bin", userInfo, s.listener.Addr())
+}
+
+func (s *testSFTPServer) getReadOffsets() []int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]int64(nil), s.readOffsets...)
+}
+
+func (s *testSFTPServer) getSentByteCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.sentByteCount
+}
+
+func (s *testSFTPServer) serve() {
+	serverConfig := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() != testSFTPUserName || string(password) != testSFTPPassword {
+				return nil, errors.New("wrong password")
+			}
+			return nil, nil
+		},
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() != testSFTPUserName || s.clientKey == nil || !bytes.Equal(key.Marshal(), s.clientKey.Marshal()) {
+				return nil, errors.New("unknown key")
+			}
+			return nil, nil
+		},
+	}
+	serverConfig.AddHostKey(s.hostKey)
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.serveConn(conn, serverConfig)
+	}
+}
+
+func (s *testSFTPServer) serveConn(conn net.Conn, serverConfig *ssh.ServerConfig) {
+	serverConn, channels, requests, err := ssh.NewServerConn(conn, serverConfig)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	defer serverConn.Close()
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "only sessions are supported")
+			continue
+		}
+
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for request := range channelRequests {
+				isSFTP := request.Type == "subsystem" && bytes.Equal(request.Payload, appendSFTPString(nil, "sftp"))
+				request.Reply(isSFTP, nil)
+				if isSFTP {
+					go s.serveSFTP(channel)
+				}
+			}
+		}()
+	}
+}
+
+func (s *testSFTPServer) serveSFTP(channel ssh.Channel) {
+	defer channel.Close()
+
+	writePacket := func(packetType byte, payload []byte) {
+		packet := binary.BigEndian.AppendUint32(nil, uint32(1+len(payload)))
+		packet = append(packet, packetType)
+		channel.Write(append(packet, payload...))
+	}
+	writeStatus := func(requestID, code uint32) {
+		payload := binary.BigEndian.AppendUint32(nil, requestID)
+		payload = binary.BigEndian.AppendUint32(payload, code)
+		payload = appendSFTPString(payload, "")
+		writePacket(sftpPacketStatus, appendSFTPString(payload, ""))
+	}
+
+	for {
+		header := make([]byte, 5)
+		_, err := io.ReadFull(channel, header)
+		if err != nil {
+			return
+		}
+		payload := make([]byte, binary.BigEndian.Uint32(header)-1)
+		_, err = io.ReadFull(channel, payload)
+		if err != nil {
+			return
+		}
+
+		packetReader := &sftpPacketReader{data: payload}
+		if header[4] == sftpPacketInit {
+			writePacket(sftpPacketVersion, binary.BigEndian.AppendUint32(nil, sftpProtocolVersion))
+			continue
+		}
+
+		requestID := packetReader.Uint32()
+		response := binary.BigEndian.AppendUint32(nil, requestID)
+		switch header[4] {
+		case sftpPacketOpen:
+			if packetReader.String() != "/data/file.bin" {
+				writeStatus(requestID, 2)
+				continue
+			}
+			writePacket(sftpPacketHandle, appendSFTPString(response, "handle"))
+		case sftpPacketFstat:
+			response = binary.BigEndian.AppendUint32(response, sftpAttrFlagSize|sftpAttrFlagACModTime)
+			response = binary.BigEndian.AppendUint64(response, uint64(len(s.payload)))
+			response = binary.BigEndian.AppendUint32(response, uint32(testSFTPModTime.Unix()))
+			response = binary.BigEndian.AppendUint32(response, uint32(testSFTPModTime.Unix()))
+			writePacket(sftpPacketAttrs, response)
+		case sftpPacketRead:
+			handle := packetReader.String()
+			offset := int64(packetReader.Uint64())
+			length := int64(min(packetReader.Uint32(), testSFTPMaxReadLength))
+			if handle != "handle" {
+				// SSH_FX_FAILURE
+				writeStatus(requestID, 4)
+				continue
+			}
+			if offset >= int64(len(s.payload)) {
+				writeStatus(requestID, sftpStatusEOF)
+				continue
+			}
+
+			data := s.payload[offset:min(offset+length, int64(len(s.payload)))]
+			s.mutex.Lock()
+			s.readOffsets = append(s.readOffsets, offset)
+			s.sentByteCount += len(data)
+			s.mutex.Unlock()
+			writePacket(sftpPacketData, appendSFTPString(response, string(data)))
+		case sftpPacketClose:
+			writeStatus(requestID, sftpStatusOK)
+		default:
+			// SSH_FX_OP_UNSUPPORTED
+			writeStatus(requestID, 8)
+		}
+	}
+}
+
+func newTestSFTPDownloader(t *testing.T) (SFTPDownloader, string) {
+	t.Helper()
+
+	directory := t.TempDir()
+	logger := zap.NewNop()
+	downloadConfig := configs.DownloadConfig{DownloadDirectory: directory, SFTPConfig: configs.SFTPConfig{DialTimeout: "5s"}}
+	fileClient, err := file.NewLocalClient(downloadConfig, logger)
+	if err != nil {
+		t.Fatalf("failed to create file client: %v", err)
+	}
+
+	downloader, err := NewSFTPDownloader(downloadConfig, fileClient, logger)
+	if err != nil {
+		t.Fatalf("failed to create SFTP downloader: %v", err)
+	}
+	return downloader, directory
+}
+
+func TestSFTPDownloaderDownloadHostKeys(t *testing.T) {
+	payload := newTestPayload(100*1024 + 3)
+	server := newTestSFTPServer(t, payload, nil)
+	hostAddress := server.listener.Addr().String()
+	hostFingerprint := ssh.FingerprintSHA256(server.hostKey.PublicKey())
+	_, otherKey := newTestSSHKey(t)
+
+	testCases := []struct {
+		name              string
+		hostKey           string
+		knownHostKeys     map[string]string
+		wantErr           error
+		wantKnownHostKeys map[string]string
+	}{
+		{
+			name:    "pinned authorized_keys line",
+			hostKey: string(ssh.MarshalAuthorizedKey(server.hostKey.PublicKey())),
+		},
+		{
+			name:    "pinned fingerprint",
+			hostKey: hostFingerprint,
+		},
+		{
+			name:    "mismatched authorized_keys line",
+			hostKey: string(ssh.MarshalAuthorizedKey(otherKey.PublicKey())),
+			wantErr: errSFTPHostKeyMismatch,
+		},
+		{
+			name:    "mismatched fingerprint",
+			hostKey: ssh.FingerprintSHA256(otherKey.PublicKey()),
+			wantErr: errSFTPHostKeyMismatch,
+		},
+		{
+			name:              "trusted on first use",
+			wantKnownHostKeys: map[string]string{hostAddress: hostFingerprint},
+		},
+		{
+			name:              "known from an earlier attempt",
+			knownHostKeys:     map[string]string{hostAddress: hostFingerprint, "other:22": "SHA256:other"},
+			wantKnownHostKeys: map[string]string{hostAddress: hostFingerprint, "other:22": "SHA256:other"},
+		},
+		{
+			name:              "changed since an earlier attempt",
+			knownHostKeys:     map[string]string{hostAddress: ssh.FingerprintSHA256(otherKey.PublicKey())},
+			wantErr:           errSFTPHostKeyMismatch,
+			wantKnownHostKeys: map[string]string{hostAddress: ssh.FingerprintSHA256(otherKey.PublicKey())},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			downloader, directory := newTestSFTPDownloader(t)
+
+			progress := newDownloadProgress(downloadTaskMetadata{
+				FileName:    "file.bin",
+				SFTPOptions: &sftpOptions{HostKey: testCase.hostKey, KnownHostKeys: testCase.knownHostKeys},
+			})
+			params := DownloadParams{URL: server.url(testSFTPUserName + ":" + testSFTPPassword + "@")}
+			err := downloader.Download(context.Background(), params, progress)
+			if !errors.Is(err, testCase.wantErr) {
+				t.Fatalf("Download() error = %v, want %v", err, testCase.wantErr)
+			}
+
+			if testCase.wantErr != nil {
+				if _, err := os.Stat(filepath.Join(directory, "file.bin")); !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("a rejected host created the file, stat error = %v", err)
+				}
+			} else {
+				checkTestDownloadedFile(t, filepath.Join(directory, "file.bin"), payload)
+			}
+
+			knownHostKeys := progress.Snapshot().SFTPOptions.KnownHostKeys
+			if fmt.Sprint(knownHostKeys) != fmt.Sprint(testCase.wantKnownHostKeys) {
+				t.Errorf("KnownHostKeys = %v, want %v", knownHostKeys, testCase.wantKnownHostKeys)
+			}
+		})
+	}
+}
+
+func TestSFTPDownloaderDownloadAuthentication(t *testing.T) {
+	payload := newTestPayload(100*1024 + 3)
+	clientPrivateKey, clientKey := newTestSSHKey(t)
+	server := newTestSFTPServer(t, payload, clientKey.PublicKey())
+
+	privateKeyBlock, err := ssh.MarshalPrivateKey(clientPrivateKey, "")
+	if err != nil {
+		t.Fatalf("failed to marshal private key: %v", err)
+	}
+	encryptedPrivateKeyBlock, err := ssh.MarshalPrivateKeyWithPassphrase(clientPrivateKey, "", []byte("passphrase"))
+	if err != nil {
+		t.Fatalf("failed to marshal private key: %v", err)
+	}
+	otherPrivateKey, _ := newTestSSHKey(t)
+	otherPrivateKeyBlock, err := ssh.MarshalPrivateKey(otherPrivateKey, "")
+	if err != nil {
+		t.Fatalf("failed to marshal private key: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		userInfo string
+		options  sftpOptions
+		wantErr  bool
+	}{
+		{
+			name:     "password in the URL",
+			userInfo: testSFTPUserName + ":" + testSFTPPassword + "@",
+		},
+		{
+			name:     "password in the options",
+			userInfo: testSFTPUserName + "@",
+			options:  sftpOptions{Password: testSFTPPassword},
+		},
+		{
+			name:     "private key",
+			userInfo: testSFTPUserName + "@",
+			options:  sftpOptions{PrivateKey: string(pem.EncodeToMemory(privateKeyBlock))},
+		},
+		{
+			name:     "private key with a passphrase",
+			userInfo: testSFTPUserName + "@",
+			options: sftpOptions{
+				PrivateKey:           string(pem.EncodeToMemory(encryptedPrivateKeyBlock)),
+				PrivateKeyPassphrase: "passphrase",
+			},
+		},
+		{
+			name:     "wrong password",
+			userInfo: testSFTPUserName + ":wrong@",
+			wantErr:  true,
+		},
+		{
+			name:     "unknown private key",
+			userInfo: testSFTPUserName + "@",
+			options:  sftpOptions{PrivateKey: string(pem.EncodeToMemory(otherPrivateKeyBlock))},
+			wantErr:  true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			downloader, directory := newTestSFTPDownloader(t)
+
+			options := testCase.options
+			options.HostKey = ssh.FingerprintSHA256(server.hostKey.PublicKey())
+			progress := newDownloadProgress(downloadTaskMetadata{FileName: "file.bin", SFTPOptions: &options})
+			err := downloader.Download(context.Background(), DownloadParams{URL: server.url(testCase.userInfo)}, progress)
+			if testCase.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "unable to authenticate") {
+					t.Fatalf("Download() error = %v, want an authentication failure", err)
+				}
+				if _, err := os.Stat(filepath.Join(directory, "file.bin")); !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("a failed login created the file, stat error = %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Download() error = %v", err)
+			}
+			checkTestDownloadedFile(t, filepath.Join(directory, "file.bin"), payload)
+		})
+	}
+}
+
+func TestSFTPDownloaderDownloadWithoutCredentials(t *testing.T) {
+	downloader, _ := newTestSFTPDownloader(t)
+
+	progress := newDownloadProgress(downloadTaskMetadata{FileName: "file.bin"})
+	err := downloader.Download(context.Background(), DownloadParams{URL: "sftp://alice@127.0.0.1:1/file.bin"}, progress)
+	if !errors.Is(err, errSFTPNoCredentials) {
+		t.Errorf("Download() error = %v, want %v", err, errSFTPNoCredentials)
+	}
+}
+
+func TestSFTPDownloaderDownloadResumes(t *testing.T) {
+	const downloadedByteCount = 40 * 1024
+
+	testCases := []struct {
+		name         string
+		lastModified string
+		wantOffset   int64
+	}{
+		{
+			name:         "same file",
+			lastModified: testSFTPModTime.Format(time.RFC3339),
+			wantOffset:   downloadedByteCount,
+		},
+		{
+			name:         "changed file starts over",
+			lastModified: testSFTPModTime.Add(time.Hour).Format(time.RFC3339),
+			wantOffset:   0,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			payload := newTestPayload(100*1024 + 3)
+			server := newTestSFTPServer(t, payload, nil)
+			downloader, directory := newTestSFTPDownloader(t)
+			downloadURL := server.url(testSFTPUserName + ":" + testSFTPPassword + "@")
+
+			// an interrupted download leaves a file of the full size with only its beginning written
+			partialContent := make([]byte, len(payload))
+			copy(partialContent, payload[:downloadedByteCount])
+			err := os.WriteFile(filepath.Join(directory, "file.bin"), partialContent, 0o644)
+			if err != nil {
+				t.Fatalf("failed to write partial file: %v", err)
+			}
+
+			progress := newDownloadProgress(downloadTaskMetadata{
+				FileName:            "file.bin",
+				ValidatedURL:        downloadURL,
+				Size:                int64(len(payload)),
+				LastModified:        testCase.lastModified,
+				DownloadedByteCount: downloadedByteCount,
+				SFTPOptions:         &sftpOptions{HostKey: ssh.FingerprintSHA256(server.hostKey.PublicKey())},
+			})
+			err = downloader.Download(context.Background(), DownloadParams{URL: downloadURL}, progress)
+			if err != nil {
+				t.Fatalf("Download() error = %v", err)
+			}
+			checkTestDownloadedFile(t, filepath.Join(directory, "file.bin"), payload)
+
+			readOffsets := server.getReadOffsets()
+			if len(readOffsets) == 0 || readOffsets[0] != testCase.wantOffset {
+				t.Errorf("read offsets %v, want the first one at %d", readOffsets, testCase.wantOffset)
+			}
+			if sentByteCount := server.getSentByteCount(); sentByteCount != len(payload)-int(testCase.wantOffset) {
+				t.Errorf("server sent %d bytes, want %d", sentByteCount, len(payload)-int(testCase.wantOffset))
+			}
+
+			metadata := progress.Snapshot()
+			if metadata.DownloadedByteCount != int64(len(payload)) || metadata.LastModified != testSFTPModTime.Format(time.RFC3339) {
+				t.Errorf(
+					"DownloadedByteCount = %d, LastModified = %s, want %d, %s",
+					metadata.DownloadedByteCount, metadata.LastModified, len(payload), testSFTPModTime.Format(time.RFC3339),
+				)
+			}
+		})
+	}
+}
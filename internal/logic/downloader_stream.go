@@ -0,0 +1,451 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"go.uber.org/zap"
+)
+
+const (
+	// streamManifestMaxSize bounds how much of a playlist or manifest is read, real ones are far smaller
+	streamManifestMaxSize = 16 * 1024 * 1024
+	streamKeySize         = 16
+)
+
+var (
+	errUnsupportedStreamManifest = errors.New("URL is neither an HLS playlist nor a DASH manifest")
+	errStreamHasNoSegments       = errors.New("stream has no media segments")
+	errInvalidStreamEncryption   = errors.New("invalid AES-128 encrypted stream segment")
+)
+
+type StreamDownloader Downloader
+
+// streamDownloader downloads HLS (.m3u8) and DASH (.mpd) streams. It selects one variant of the stream, downloads its
+// media segments in parallel into part files, decrypting AES-128 segments on the way, and concatenates the part files
+// into the output file once all of them are there.
+type streamDownloader struct {
+	httpClient *http.Client
+	fileClient file.Client
+	logger     *zap.Logger
+}
+
+func NewStreamDownloader(fileClient file.Client, logger *zap.Logger) StreamDownloader {
+	return &streamDownloader{
+		httpClient: &http.Client{},
+		fileClient: fileClient,
+		logger:     logger,
+	}
+}
+
+// CanResume implements Downloader.
+func (d streamDownloader) CanResume() bool {
+	return true
+}
+
+// streamSegment is a media segment of the selected variant. RangeLength is 0 when the whole resource is the segment.
+type streamSegment struct {
+	URL         string
+	RangeStart  int64
+	RangeLength int64
+	Key         *streamKey
+}
+
+// streamKey is the AES-128 key a segment is encrypted with.
+type streamKey struct {
+	URL string
+	IV  []byte
+}
+
+// streamManifest is the media of the selected variant, Extension is the extension of the output file.
+type streamManifest struct {
+	Segments  []streamSegment
+	Extension string
+}
+
+// selectStreamVariant returns the index of the variant with the highest bandwidth not above the requested bandwidth,
+// or of the lowest bandwidth variant if they are all above it. A requested bandwidth of 0 selects the highest one.
+func selectStreamVariant(bandwidths []uint64, requestedBandwidth uint64) int {
+	selectedIndex := -1
+	lowestIndex := 0
+	for i, bandwidth := range bandwidths {
+		if bandwidth < bandwidths[lowestIndex] {
+			lowestIndex = i
+		}
+		if requestedBandwidth > 0 && bandwidth > requestedBandwidth {
+			continue
+		}
+		if selectedIndex < 0 || bandwidth > bandwidths[selectedIndex] {
+			selectedIndex = i
+		}
+	}
+
+	if selectedIndex < 0 {
+		return lowestIndex
+	}
+	return selectedIndex
+}
+
+// Download implements Downloader.
+func (d streamDownloader) Download(ctx context.Context, params DownloadParams, progress *DownloadProgress) error {
+	metadata := progress.Snapshot()
+	requestedBandwidth := uint64(0)
+	if metadata.StreamOptions != nil {
+		requestedBandwidth = metadata.StreamOptions.Bandwidth
+	}
+
+	manifest, err := d.getStreamManifest(ctx, params.URL, requestedBandwidth)
+	if err != nil {
+		return err
+	}
+
+	if len(manifest.Segments) == 0 {
+		return errStreamHasNoSegments
+	}
+
+	keys, err := d.getStreamKeys(ctx, manifest.Segments)
+	if err != nil {
+		return err
+	}
+
+	fileName := getStreamFileName(metadata.FileName, manifest.Extension)
+	completedSegments := make([]bool, len(manifest.Segments))
+	downloadedByteCount := int64(0)
+	if metadata.ValidatedURL == params.URL && len(metadata.StreamSegments) == len(manifest.Segments) {
+		// a part file is only marked as completed after it is fully written, it only has to still be there
+		for segmentIndex, completed := range metadata.StreamSegments {
+			if !completed {
+				continue
+			}
+			size, err := d.fileClient.Size(ctx, getStreamSegmentFileName(fileName, segmentIndex))
+			if err != nil {
+				continue
+			}
+			completedSegments[segmentIndex] = true
+			downloadedByteCount += size
+		}
+		d.logger.With(zap.String("url", params.URL)).Info("resuming stream download")
+	}
+
+	progress.Update(func(metadata *downloadTaskMetadata) {
+		metadata.FileName = fileName
+		metadata.ValidatedURL = params.URL
+		metadata.Size = 0
+		metadata.ETag = ""
+		metadata.LastModified = ""
+		metadata.Segments = nil
+		metadata.StreamSegments = completedSegments
+		metadata.DownloadedByteCount = downloadedByteCount
+	})
+
+	err = d.downloadStreamSegments(ctx, manifest.Segments, keys, params.SegmentCount, progress)
+	if err != nil {
+		return err
+	}
+
+	size, err := d.concatenateStreamSegments(ctx, fileName, len(manifest.Segments))
+	if err != nil {
+		return err
+	}
+	progress.SetSize(size)
+
+	return nil
+}
+
+func (d streamDownloader) get(ctx context.Context, resourceURL string, rangeStart, rangeLength int64) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, resourceURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if rangeLength > 0 {
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", rangeStart, rangeStart+rangeLength-1))
+	}
+
+	response, err := d.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedStatusCode := http.StatusOK
+	if rangeLength > 0 {
+		expectedStatusCode = http.StatusPartialContent
+	}
+	if response.StatusCode != expectedStatusCode {
+		response.Body.Close()
+		return nil, newHTTPStatusError(response.StatusCode, "unexpected response status for %s: %s", resourceURL, response.Status)
+	}
+
+	return response, nil
+}
+
+// getManifest downloads a playlist or a manifest, it returns the URL it was served from after redirects, which is
+// what relative URLs in it are resolved against.
+func (d streamDownloader) getManifest(ctx context.Context, manifestURL string) (*url.URL, string, error) {
+	response, err := d.get(ctx, manifestURL, 0, 0)
+	if err != nil {
+		return nil, "", err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, streamManifestMaxSize))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return response.Request.URL, string(body), nil
+}
+
+func (d streamDownloader) getStreamManifest(ctx context.Context, manifestURL string, requestedBandwidth uint64) (streamManifest, error) {
+	baseURL, body, err := d.getManifest(ctx, manifestURL)
+	if err != nil {
+		return streamManifest{}, err
+	}
+
+	switch {
+	case strings.HasPrefix(strings.TrimPrefix(body, "\ufeff"), hlsPlaylistHeader):
+		playlist, err := parseHLSPlaylist(baseURL, body)
+		if err != nil {
+			return streamManifest{}, err
+		}
+
+		if len(playlist.Variants) > 0 {
+			bandwidths := make([]uint64, len(playlist.Variants))
+			for i, variant := range playlist.Variants {
+				bandwidths[i] = variant.Bandwidth
+			}
+			variant := playlist.Variants[selectStreamVariant(bandwidths, requestedBandwidth)]
+			d.logger.With(zap.String("url", manifestURL), zap.Uint64("bandwidth", variant.Bandwidth)).Info("selected HLS variant")
+
+			baseURL, body, err = d.getManifest(ctx, variant.URL)
+			if err != nil {
+				return streamManifest{}, err
+			}
+			playlist, err = parseHLSPlaylist(baseURL, body)
+			if err != nil {
+				return streamManifest{}, err
+			}
+			if len(playlist.Variants) > 0 {
+				return streamManifest{}, errors.New("HLS variant playlist is a master playlist")
+			}
+		}
+
+		return playlist.Manifest, nil
+	case strings.Contains(body, "<MPD"):
+		return parseDASHManifest(baseURL, body, requestedBandwidth)
+	default:
+		return streamManifest{}, errUnsupportedStreamManifest
+	}
+}
+
+// getStreamKeys downloads every distinct key the segments are encrypted with.
+func (d streamDownloader) getStreamKeys(ctx context.Context, segments []streamSegment) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for _, segment := range segments {
+		if segment.Key == nil {
+			continue
+		}
+		if _, ok := keys[segment.Key.URL]; ok {
+			continue
+		}
+
+		response, err := d.get(ctx, segment.Key.URL, 0, 0)
+		if err != nil {
+			return nil, err
+		}
+		key, err := io.ReadAll(io.LimitReader(response.Body, streamKeySize+1))
+		response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(key) != streamKeySize {
+			return nil, fmt.Errorf("AES-128 key at %s is %d bytes long", segment.Key.URL, len(key))
+		}
+
+		keys[segment.Key.URL] = key
+	}
+	return keys, nil
+}
+
+func (d streamDownloader) downloadStreamSegments(
+	ctx context.Context,
+	segments []streamSegment,
+	keys map[string][]byte,
+	workerCount int,
+	progress *DownloadProgress,
+) error {
+	metadata := progress.Snapshot()
+	segmentIndexes := make(chan int, len(segments))
+	for segmentIndex := range segments {
+		if !metadata.StreamSegments[segmentIndex] {
+			segmentIndexes <- segmentIndex
+		}
+	}
+	close(segmentIndexes)
+
+	// the first failing segment cancels the others, there is no point in finishing a download that is already broken
+	segmentCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		waitGroup  sync.WaitGroup
+		errorLock  sync.Mutex
+		segmentErr error
+	)
+	for range max(workerCount, 1) {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for segmentIndex := range segmentIndexes {
+				if segmentCtx.Err() != nil {
+					return
+				}
+
+				err := d.downloadStreamSegment(segmentCtx, metadata.FileName, segmentIndex, segments[segmentIndex], keys, progress)
+				if err != nil {
+					errorLock.Lock()
+					if segmentErr == nil {
+						segmentErr = err
+						cancel()
+					}
+					errorLock.Unlock()
+					return
+				}
+
+				progress.Update(func(metadata *downloadTaskMetadata) {
+					metadata.StreamSegments[segmentIndex] = true
+				})
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	return errors.Join(segmentErr, ctx.Err())
+}
+
+func (d streamDownloader) downloadStreamSegment(
+	ctx context.Context,
+	fileName string,
+	segmentIndex int,
+	segment streamSegment,
+	keys map[string][]byte,
+	progress *DownloadProgress,
+) error {
+	response, err := d.get(ctx, segment.URL, segment.RangeStart, segment.RangeLength)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	writer, err := d.fileClient.Write(ctx, getStreamSegmentFileName(fileName, segmentIndex))
+	if err != nil {
+		return err
+	}
+
+	progressWriter := downloadProgressWriter{writer: writer, progress: progress}
+	if segment.Key == nil {
+		_, err = io.Copy(progressWriter, response.Body)
+		closeErr := writer.Close()
+		return errors.Join(err, closeErr)
+	}
+
+	// CBC with PKCS#7 padding can only be decrypted once the whole segment is there
+	data, err := io.ReadAll(response.Body)
+	if err == nil {
+		data, err = decryptStreamSegment(data, keys[segment.Key.URL], segment.Key.IV)
+	}
+	if err == nil {
+		_, err = progressWriter.Write(data)
+	}
+	closeErr := writer.Close()
+	return errors.Join(err, closeErr)
+}
+
+func decryptStreamSegment(data, key, iv []byte) ([]byte, error) {
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, errInvalidStreamEncryption
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(data, data)
+
+	paddingLength := int(data[len(data)-1])
+	if paddingLength == 0 || paddingLength > aes.BlockSize ||
+		!bytes.Equal(data[len(data)-paddingLength:], bytes.Repeat([]byte{byte(paddingLength)}, paddingLength)) {
+		return nil, errInvalidStreamEncryption
+	}
+
+	return data[:len(data)-paddingLength], nil
+}
+
+// concatenateStreamSegments writes the part files into the output file in order and removes them, it returns the
+// size of the output file.
+func (d streamDownloader) concatenateStreamSegments(ctx context.Context, fileName string, segmentCount int) (int64, error) {
+	writer, err := d.fileClient.Write(ctx, fileName)
+	if err != nil {
+		return 0, err
+	}
+
+	size := int64(0)
+	for segmentIndex := range segmentCount {
+		reader, err := d.fileClient.Read(ctx, getStreamSegmentFileName(fileName, segmentIndex))
+		if err != nil {
+			writer.Close()
+			return 0, err
+		}
+
+		writtenByteCount, err := io.Copy(writer, reader)
+		reader.Close()
+		if err != nil {
+			writer.Close()
+			return 0, err
+		}
+		size += writtenByteCount
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	for segmentIndex := range segmentCount {
+		err = d.fileClient.Delete(ctx, getStreamSegmentFileName(fileName, segmentIndex))
+		if err != nil {
+			d.logger.With(zap.Error(err), zap.String("fileName", fileName), zap.Int("segmentIndex", segmentIndex)).
+				Warn("failed to delete stream segment file")
+		}
+	}
+
+	return size, nil
+}
+
+// getStreamFileName replaces the playlist or manifest extension of the file name derived from the URL with the
+// extension of the media.
+func getStreamFileName(fileName, extension string) string {
+	switch path.Ext(fileName) {
+	case ".m3u8", ".m3u", ".mpd":
+		return strings.TrimSuffix(fileName, path.Ext(fileName)) + extension
+	case "":
+		return fileName + extension
+	default:
+		return fileName
+	}
+}
+
+func getStreamSegmentFileName(fileName string, segmentIndex int) string {
+	return fmt.Sprintf("%s.part%05d", fileName, segmentIndex)
+}
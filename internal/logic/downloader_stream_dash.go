@@ -0,0 +1,493 @@
+package logic
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	dashDefaultStartNumber = 1
+	dashDefaultTimescale   = 1
+)
+
+var (
+	errDASHDynamicManifest  = errors.New("DASH manifest is a live stream, only static manifests can be downloaded")
+	errDASHProtectedContent = errors.New("DASH stream is DRM protected")
+	errDASHUnknownDuration  = errors.New("DASH period duration is needed to list its segments but it is not known")
+
+	dashDurationRegexp = regexp.MustCompile(
+		`^P(?:([\d.]+)D)?(?:T(?:([\d.]+)H)?(?:([\d.]+)M)?(?:([\d.]+)S)?)?$`)
+	dashTemplateIdentifierRegexp = regexp.MustCompile(`\$(RepresentationID|Number|Bandwidth|Time)(%0\d+d)?\$`)
+)
+
+// The types below map the parts of an ISO/IEC 23009-1 MPD needed to list the segments of a representation.
+
+type dashMPD struct {
+	Type                      string       `xml:"type,attr"`
+	MediaPresentationDuration string       `xml:"mediaPresentationDuration,attr"`
+	BaseURLs                  []string     `xml:"BaseURL"`
+	Periods                   []dashPeriod `xml:"Period"`
+}
+
+type dashPeriod struct {
+	Start           string               `xml:"start,attr"`
+	Duration        string               `xml:"duration,attr"`
+	BaseURLs        []string             `xml:"BaseURL"`
+	SegmentTemplate *dashSegmentTemplate `xml:"SegmentTemplate"`
+	SegmentList     *dashSegmentList     `xml:"SegmentList"`
+	AdaptationSets  []dashAdaptationSet  `xml:"AdaptationSet"`
+}
+
+type dashAdaptationSet struct {
+	ContentType        string               `xml:"contentType,attr"`
+	MimeType           string               `xml:"mimeType,attr"`
+	BaseURLs           []string             `xml:"BaseURL"`
+	ContentProtections []struct{}           `xml:"ContentProtection"`
+	SegmentTemplate    *dashSegmentTemplate `xml:"SegmentTemplate"`
+	SegmentList        *dashSegmentList     `xml:"SegmentList"`
+	Representations    []dashRepresentation `xml:"Representation"`
+}
+
+type dashRepresentation struct {
+	ID                 string               `xml:"id,attr"`
+	Bandwidth          uint64               `xml:"bandwidth,attr"`
+	MimeType           string               `xml:"mimeType,attr"`
+	BaseURLs           []string             `xml:"BaseURL"`
+	ContentProtections []struct{}           `xml:"ContentProtection"`
+	SegmentTemplate    *dashSegmentTemplate `xml:"SegmentTemplate"`
+	SegmentList        *dashSegmentList     `xml:"SegmentList"`
+}
+
+type dashSegmentTemplate struct {
+	Media           string               `xml:"media,attr"`
+	Initialization  string               `xml:"initialization,attr"`
+	StartNumber     string               `xml:"startNumber,attr"`
+	Timescale       string               `xml:"timescale,attr"`
+	Duration        string               `xml:"duration,attr"`
+	SegmentTimeline *dashSegmentTimeline `xml:"SegmentTimeline"`
+}
+
+type dashSegmentTimeline struct {
+	Segments []dashTimelineSegment `xml:"S"`
+}
+
+type dashTimelineSegment struct {
+	Time        *uint64 `xml:"t,attr"`
+	Duration    uint64  `xml:"d,attr"`
+	RepeatCount int64   `xml:"r,attr"`
+}
+
+type dashSegmentList struct {
+	Initialization *dashURL         `xml:"Initialization"`
+	SegmentURLs    []dashSegmentURL `xml:"SegmentURL"`
+}
+
+type dashURL struct {
+	SourceURL string `xml:"sourceURL,attr"`
+	Range     string `xml:"range,attr"`
+}
+
+type dashSegmentURL struct {
+	Media      string `xml:"media,attr"`
+	MediaRange string `xml:"mediaRange,attr"`
+}
+
+// dashCandidate is a representation that can be selected, together with everything it inherits from its parents.
+type dashCandidate struct {
+	representation  dashRepresentation
+	mimeType        string
+	baseURL         *url.URL
+	segmentTemplate *dashSegmentTemplate
+	segmentList     *dashSegmentList
+}
+
+// parseDASHManifest lists the segments of the representation selected by bandwidth in each period of a static MPD.
+// The output is a single file, so when video and audio are in separate adaptation sets only the video is downloaded.
+func parseDASHManifest(baseURL *url.URL, body string, requestedBandwidth uint64) (streamManifest, error) {
+	var mpd dashMPD
+	err := xml.Unmarshal([]byte(body), &mpd)
+	if err != nil {
+		return streamManifest{}, err
+	}
+
+	if mpd.Type == "dynamic" {
+		return streamManifest{}, errDASHDynamicManifest
+	}
+
+	mpdBaseURL, err := resolveDASHBaseURL(baseURL, mpd.BaseURLs)
+	if err != nil {
+		return streamManifest{}, err
+	}
+
+	manifest := streamManifest{Extension: ".mp4"}
+	for periodIndex, period := range mpd.Periods {
+		periodDuration, err := getDASHPeriodDuration(mpd, periodIndex)
+		if err != nil {
+			return streamManifest{}, err
+		}
+
+		periodBaseURL, err := resolveDASHBaseURL(mpdBaseURL, period.BaseURLs)
+		if err != nil {
+			return streamManifest{}, err
+		}
+
+		candidates, err := getDASHCandidates(period, periodBaseURL)
+		if err != nil {
+			return streamManifest{}, err
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+
+		bandwidths := make([]uint64, len(candidates))
+		for i, candidate := range candidates {
+			bandwidths[i] = candidate.representation.Bandwidth
+		}
+		candidate := candidates[selectStreamVariant(bandwidths, requestedBandwidth)]
+
+		segments, err := getDASHSegments(candidate, periodDuration)
+		if err != nil {
+			return streamManifest{}, err
+		}
+		manifest.Segments = append(manifest.Segments, segments...)
+
+		switch {
+		case strings.HasSuffix(candidate.mimeType, "/webm"):
+			manifest.Extension = ".webm"
+		case strings.HasSuffix(candidate.mimeType, "/mp2t"):
+			manifest.Extension = ".ts"
+		}
+	}
+
+	return manifest, nil
+}
+
+func getDASHCandidates(period dashPeriod, periodBaseURL *url.URL) ([]dashCandidate, error) {
+	var videoCandidates, otherCandidates []dashCandidate
+	for _, adaptationSet := range period.AdaptationSets {
+		if len(adaptationSet.ContentProtections) > 0 {
+			return nil, errDASHProtectedContent
+		}
+
+		adaptationSetBaseURL, err := resolveDASHBaseURL(periodBaseURL, adaptationSet.BaseURLs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, representation := range adaptationSet.Representations {
+			if len(representation.ContentProtections) > 0 {
+				return nil, errDASHProtectedContent
+			}
+
+			representationBaseURL, err := resolveDASHBaseURL(adaptationSetBaseURL, representation.BaseURLs)
+			if err != nil {
+				return nil, err
+			}
+
+			candidate := dashCandidate{
+				representation: representation,
+				mimeType:       representation.MimeType,
+				baseURL:        representationBaseURL,
+				segmentTemplate: mergeDASHSegmentTemplates(
+					period.SegmentTemplate, adaptationSet.SegmentTemplate, representation.SegmentTemplate),
+				segmentList: representation.SegmentList,
+			}
+			if candidate.mimeType == "" {
+				candidate.mimeType = adaptationSet.MimeType
+			}
+			if candidate.segmentList == nil {
+				candidate.segmentList = adaptationSet.SegmentList
+			}
+			if candidate.segmentList == nil {
+				candidate.segmentList = period.SegmentList
+			}
+
+			if adaptationSet.ContentType == "video" || strings.HasPrefix(candidate.mimeType, "video/") {
+				videoCandidates = append(videoCandidates, candidate)
+			} else {
+				otherCandidates = append(otherCandidates, candidate)
+			}
+		}
+	}
+
+	if len(videoCandidates) > 0 {
+		return videoCandidates, nil
+	}
+	return otherCandidates, nil
+}
+
+func getDASHSegments(candidate dashCandidate, periodDuration float64) ([]streamSegment, error) {
+	switch {
+	case candidate.segmentTemplate != nil && candidate.segmentTemplate.Media != "":
+		return getDASHTemplateSegments(candidate, periodDuration)
+	case candidate.segmentList != nil:
+		return getDASHListSegments(candidate)
+	default:
+		// a single segment representation, possibly with a SegmentBase index that is not needed to download it whole
+		return []streamSegment{{URL: candidate.baseURL.String()}}, nil
+	}
+}
+
+func getDASHTemplateSegments(candidate dashCandidate, periodDuration float64) ([]streamSegment, error) {
+	template := candidate.segmentTemplate
+	startNumber, err := parseDASHUint(template.StartNumber, dashDefaultStartNumber)
+	if err != nil {
+		return nil, err
+	}
+	timescale, err := parseDASHUint(template.Timescale, dashDefaultTimescale)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := make([]streamSegment, 0)
+	appendSegment := func(urlTemplate string, number, time uint64) error {
+		segmentURL, err := candidate.baseURL.Parse(
+			expandDASHTemplate(urlTemplate, candidate.representation.ID, candidate.representation.Bandwidth, number, time))
+		if err != nil {
+			return err
+		}
+		segments = append(segments, streamSegment{URL: segmentURL.String()})
+		return nil
+	}
+
+	if template.Initialization != "" {
+		err = appendSegment(template.Initialization, 0, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if template.SegmentTimeline != nil {
+		number := startNumber
+		time := uint64(0)
+		periodEnd := uint64(math.Ceil(periodDuration * float64(timescale)))
+		for i, timelineSegment := range template.SegmentTimeline.Segments {
+			if timelineSegment.Time != nil {
+				time = *timelineSegment.Time
+			}
+			if timelineSegment.Duration == 0 {
+				return nil, errors.New("DASH segment timeline entry has no duration")
+			}
+
+			repeatCount := timelineSegment.RepeatCount
+			if repeatCount < 0 {
+				// repeat until the next entry, or until the end of the period for the last one
+				end := periodEnd
+				if i+1 < len(template.SegmentTimeline.Segments) && template.SegmentTimeline.Segments[i+1].Time != nil {
+					end = *template.SegmentTimeline.Segments[i+1].Time
+				} else if periodDuration <= 0 {
+					return nil, errDASHUnknownDuration
+				}
+				repeatCount = int64((end-min(time, end)+timelineSegment.Duration-1)/timelineSegment.Duration) - 1
+			}
+
+			for range repeatCount + 1 {
+				err = appendSegment(template.Media, number, time)
+				if err != nil {
+					return nil, err
+				}
+				number++
+				time += timelineSegment.Duration
+			}
+		}
+		return segments, nil
+	}
+
+	duration, err := parseDASHUint(template.Duration, 0)
+	if err != nil {
+		return nil, err
+	}
+	if duration == 0 {
+		return nil, errors.New("DASH segment template has neither a duration nor a segment timeline")
+	}
+	if periodDuration <= 0 {
+		return nil, errDASHUnknownDuration
+	}
+
+	segmentCount := uint64(math.Ceil(periodDuration * float64(timescale) / float64(duration)))
+	for i := range segmentCount {
+		err = appendSegment(template.Media, startNumber+i, i*duration)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return segments, nil
+}
+
+func getDASHListSegments(candidate dashCandidate) ([]streamSegment, error) {
+	segments := make([]streamSegment, 0, len(candidate.segmentList.SegmentURLs)+1)
+	appendSegment := func(sourceURL, byteRange string) error {
+		segmentURL := candidate.baseURL
+		if sourceURL != "" {
+			var err error
+			segmentURL, err = candidate.baseURL.Parse(sourceURL)
+			if err != nil {
+				return err
+			}
+		}
+
+		segment := streamSegment{URL: segmentURL.String()}
+		if byteRange != "" {
+			startValue, endValue, _ := strings.Cut(byteRange, "-")
+			start, startErr := strconv.ParseInt(startValue, 10, 64)
+			end, endErr := strconv.ParseInt(endValue, 10, 64)
+			if startErr != nil || endErr != nil || end < start {
+				return fmt.Errorf("invalid DASH byte range %q", byteRange)
+			}
+			segment.RangeStart, segment.RangeLength = start, end-start+1
+		}
+
+		segments = append(segments, segment)
+		return nil
+	}
+
+	if initialization := candidate.segmentList.Initialization; initialization != nil {
+		err := appendSegment(initialization.SourceURL, initialization.Range)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, segmentURL := range candidate.segmentList.SegmentURLs {
+		err := appendSegment(segmentURL.Media, segmentURL.MediaRange)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return segments, nil
+}
+
+// mergeDASHSegmentTemplates merges segment templates from the outermost to the innermost level, attributes of inner
+// levels override the ones they inherit.
+func mergeDASHSegmentTemplates(templates ...*dashSegmentTemplate) *dashSegmentTemplate {
+	var merged *dashSegmentTemplate
+	for _, template := range templates {
+		if template == nil {
+			continue
+		}
+		if merged == nil {
+			merged = &dashSegmentTemplate{}
+		}
+
+		if template.Media != "" {
+			merged.Media = template.Media
+		}
+		if template.Initialization != "" {
+			merged.Initialization = template.Initialization
+		}
+		if template.StartNumber != "" {
+			merged.StartNumber = template.StartNumber
+		}
+		if template.Timescale != "" {
+			merged.Timescale = template.Timescale
+		}
+		if template.Duration != "" {
+			merged.Duration = template.Duration
+		}
+		if template.SegmentTimeline != nil {
+			merged.SegmentTimeline = template.SegmentTimeline
+		}
+	}
+	return merged
+}
+
+// expandDASHTemplate substitutes the identifiers of a segment template, with their optional printf width.
+func expandDASHTemplate(template, representationID string, bandwidth, number, time uint64) string {
+	// $$ is an escaped dollar sign, it is split off first so that it cannot be mistaken for an identifier boundary
+	parts := strings.Split(template, "$$")
+	for i, part := range parts {
+		parts[i] = dashTemplateIdentifierRegexp.ReplaceAllStringFunc(part, func(identifier string) string {
+			match := dashTemplateIdentifierRegexp.FindStringSubmatch(identifier)
+			format := "%d"
+			if match[2] != "" {
+				format = match[2]
+			}
+
+			switch match[1] {
+			case "RepresentationID":
+				return representationID
+			case "Bandwidth":
+				return fmt.Sprintf(format, bandwidth)
+			case "Number":
+				return fmt.Sprintf(format, number)
+			default:
+				return fmt.Sprintf(format, time)
+			}
+		})
+	}
+	return strings.Join(parts, "$")
+}
+
+func resolveDASHBaseURL(parentURL *url.URL, baseURLs []string) (*url.URL, error) {
+	if len(baseURLs) == 0 || strings.TrimSpace(baseURLs[0]) == "" {
+		return parentURL, nil
+	}
+	return parentURL.Parse(strings.TrimSpace(baseURLs[0]))
+}
+
+// getDASHPeriodDuration returns the duration of the period in seconds, 0 when it is not known.
+func getDASHPeriodDuration(mpd dashMPD, periodIndex int) (float64, error) {
+	period := mpd.Periods[periodIndex]
+	if period.Duration != "" {
+		return parseDASHDuration(period.Duration)
+	}
+
+	start, err := parseDASHDuration(period.Start)
+	if err != nil {
+		return 0, err
+	}
+
+	end := 0.0
+	if periodIndex+1 < len(mpd.Periods) && mpd.Periods[periodIndex+1].Start != "" {
+		end, err = parseDASHDuration(mpd.Periods[periodIndex+1].Start)
+	} else {
+		end, err = parseDASHDuration(mpd.MediaPresentationDuration)
+	}
+	if err != nil || end <= start {
+		return 0, err
+	}
+	return end - start, nil
+}
+
+// parseDASHDuration parses an xs:duration into seconds, an empty value is 0.
+func parseDASHDuration(value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	match := dashDurationRegexp.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, fmt.Errorf("invalid DASH duration %q", value)
+	}
+
+	seconds := 0.0
+	for i, unitSeconds := range []float64{24 * 60 * 60, 60 * 60, 60, 1} {
+		if match[i+1] == "" {
+			continue
+		}
+		unitCount, err := strconv.ParseFloat(match[i+1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid DASH duration %q", value)
+		}
+		seconds += unitCount * unitSeconds
+	}
+	return seconds, nil
+}
+
+func parseDASHUint(value string, defaultValue uint64) (uint64, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	result, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid DASH number %q", value)
+	}
+	return result, nil
+}
@@ -0,0 +1,231 @@
+package logic
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	hlsPlaylistHeader = "#EXTM3U"
+)
+
+// hlsVariant is a variant stream listed in an HLS master playlist.
+type hlsVariant struct {
+	Bandwidth uint64
+	URL       string
+}
+
+// hlsByteRange is a sub-range of a resource, Offset is -1 when the range follows the previous one.
+type hlsByteRange struct {
+	Length int64
+	Offset int64
+}
+
+// hlsPlaylist is either a master playlist, which only has variants, or a media playlist, which only has segments.
+type hlsPlaylist struct {
+	Variants []hlsVariant
+	Manifest streamManifest
+}
+
+// parseHLSPlaylist parses the subset of RFC 8216 needed to download a recorded stream: variant streams, media
+// sequence numbers, byte ranges, media initialization sections and AES-128 encryption.
+func parseHLSPlaylist(baseURL *url.URL, body string) (hlsPlaylist, error) {
+	lines := strings.Split(strings.TrimPrefix(body, "\ufeff"), "\n")
+	if strings.TrimSpace(lines[0]) != hlsPlaylistHeader {
+		return hlsPlaylist{}, errUnsupportedStreamManifest
+	}
+
+	var (
+		playlist                = hlsPlaylist{Manifest: streamManifest{Extension: ".ts"}}
+		mediaSequence           uint64
+		mediaIndex              uint64
+		key                     *streamKey
+		variant                 *hlsVariant
+		byteRange               *hlsByteRange
+		initializationSectionID string
+		nextRangeStart          = make(map[string]int64)
+	)
+	for _, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		tag, value, _ := strings.Cut(line, ":")
+		switch {
+		case line == "":
+		case tag == "#EXT-X-STREAM-INF":
+			attributes := parseHLSAttributes(value)
+			bandwidth, err := strconv.ParseUint(attributes["BANDWIDTH"], 10, 64)
+			if err != nil {
+				return hlsPlaylist{}, fmt.Errorf("invalid HLS variant bandwidth %q", attributes["BANDWIDTH"])
+			}
+			variant = &hlsVariant{Bandwidth: bandwidth}
+		case tag == "#EXT-X-MEDIA-SEQUENCE":
+			var err error
+			mediaSequence, err = strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return hlsPlaylist{}, fmt.Errorf("invalid HLS media sequence %q", value)
+			}
+		case tag == "#EXT-X-KEY":
+			attributes := parseHLSAttributes(value)
+			switch attributes["METHOD"] {
+			case "NONE":
+				key = nil
+			case "AES-128":
+				keyURL, err := baseURL.Parse(attributes["URI"])
+				if err != nil {
+					return hlsPlaylist{}, err
+				}
+				key = &streamKey{URL: keyURL.String()}
+				if iv, ok := attributes["IV"]; ok {
+					key.IV, err = parseHLSInitializationVector(iv)
+					if err != nil {
+						return hlsPlaylist{}, err
+					}
+				}
+			default:
+				return hlsPlaylist{}, fmt.Errorf("unsupported HLS encryption method %q", attributes["METHOD"])
+			}
+		case tag == "#EXT-X-BYTERANGE":
+			var err error
+			byteRange, err = parseHLSByteRange(value)
+			if err != nil {
+				return hlsPlaylist{}, err
+			}
+		case tag == "#EXT-X-MAP":
+			attributes := parseHLSAttributes(value)
+			initializationURL, err := baseURL.Parse(attributes["URI"])
+			if err != nil {
+				return hlsPlaylist{}, err
+			}
+
+			segment := streamSegment{URL: initializationURL.String(), Key: key}
+			if value, ok := attributes["BYTERANGE"]; ok {
+				initializationRange, err := parseHLSByteRange(value)
+				if err != nil {
+					return hlsPlaylist{}, err
+				}
+				segment.RangeStart, segment.RangeLength = max(initializationRange.Offset, 0), initializationRange.Length
+			}
+
+			// the same section is repeated after discontinuities, it only has to be in the output when it changes
+			sectionID := fmt.Sprintf("%s@%d-%d", segment.URL, segment.RangeStart, segment.RangeLength)
+			if sectionID != initializationSectionID {
+				initializationSectionID = sectionID
+				playlist.Manifest.Segments = append(playlist.Manifest.Segments, withHLSInitializationVector(segment, mediaSequence+mediaIndex))
+			}
+			// fragmented MP4 media instead of MPEG-TS
+			playlist.Manifest.Extension = ".mp4"
+		case strings.HasPrefix(line, "#"):
+			// other tags, like #EXTINF, do not change how segments are downloaded
+		case variant != nil:
+			variantURL, err := baseURL.Parse(line)
+			if err != nil {
+				return hlsPlaylist{}, err
+			}
+			variant.URL = variantURL.String()
+			playlist.Variants = append(playlist.Variants, *variant)
+			variant = nil
+		default:
+			segmentURL, err := baseURL.Parse(line)
+			if err != nil {
+				return hlsPlaylist{}, err
+			}
+
+			segment := streamSegment{URL: segmentURL.String(), Key: key}
+			if byteRange != nil {
+				// without an offset the range starts where the previous range of the same resource ended
+				rangeStart := byteRange.Offset
+				if rangeStart < 0 {
+					rangeStart = nextRangeStart[segment.URL]
+				}
+				segment.RangeStart, segment.RangeLength = rangeStart, byteRange.Length
+				nextRangeStart[segment.URL] = rangeStart + byteRange.Length
+				byteRange = nil
+			}
+
+			playlist.Manifest.Segments = append(playlist.Manifest.Segments, withHLSInitializationVector(segment, mediaSequence+mediaIndex))
+			mediaIndex++
+		}
+	}
+
+	if len(playlist.Variants) == 0 && len(playlist.Manifest.Segments) == 0 {
+		return hlsPlaylist{}, errStreamHasNoSegments
+	}
+
+	return playlist, nil
+}
+
+// withHLSInitializationVector sets the IV of an encrypted segment that has none to its media sequence number, as
+// RFC 8216 section 5.2 requires.
+func withHLSInitializationVector(segment streamSegment, sequenceNumber uint64) streamSegment {
+	if segment.Key == nil || segment.Key.IV != nil {
+		return segment
+	}
+
+	iv := make([]byte, streamKeySize)
+	binary.BigEndian.PutUint64(iv[8:], sequenceNumber)
+	segment.Key = &streamKey{URL: segment.Key.URL, IV: iv}
+	return segment
+}
+
+func parseHLSInitializationVector(value string) ([]byte, error) {
+	hexValue, ok := strings.CutPrefix(strings.ToLower(value), "0x")
+	if !ok {
+		return nil, fmt.Errorf("invalid HLS initialization vector %q", value)
+	}
+
+	iv, err := hex.DecodeString(hexValue)
+	if err != nil || len(iv) != streamKeySize {
+		return nil, fmt.Errorf("invalid HLS initialization vector %q", value)
+	}
+	return iv, nil
+}
+
+// parseHLSByteRange parses a <length>[@<offset>] byte range.
+func parseHLSByteRange(value string) (*hlsByteRange, error) {
+	lengthValue, offsetValue, hasOffset := strings.Cut(value, "@")
+	length, err := strconv.ParseInt(lengthValue, 10, 64)
+	if err != nil || length <= 0 {
+		return nil, fmt.Errorf("invalid HLS byte range %q", value)
+	}
+
+	offset := int64(-1)
+	if hasOffset {
+		offset, err = strconv.ParseInt(offsetValue, 10, 64)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("invalid HLS byte range %q", value)
+		}
+	}
+
+	return &hlsByteRange{Length: length, Offset: offset}, nil
+}
+
+// parseHLSAttributes parses an attribute list, quoted values keep their commas and lose their quotes.
+func parseHLSAttributes(value string) map[string]string {
+	attributes := make(map[string]string)
+	for value != "" {
+		name, rest, ok := strings.Cut(value, "=")
+		if !ok {
+			break
+		}
+
+		var attributeValue string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				attributeValue, rest = rest[1:], ""
+			} else {
+				attributeValue, rest = rest[1:end+1], rest[end+2:]
+			}
+			rest = strings.TrimPrefix(rest, ",")
+		} else {
+			attributeValue, rest, _ = strings.Cut(rest, ",")
+		}
+
+		attributes[strings.TrimSpace(name)] = attributeValue
+		value = rest
+	}
+	return attributes
+}
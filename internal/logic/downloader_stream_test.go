@@ -0,0 +1,365 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"go.uber.org/zap"
+)
+
+func TestSelectStreamVariant(t *testing.T) {
+	testCases := []struct {
+		name               string
+		bandwidths         []uint64
+		requestedBandwidth uint64
+		want               int
+	}{
+		{name: "highest by default", bandwidths: []uint64{300, 900, 600}, want: 1},
+		{name: "exact match", bandwidths: []uint64{300, 900, 600}, requestedBandwidth: 600, want: 2},
+		{name: "highest not above the requested one", bandwidths: []uint64{300, 900, 600}, requestedBandwidth: 800, want: 2},
+		{name: "lowest when all are above", bandwidths: []uint64{300, 900, 600}, requestedBandwidth: 100, want: 0},
+		{name: "single variant", bandwidths: []uint64{300}, requestedBandwidth: 100, want: 0},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := selectStreamVariant(testCase.bandwidths, testCase.requestedBandwidth)
+			if got != testCase.want {
+				t.Errorf("selectStreamVariant(%v, %d) = %d, want %d", testCase.bandwidths, testCase.requestedBandwidth, got, testCase.want)
+			}
+		})
+	}
+}
+
+// testStreamServer serves playlists, manifests, keys and segments from memory. Segments that come earlier in a
+// stream are served slower, so that parallel downloads complete them out of order.
+type testStreamServer struct {
+	server    *httptest.Server
+	resources map[string][]byte
+	delays    map[string]time.Duration
+
+	mutex          sync.Mutex
+	requestedPaths []string
+}
+
+func newTestStreamServer(t *testing.T) *testStreamServer {
+	t.Helper()
+
+	server := &testStreamServer{resources: make(map[string][]byte), delays: make(map[string]time.Duration)}
+	server.server = httptest.NewServer(http.HandlerFunc(server.serveHTTP))
+	t.Cleanup(server.server.Close)
+	return server
+}
+
+func (s *testStreamServer) getRequestedPaths() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return slices.Clone(s.requestedPaths)
+}
+
+func (s *testStreamServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	s.requestedPaths = append(s.requestedPaths, r.URL.Path)
+	s.mutex.Unlock()
+
+	content, ok := s.resources[r.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	time.Sleep(s.delays[r.URL.Path])
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+}
+
+// addSegments adds the segments of a variant numbered from firstNumber, each with its own content, and returns their
+// paths and the content they concatenate to.
+func (s *testStreamServer) addSegments(pathFormat string, firstNumber, segmentCount int) ([]string, []byte) {
+	var (
+		paths   []string
+		content []byte
+	)
+	for i := range segmentCount {
+		segmentPath := fmt.Sprintf(pathFormat, firstNumber+i)
+		segment := bytes.Repeat([]byte(segmentPath+";"), 100+i)
+		s.resources[segmentPath] = segment
+		s.delays[segmentPath] = time.Duration(segmentCount-i) * 10 * time.Millisecond
+		paths = append(paths, segmentPath)
+		content = append(content, segment...)
+	}
+	return paths, content
+}
+
+func encryptTestStreamSegment(t *testing.T, data, key, iv []byte) []byte {
+	t.Helper()
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatalf("failed to create cipher: %v", err)
+	}
+	paddingLength := aes.BlockSize - len(data)%aes.BlockSize
+	encrypted := append(slices.Clone(data), bytes.Repeat([]byte{byte(paddingLength)}, paddingLength)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, encrypted)
+	return encrypted
+}
+
+func newTestStreamDownloader(t *testing.T) (StreamDownloader, string) {
+	t.Helper()
+
+	directory := t.TempDir()
+	logger := zap.NewNop()
+	fileClient, err := file.NewLocalClient(configs.DownloadConfig{DownloadDirectory: directory}, logger)
+	if err != nil {
+		t.Fatalf("failed to create file client: %v", err)
+	}
+	return NewStreamDownloader(fileClient, logger), directory
+}
+
+// checkTestStreamDownload checks the output file, that no part file is left and that the progress counted every
+// segment.
+func checkTestStreamDownload(t *testing.T, directory, fileName string, want []byte, segmentCount int, progress *DownloadProgress) {
+	t.Helper()
+
+	checkTestDownloadedFile(t, filepath.Join(directory, fileName), want)
+	partFilePaths, err := filepath.Glob(filepath.Join(directory, "*.part*"))
+	if err != nil || len(partFilePaths) != 0 {
+		t.Errorf("part files left behind: %v, %v", partFilePaths, err)
+	}
+
+	metadata := progress.Snapshot()
+	if metadata.FileName != fileName || metadata.Size != int64(len(want)) || metadata.DownloadedByteCount != int64(len(want)) {
+		t.Errorf(
+			"FileName = %s, Size = %d, DownloadedByteCount = %d, want %s, %d, %d",
+			metadata.FileName, metadata.Size, metadata.DownloadedByteCount, fileName, len(want), len(want),
+		)
+	}
+	if len(metadata.StreamSegments) != segmentCount || slices.Contains(metadata.StreamSegments, false) {
+		t.Errorf("StreamSegments = %v, want all %d segments completed", metadata.StreamSegments, segmentCount)
+	}
+}
+
+// newTestHLSVariantServer serves a master playlist of a low, a mid and a high bandwidth variant, and returns the
+// content every variant concatenates to.
+func newTestHLSVariantServer(t *testing.T, segmentCount int) (*testStreamServer, map[string][]byte) {
+	t.Helper()
+
+	server := newTestStreamServer(t)
+	server.resources["/master.m3u8"] = []byte(strings.Join([]string{
+		"#EXTM3U",
+		`#EXT-X-STREAM-INF:BANDWIDTH=300000,RESOLUTION=640x360,CODECS="avc1.4d401e,mp4a.40.2"`,
+		"low/index.m3u8",
+		"#EXT-X-STREAM-INF:BANDWIDTH=900000,RESOLUTION=1920x1080",
+		"high/index.m3u8",
+		"#EXT-X-STREAM-INF:BANDWIDTH=600000,RESOLUTION=1280x720",
+		"mid/index.m3u8",
+	}, "\n"))
+
+	variantContents := make(map[string][]byte)
+	for _, variant := range []string{"low", "mid", "high"} {
+		segmentPaths, content := server.addSegments("/"+variant+"/segment%d.ts", 0, segmentCount)
+		playlist := []string{"#EXTM3U", "#EXT-X-TARGETDURATION:4"}
+		for _, segmentPath := range segmentPaths {
+			playlist = append(playlist, "#EXTINF:4.0,", strings.TrimPrefix(segmentPath, "/"+variant+"/"))
+		}
+		playlist = append(playlist, "#EXT-X-ENDLIST")
+		server.resources["/"+variant+"/index.m3u8"] = []byte(strings.Join(playlist, "\n"))
+		variantContents[variant] = content
+	}
+	return server, variantContents
+}
+
+func TestStreamDownloaderDownloadHLSVariants(t *testing.T) {
+	const segmentCount = 6
+
+	testCases := []struct {
+		name        string
+		bandwidth   uint64
+		wantVariant string
+	}{
+		{name: "highest bandwidth by default", wantVariant: "high"},
+		{name: "chosen bandwidth", bandwidth: 700000, wantVariant: "mid"},
+		{name: "chosen bandwidth below every variant", bandwidth: 1000, wantVariant: "low"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			server, variantContents := newTestHLSVariantServer(t, segmentCount)
+			downloader, directory := newTestStreamDownloader(t)
+
+			progress := newDownloadProgress(downloadTaskMetadata{
+				FileName:      "master.m3u8",
+				StreamOptions: &streamOptions{Bandwidth: testCase.bandwidth},
+			})
+			params := DownloadParams{URL: server.server.URL + "/master.m3u8", SegmentCount: 4}
+			err := downloader.Download(context.Background(), params, progress)
+			if err != nil {
+				t.Fatalf("Download() error = %v", err)
+			}
+			checkTestStreamDownload(t, directory, "master.ts", variantContents[testCase.wantVariant], segmentCount, progress)
+
+			for _, requestedPath := range server.getRequestedPaths() {
+				if strings.HasSuffix(requestedPath, ".ts") && !strings.HasPrefix(requestedPath, "/"+testCase.wantVariant+"/") {
+					t.Errorf("segment %s of a variant that was not selected was requested", requestedPath)
+				}
+			}
+		})
+	}
+}
+
+func TestStreamDownloaderDownloadHLSEncrypted(t *testing.T) {
+	server := newTestStreamServer(t)
+	firstKey := []byte("0123456789abcdef")
+	secondKey := []byte("fedcba9876543210")
+	explicitIV := []byte("an explicit IV!!")
+	server.resources["/keys/first.key"] = firstKey
+	server.resources["/keys/second.key"] = secondKey
+
+	const (
+		mediaSequence = 7
+		segmentCount  = 5
+	)
+	segmentPaths, content := server.addSegments("/stream/segment%d.ts", 0, segmentCount)
+	playlist := []string{
+		"#EXTM3U",
+		"#EXT-X-TARGETDURATION:4",
+		fmt.Sprintf("#EXT-X-MEDIA-SEQUENCE:%d", mediaSequence),
+		`#EXT-X-KEY:METHOD=AES-128,URI="../keys/first.key"`,
+	}
+	for i, segmentPath := range segmentPaths {
+		key := firstKey
+		// without an IV attribute the IV is the media sequence number of the segment
+		iv := make([]byte, aes.BlockSize)
+		binary.BigEndian.PutUint64(iv[8:], uint64(mediaSequence+i))
+		switch {
+		case i == 3:
+			playlist = append(playlist, fmt.Sprintf(`#EXT-X-KEY:METHOD=AES-128,URI="/keys/second.key",IV=0x%x`, explicitIV))
+			fallthrough
+		case i > 3:
+			key, iv = secondKey, explicitIV
+		}
+
+		server.resources[segmentPath] = encryptTestStreamSegment(t, server.resources[segmentPath], key, iv)
+		playlist = append(playlist, "#EXTINF:4.0,", strings.TrimPrefix(segmentPath, "/stream/"))
+	}
+	playlist = append(playlist, "#EXT-X-ENDLIST")
+	server.resources["/stream/index.m3u8"] = []byte(strings.Join(playlist, "\n"))
+
+	downloader, directory := newTestStreamDownloader(t)
+	progress := newDownloadProgress(downloadTaskMetadata{FileName: "index.m3u8"})
+	params := DownloadParams{URL: server.server.URL + "/stream/index.m3u8", SegmentCount: 3}
+	err := downloader.Download(context.Background(), params, progress)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	checkTestStreamDownload(t, directory, "index.ts", content, segmentCount, progress)
+}
+
+func TestStreamDownloaderDownloadHLSInvalidKey(t *testing.T) {
+	testCases := []struct {
+		name string
+		key  []byte
+	}{
+		{name: "short key", key: []byte("0123456789abcde")},
+		{name: "long key", key: []byte("0123456789abcdef0")},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			server := newTestStreamServer(t)
+			server.resources["/stream/segment.key"] = testCase.key
+			server.resources["/stream/segment0.ts"] = bytes.Repeat([]byte("x"), aes.BlockSize)
+			server.resources["/stream/index.m3u8"] = []byte(strings.Join([]string{
+				"#EXTM3U",
+				`#EXT-X-KEY:METHOD=AES-128,URI="segment.key"`,
+				"#EXTINF:4.0,",
+				"segment0.ts",
+				"#EXT-X-ENDLIST",
+			}, "\n"))
+
+			downloader, directory := newTestStreamDownloader(t)
+			progress := newDownloadProgress(downloadTaskMetadata{FileName: "index.m3u8"})
+			err := downloader.Download(context.Background(), DownloadParams{URL: server.server.URL + "/stream/index.m3u8"}, progress)
+			wantError := fmt.Sprintf("is %d bytes long", len(testCase.key))
+			if err == nil || !strings.Contains(err.Error(), wantError) {
+				t.Fatalf("Download() error = %v, want one saying the key %s", err, wantError)
+			}
+
+			if slices.Contains(server.getRequestedPaths(), "/stream/segment0.ts") {
+				t.Errorf("segment requested although its key is invalid")
+			}
+			if _, err := os.Stat(filepath.Join(directory, "index.ts")); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("output file created, stat error = %v", err)
+			}
+		})
+	}
+}
+
+func TestStreamDownloaderDownloadDASH(t *testing.T) {
+	server := newTestStreamServer(t)
+	server.resources["/dash/manifest.mpd"] = []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<MPD xmlns="urn:mpeg:dash:schema:mpd:2011" type="static" mediaPresentationDuration="PT24S">
+  <Period>
+    <AdaptationSet contentType="audio" mimeType="audio/mp4">
+      <SegmentTemplate media="$RepresentationID$/$Number%03d$.m4s" initialization="$RepresentationID$/init.mp4" duration="4" />
+      <Representation id="audio" bandwidth="64000" />
+    </AdaptationSet>
+    <AdaptationSet contentType="video" mimeType="video/mp4">
+      <SegmentTemplate media="$RepresentationID$/$Number%03d$.m4s" initialization="$RepresentationID$/init.mp4" duration="4" />
+      <Representation id="video-480" bandwidth="800000" />
+      <Representation id="video-1080" bandwidth="4000000" />
+      <Representation id="video-720" bandwidth="2000000" />
+    </AdaptationSet>
+  </Period>
+</MPD>`)
+
+	// an initialization section and 6 media segments of 4 seconds
+	const segmentCount = 7
+	representationContents := make(map[string][]byte)
+	for _, representationID := range []string{"audio", "video-480", "video-720", "video-1080"} {
+		initialization := []byte(representationID + " init;")
+		server.resources["/dash/"+representationID+"/init.mp4"] = initialization
+		_, content := server.addSegments("/dash/"+representationID+"/%03d.m4s", dashDefaultStartNumber, segmentCount-1)
+		representationContents[representationID] = append(initialization, content...)
+	}
+
+	testCases := []struct {
+		name               string
+		bandwidth          uint64
+		wantRepresentation string
+	}{
+		{name: "highest bandwidth video by default", wantRepresentation: "video-1080"},
+		{name: "chosen bandwidth", bandwidth: 2500000, wantRepresentation: "video-720"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			downloader, directory := newTestStreamDownloader(t)
+
+			progress := newDownloadProgress(downloadTaskMetadata{
+				FileName:      "manifest.mpd",
+				StreamOptions: &streamOptions{Bandwidth: testCase.bandwidth},
+			})
+			params := DownloadParams{URL: server.server.URL + "/dash/manifest.mpd", SegmentCount: 4}
+			err := downloader.Download(context.Background(), params, progress)
+			if err != nil {
+				t.Fatalf("Download() error = %v", err)
+			}
+			checkTestStreamDownload(t, directory, "manifest.mp4", representationContents[testCase.wantRepresentation], segmentCount, progress)
+		})
+	}
+}
@@ -0,0 +1,671 @@
+package logic
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTorrentPort              = 6881
+	defaultTorrentMaxPeerCount      = 8
+	defaultTorrentDialTimeout       = 10 * time.Second
+	torrentPeerIDPrefix             = "-GL0001-"
+	torrentMaxPipelinedRequestCount = 16
+	torrentTrackerResponseMaxSize   = 1024 * 1024
+	torrentMaxAnnounceInterval      = time.Minute
+	torrentMaxIdleAnnounceCount     = 5
+	torrentCompactPeerLength        = 6
+	torrentAnnounceEventStarted     = "started"
+	torrentAnnounceEventCompleted   = "completed"
+	defaultTorrentAnnounceInterval  = 30 * time.Second
+)
+
+var (
+	errTorrentNoTrackers    = errors.New("torrent has no HTTP tracker")
+	errTorrentNoPeers       = errors.New("no peer could provide the missing pieces of the torrent")
+	errTorrentPieceMismatch = errors.New("torrent piece failed hash verification")
+)
+
+type BitTorrentDownloader Downloader
+
+// bitTorrentDownloader downloads the torrent of a task from the peers its HTTP trackers return. Pieces are verified
+// against their SHA-1 hash before they are written, and only the pieces overlapping the selected files are downloaded.
+// Seeding is off: the downloader does not accept connections and never unchokes the peers it connects to.
+type bitTorrentDownloader struct {
+	httpClient   *http.Client
+	dialer       *net.Dialer
+	fileClient   file.Client
+	peerID       []byte
+	port         int
+	maxPeerCount int
+	logger       *zap.Logger
+}
+
+func NewBitTorrentDownloader(configs configs.DownloadConfig, fileClient file.Client, logger *zap.Logger) (BitTorrentDownloader, error) {
+	dialTimeout := defaultTorrentDialTimeout
+	if configs.TorrentConfig.DialTimeout != "" {
+		var err error
+		dialTimeout, err = configs.TorrentConfig.GetDialTimeoutDuration()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	port := configs.TorrentConfig.Port
+	if port <= 0 {
+		port = defaultTorrentPort
+	}
+
+	maxPeerCount := configs.TorrentConfig.MaxPeerCount
+	if maxPeerCount <= 0 {
+		maxPeerCount = defaultTorrentMaxPeerCount
+	}
+
+	peerID := make([]byte, torrentPeerIDLength)
+	copy(peerID, torrentPeerIDPrefix)
+	_, err := rand.Read(peerID[len(torrentPeerIDPrefix):])
+	if err != nil {
+		return nil, err
+	}
+
+	return &bitTorrentDownloader{
+		httpClient:   &http.Client{Timeout: dialTimeout * 3},
+		dialer:       &net.Dialer{Timeout: dialTimeout},
+		fileClient:   fileClient,
+		peerID:       peerID,
+		port:         port,
+		maxPeerCount: maxPeerCount,
+		logger:       logger,
+	}, nil
+}
+
+// CanResume implements Downloader.
+func (d bitTorrentDownloader) CanResume() bool {
+	return true
+}
+
+// getTorrentFileName returns the name the .torrent file of a task is stored under, next to the downloaded files.
+func getTorrentFileName(taskID uint64) string {
+	return fmt.Sprintf("%d.torrent", taskID)
+}
+
+// torrentDownload is the state shared by the peers of a running download.
+type torrentDownload struct {
+	metainfo      torrentMetainfo
+	selectedFiles []bool
+	filePaths     []string
+	progress      *DownloadProgress
+
+	lock sync.Mutex
+	// pendingPieces are the needed pieces that are neither verified nor being downloaded from a peer
+	pendingPieces       []bool
+	remainingPieceCount int
+	done                chan struct{}
+}
+
+// nextPiece assigns a pending piece the peer has to the peer.
+func (t *torrentDownload) nextPiece(hasPiece func(pieceIndex int) bool) (int, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for pieceIndex, pending := range t.pendingPieces {
+		if pending && hasPiece(pieceIndex) {
+			t.pendingPieces[pieceIndex] = false
+			return pieceIndex, true
+		}
+	}
+	return 0, false
+}
+
+// returnPiece puts back a piece whose download was interrupted.
+func (t *torrentDownload) returnPiece(pieceIndex int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.pendingPieces[pieceIndex] = true
+}
+
+func (t *torrentDownload) completePiece(pieceIndex int) {
+	t.progress.Update(func(metadata *downloadTaskMetadata) {
+		metadata.TorrentPieces[pieceIndex/8] |= 0x80 >> (pieceIndex % 8)
+	})
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.remainingPieceCount--
+	if t.remainingPieceCount == 0 {
+		close(t.done)
+	}
+}
+
+func (t *torrentDownload) getRemainingPieceCount() int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.remainingPieceCount
+}
+
+// Download implements Downloader.
+func (d bitTorrentDownloader) Download(ctx context.Context, params DownloadParams, progress *DownloadProgress) error {
+	metainfo, err := d.readTorrent(ctx, params.TaskID)
+	if err != nil {
+		return err
+	}
+
+	metadata := progress.Snapshot()
+	fileIndexes := make([]uint32, 0)
+	if metadata.TorrentOptions != nil {
+		fileIndexes = metadata.TorrentOptions.FileIndexes
+	}
+	selectedFiles, err := metainfo.getSelectedFiles(fileIndexes)
+	if err != nil {
+		return err
+	}
+
+	fileName := metadata.FileName
+	if metadata.ValidatedURL == "" {
+		// the file name derived from the magnet URL says nothing, name the download after the torrent instead
+		fileName = fmt.Sprintf("%d_%s", params.TaskID, metainfo.Name)
+	}
+
+	download := &torrentDownload{
+		metainfo:      metainfo,
+		selectedFiles: selectedFiles,
+		filePaths:     make([]string, len(metainfo.Files)),
+		progress:      progress,
+		pendingPieces: metainfo.getNeededPieces(selectedFiles),
+		done:          make(chan struct{}),
+	}
+
+	selectedFilePaths := make([]string, 0)
+	size := int64(0)
+	for fileIndex, file := range metainfo.Files {
+		download.filePaths[fileIndex] = fileName
+		if metainfo.MultiFile {
+			download.filePaths[fileIndex] = path.Join(fileName, file.Path)
+		}
+		if selectedFiles[fileIndex] {
+			selectedFilePaths = append(selectedFilePaths, file.Path)
+			size += file.Length
+		}
+	}
+
+	verifiedPieces := make([]byte, (len(metainfo.PieceHashes)+7)/8)
+	if metadata.ValidatedURL == params.URL && len(metadata.TorrentPieces) == len(verifiedPieces) &&
+		d.areTorrentFilesIntact(ctx, download) {
+		copy(verifiedPieces, metadata.TorrentPieces)
+		d.logger.With(zap.String("url", params.URL)).Info("resuming BitTorrent download")
+	}
+
+	neededPieceCount := 0
+	downloadedByteCount := int64(0)
+	for pieceIndex, needed := range download.pendingPieces {
+		if !needed {
+			continue
+		}
+		neededPieceCount++
+		if isTorrentPieceSet(verifiedPieces, pieceIndex) {
+			download.pendingPieces[pieceIndex] = false
+			downloadedByteCount += d.getSelectedPieceByteCount(download, pieceIndex)
+		} else {
+			download.remainingPieceCount++
+		}
+	}
+
+	progress.Update(func(metadata *downloadTaskMetadata) {
+		metadata.FileName = fileName
+		metadata.ValidatedURL = params.URL
+		metadata.Size = size
+		metadata.ETag = ""
+		metadata.LastModified = ""
+		metadata.Segments = nil
+		metadata.DownloadedByteCount = downloadedByteCount
+		metadata.TorrentPieces = verifiedPieces
+		metadata.TorrentPieceCount = uint32(neededPieceCount)
+		metadata.Files = nil
+		if metainfo.MultiFile {
+			metadata.Files = selectedFilePaths
+		}
+	})
+
+	err = d.createTorrentFiles(ctx, download)
+	if err != nil {
+		return err
+	}
+
+	if download.remainingPieceCount == 0 {
+		return nil
+	}
+
+	if len(metainfo.AnnounceURLs) == 0 {
+		return errTorrentNoTrackers
+	}
+
+	return d.downloadTorrentPieces(ctx, download)
+}
+
+func (d bitTorrentDownloader) readTorrent(ctx context.Context, taskID uint64) (torrentMetainfo, error) {
+	reader, err := d.fileClient.Read(ctx, getTorrentFileName(taskID))
+	if err != nil {
+		return torrentMetainfo{}, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return torrentMetainfo{}, err
+	}
+
+	return parseTorrentMetainfo(data)
+}
+
+// areTorrentFilesIntact reports whether the selected files are still there with their full size, otherwise the saved
+// progress cannot be trusted.
+func (d bitTorrentDownloader) areTorrentFilesIntact(ctx context.Context, download *torrentDownload) bool {
+	for fileIndex, file := range download.metainfo.Files {
+		if !download.selectedFiles[fileIndex] {
+			continue
+		}
+		size, err := d.fileClient.Size(ctx, download.filePaths[fileIndex])
+		if err != nil || size != file.Length {
+			return false
+		}
+	}
+	return true
+}
+
+// createTorrentFiles creates the selected files at their full size, empty files do not overlap with any piece.
+func (d bitTorrentDownloader) createTorrentFiles(ctx context.Context, download *torrentDownload) error {
+	for fileIndex, file := range download.metainfo.Files {
+		if !download.selectedFiles[fileIndex] {
+			continue
+		}
+		writer, err := d.fileClient.WriteAt(ctx, download.filePaths[fileIndex], file.Length)
+		if err != nil {
+			return err
+		}
+		err = writer.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getSelectedPieceByteCount returns how many bytes of the piece belong to selected files.
+func (d bitTorrentDownloader) getSelectedPieceByteCount(download *torrentDownload, pieceIndex int) int64 {
+	pieceStart := int64(pieceIndex) * download.metainfo.PieceLength
+	pieceEnd := pieceStart + download.metainfo.getPieceLength(pieceIndex)
+
+	byteCount := int64(0)
+	for fileIndex, file := range download.metainfo.Files {
+		if download.selectedFiles[fileIndex] {
+			byteCount += max(0, min(pieceEnd, file.Offset+file.Length)-max(pieceStart, file.Offset))
+		}
+	}
+	return byteCount
+}
+
+// writeTorrentPiece writes the parts of a verified piece that belong to selected files, it returns how many bytes
+// were written.
+func (d bitTorrentDownloader) writeTorrentPiece(ctx context.Context, download *torrentDownload, pieceIndex int, data []byte) (int64, error) {
+	pieceStart := int64(pieceIndex) * download.metainfo.PieceLength
+	pieceEnd := pieceStart + int64(len(data))
+
+	writtenByteCount := int64(0)
+	for fileIndex, file := range download.metainfo.Files {
+		start := max(pieceStart, file.Offset)
+		end := min(pieceEnd, file.Offset+file.Length)
+		if !download.selectedFiles[fileIndex] || start >= end {
+			continue
+		}
+
+		writer, err := d.fileClient.WriteAt(ctx, download.filePaths[fileIndex], file.Length)
+		if err != nil {
+			return writtenByteCount, err
+		}
+		_, err = writer.WriteAt(data[start-pieceStart:end-pieceStart], start-file.Offset)
+		closeErr := writer.Close()
+		if err != nil || closeErr != nil {
+			return writtenByteCount, errors.Join(err, closeErr)
+		}
+		writtenByteCount += end - start
+	}
+	return writtenByteCount, nil
+}
+
+// downloadTorrentPieces announces to the trackers and downloads from the peers they return, until every needed piece
+// is verified. Trackers are asked again for peers as long as the previous peers made progress.
+func (d bitTorrentDownloader) downloadTorrentPieces(ctx context.Context, download *torrentDownload) error {
+	downloadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-download.done:
+			cancel()
+		case <-downloadCtx.Done():
+		}
+	}()
+
+	event := torrentAnnounceEventStarted
+	idleAnnounceCount := 0
+	for {
+		remainingPieceCount := download.getRemainingPieceCount()
+		peerAddresses, interval, err := d.announce(downloadCtx, download, event)
+		if err != nil {
+			d.logger.With(zap.Error(err)).Warn("failed to announce to any tracker")
+		}
+		event = ""
+
+		d.downloadFromPeers(downloadCtx, download, peerAddresses)
+
+		select {
+		case <-download.done:
+			_, _, err = d.announce(context.WithoutCancel(ctx), download, torrentAnnounceEventCompleted)
+			if err != nil {
+				d.logger.With(zap.Error(err)).Debug("failed to announce torrent completion")
+			}
+			return nil
+		default:
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if download.getRemainingPieceCount() < remainingPieceCount {
+			idleAnnounceCount = 0
+		} else {
+			idleAnnounceCount++
+			if idleAnnounceCount >= torrentMaxIdleAnnounceCount {
+				return errTorrentNoPeers
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(min(interval, torrentMaxAnnounceInterval)):
+		}
+	}
+}
+
+func (d bitTorrentDownloader) downloadFromPeers(ctx context.Context, download *torrentDownload, peerAddresses []string) {
+	peerAddressChannel := make(chan string, len(peerAddresses))
+	for _, peerAddress := range peerAddresses {
+		peerAddressChannel <- peerAddress
+	}
+	close(peerAddressChannel)
+
+	var waitGroup sync.WaitGroup
+	for range min(d.maxPeerCount, len(peerAddresses)) {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for peerAddress := range peerAddressChannel {
+				if ctx.Err() != nil {
+					return
+				}
+
+				err := d.downloadFromPeer(ctx, download, peerAddress)
+				if err != nil && ctx.Err() == nil {
+					d.logger.With(zap.Error(err), zap.String("peer", peerAddress)).Debug("peer connection ended")
+				}
+			}
+		}()
+	}
+	waitGroup.Wait()
+}
+
+// torrentPieceDownload is a piece being downloaded from a peer, block by block.
+type torrentPieceDownload struct {
+	index              int
+	data               []byte
+	nextBlockOffset    int64
+	receivedBlocks     []bool
+	receivedBlockCount int
+	pendingBlockCount  int
+}
+
+func (d bitTorrentDownloader) downloadFromPeer(ctx context.Context, download *torrentDownload, peerAddress string) error {
+	conn, err := d.dialer.DialContext(ctx, "tcp", peerAddress)
+	if err != nil {
+		return err
+	}
+
+	peer := newTorrentPeerConnection(conn, len(download.metainfo.PieceHashes))
+	defer peer.Close()
+	// Close is also what interrupts blocked reads when the download is cancelled or completed
+	stopCloseOnCancel := context.AfterFunc(ctx, func() { peer.Close() })
+	defer stopCloseOnCancel()
+
+	err = peer.Handshake(download.metainfo.InfoHash, d.peerID)
+	if err != nil {
+		return err
+	}
+
+	err = peer.WriteMessage(torrentMessageInterested, nil)
+	if err != nil {
+		return err
+	}
+
+	var piece *torrentPieceDownload
+	defer func() {
+		if piece != nil {
+			download.returnPiece(piece.index)
+		}
+	}()
+
+	for {
+		if piece == nil && !peer.choked {
+			if pieceIndex, ok := download.nextPiece(peer.HasPiece); ok {
+				pieceLength := download.metainfo.getPieceLength(pieceIndex)
+				piece = &torrentPieceDownload{
+					index:          pieceIndex,
+					data:           make([]byte, pieceLength),
+					receivedBlocks: make([]bool, (pieceLength+torrentBlockSize-1)/torrentBlockSize),
+				}
+			}
+		}
+
+		if piece != nil && !peer.choked {
+			for piece.pendingBlockCount < torrentMaxPipelinedRequestCount && piece.nextBlockOffset < int64(len(piece.data)) {
+				blockLength := min(torrentBlockSize, int64(len(piece.data))-piece.nextBlockOffset)
+				err = peer.RequestBlock(piece.index, piece.nextBlockOffset, blockLength)
+				if err != nil {
+					return err
+				}
+				piece.nextBlockOffset += blockLength
+				piece.pendingBlockCount++
+			}
+		}
+
+		message, err := peer.ReadMessage()
+		if err != nil {
+			return err
+		}
+		if message == nil {
+			continue
+		}
+
+		switch message.ID {
+		case torrentMessageChoke:
+			// a choking peer discards the requests it has not served yet, the piece can go to another peer
+			if piece != nil {
+				download.returnPiece(piece.index)
+				piece = nil
+			}
+		case torrentMessagePiece:
+			if piece == nil {
+				continue
+			}
+			pieceIndex, begin, block, err := parseTorrentPieceMessage(message.Payload)
+			if err != nil {
+				return err
+			}
+			if pieceIndex != piece.index || begin%torrentBlockSize != 0 || begin+int64(len(block)) > int64(len(piece.data)) {
+				continue
+			}
+
+			blockIndex := begin / torrentBlockSize
+			if !piece.receivedBlocks[blockIndex] {
+				piece.receivedBlocks[blockIndex] = true
+				piece.receivedBlockCount++
+				copy(piece.data[begin:], block)
+			}
+			piece.pendingBlockCount--
+
+			if piece.receivedBlockCount < len(piece.receivedBlocks) {
+				continue
+			}
+
+			completedPiece := piece
+			piece = nil
+			err = d.completeTorrentPiece(ctx, download, completedPiece)
+			if err != nil {
+				download.returnPiece(completedPiece.index)
+				return err
+			}
+		}
+	}
+}
+
+func (d bitTorrentDownloader) completeTorrentPiece(ctx context.Context, download *torrentDownload, piece *torrentPieceDownload) error {
+	if sha1.Sum(piece.data) != download.metainfo.PieceHashes[piece.index] {
+		return fmt.Errorf("%w: piece %d", errTorrentPieceMismatch, piece.index)
+	}
+
+	writtenByteCount, err := d.writeTorrentPiece(ctx, download, piece.index, piece.data)
+	if err != nil {
+		return err
+	}
+
+	download.progress.AddDownloadedByteCount(writtenByteCount)
+	download.completePiece(piece.index)
+	return nil
+}
+
+// announce asks the trackers for peers, the first tracker that answers wins. It returns the peer addresses and how
+// long to wait before asking again.
+func (d bitTorrentDownloader) announce(ctx context.Context, download *torrentDownload, event string) ([]string, time.Duration, error) {
+	metadata := download.progress.Snapshot()
+	query := url.Values{}
+	query.Set("info_hash", string(download.metainfo.InfoHash[:]))
+	query.Set("peer_id", string(d.peerID))
+	query.Set("port", strconv.Itoa(d.port))
+	query.Set("uploaded", "0")
+	query.Set("downloaded", strconv.FormatInt(metadata.DownloadedByteCount, 10))
+	query.Set("left", strconv.FormatInt(max(metadata.Size-metadata.DownloadedByteCount, 0), 10))
+	query.Set("compact", "1")
+	if event != "" {
+		query.Set("event", event)
+	}
+
+	var errs []error
+	for _, announceURL := range download.metainfo.AnnounceURLs {
+		separator := "?"
+		if strings.Contains(announceURL, "?") {
+			separator = "&"
+		}
+
+		peerAddresses, interval, err := d.announceToTracker(ctx, announceURL+separator+query.Encode())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", announceURL, err))
+			continue
+		}
+		return peerAddresses, interval, nil
+	}
+	return nil, defaultTorrentAnnounceInterval, errors.Join(errs...)
+}
+
+func (d bitTorrentDownloader) announceToTracker(ctx context.Context, announceURL string) ([]string, time.Duration, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, announceURL, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	response, err := d.httpClient.Do(request)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, 0, newHTTPStatusError(response.StatusCode, "unexpected tracker response status: %s", response.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, torrentTrackerResponseMaxSize))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	value, err := decodeBencode(body)
+	if err != nil {
+		return nil, 0, err
+	}
+	trackerResponse, ok := value.(map[string]any)
+	if !ok {
+		return nil, 0, errInvalidBencode
+	}
+
+	if failureReason, ok := trackerResponse["failure reason"].(string); ok {
+		return nil, 0, fmt.Errorf("tracker failure: %s", failureReason)
+	}
+
+	interval := defaultTorrentAnnounceInterval
+	if intervalSeconds, ok := trackerResponse["interval"].(int64); ok && intervalSeconds > 0 {
+		interval = time.Duration(intervalSeconds) * time.Second
+	}
+
+	peerAddresses := make([]string, 0)
+	switch peers := trackerResponse["peers"].(type) {
+	case string:
+		// compact format from BEP 23, 4 bytes of IPv4 address followed by 2 bytes of port per peer
+		for i := 0; i+torrentCompactPeerLength <= len(peers); i += torrentCompactPeerLength {
+			ip := net.IP([]byte(peers[i : i+4]))
+			port := binary.BigEndian.Uint16([]byte(peers[i+4 : i+6]))
+			peerAddresses = append(peerAddresses, net.JoinHostPort(ip.String(), strconv.Itoa(int(port))))
+		}
+	case []any:
+		for _, value := range peers {
+			peer, ok := value.(map[string]any)
+			if !ok {
+				continue
+			}
+			ip, ipOK := peer["ip"].(string)
+			port, portOK := peer["port"].(int64)
+			if ipOK && portOK {
+				peerAddresses = append(peerAddresses, net.JoinHostPort(ip, strconv.FormatInt(port, 10)))
+			}
+		}
+	}
+
+	return peerAddresses, interval, nil
+}
+
+// countTorrentPieces returns how many pieces are set in the bitfield.
+func countTorrentPieces(bitfield []byte) uint32 {
+	count := 0
+	for _, value := range bitfield {
+		count += bits.OnesCount8(value)
+	}
+	return uint32(count)
+}
@@ -0,0 +1,348 @@
+package logic
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// bencodeMaxDepth bounds the nesting of lists and dictionaries, so that a crafted file cannot exhaust the stack
+	bencodeMaxDepth  = 64
+	torrentHashSize  = sha1.Size
+	torrentInfoKey   = "info"
+	torrentMagnetURI = "magnet:?xt=urn:btih:%s&dn=%s"
+)
+
+var (
+	errInvalidBencode          = errors.New("invalid bencoded data")
+	errInvalidTorrent          = errors.New("invalid torrent file")
+	errInvalidTorrentFileIndex = errors.New("torrent file index is out of range")
+)
+
+// bencodeDecoder decodes bencoded data into int64, string, []any and map[string]any values. It also records where
+// the info dictionary of a torrent starts and ends, since the info hash is computed over its exact bytes.
+type bencodeDecoder struct {
+	data      []byte
+	offset    int
+	depth     int
+	infoStart int
+	infoEnd   int
+}
+
+func decodeBencode(data []byte) (any, error) {
+	decoder := &bencodeDecoder{data: data}
+	value, err := decoder.decode()
+	if err != nil {
+		return nil, err
+	}
+	if decoder.offset != len(data) {
+		return nil, errInvalidBencode
+	}
+	return value, nil
+}
+
+func (d *bencodeDecoder) decode() (any, error) {
+	if d.offset >= len(d.data) {
+		return nil, errInvalidBencode
+	}
+
+	switch d.data[d.offset] {
+	case 'i':
+		end := d.indexFrom(d.offset+1, 'e')
+		if end < 0 {
+			return nil, errInvalidBencode
+		}
+		value, err := strconv.ParseInt(string(d.data[d.offset+1:end]), 10, 64)
+		if err != nil {
+			return nil, errInvalidBencode
+		}
+		d.offset = end + 1
+		return value, nil
+	case 'l':
+		return d.decodeList()
+	case 'd':
+		return d.decodeDictionary()
+	default:
+		return d.decodeString()
+	}
+}
+
+func (d *bencodeDecoder) indexFrom(start int, value byte) int {
+	for i := start; i < len(d.data); i++ {
+		if d.data[i] == value {
+			return i
+		}
+	}
+	return -1
+}
+
+func (d *bencodeDecoder) decodeString() (string, error) {
+	colon := d.indexFrom(d.offset, ':')
+	if colon < 0 {
+		return "", errInvalidBencode
+	}
+
+	length, err := strconv.Atoi(string(d.data[d.offset:colon]))
+	if err != nil || length < 0 || length > len(d.data)-colon-1 {
+		return "", errInvalidBencode
+	}
+
+	d.offset = colon + 1 + length
+	return string(d.data[colon+1 : d.offset]), nil
+}
+
+func (d *bencodeDecoder) decodeList() ([]any, error) {
+	d.depth++
+	defer func() { d.depth-- }()
+	if d.depth > bencodeMaxDepth {
+		return nil, errInvalidBencode
+	}
+
+	d.offset++
+	list := make([]any, 0)
+	for d.offset < len(d.data) && d.data[d.offset] != 'e' {
+		value, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+	}
+
+	if d.offset >= len(d.data) {
+		return nil, errInvalidBencode
+	}
+	d.offset++
+	return list, nil
+}
+
+func (d *bencodeDecoder) decodeDictionary() (map[string]any, error) {
+	d.depth++
+	defer func() { d.depth-- }()
+	if d.depth > bencodeMaxDepth {
+		return nil, errInvalidBencode
+	}
+
+	d.offset++
+	dictionary := make(map[string]any)
+	for d.offset < len(d.data) && d.data[d.offset] != 'e' {
+		key, err := d.decodeString()
+		if err != nil {
+			return nil, err
+		}
+
+		valueStart := d.offset
+		value, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		if d.depth == 1 && key == torrentInfoKey {
+			d.infoStart, d.infoEnd = valueStart, d.offset
+		}
+
+		dictionary[key] = value
+	}
+
+	if d.offset >= len(d.data) {
+		return nil, errInvalidBencode
+	}
+	d.offset++
+	return dictionary, nil
+}
+
+// torrentFile is a file of a torrent, Offset is where it starts in the concatenation of all files of the torrent.
+type torrentFile struct {
+	Path   string
+	Length int64
+	Offset int64
+}
+
+// torrentMetainfo is the content of a .torrent file, as described in BEP 3 and BEP 12.
+type torrentMetainfo struct {
+	InfoHash    [torrentHashSize]byte
+	Name        string
+	PieceLength int64
+	PieceHashes [][torrentHashSize]byte
+	// Files has a single file named after the torrent for single-file torrents
+	Files        []torrentFile
+	TotalLength  int64
+	AnnounceURLs []string
+	MultiFile    bool
+}
+
+func parseTorrentMetainfo(data []byte) (torrentMetainfo, error) {
+	decoder := &bencodeDecoder{data: data}
+	value, err := decoder.decode()
+	if err != nil {
+		return torrentMetainfo{}, err
+	}
+
+	root, ok := value.(map[string]any)
+	if !ok || decoder.infoEnd == 0 {
+		return torrentMetainfo{}, errInvalidTorrent
+	}
+	info, ok := root[torrentInfoKey].(map[string]any)
+	if !ok {
+		return torrentMetainfo{}, errInvalidTorrent
+	}
+
+	metainfo := torrentMetainfo{
+		InfoHash:     sha1.Sum(data[decoder.infoStart:decoder.infoEnd]),
+		AnnounceURLs: getTorrentAnnounceURLs(root),
+	}
+
+	metainfo.Name, ok = info["name"].(string)
+	if !ok || !isSafePathElement(metainfo.Name) {
+		return torrentMetainfo{}, fmt.Errorf("%w: missing or unsafe name", errInvalidTorrent)
+	}
+
+	metainfo.PieceLength, ok = info["piece length"].(int64)
+	if !ok || metainfo.PieceLength <= 0 {
+		return torrentMetainfo{}, fmt.Errorf("%w: invalid piece length", errInvalidTorrent)
+	}
+
+	pieces, ok := info["pieces"].(string)
+	if !ok || len(pieces)%torrentHashSize != 0 {
+		return torrentMetainfo{}, fmt.Errorf("%w: invalid piece hashes", errInvalidTorrent)
+	}
+	for i := 0; i < len(pieces); i += torrentHashSize {
+		metainfo.PieceHashes = append(metainfo.PieceHashes, [torrentHashSize]byte([]byte(pieces[i:i+torrentHashSize])))
+	}
+
+	if length, ok := info["length"].(int64); ok {
+		if length < 0 {
+			return torrentMetainfo{}, fmt.Errorf("%w: invalid length", errInvalidTorrent)
+		}
+		metainfo.Files = []torrentFile{{Path: metainfo.Name, Length: length}}
+	} else {
+		files, ok := info["files"].([]any)
+		if !ok || len(files) == 0 {
+			return torrentMetainfo{}, fmt.Errorf("%w: neither length nor files", errInvalidTorrent)
+		}
+		metainfo.MultiFile = true
+
+		for _, value := range files {
+			fileInfo, ok := value.(map[string]any)
+			if !ok {
+				return torrentMetainfo{}, errInvalidTorrent
+			}
+			length, ok := fileInfo["length"].(int64)
+			if !ok || length < 0 {
+				return torrentMetainfo{}, fmt.Errorf("%w: invalid file length", errInvalidTorrent)
+			}
+			filePath, err := getTorrentFilePath(fileInfo["path"])
+			if err != nil {
+				return torrentMetainfo{}, err
+			}
+			metainfo.Files = append(metainfo.Files, torrentFile{Path: filePath, Length: length})
+		}
+	}
+
+	for i := range metainfo.Files {
+		metainfo.Files[i].Offset = metainfo.TotalLength
+		metainfo.TotalLength += metainfo.Files[i].Length
+	}
+
+	expectedPieceCount := (metainfo.TotalLength + metainfo.PieceLength - 1) / metainfo.PieceLength
+	if int64(len(metainfo.PieceHashes)) != expectedPieceCount {
+		return torrentMetainfo{}, fmt.Errorf("%w: %d piece hashes for %d pieces", errInvalidTorrent, len(metainfo.PieceHashes), expectedPieceCount)
+	}
+
+	return metainfo, nil
+}
+
+// getTorrentAnnounceURLs returns the HTTP trackers of the torrent, the announce-list tiers first.
+func getTorrentAnnounceURLs(root map[string]any) []string {
+	announceURLs := make([]string, 0)
+	seen := make(map[string]bool)
+	addAnnounceURL := func(value any) {
+		announceURL, ok := value.(string)
+		if !ok || seen[announceURL] {
+			return
+		}
+		if !strings.HasPrefix(announceURL, "http://") && !strings.HasPrefix(announceURL, "https://") {
+			return
+		}
+		seen[announceURL] = true
+		announceURLs = append(announceURLs, announceURL)
+	}
+
+	if tiers, ok := root["announce-list"].([]any); ok {
+		for _, tier := range tiers {
+			if tierURLs, ok := tier.([]any); ok {
+				for _, announceURL := range tierURLs {
+					addAnnounceURL(announceURL)
+				}
+			}
+		}
+	}
+	addAnnounceURL(root["announce"])
+	return announceURLs
+}
+
+// getTorrentFilePath joins the path elements of a file of a multi-file torrent, rejecting elements that would escape
+// the torrent directory.
+func getTorrentFilePath(value any) (string, error) {
+	elements, ok := value.([]any)
+	if !ok || len(elements) == 0 {
+		return "", fmt.Errorf("%w: invalid file path", errInvalidTorrent)
+	}
+
+	pathElements := make([]string, 0, len(elements))
+	for _, element := range elements {
+		pathElement, ok := element.(string)
+		if !ok || !isSafePathElement(pathElement) {
+			return "", fmt.Errorf("%w: unsafe file path", errInvalidTorrent)
+		}
+		pathElements = append(pathElements, pathElement)
+	}
+	return path.Join(pathElements...), nil
+}
+
+// getPieceLength returns the length of the piece, the last piece is usually shorter.
+func (m torrentMetainfo) getPieceLength(pieceIndex int) int64 {
+	return min(m.PieceLength, m.TotalLength-int64(pieceIndex)*m.PieceLength)
+}
+
+// getMagnetURI returns a magnet link for the torrent, it is used as the URL of BitTorrent download tasks.
+func (m torrentMetainfo) getMagnetURI() string {
+	return fmt.Sprintf(torrentMagnetURI, hex.EncodeToString(m.InfoHash[:]), url.QueryEscape(m.Name))
+}
+
+// getSelectedFiles returns the indexes of the files to download, all of them when fileIndexes is empty.
+func (m torrentMetainfo) getSelectedFiles(fileIndexes []uint32) ([]bool, error) {
+	selectedFiles := make([]bool, len(m.Files))
+	for i := range selectedFiles {
+		selectedFiles[i] = len(fileIndexes) == 0
+	}
+
+	for _, fileIndex := range fileIndexes {
+		if int(fileIndex) >= len(m.Files) {
+			return nil, errInvalidTorrentFileIndex
+		}
+		selectedFiles[fileIndex] = true
+	}
+	return selectedFiles, nil
+}
+
+// getNeededPieces returns which pieces overlap with the selected files.
+func (m torrentMetainfo) getNeededPieces(selectedFiles []bool) []bool {
+	neededPieces := make([]bool, len(m.PieceHashes))
+	for fileIndex, file := range m.Files {
+		if !selectedFiles[fileIndex] || file.Length == 0 {
+			continue
+		}
+
+		firstPiece := file.Offset / m.PieceLength
+		lastPiece := (file.Offset + file.Length - 1) / m.PieceLength
+		for pieceIndex := firstPiece; pieceIndex <= lastPiece; pieceIndex++ {
+			neededPieces[pieceIndex] = true
+		}
+	}
+	return neededPieces
+}
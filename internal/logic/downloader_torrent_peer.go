@@ -0,0 +1,194 @@
+package logic
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+const (
+	torrentProtocolName     = "BitTorrent protocol"
+	torrentHandshakeLength  = 1 + len(torrentProtocolName) + 8 + torrentHashSize + torrentPeerIDLength
+	torrentPeerIDLength     = 20
+	torrentBlockSize        = 16 * 1024
+	torrentPeerReadTimeout  = 2 * time.Minute
+	torrentPeerWriteTimeout = 30 * time.Second
+	// torrentMaxMessageLength leaves room for a block or the bitfield of a torrent with a few million pieces
+	torrentMaxMessageLength = 1024 * 1024
+)
+
+// Peer wire protocol message IDs from BEP 3.
+const (
+	torrentMessageChoke         = 0
+	torrentMessageUnchoke       = 1
+	torrentMessageInterested    = 2
+	torrentMessageNotInterested = 3
+	torrentMessageHave          = 4
+	torrentMessageBitfield      = 5
+	torrentMessageRequest       = 6
+	torrentMessagePiece         = 7
+	torrentMessageCancel        = 8
+)
+
+var (
+	errTorrentInvalidHandshake = errors.New("invalid BitTorrent handshake")
+	errTorrentInvalidMessage   = errors.New("invalid BitTorrent peer message")
+)
+
+// torrentMessage is a peer wire protocol message, a nil message is a keep-alive.
+type torrentMessage struct {
+	ID      byte
+	Payload []byte
+}
+
+// torrentPeerConnection is a connection to a peer that only downloads. It never unchokes the peer, so the peer never
+// requests anything from it.
+type torrentPeerConnection struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	// pieces is the bitfield of the pieces the peer has
+	pieces []byte
+	choked bool
+}
+
+func newTorrentPeerConnection(conn net.Conn, pieceCount int) *torrentPeerConnection {
+	return &torrentPeerConnection{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+		pieces: make([]byte, (pieceCount+7)/8),
+		choked: true,
+	}
+}
+
+func (c *torrentPeerConnection) Close() error {
+	return c.conn.Close()
+}
+
+func (c *torrentPeerConnection) Handshake(infoHash [torrentHashSize]byte, peerID []byte) error {
+	handshake := make([]byte, 0, torrentHandshakeLength)
+	handshake = append(handshake, byte(len(torrentProtocolName)))
+	handshake = append(handshake, torrentProtocolName...)
+	handshake = append(handshake, make([]byte, 8)...)
+	handshake = append(handshake, infoHash[:]...)
+	handshake = append(handshake, peerID...)
+
+	err := c.conn.SetWriteDeadline(time.Now().Add(torrentPeerWriteTimeout))
+	if err != nil {
+		return err
+	}
+	_, err = c.conn.Write(handshake)
+	if err != nil {
+		return err
+	}
+
+	err = c.conn.SetReadDeadline(time.Now().Add(torrentPeerReadTimeout))
+	if err != nil {
+		return err
+	}
+	response := make([]byte, torrentHandshakeLength)
+	_, err = io.ReadFull(c.reader, response)
+	if err != nil {
+		return err
+	}
+
+	if response[0] != byte(len(torrentProtocolName)) || string(response[1:1+len(torrentProtocolName)]) != torrentProtocolName {
+		return errTorrentInvalidHandshake
+	}
+	infoHashStart := 1 + len(torrentProtocolName) + 8
+	if !bytes.Equal(response[infoHashStart:infoHashStart+torrentHashSize], infoHash[:]) {
+		return fmt.Errorf("%w: peer serves another torrent", errTorrentInvalidHandshake)
+	}
+
+	return nil
+}
+
+func (c *torrentPeerConnection) ReadMessage() (*torrentMessage, error) {
+	err := c.conn.SetReadDeadline(time.Now().Add(torrentPeerReadTimeout))
+	if err != nil {
+		return nil, err
+	}
+
+	lengthBytes := make([]byte, 4)
+	_, err = io.ReadFull(c.reader, lengthBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	length := binary.BigEndian.Uint32(lengthBytes)
+	if length == 0 {
+		return nil, nil
+	}
+	if length > torrentMaxMessageLength {
+		return nil, errTorrentInvalidMessage
+	}
+
+	data := make([]byte, length)
+	_, err = io.ReadFull(c.reader, data)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &torrentMessage{ID: data[0], Payload: data[1:]}
+	switch message.ID {
+	case torrentMessageChoke:
+		c.choked = true
+	case torrentMessageUnchoke:
+		c.choked = false
+	case torrentMessageHave:
+		if len(message.Payload) != 4 {
+			return nil, errTorrentInvalidMessage
+		}
+		pieceIndex := int(binary.BigEndian.Uint32(message.Payload))
+		if pieceIndex/8 < len(c.pieces) {
+			c.pieces[pieceIndex/8] |= 0x80 >> (pieceIndex % 8)
+		}
+	case torrentMessageBitfield:
+		if len(message.Payload) != len(c.pieces) {
+			return nil, errTorrentInvalidMessage
+		}
+		copy(c.pieces, message.Payload)
+	}
+	return message, nil
+}
+
+func (c *torrentPeerConnection) WriteMessage(id byte, payload []byte) error {
+	message := make([]byte, 0, 5+len(payload))
+	message = binary.BigEndian.AppendUint32(message, uint32(1+len(payload)))
+	message = append(message, id)
+	message = append(message, payload...)
+
+	err := c.conn.SetWriteDeadline(time.Now().Add(torrentPeerWriteTimeout))
+	if err != nil {
+		return err
+	}
+	_, err = c.conn.Write(message)
+	return err
+}
+
+func (c *torrentPeerConnection) RequestBlock(pieceIndex int, begin, length int64) error {
+	payload := binary.BigEndian.AppendUint32(nil, uint32(pieceIndex))
+	payload = binary.BigEndian.AppendUint32(payload, uint32(begin))
+	payload = binary.BigEndian.AppendUint32(payload, uint32(length))
+	return c.WriteMessage(torrentMessageRequest, payload)
+}
+
+func (c *torrentPeerConnection) HasPiece(pieceIndex int) bool {
+	return isTorrentPieceSet(c.pieces, pieceIndex)
+}
+
+func isTorrentPieceSet(bitfield []byte, pieceIndex int) bool {
+	return pieceIndex/8 < len(bitfield) && bitfield[pieceIndex/8]&(0x80>>(pieceIndex%8)) != 0
+}
+
+// parseTorrentPieceMessage returns the piece index, the offset in the piece and the data of a piece message.
+func parseTorrentPieceMessage(payload []byte) (int, int64, []byte, error) {
+	if len(payload) < 8 {
+		return 0, 0, nil, errTorrentInvalidMessage
+	}
+	return int(binary.BigEndian.Uint32(payload)), int64(binary.BigEndian.Uint32(payload[4:])), payload[8:], nil
+}
@@ -0,0 +1,375 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"go.uber.org/zap"
+)
+
+const (
+	testTorrentTaskID      = 7
+	testTorrentPieceLength = 2 * torrentBlockSize
+)
+
+// encodeTestBencode encodes ints, strings, lists and dictionaries, the keys of dictionaries sorted as BEP 3 requires.
+func encodeTestBencode(value any) string {
+	switch value := value.(type) {
+	case int:
+		return fmt.Sprintf("i%de", value)
+	case string:
+		return fmt.Sprintf("%d:%s", len(value), value)
+	case []any:
+		var builder strings.Builder
+		builder.WriteString("l")
+		for _, element := range value {
+			builder.WriteString(encodeTestBencode(element))
+		}
+		builder.WriteString("e")
+		return builder.String()
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		var builder strings.Builder
+		builder.WriteString("d")
+		for _, key := range keys {
+			builder.WriteString(encodeTestBencode(key))
+			builder.WriteString(encodeTestBencode(value[key]))
+		}
+		builder.WriteString("e")
+		return builder.String()
+	default:
+		panic(fmt.Sprintf("cannot bencode %T", value))
+	}
+}
+
+type testTorrentFile struct {
+	name    string
+	content []byte
+}
+
+// testTorrent is a multi-file torrent with the data of its files laid out back to back.
+type testTorrent struct {
+	files    []testTorrentFile
+	data     []byte
+	info     map[string]any
+	infoHash [torrentHashSize]byte
+}
+
+func newTestTorrent(files []testTorrentFile) testTorrent {
+	torrent := testTorrent{files: files}
+	fileList := make([]any, 0, len(files))
+	for _, torrentFile := range files {
+		torrent.data = append(torrent.data, torrentFile.content...)
+		fileList = append(fileList, map[string]any{"length": len(torrentFile.content), "path": []any{torrentFile.name}})
+	}
+
+	var pieceHashes strings.Builder
+	for offset := 0; offset < len(torrent.data); offset += testTorrentPieceLength {
+		pieceHash := sha1.Sum(torrent.data[offset:min(offset+testTorrentPieceLength, len(torrent.data))])
+		pieceHashes.Write(pieceHash[:])
+	}
+
+	torrent.info = map[string]any{
+		"name":         "multi",
+		"piece length": testTorrentPieceLength,
+		"pieces":       pieceHashes.String(),
+		"files":        fileList,
+	}
+	torrent.infoHash = sha1.Sum([]byte(encodeTestBencode(torrent.info)))
+	return torrent
+}
+
+func (t testTorrent) getPieceCount() int {
+	return (len(t.data) + testTorrentPieceLength - 1) / testTorrentPieceLength
+}
+
+func (t testTorrent) getMetainfo(announceURL string) []byte {
+	return []byte(encodeTestBencode(map[string]any{"announce": announceURL, "info": t.info}))
+}
+
+// testTorrentSeeder serves every piece of a torrent to whoever connects, flipping a bit of every block it sends when
+// it is corrupt.
+type testTorrentSeeder struct {
+	listener         net.Listener
+	torrent          testTorrent
+	corrupt          bool
+	servedBlockCount atomic.Int64
+	connectionCount  atomic.Int64
+}
+
+func newTestTorrentSeeder(t *testing.T, torrent testTorrent, corrupt bool) *testTorrentSeeder {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	seeder := &testTorrentSeeder{listener: listener, torrent: torrent, corrupt: corrupt}
+	go seeder.serve()
+	t.Cleanup(func() { listener.Close() })
+	return seeder
+}
+
+func (s *testTorrentSeeder) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.connectionCount.Add(1)
+		go s.servePeer(conn)
+	}
+}
+
+func (s *testTorrentSeeder) servePeer(conn net.Conn) {
+	pieceCount := s.torrent.getPieceCount()
+	peer := newTorrentPeerConnection(conn, pieceCount)
+	defer peer.Close()
+
+	peerID := []byte("-TS0001-000000000000")
+	if peer.Handshake(s.torrent.infoHash, peerID) != nil {
+		return
+	}
+
+	bitfield := make([]byte, (pieceCount+7)/8)
+	for pieceIndex := range pieceCount {
+		bitfield[pieceIndex/8] |= 0x80 >> (pieceIndex % 8)
+	}
+	if peer.WriteMessage(torrentMessageBitfield, bitfield) != nil {
+		return
+	}
+
+	for {
+		message, err := peer.ReadMessage()
+		if err != nil {
+			return
+		}
+		if message == nil {
+			continue
+		}
+
+		switch message.ID {
+		case torrentMessageInterested:
+			err = peer.WriteMessage(torrentMessageUnchoke, nil)
+		case torrentMessageRequest:
+			pieceIndex := binary.BigEndian.Uint32(message.Payload)
+			begin := binary.BigEndian.Uint32(message.Payload[4:])
+			length := binary.BigEndian.Uint32(message.Payload[8:])
+			offset := int(pieceIndex)*testTorrentPieceLength + int(begin)
+
+			block := slices.Clone(s.torrent.data[offset : offset+int(length)])
+			if s.corrupt {
+				block[0] ^= 1
+			}
+			s.servedBlockCount.Add(1)
+			err = peer.WriteMessage(torrentMessagePiece, append(message.Payload[:8:8], block...))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// testTorrentTracker answers announces with the peers returned for the number of the announce, counting from zero.
+type testTorrentTracker struct {
+	server *httptest.Server
+
+	mutex  sync.Mutex
+	events []string
+}
+
+func newTestTorrentTracker(t *testing.T, torrent testTorrent, getPeers func(announceIndex int) []*testTorrentSeeder) *testTorrentTracker {
+	t.Helper()
+
+	tracker := &testTorrentTracker{}
+	tracker.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("info_hash") != string(torrent.infoHash[:]) {
+			fmt.Fprint(w, encodeTestBencode(map[string]any{"failure reason": "unknown torrent"}))
+			return
+		}
+
+		tracker.mutex.Lock()
+		announceIndex := len(tracker.events)
+		tracker.events = append(tracker.events, r.URL.Query().Get("event"))
+		tracker.mutex.Unlock()
+
+		var peers strings.Builder
+		for _, seeder := range getPeers(announceIndex) {
+			address := seeder.listener.Addr().(*net.TCPAddr)
+			peers.Write(address.IP.To4())
+			peers.Write(binary.BigEndian.AppendUint16(nil, uint16(address.Port)))
+		}
+		fmt.Fprint(w, encodeTestBencode(map[string]any{"interval": 1, "peers": peers.String()}))
+	}))
+	t.Cleanup(tracker.server.Close)
+	return tracker
+}
+
+func (t *testTorrentTracker) getEvents() []string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return slices.Clone(t.events)
+}
+
+func newTestTorrentFiles() []testTorrentFile {
+	files := []testTorrentFile{
+		{name: "a.bin", content: make([]byte, 50000)},
+		{name: "empty", content: nil},
+		{name: "b.bin", content: make([]byte, 70001)},
+	}
+	for fileIndex, torrentFile := range files {
+		for i := range torrentFile.content {
+			torrentFile.content[i] = byte(i*7 + fileIndex)
+		}
+	}
+	return files
+}
+
+func newTestBitTorrentDownloader(t *testing.T, metainfo []byte) (BitTorrentDownloader, string) {
+	t.Helper()
+
+	directory := t.TempDir()
+	logger := zap.NewNop()
+	downloadConfig := configs.DownloadConfig{DownloadDirectory: directory}
+	fileClient, err := file.NewLocalClient(downloadConfig, logger)
+	if err != nil {
+		t.Fatalf("failed to create file client: %v", err)
+	}
+
+	err = os.WriteFile(filepath.Join(directory, getTorrentFileName(testTorrentTaskID)), metainfo, 0o644)
+	if err != nil {
+		t.Fatalf("failed to write torrent file: %v", err)
+	}
+
+	downloader, err := NewBitTorrentDownloader(downloadConfig, fileClient, logger)
+	if err != nil {
+		t.Fatalf("failed to create BitTorrent downloader: %v", err)
+	}
+	return downloader, directory
+}
+
+func downloadTestTorrent(t *testing.T, torrent testTorrent, tracker *testTorrentTracker) (downloadTaskMetadata, string) {
+	t.Helper()
+
+	metainfo := torrent.getMetainfo(tracker.server.URL + "/announce")
+	downloader, directory := newTestBitTorrentDownloader(t, metainfo)
+	parsedMetainfo, err := parseTorrentMetainfo(metainfo)
+	if err != nil {
+		t.Fatalf("parseTorrentMetainfo() error = %v", err)
+	}
+
+	progress := newDownloadProgress(downloadTaskMetadata{FileName: "download"})
+	err = downloader.Download(
+		context.Background(),
+		DownloadParams{TaskID: testTorrentTaskID, URL: parsedMetainfo.getMagnetURI()},
+		progress,
+	)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	return progress.Snapshot(), directory
+}
+
+func checkTestTorrentDownload(t *testing.T, torrent testTorrent, metadata downloadTaskMetadata, directory string) {
+	t.Helper()
+
+	if want := fmt.Sprintf("%d_multi", testTorrentTaskID); metadata.FileName != want {
+		t.Errorf("FileName = %q, want %q", metadata.FileName, want)
+	}
+	for _, torrentFile := range torrent.files {
+		content, err := os.ReadFile(filepath.Join(directory, metadata.FileName, torrentFile.name))
+		if err != nil {
+			t.Fatalf("failed to read downloaded file %s: %v", torrentFile.name, err)
+		}
+		if !bytes.Equal(content, torrentFile.content) {
+			t.Errorf("downloaded file %s differs from the torrent", torrentFile.name)
+		}
+	}
+
+	pieceCount := torrent.getPieceCount()
+	if metadata.TorrentPieceCount != uint32(pieceCount) || countTorrentPieces(metadata.TorrentPieces) != uint32(pieceCount) {
+		t.Errorf(
+			"verified %d of %d pieces, want all %d",
+			countTorrentPieces(metadata.TorrentPieces), metadata.TorrentPieceCount, pieceCount,
+		)
+	}
+	if metadata.Size != int64(len(torrent.data)) || metadata.DownloadedByteCount != int64(len(torrent.data)) {
+		t.Errorf(
+			"Size = %d, DownloadedByteCount = %d, want both %d",
+			metadata.Size, metadata.DownloadedByteCount, len(torrent.data),
+		)
+	}
+}
+
+func TestBitTorrentDownloaderDownload(t *testing.T) {
+	torrent := newTestTorrent(newTestTorrentFiles())
+	seeder := newTestTorrentSeeder(t, torrent, false)
+	tracker := newTestTorrentTracker(t, torrent, func(int) []*testTorrentSeeder {
+		return []*testTorrentSeeder{seeder}
+	})
+
+	metadata, directory := downloadTestTorrent(t, torrent, tracker)
+	checkTestTorrentDownload(t, torrent, metadata, directory)
+
+	blockCount := int64(0)
+	for offset := 0; offset < len(torrent.data); offset += testTorrentPieceLength {
+		pieceLength := min(testTorrentPieceLength, len(torrent.data)-offset)
+		blockCount += int64((pieceLength + torrentBlockSize - 1) / torrentBlockSize)
+	}
+	if servedBlockCount := seeder.servedBlockCount.Load(); servedBlockCount != blockCount {
+		t.Errorf("seeder served %d blocks, want each of the %d blocks once", servedBlockCount, blockCount)
+	}
+
+	events := tracker.getEvents()
+	if len(events) < 2 || events[0] != torrentAnnounceEventStarted || events[len(events)-1] != torrentAnnounceEventCompleted {
+		t.Errorf("announce events = %q, want started first and completed last", events)
+	}
+}
+
+func TestBitTorrentDownloaderDownloadRejectsCorruptPiece(t *testing.T) {
+	torrent := newTestTorrent(newTestTorrentFiles())
+	corruptSeeder := newTestTorrentSeeder(t, torrent, true)
+	seeder := newTestTorrentSeeder(t, torrent, false)
+	// the corrupt seeder is the only peer of the first announce, so its pieces are certainly tried before any other
+	tracker := newTestTorrentTracker(t, torrent, func(announceIndex int) []*testTorrentSeeder {
+		if announceIndex == 0 {
+			return []*testTorrentSeeder{corruptSeeder}
+		}
+		return []*testTorrentSeeder{seeder}
+	})
+
+	metadata, directory := downloadTestTorrent(t, torrent, tracker)
+	checkTestTorrentDownload(t, torrent, metadata, directory)
+
+	if corruptSeeder.servedBlockCount.Load() == 0 {
+		t.Fatalf("corrupt seeder served no block")
+	}
+	// a corrupt piece ends the connection to the peer that sent it, which is then left for the next announce
+	if connectionCount := corruptSeeder.connectionCount.Load(); connectionCount != 1 {
+		t.Errorf("corrupt seeder got %d connections, want 1", connectionCount)
+	}
+	if seeder.servedBlockCount.Load() == 0 {
+		t.Errorf("pieces rejected from the corrupt seeder were not downloaded again from the other seeder")
+	}
+}
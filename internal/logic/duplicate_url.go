@@ -0,0 +1,298 @@
+package logic
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/database"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultFreshnessCheckTimeout is short as the check runs while the task is created, a slow origin only means the
+	// URL is downloaded again
+	defaultFreshnessCheckTimeout = 2 * time.Second
+	// maxDuplicateDownloadTaskCount bounds the completed tasks of a URL looked at for one that can be reused
+	maxDuplicateDownloadTaskCount = 20
+)
+
+// duplicateURLResolver finds a completed download task of the same URL whose file a new task can reuse instead of
+// downloading it again. Only HTTP downloads stored as blobs are reused, and only once a conditional request with the
+// validators recorded by the download shows the origin still serves the same file.
+type duplicateURLResolver struct {
+	downloadTaskDataAccessor database.DownloadTaskDataAccessor
+	blobDataAccessor         database.BlobDataAccessor
+	fileClient               file.Client
+	httpClient               *http.Client
+	reuseAcrossAccounts      bool
+	logger                   *zap.Logger
+}
+
+func newDuplicateURLResolver(
+	configs configs.DuplicateURLConfig,
+	downloadTaskDataAccessor database.DownloadTaskDataAccessor,
+	blobDataAccessor database.BlobDataAccessor,
+	fileClient file.Client,
+	logger *zap.Logger,
+) (duplicateURLResolver, error) {
+	freshnessCheckTimeout := defaultFreshnessCheckTimeout
+	if configs.FreshnessCheckTimeout != "" {
+		var err error
+		freshnessCheckTimeout, err = configs.GetFreshnessCheckTimeoutDuration()
+		if err != nil {
+			return duplicateURLResolver{}, err
+		}
+	}
+
+	return duplicateURLResolver{
+		downloadTaskDataAccessor: downloadTaskDataAccessor,
+		blobDataAccessor:         blobDataAccessor,
+		fileClient:               fileClient,
+		httpClient:               &http.Client{Timeout: freshnessCheckTimeout},
+		reuseAcrossAccounts:      configs.ReuseAcrossAccounts,
+		logger:                   logger,
+	}, nil
+}
+
+// canReuseDownload reports whether a task created with params may be completed with an existing file. Tasks that
+// start later, that are checked or extracted in ways the existing file was not, that keep versions of their own file,
+// or that crawl, are always downloaded.
+func canReuseDownload(params CreateDownloadTaskParams, expectedDigest *digest) bool {
+	if params.DuplicateURLMode != go_load.DuplicateURLMode_AskOnDuplicateURL &&
+		params.DuplicateURLMode != go_load.DuplicateURLMode_ReuseDuplicateURL {
+		return false
+	}
+
+	return params.DownloadType == go_load.DownloadType_HTTP &&
+		params.CronExpression == "" &&
+		!params.StartAt.After(time.Now()) &&
+		params.PieceDigests == nil &&
+		!params.ExtractArchive &&
+		params.WatchOptions == nil &&
+		params.CrawlOptions == nil &&
+		(expectedDigest == nil || expectedDigest.Algorithm == go_load.DigestAlgorithm_SHA256)
+}
+
+// findReusableDownloadTask returns the newest completed task of the URL the account may reuse the file of, provided
+// the origin did not change the file since it was downloaded.
+func (r duplicateURLResolver) findReusableDownloadTask(
+	ctx context.Context,
+	accountID uint64,
+	downloadURL string,
+	expectedSize uint64,
+	expectedDigest *digest,
+) (database.DownloadTask, downloadTaskMetadata, bool) {
+	logger := r.logger.With(zap.Uint64("accountID", accountID), zap.String("url", downloadURL))
+
+	tasks, err := r.downloadTaskDataAccessor.GetDownloadTasksByURLAndStatus(
+		ctx, downloadURL, uint16(go_load.DownloadStatus_Success), maxDuplicateDownloadTaskCount)
+	if err != nil {
+		logger.With(zap.Error(err)).Warn("failed to get completed download tasks of URL, downloading it")
+		return database.DownloadTask{}, downloadTaskMetadata{}, false
+	}
+
+	for _, task := range tasks {
+		if task.OfAccountID != accountID && !r.reuseAcrossAccounts {
+			continue
+		}
+
+		metadata, ok := getReusableDownloadTaskMetadata(task, expectedSize, expectedDigest)
+		if !ok {
+			continue
+		}
+
+		// only the newest download is checked, older ones are not fresher
+		unchanged, err := r.isRemoteFileUnchanged(ctx, downloadURL, metadata)
+		if err != nil {
+			logger.With(zap.Error(err)).Info("failed to check whether the remote file changed, downloading it")
+			return database.DownloadTask{}, downloadTaskMetadata{}, false
+		}
+		if !unchanged {
+			logger.With(zap.Uint64("duplicateTaskID", task.ID)).Info("remote file changed since it was downloaded, downloading it")
+			return database.DownloadTask{}, downloadTaskMetadata{}, false
+		}
+
+		logger.With(zap.Uint64("duplicateTaskID", task.ID)).Info("found reusable download of URL")
+		return task, metadata, true
+	}
+
+	return database.DownloadTask{}, downloadTaskMetadata{}, false
+}
+
+// getReusableDownloadTaskMetadata returns the metadata of a completed task if its file can be given to a new task of
+// the same URL. The file has to be what the URL served as is, with the validators of the response it came from.
+func getReusableDownloadTaskMetadata(
+	task database.DownloadTask,
+	expectedSize uint64,
+	expectedDigest *digest,
+) (downloadTaskMetadata, bool) {
+	if task.DownloadType != uint16(go_load.DownloadType_HTTP) || task.BlobSHA256 == "" {
+		return downloadTaskMetadata{}, false
+	}
+
+	metadata, err := parseDownloadTaskMetadata(task.Metadata)
+	if err != nil {
+		return downloadTaskMetadata{}, false
+	}
+
+	// a file downloaded from a mirror carries the validators of the mirror
+	if len(metadata.Files) > 0 || len(metadata.PostDownloadStepResults) > 0 || metadata.ValidatedURL != task.URL ||
+		(metadata.ETag == "" && metadata.LastModified == "") {
+		return downloadTaskMetadata{}, false
+	}
+
+	if expectedSize > 0 && metadata.Size != int64(expectedSize) {
+		return downloadTaskMetadata{}, false
+	}
+
+	if expectedDigest != nil && expectedDigest.Value != task.BlobSHA256 {
+		return downloadTaskMetadata{}, false
+	}
+
+	return metadata, true
+}
+
+// isRemoteFileUnchanged makes a conditional HEAD request for the file with the validators recorded when it was
+// downloaded. Origins ignoring the conditions answer with the headers of the whole file, whose validators are then
+// compared instead.
+func (r duplicateURLResolver) isRemoteFileUnchanged(ctx context.Context, downloadURL string, metadata downloadTaskMetadata) (bool, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodHead, downloadURL, nil)
+	if err != nil {
+		return false, err
+	}
+
+	if metadata.ETag != "" {
+		request.Header.Set("If-None-Match", metadata.ETag)
+	}
+	if metadata.LastModified != "" {
+		request.Header.Set("If-Modified-Since", metadata.LastModified)
+	}
+
+	response, err := r.httpClient.Do(request)
+	if err != nil {
+		return false, err
+	}
+	response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusNotModified:
+		return true, nil
+	case http.StatusOK:
+		return !isRemoteFileChanged(metadata, response), nil
+	default:
+		return false, newHTTPStatusError(response.StatusCode, "unexpected response status: %s", response.Status)
+	}
+}
+
+// isRemoteFileChanged compares the validators of a full response with the ones recorded when the file was downloaded,
+// the way saved progress is checked before resuming.
+func isRemoteFileChanged(metadata downloadTaskMetadata, response *http.Response) bool {
+	if response.ContentLength >= 0 && response.ContentLength != metadata.Size {
+		return true
+	}
+
+	etag := response.Header.Get("ETag")
+	if metadata.ETag != "" || etag != "" {
+		return metadata.ETag != etag
+	}
+
+	return metadata.LastModified != response.Header.Get("Last-Modified")
+}
+
+// reuseDownloadTask completes a task just created in tx with the file of a duplicate task. It returns false, leaving
+// the task to be downloaded, when the duplicate was deleted in the meantime.
+func (r duplicateURLResolver) reuseDownloadTask(
+	ctx context.Context,
+	tx *goqu.TxDatabase,
+	task *database.DownloadTask,
+	duplicateTaskID uint64,
+) (bool, error) {
+	downloadTaskDataAccessor := r.downloadTaskDataAccessor.WithDatabase(tx)
+	duplicateTask, err := downloadTaskDataAccessor.GetDownloadTaskByIDWithXLock(ctx, duplicateTaskID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	duplicateMetadata, err := parseDownloadTaskMetadata(duplicateTask.Metadata)
+	if err != nil {
+		return false, err
+	}
+	if duplicateTask.DownloadStatus != uint16(go_load.DownloadStatus_Success) || duplicateTask.BlobSHA256 == "" {
+		return false, nil
+	}
+
+	blobDataAccessor := r.blobDataAccessor.WithDatabase(tx)
+	blob, err := blobDataAccessor.GetBlobBySHA256WithXLock(ctx, duplicateTask.BlobSHA256)
+	if err != nil {
+		return false, err
+	}
+
+	metadata, err := parseDownloadTaskMetadata(task.Metadata)
+	if err != nil {
+		return false, err
+	}
+
+	metadata.FileName, err = getDownloadFileName(*task)
+	if err != nil {
+		return false, err
+	}
+	metadata.ValidatedURL = duplicateMetadata.ValidatedURL
+	metadata.Size = blob.Size
+	metadata.ETag = duplicateMetadata.ETag
+	metadata.LastModified = duplicateMetadata.LastModified
+	metadata.DownloadedByteCount = blob.Size
+	metadata.SHA256 = blob.SHA256
+	metadata.ReusedExistingDownload = true
+
+	err = blobDataAccessor.UpdateBlobReferenceCount(ctx, blob.SHA256, blob.ReferenceCount+1)
+	if err != nil {
+		return false, err
+	}
+
+	err = downloadTaskDataAccessor.UpdateDownloadTaskBlobSHA256(ctx, task.ID, blob.SHA256)
+	if err != nil {
+		return false, err
+	}
+
+	task.DownloadStatus = uint16(go_load.DownloadStatus_Success)
+	task.Metadata = metadata.String()
+	task.BlobSHA256 = blob.SHA256
+	err = downloadTaskDataAccessor.UpdateDownloadTaskStatusAndMetadata(ctx, task.ID, task.DownloadStatus, task.Metadata)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.fileClient.Link(ctx, getBlobFileName(blob.SHA256), metadata.FileName)
+	if err != nil {
+		return false, err
+	}
+
+	r.logger.With(zap.Uint64("taskID", task.ID), zap.Uint64("duplicateTaskID", duplicateTask.ID)).
+		Info("completed download task with the file of a duplicate")
+	return true, nil
+}
+
+// getDuplicateDownload describes a reusable duplicate to the account asking. A duplicate of another account is left
+// empty, only telling that a reusable file exists, not what the other account downloaded.
+func getDuplicateDownload(accountID uint64, task database.DownloadTask, metadata downloadTaskMetadata) *go_load.DuplicateDownload {
+	if task.OfAccountID != accountID {
+		return &go_load.DuplicateDownload{}
+	}
+
+	return &go_load.DuplicateDownload{
+		DownloadTaskId: task.ID,
+		FileName:       metadata.FileName,
+		Size:           uint64(metadata.Size),
+		Sha256:         task.BlobSHA256,
+	}
+}
@@ -0,0 +1,229 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/database"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultAccountWeight = 1
+)
+
+var (
+	errPermissionDenied = errors.New("permission denied")
+)
+
+// accountWeights looks up the fair share weight of accounts, which the config sets by account name.
+type accountWeights struct {
+	accountDataAccessor database.AccountDataAccessor
+	defaultWeight       uint32
+	weights             map[string]uint32
+}
+
+func newAccountWeights(configs configs.FairShareConfig, accountDataAccessor database.AccountDataAccessor) accountWeights {
+	defaultWeight := configs.DefaultAccountWeight
+	if defaultWeight == 0 {
+		defaultWeight = defaultAccountWeight
+	}
+
+	return accountWeights{
+		accountDataAccessor: accountDataAccessor,
+		defaultWeight:       defaultWeight,
+		weights:             configs.AccountWeights,
+	}
+}
+
+func (a accountWeights) getAccountWeight(accountName string) uint32 {
+	weight, ok := a.weights[accountName]
+	if !ok || weight == 0 {
+		return a.defaultWeight
+	}
+	return weight
+}
+
+// getAccountWeights returns the weight and name of each account.
+func (a accountWeights) getAccountWeights(ctx context.Context, accountIDs []uint64) (map[uint64]uint32, map[uint64]string, error) {
+	weights := make(map[uint64]uint32, len(accountIDs))
+	accountNames := make(map[uint64]string, len(accountIDs))
+	for _, accountID := range accountIDs {
+		account, err := a.accountDataAccessor.GetAccountByID(ctx, accountID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		weights[accountID] = a.getAccountWeight(account.AccountName)
+		accountNames[accountID] = account.AccountName
+	}
+	return weights, accountNames, nil
+}
+
+// pickFairShareTasks picks up to limit tasks with weighted fair queuing across accounts. Each pick goes to the account
+// whose running downloads, counting the picked ones and the next one, are the fewest relative to its weight, so every
+// account with pending tasks gets download slots in proportion to its weight no matter how many tasks it queued.
+// candidateTasks are the tasks each account may start, in the order they should start.
+func pickFairShareTasks(
+	candidateTasks map[uint64][]database.DownloadTask,
+	runningTaskCounts map[uint64]uint64,
+	weights map[uint64]uint32,
+	limit int,
+) []database.DownloadTask {
+	accountIDs := make([]uint64, 0, len(candidateTasks))
+	for accountID := range candidateTasks {
+		accountIDs = append(accountIDs, accountID)
+	}
+	// ties go to the account with the lower ID, so picks do not depend on map order
+	slices.Sort(accountIDs)
+
+	taskCounts := make(map[uint64]uint64, len(accountIDs))
+	for _, accountID := range accountIDs {
+		taskCounts[accountID] = runningTaskCounts[accountID]
+	}
+
+	pickedTasks := make([]database.DownloadTask, 0, limit)
+	for len(pickedTasks) < limit {
+		var (
+			nextAccountID uint64
+			found         bool
+		)
+		for _, accountID := range accountIDs {
+			if len(candidateTasks[accountID]) == 0 {
+				continue
+			}
+			// (count+1)/weight < (nextCount+1)/nextWeight, compared without division
+			if !found || (taskCounts[accountID]+1)*uint64(weights[nextAccountID]) <
+				(taskCounts[nextAccountID]+1)*uint64(weights[accountID]) {
+				nextAccountID = accountID
+				found = true
+			}
+		}
+		if !found {
+			break
+		}
+
+		pickedTasks = append(pickedTasks, candidateTasks[nextAccountID][0])
+		candidateTasks[nextAccountID] = candidateTasks[nextAccountID][1:]
+		taskCounts[nextAccountID]++
+	}
+	return pickedTasks
+}
+
+type GetAccountShareListParams struct {
+	Token string
+}
+
+// FairShareHandler reports how download slots are currently shared across accounts.
+type FairShareHandler interface {
+	GetAccountShareList(ctx context.Context, params GetAccountShareListParams) ([]*go_load.AccountShare, error)
+}
+
+type fairShareHandler struct {
+	tokenHandler             TokenHandler
+	accountDataAccessor      database.AccountDataAccessor
+	downloadTaskDataAccessor database.DownloadTaskDataAccessor
+	accountWeights           accountWeights
+	adminAccountNames        []string
+	logger                   *zap.Logger
+}
+
+func NewFairShareHandler(
+	authConfig configs.AuthConfig,
+	downloadConfig configs.DownloadConfig,
+	tokenHandler TokenHandler,
+	accountDataAccessor database.AccountDataAccessor,
+	downloadTaskDataAccessor database.DownloadTaskDataAccessor,
+	logger *zap.Logger,
+) FairShareHandler {
+	return &fairShareHandler{
+		tokenHandler:             tokenHandler,
+		accountDataAccessor:      accountDataAccessor,
+		downloadTaskDataAccessor: downloadTaskDataAccessor,
+		accountWeights:           newAccountWeights(downloadConfig.FairShareConfig, accountDataAccessor),
+		adminAccountNames:        authConfig.AdminAccountNames,
+		logger:                   logger,
+	}
+}
+
+func (f fairShareHandler) verifyAdmin(ctx context.Context, token string) error {
+	accountID, _, err := f.tokenHandler.GetAccountIDAndExpireTime(ctx, token)
+	if err != nil {
+		f.logger.With(zap.Error(err)).Error("failed to verify token")
+		return err
+	}
+
+	account, err := f.accountDataAccessor.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(f.adminAccountNames, account.AccountName) {
+		f.logger.With(zap.Uint64("accountID", accountID)).Warn("non-admin account called admin RPC")
+		return errPermissionDenied
+	}
+	return nil
+}
+
+// GetAccountShareList returns every account with running or pending tasks. The share of an account is its weight over
+// the total weight of those accounts, which is the part of the download slots it gets while all of them have pending
+// tasks. The running share is the part of the running downloads that actually belong to it.
+func (f fairShareHandler) GetAccountShareList(ctx context.Context, params GetAccountShareListParams) ([]*go_load.AccountShare, error) {
+	err := f.verifyAdmin(ctx, params.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	runningTaskCounts, err := f.downloadTaskDataAccessor.GetDownloadTaskCountsByAccountID(ctx, uint16(go_load.DownloadStatus_Downloading))
+	if err != nil {
+		return nil, err
+	}
+
+	pendingTaskCounts, err := f.downloadTaskDataAccessor.GetDownloadTaskCountsByAccountID(ctx, uint16(go_load.DownloadStatus_Pending))
+	if err != nil {
+		return nil, err
+	}
+
+	accountIDs := make([]uint64, 0, len(runningTaskCounts)+len(pendingTaskCounts))
+	for accountID := range runningTaskCounts {
+		accountIDs = append(accountIDs, accountID)
+	}
+	for accountID := range pendingTaskCounts {
+		if _, ok := runningTaskCounts[accountID]; !ok {
+			accountIDs = append(accountIDs, accountID)
+		}
+	}
+	slices.Sort(accountIDs)
+
+	weights, accountNames, err := f.accountWeights.getAccountWeights(ctx, accountIDs)
+	if err != nil {
+		f.logger.With(zap.Error(err)).Error("failed to get account weights")
+		return nil, err
+	}
+
+	var totalWeight, totalRunningTaskCount uint64
+	for _, accountID := range accountIDs {
+		totalWeight += uint64(weights[accountID])
+		totalRunningTaskCount += runningTaskCounts[accountID]
+	}
+
+	accountShareList := make([]*go_load.AccountShare, 0, len(accountIDs))
+	for _, accountID := range accountIDs {
+		accountShare := &go_load.AccountShare{
+			AccountId:                accountID,
+			AccountName:              accountNames[accountID],
+			Weight:                   weights[accountID],
+			Share:                    float64(weights[accountID]) / float64(totalWeight),
+			RunningDownloadTaskCount: runningTaskCounts[accountID],
+			PendingDownloadTaskCount: pendingTaskCounts[accountID],
+		}
+		if totalRunningTaskCount > 0 {
+			accountShare.RunningShare = float64(runningTaskCounts[accountID]) / float64(totalRunningTaskCount)
+		}
+		accountShareList = append(accountShareList, accountShare)
+	}
+	return accountShareList, nil
+}
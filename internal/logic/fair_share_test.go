@@ -0,0 +1,102 @@
+package logic
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/database"
+)
+
+func newTestFairShareTasks(accountID uint64, taskIDs ...uint64) []database.DownloadTask {
+	tasks := make([]database.DownloadTask, 0, len(taskIDs))
+	for _, taskID := range taskIDs {
+		tasks = append(tasks, database.DownloadTask{ID: taskID, OfAccountID: accountID})
+	}
+	return tasks
+}
+
+func TestPickFairShareTasks(t *testing.T) {
+	testCases := []struct {
+		name              string
+		candidateTasks    map[uint64][]database.DownloadTask
+		runningTaskCounts map[uint64]uint64
+		weights           map[uint64]uint32
+		limit             int
+		wantTaskIDs       []uint64
+	}{
+		{
+			name: "equal weights alternate, ties going to the lower account ID",
+			candidateTasks: map[uint64][]database.DownloadTask{
+				2: newTestFairShareTasks(2, 21, 22, 23),
+				1: newTestFairShareTasks(1, 11, 12, 13),
+			},
+			weights:     map[uint64]uint32{1: 1, 2: 1},
+			limit:       4,
+			wantTaskIDs: []uint64{11, 21, 12, 22},
+		},
+		{
+			name: "slots follow the weights",
+			candidateTasks: map[uint64][]database.DownloadTask{
+				1: newTestFairShareTasks(1, 11, 12, 13, 14, 15),
+				2: newTestFairShareTasks(2, 21, 22, 23, 24, 25),
+			},
+			weights:     map[uint64]uint32{1: 2, 2: 1},
+			limit:       6,
+			wantTaskIDs: []uint64{11, 12, 21, 13, 14, 22},
+		},
+		{
+			name: "running downloads count against the account",
+			candidateTasks: map[uint64][]database.DownloadTask{
+				1: newTestFairShareTasks(1, 11, 12),
+				2: newTestFairShareTasks(2, 21, 22),
+			},
+			runningTaskCounts: map[uint64]uint64{1: 2},
+			weights:           map[uint64]uint32{1: 1, 2: 1},
+			limit:             3,
+			wantTaskIDs:       []uint64{21, 22, 11},
+		},
+		{
+			name: "queue length does not buy slots",
+			candidateTasks: map[uint64][]database.DownloadTask{
+				1: newTestFairShareTasks(1, 11, 12, 13, 14, 15, 16),
+				2: newTestFairShareTasks(2, 21),
+			},
+			weights:     map[uint64]uint32{1: 1, 2: 1},
+			limit:       2,
+			wantTaskIDs: []uint64{11, 21},
+		},
+		{
+			name: "fewer candidates than the limit",
+			candidateTasks: map[uint64][]database.DownloadTask{
+				1: newTestFairShareTasks(1, 11),
+				2: {},
+			},
+			weights:     map[uint64]uint32{1: 1, 2: 1},
+			limit:       5,
+			wantTaskIDs: []uint64{11},
+		},
+		{
+			name: "zero limit",
+			candidateTasks: map[uint64][]database.DownloadTask{
+				1: newTestFairShareTasks(1, 11),
+			},
+			weights:     map[uint64]uint32{1: 1},
+			limit:       0,
+			wantTaskIDs: []uint64{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			pickedTasks := pickFairShareTasks(testCase.candidateTasks, testCase.runningTaskCounts, testCase.weights, testCase.limit)
+
+			pickedTaskIDs := make([]uint64, 0, len(pickedTasks))
+			for _, task := range pickedTasks {
+				pickedTaskIDs = append(pickedTaskIDs, task.ID)
+			}
+			if !slices.Equal(pickedTaskIDs, testCase.wantTaskIDs) {
+				t.Errorf("pickFairShareTasks() picked %v, want %v", pickedTaskIDs, testCase.wantTaskIDs)
+			}
+		})
+	}
+}
@@ -0,0 +1,428 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxGrabbedLinkCount bounds the links of a page looked at, and the links tasks are created for at once
+	maxGrabbedLinkCount = 1000
+	// grabbedPageMaxSize is how much of a page or pasted content is searched for links
+	grabbedPageMaxSize         = 10 * 1024 * 1024
+	grabbedPageFetchTimeout    = 30 * time.Second
+	grabbedLinkHeadTimeout     = 10 * time.Second
+	maxConcurrentLinkHeadCount = 8
+)
+
+var (
+	errGrabLinksSourceMissing   = errors.New("either the URL of a page or its content is required to grab links")
+	errInvalidGrabLinksURL      = errors.New("the URL to grab links from must be an HTTP or HTTPS URL")
+	errGrabbedContentTooLarge   = fmt.Errorf("content to grab links from cannot be larger than %d bytes", grabbedPageMaxSize)
+	errNoLinksToCreate          = errors.New("at least one link is required to create download tasks")
+	errTooManyLinksToCreate     = fmt.Errorf("download tasks can be created for at most %d links at once", maxGrabbedLinkCount)
+	errUnsupportedLinkURLScheme = errors.New("only HTTP, HTTPS and FTP links can be downloaded")
+)
+
+var (
+	// textLinkExpression matches the URLs written out in plain text
+	textLinkExpression = regexp.MustCompile("(?i)\\b(?:https?|ftp)://[^\\s<>\"'`]+")
+	// fileCategoryExtensions are the extensions of every file category, files with other extensions are categorized by
+	// their content type
+	fileCategoryExtensions = map[go_load.FileCategory][]string{
+		go_load.FileCategory_CompressedFile: {".zip", ".rar", ".7z", ".tar", ".gz", ".tgz", ".bz2", ".tbz2", ".xz", ".txz", ".zst", ".lz", ".lzma", ".cab", ".arj"},
+		go_load.FileCategory_DocumentFile:   {".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx", ".odt", ".ods", ".odp", ".rtf", ".txt", ".csv", ".epub", ".mobi"},
+		go_load.FileCategory_MusicFile:      {".mp3", ".wav", ".flac", ".aac", ".m4a", ".ogg", ".oga", ".opus", ".wma", ".aif", ".aiff"},
+		go_load.FileCategory_VideoFile:      {".mp4", ".m4v", ".mkv", ".avi", ".mov", ".wmv", ".flv", ".webm", ".mpg", ".mpeg", ".3gp", ".ts"},
+		go_load.FileCategory_ImageFile:      {".jpg", ".jpeg", ".png", ".gif", ".bmp", ".webp", ".svg", ".tif", ".tiff", ".ico", ".heic"},
+		go_load.FileCategory_ProgramFile:    {".exe", ".msi", ".dmg", ".pkg", ".deb", ".rpm", ".apk", ".appimage", ".iso", ".img", ".bin", ".jar"},
+	}
+)
+
+type GrabLinksParams struct {
+	Token string
+	// URL is the page links are grabbed from, or the URL relative links of Content are resolved against
+	URL string
+	// Content is pasted HTML or text links are grabbed from instead of fetching URL
+	Content string
+	// Extensions keeps only the links to files with one of these extensions, with or without their leading dot
+	Extensions []string
+	// Pattern keeps only the links whose URL or file name it matches
+	Pattern string
+}
+
+type CreateDownloadTasksFromLinksParams struct {
+	Token string
+	URLs  []string
+	// DownloadQueueID adds the created tasks to the end of one of the account's download queues
+	DownloadQueueID uint64
+}
+
+// grabbedLinkFilter keeps the links the user asked for. Without extensions or a pattern, every link to a file that is
+// not a page is kept.
+type grabbedLinkFilter struct {
+	extensions []string
+	pattern    *regexp.Regexp
+}
+
+func newGrabbedLinkFilter(extensions []string, pattern string) (grabbedLinkFilter, error) {
+	filter := grabbedLinkFilter{}
+	for _, extension := range extensions {
+		extension = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(extension), "."))
+		if extension != "" {
+			filter.extensions = append(filter.extensions, "."+extension)
+		}
+	}
+
+	if pattern != "" {
+		expression, err := regexp.Compile(pattern)
+		if err != nil {
+			return grabbedLinkFilter{}, fmt.Errorf("invalid link pattern %q: %w", pattern, err)
+		}
+		filter.pattern = expression
+	}
+	return filter, nil
+}
+
+func (f grabbedLinkFilter) isEmpty() bool {
+	return len(f.extensions) == 0 && f.pattern == nil
+}
+
+func (f grabbedLinkFilter) matches(linkURL string, fileName string) bool {
+	if len(f.extensions) > 0 && !slices.Contains(f.extensions, strings.ToLower(path.Ext(fileName))) {
+		return false
+	}
+	return f.pattern == nil || f.pattern.MatchString(linkURL) || f.pattern.MatchString(fileName)
+}
+
+// linkGrabber finds the links to files on a page, and looks up their file names, sizes and types the way a browser
+// download would, with a HEAD request.
+type linkGrabber struct {
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+func newLinkGrabber(logger *zap.Logger) linkGrabber {
+	return linkGrabber{
+		httpClient: &http.Client{},
+		logger:     logger,
+	}
+}
+
+// grabLinks returns the links of the page or pasted content of params that pass its filter, in the order they appear.
+func (g linkGrabber) grabLinks(ctx context.Context, params GrabLinksParams) ([]*go_load.GrabbedLink, error) {
+	filter, err := newGrabbedLinkFilter(params.Extensions, params.Pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		baseURL *url.URL
+		content []byte
+	)
+	switch {
+	case params.URL == "" && params.Content == "":
+		return nil, errGrabLinksSourceMissing
+	case params.URL != "":
+		baseURL, err = url.Parse(strings.TrimSpace(params.URL))
+		if err != nil || baseURL.Scheme != "http" && baseURL.Scheme != "https" || baseURL.Host == "" {
+			return nil, errInvalidGrabLinksURL
+		}
+	default:
+		// without a URL, only absolute links of the content can be grabbed
+		baseURL = &url.URL{}
+	}
+
+	if params.Content != "" {
+		if len(params.Content) > grabbedPageMaxSize {
+			return nil, errGrabbedContentTooLarge
+		}
+		content = []byte(params.Content)
+	} else {
+		baseURL, content, err = g.fetchPage(ctx, baseURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	links := extractGrabbedLinks(baseURL, content)
+	if len(links) > maxGrabbedLinkCount {
+		g.logger.With(zap.Int("linkCount", len(links))).Warn("too many links to grab, leaving out the rest")
+		links = links[:maxGrabbedLinkCount]
+	}
+
+	grabbedLinks := make([]*go_load.GrabbedLink, len(links))
+	semaphore := make(chan struct{}, maxConcurrentLinkHeadCount)
+	var waitGroup sync.WaitGroup
+	for i, link := range links {
+		extension := strings.ToLower(path.Ext(link.Path))
+		isPageLink := slices.Contains(pageExtensions, extension)
+		// a link with an extension already shows what it leads to, links without one may still name a file once asked
+		if !isPageLink && !filter.isEmpty() && !filter.matches(link.String(), path.Base(link.Path)) {
+			continue
+		}
+
+		semaphore <- struct{}{}
+		waitGroup.Add(1)
+		go func(i int, link *url.URL) {
+			defer func() {
+				<-semaphore
+				waitGroup.Done()
+			}()
+
+			grabbedLink, ok := g.getGrabbedLink(ctx, link, isPageLink)
+			if ok && (filter.isEmpty() || filter.matches(grabbedLink.GetUrl(), grabbedLink.GetFileName())) {
+				grabbedLinks[i] = grabbedLink
+			}
+		}(i, link)
+	}
+	waitGroup.Wait()
+
+	return slices.DeleteFunc(grabbedLinks, func(grabbedLink *go_load.GrabbedLink) bool {
+		return grabbedLink == nil
+	}), nil
+}
+
+// fetchPage returns the content of a page and the URL it ended up at after redirects, which its relative links are
+// resolved against.
+func (g linkGrabber) fetchPage(ctx context.Context, pageURL *url.URL) (*url.URL, []byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, grabbedPageFetchTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response, err := g.httpClient.Do(request)
+	if err != nil {
+		g.logger.With(zap.Error(err), zap.String("url", pageURL.String())).Warn("failed to fetch page to grab links from")
+		return nil, nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return nil, nil, newHTTPStatusError(response.StatusCode, "unexpected response status: %s", response.Status)
+	}
+
+	var page bytes.Buffer
+	_, err = io.Copy(&page, io.LimitReader(response.Body, grabbedPageMaxSize))
+	if err != nil {
+		return nil, nil, err
+	}
+	return response.Request.URL, page.Bytes(), nil
+}
+
+// extractGrabbedLinks returns the links of HTML elements and the URLs written out in the text of the content, without
+// duplicates.
+func extractGrabbedLinks(baseURL *url.URL, content []byte) []*url.URL {
+	links := extractHTMLLinks(baseURL, content)
+	seen := make(map[string]bool, len(links))
+	for _, link := range links {
+		seen[link.String()] = true
+	}
+
+	for _, match := range textLinkExpression.FindAll(content, -1) {
+		// punctuation ending a sentence or closing a parenthesis around the URL is not part of it
+		link, ok := resolveHTMLLink(baseURL, strings.TrimRight(string(match), ".,;:!?)]}"))
+		if ok && !seen[link.String()] {
+			seen[link.String()] = true
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// getGrabbedLink looks up the file an HTTP link leads to with a HEAD request, and guesses the file of an FTP link or
+// an HTTP link the request failed for from its URL. Links to pages are left out, as are links that look like pages
+// when the request fails.
+func (g linkGrabber) getGrabbedLink(ctx context.Context, link *url.URL, isPageLink bool) (*go_load.GrabbedLink, bool) {
+	downloadType, ok := getURLDownloadType(link.String())
+	if !ok {
+		return nil, false
+	}
+
+	grabbedLink := &go_load.GrabbedLink{
+		Url:          link.String(),
+		DownloadType: downloadType,
+		FileName:     getLinkFileName(link),
+	}
+	if downloadType == go_load.DownloadType_HTTP {
+		response, err := g.head(ctx, link)
+		switch {
+		case err != nil:
+			g.logger.With(zap.Error(err), zap.String("url", link.String())).Debug("failed to look up grabbed link")
+			if isPageLink {
+				return nil, false
+			}
+		case isHTMLContentType(response.Header.Get("Content-Type")):
+			return nil, false
+		default:
+			grabbedLink.FileName = getLinkFileName(response.Request.URL)
+			if fileName, ok := getContentDispositionFileName(response.Header.Get("Content-Disposition")); ok {
+				grabbedLink.FileName = fileName
+			}
+			if response.ContentLength > 0 {
+				grabbedLink.Size = uint64(response.ContentLength)
+			}
+			if mediaType, _, err := mime.ParseMediaType(response.Header.Get("Content-Type")); err == nil &&
+				mediaType != "application/octet-stream" {
+				grabbedLink.ContentType = mediaType
+			}
+		}
+	} else if isPageLink {
+		return nil, false
+	}
+
+	if grabbedLink.ContentType == "" {
+		if mediaType, _, err := mime.ParseMediaType(mime.TypeByExtension(path.Ext(grabbedLink.FileName))); err == nil {
+			grabbedLink.ContentType = mediaType
+		}
+	}
+	grabbedLink.FileCategory = getFileCategory(grabbedLink.FileName, grabbedLink.ContentType)
+	return grabbedLink, true
+}
+
+// head sends a HEAD request for the link. Servers that do not answer HEAD requests are treated as failures, the link
+// is then guessed from its URL.
+func (g linkGrabber) head(ctx context.Context, link *url.URL) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, grabbedLinkHeadTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodHead, link.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := g.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return nil, newHTTPStatusError(response.StatusCode, "unexpected response status: %s", response.Status)
+	}
+	return response, nil
+}
+
+// getLinkFileName returns the last element of the path of a link, or "download" when it has none.
+func getLinkFileName(link *url.URL) string {
+	fileName := path.Base(link.Path)
+	if !isSafePathElement(fileName) {
+		return "download"
+	}
+	return fileName
+}
+
+// getContentDispositionFileName returns the file name a server suggests saving a file as, unless it is not a safe
+// file name.
+func getContentDispositionFileName(contentDisposition string) (string, bool) {
+	_, params, err := mime.ParseMediaType(contentDisposition)
+	if err != nil {
+		return "", false
+	}
+
+	fileName := strings.TrimSpace(params["filename"])
+	return fileName, isSafePathElement(fileName)
+}
+
+// getFileCategory returns the category of a file by its extension, or by its content type for unknown extensions.
+func getFileCategory(fileName string, contentType string) go_load.FileCategory {
+	extension := strings.ToLower(path.Ext(fileName))
+	for category, extensions := range fileCategoryExtensions {
+		if slices.Contains(extensions, extension) {
+			return category
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(contentType, "video/"):
+		return go_load.FileCategory_VideoFile
+	case strings.HasPrefix(contentType, "audio/"):
+		return go_load.FileCategory_MusicFile
+	case strings.HasPrefix(contentType, "image/"):
+		return go_load.FileCategory_ImageFile
+	default:
+		return go_load.FileCategory_OtherFile
+	}
+}
+
+// GrabLinks returns the links to files on a page, or in pasted HTML or text, with their file names, sizes and types,
+// for the user to pick the ones to create download tasks for.
+func (d downloadTaskHandler) GrabLinks(ctx context.Context, params GrabLinksParams) ([]*go_load.GrabbedLink, error) {
+	accountID, _, err := d.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to verify token")
+		return nil, err
+	}
+
+	grabbedLinks, err := d.linkGrabber.grabLinks(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	d.logger.With(zap.Uint64("accountID", accountID), zap.Int("linkCount", len(grabbedLinks))).Info("links grabbed")
+	return grabbedLinks, nil
+}
+
+// CreateDownloadTasksFromLinks creates a download task for every HTTP, HTTPS and FTP link, usually ones picked from
+// the links GrabLinks returned. Every link is checked before any task is created.
+func (d downloadTaskHandler) CreateDownloadTasksFromLinks(
+	ctx context.Context,
+	params CreateDownloadTasksFromLinksParams,
+) ([]*go_load.DownloadTask, error) {
+	accountID, _, err := d.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to verify token")
+		return nil, err
+	}
+
+	switch {
+	case len(params.URLs) == 0:
+		return nil, errNoLinksToCreate
+	case len(params.URLs) > maxGrabbedLinkCount:
+		return nil, errTooManyLinksToCreate
+	}
+
+	createParamsList := make([]CreateDownloadTaskParams, 0, len(params.URLs))
+	for _, linkURL := range params.URLs {
+		downloadType, ok := getURLDownloadType(linkURL)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", errUnsupportedLinkURLScheme, linkURL)
+		}
+
+		createParamsList = append(createParamsList, CreateDownloadTaskParams{
+			DownloadType:    downloadType,
+			URL:             strings.TrimSpace(linkURL),
+			DownloadQueueID: params.DownloadQueueID,
+		})
+	}
+
+	downloadTaskList := make([]*go_load.DownloadTask, 0, len(createParamsList))
+	for _, createParams := range createParamsList {
+		output, err := d.createDownloadTask(ctx, accountID, createParams)
+		if err != nil {
+			return nil, err
+		}
+		downloadTaskList = append(downloadTaskList, output.DownloadTask)
+	}
+
+	d.logger.With(zap.Uint64("accountID", accountID), zap.Int("taskCount", len(downloadTaskList))).Info("download tasks created from links")
+	return downloadTaskList, nil
+}
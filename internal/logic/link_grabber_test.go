@@ -0,0 +1,756 @@
+package logic
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/database"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
+	"go.uber.org/zap"
+)
+
+func TestExtractGrabbedLinks(t *testing.T) {
+	testCases := []struct {
+		name    string
+		baseURL string
+		content string
+		want    []string
+	}{
+		{
+			name:    "HTML links resolved against the page",
+			baseURL: "http://example.com/pub/index.html",
+			content: `<a href="a.zip">a</a> <img src="/img/b.png"> <a href="../c.pdf#page=2">c</a> <a href="#top">top</a>`,
+			want: []string{
+				"http://example.com/pub/a.zip",
+				"http://example.com/img/b.png",
+				"http://example.com/c.pdf",
+			},
+		},
+		{
+			name:    "links of other schemes",
+			baseURL: "http://example.com/",
+			content: `<a href="mailto:someone@example.com">mail</a> <a href="javascript:void(0)">js</a> <a href="ftp://example.com/pub/a.iso">ftp</a>`,
+			want:    []string{"ftp://example.com/pub/a.iso"},
+		},
+		{
+			name:    "text URLs",
+			content: "the archive http://example.com/a.zip\nthe image\thttps://example.com/b.png and ftp://example.com/c.iso",
+			want: []string{
+				"http://example.com/a.zip",
+				"https://example.com/b.png",
+				"ftp://example.com/c.iso",
+			},
+		},
+		{
+			name: "trailing punctuation of text URLs",
+			content: "See http://example.com/a.zip. Or (http://example.com/b.zip), [http://example.com/c.zip]; " +
+				"maybe http://example.com/d.zip?! Mirrors: {ftp://example.com/e.zip}, https://example.com/f.zip:",
+			want: []string{
+				"http://example.com/a.zip",
+				"http://example.com/b.zip",
+				"http://example.com/c.zip",
+				"http://example.com/d.zip",
+				"ftp://example.com/e.zip",
+				"https://example.com/f.zip",
+			},
+		},
+		{
+			name:    "punctuation inside text URLs",
+			content: "http://example.com/a.b/c,d.zip?e=f;g.",
+			want:    []string{"http://example.com/a.b/c,d.zip?e=f;g"},
+		},
+		{
+			name:    "text URLs ending at quotes and tags",
+			content: `<p>"http://example.com/a.zip"</p><p>'http://example.com/b.zip'</p>`,
+			want:    []string{"http://example.com/a.zip", "http://example.com/b.zip"},
+		},
+		{
+			name:    "duplicates",
+			baseURL: "http://example.com/",
+			content: `<a href="/a.zip">http://example.com/a.zip</a> <a href="a.zip#b">again</a> http://example.com/b.zip http://example.com/b.zip.`,
+			want:    []string{"http://example.com/a.zip", "http://example.com/b.zip"},
+		},
+		{
+			name:    "relative links without a URL to resolve them against",
+			content: `<a href="a.zip">a</a> <a href="http://example.com/b.zip">b</a>`,
+			want:    []string{"http://example.com/b.zip"},
+		},
+		{
+			name:    "no links",
+			baseURL: "http://example.com/",
+			content: "nothing to see here",
+			want:    []string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			baseURL, err := url.Parse(testCase.baseURL)
+			if err != nil {
+				t.Fatalf("failed to parse URL: %v", err)
+			}
+
+			links := make([]string, 0)
+			for _, link := range extractGrabbedLinks(baseURL, []byte(testCase.content)) {
+				links = append(links, link.String())
+			}
+			if !slices.Equal(links, testCase.want) {
+				t.Errorf("extractGrabbedLinks() = %q, want %q", links, testCase.want)
+			}
+		})
+	}
+}
+
+func TestGrabbedLinkFilterMatches(t *testing.T) {
+	testCases := []struct {
+		name       string
+		extensions []string
+		pattern    string
+		linkURL    string
+		fileName   string
+		want       bool
+	}{
+		{
+			name:     "no extensions or pattern",
+			linkURL:  "http://example.com/a.zip",
+			fileName: "a.zip",
+			want:     true,
+		},
+		{
+			name:       "extension",
+			extensions: []string{"zip", "iso"},
+			linkURL:    "http://example.com/a.zip",
+			fileName:   "a.zip",
+			want:       true,
+		},
+		{
+			name:       "extension with a dot, spaces and capitals",
+			extensions: []string{" .ZIP "},
+			linkURL:    "http://example.com/a.Zip",
+			fileName:   "a.Zip",
+			want:       true,
+		},
+		{
+			name:       "other extension",
+			extensions: []string{"zip"},
+			linkURL:    "http://example.com/a.zip.sig",
+			fileName:   "a.zip.sig",
+			want:       false,
+		},
+		{
+			name:       "extension of the file name, not the URL",
+			extensions: []string{"pdf"},
+			linkURL:    "http://example.com/download?id=1",
+			fileName:   "report.pdf",
+			want:       true,
+		},
+		{
+			name:       "blank extensions",
+			extensions: []string{"", " ", "."},
+			linkURL:    "http://example.com/a",
+			fileName:   "a",
+			want:       true,
+		},
+		{
+			name:     "pattern matching the URL",
+			pattern:  `/releases/v\d+/`,
+			linkURL:  "http://example.com/releases/v2/a.zip",
+			fileName: "a.zip",
+			want:     true,
+		},
+		{
+			name:     "pattern matching the file name",
+			pattern:  `^tool-\d`,
+			linkURL:  "http://example.com/latest",
+			fileName: "tool-2.0.exe",
+			want:     true,
+		},
+		{
+			name:     "pattern matching neither",
+			pattern:  `^tool-\d`,
+			linkURL:  "http://example.com/a.zip",
+			fileName: "a.zip",
+			want:     false,
+		},
+		{
+			name:       "extension and pattern both matching",
+			extensions: []string{"exe"},
+			pattern:    "tool",
+			linkURL:    "http://example.com/tool.exe",
+			fileName:   "tool.exe",
+			want:       true,
+		},
+		{
+			name:       "extension matching but not pattern",
+			extensions: []string{"exe"},
+			pattern:    "tool",
+			linkURL:    "http://example.com/setup.exe",
+			fileName:   "setup.exe",
+			want:       false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			filter, err := newGrabbedLinkFilter(testCase.extensions, testCase.pattern)
+			if err != nil {
+				t.Fatalf("newGrabbedLinkFilter() error = %v", err)
+			}
+
+			if matches := filter.matches(testCase.linkURL, testCase.fileName); matches != testCase.want {
+				t.Errorf("matches(%q, %q) = %v, want %v", testCase.linkURL, testCase.fileName, matches, testCase.want)
+			}
+		})
+	}
+}
+
+const testLinkPage = `<html><head><title>Downloads</title></head><body>
+<a href="../files/a.zip">archive</a>
+<a href="/download?id=1#top">report</a>
+<a href="about.html">about</a>
+<a href="/sub/">more</a>
+<img src="/missing.png">
+<a href="/gone">gone</a>
+<a href="/latest">latest tool</a>
+<a href="mailto:someone@example.com">mail</a>
+<p>Also on FTP: ftp://example.invalid/pub/manual.pdf, or browse ftp://example.invalid/pub/.</p>
+</body></html>`
+
+// testLinkServer serves a page of links at /pub/index.html, and answers HEAD requests for the files it links to.
+type testLinkServer struct {
+	*httptest.Server
+	page string
+
+	mutex sync.Mutex
+	// pageGetCount is the number of times the page was fetched, and headPaths the paths HEAD requests were sent for
+	pageGetCount int
+	headPaths    []string
+}
+
+func newTestLinkServer(t *testing.T, page string) *testLinkServer {
+	t.Helper()
+
+	server := &testLinkServer{page: page}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.serveHTTP))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (s *testLinkServer) getPageGetCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.pageGetCount
+}
+
+func (s *testLinkServer) getHeadPaths() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return slices.Sorted(slices.Values(s.headPaths))
+}
+
+func (s *testLinkServer) serveHTTP(writer http.ResponseWriter, request *http.Request) {
+	s.mutex.Lock()
+	if request.Method == http.MethodHead {
+		s.headPaths = append(s.headPaths, request.URL.Path)
+	} else if request.URL.Path == "/pub/index.html" {
+		s.pageGetCount++
+	}
+	s.mutex.Unlock()
+
+	header := writer.Header()
+	switch {
+	case request.URL.Path == "/pub/index.html":
+		header.Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(writer, s.page)
+	case request.URL.Path == "/download":
+		header.Set("Content-Type", "application/octet-stream")
+		header.Set("Content-Disposition", `attachment; filename="report.pdf"`)
+		header.Set("Content-Length", "5000")
+	case request.URL.Path == "/latest":
+		http.Redirect(writer, request, "/files/tool-2.0.exe", http.StatusFound)
+	case request.URL.Path == "/files/tool-2.0.exe":
+		header.Set("Content-Type", "application/x-msdownload")
+		header.Set("Content-Length", "2048")
+	case strings.HasPrefix(request.URL.Path, "/files/"):
+		header.Set("Content-Type", "application/zip")
+		header.Set("Content-Length", "1234")
+	case request.URL.Path == "/pub/about.html" || request.URL.Path == "/sub/":
+		header.Set("Content-Type", "text/html; charset=utf-8")
+	default:
+		http.NotFound(writer, request)
+	}
+}
+
+func newTestLinkGrabber() linkGrabber {
+	return newLinkGrabber(zap.NewNop())
+}
+
+func getTestGrabbedFileNames(grabbedLinks []*go_load.GrabbedLink) []string {
+	fileNames := make([]string, 0, len(grabbedLinks))
+	for _, grabbedLink := range grabbedLinks {
+		fileNames = append(fileNames, grabbedLink.GetFileName())
+	}
+	return fileNames
+}
+
+func TestLinkGrabberGrabLinks(t *testing.T) {
+	server := newTestLinkServer(t, testLinkPage)
+
+	grabbedLinks, err := newTestLinkGrabber().grabLinks(context.Background(), GrabLinksParams{URL: server.URL + "/pub/index.html"})
+	if err != nil {
+		t.Fatalf("grabLinks() error = %v", err)
+	}
+
+	want := []*go_load.GrabbedLink{
+		{
+			Url:          server.URL + "/files/a.zip",
+			DownloadType: go_load.DownloadType_HTTP,
+			FileName:     "a.zip",
+			Size:         1234,
+			ContentType:  "application/zip",
+			FileCategory: go_load.FileCategory_CompressedFile,
+		},
+		{
+			// the file name the server suggests, and the content type guessed from it over application/octet-stream
+			Url:          server.URL + "/download?id=1",
+			DownloadType: go_load.DownloadType_HTTP,
+			FileName:     "report.pdf",
+			Size:         5000,
+			ContentType:  "application/pdf",
+			FileCategory: go_load.FileCategory_DocumentFile,
+		},
+		{
+			// a link to a file the HEAD request failed for is guessed from its URL
+			Url:          server.URL + "/missing.png",
+			DownloadType: go_load.DownloadType_HTTP,
+			FileName:     "missing.png",
+			ContentType:  "image/png",
+			FileCategory: go_load.FileCategory_ImageFile,
+		},
+		{
+			// the file name of a redirected link is the one of the URL it ended up at
+			Url:          server.URL + "/latest",
+			DownloadType: go_load.DownloadType_HTTP,
+			FileName:     "tool-2.0.exe",
+			Size:         2048,
+			ContentType:  "application/x-msdownload",
+			FileCategory: go_load.FileCategory_ProgramFile,
+		},
+		{
+			Url:          "ftp://example.invalid/pub/manual.pdf",
+			DownloadType: go_load.DownloadType_FTP,
+			FileName:     "manual.pdf",
+			ContentType:  "application/pdf",
+			FileCategory: go_load.FileCategory_DocumentFile,
+		},
+	}
+	if len(grabbedLinks) != len(want) {
+		t.Fatalf("grabLinks() returned %q, want %d links", getTestGrabbedFileNames(grabbedLinks), len(want))
+	}
+	for i, grabbedLink := range grabbedLinks {
+		if grabbedLink.GetUrl() != want[i].GetUrl() ||
+			grabbedLink.GetDownloadType() != want[i].GetDownloadType() ||
+			grabbedLink.GetFileName() != want[i].GetFileName() ||
+			grabbedLink.GetSize() != want[i].GetSize() ||
+			grabbedLink.GetContentType() != want[i].GetContentType() ||
+			grabbedLink.GetFileCategory() != want[i].GetFileCategory() {
+			t.Errorf("link %d = %v, want %v", i, grabbedLink, want[i])
+		}
+	}
+
+	// pages, directories and links that look like pages the HEAD request failed for are left out
+	wantHeadPaths := []string{"/download", "/files/a.zip", "/files/tool-2.0.exe", "/gone", "/latest", "/missing.png", "/pub/about.html", "/sub/"}
+	if headPaths := server.getHeadPaths(); !slices.Equal(headPaths, wantHeadPaths) {
+		t.Errorf("HEAD requests sent for %q, want %q", headPaths, wantHeadPaths)
+	}
+}
+
+func TestLinkGrabberGrabLinksFilter(t *testing.T) {
+	testCases := []struct {
+		name          string
+		extensions    []string
+		pattern       string
+		wantFileNames []string
+		// skippedPaths are links not looked up, their extension already ruling them out
+		skippedPaths []string
+	}{
+		{
+			name:          "extension",
+			extensions:    []string{" .PDF "},
+			wantFileNames: []string{"report.pdf", "manual.pdf"},
+			skippedPaths:  []string{"/files/a.zip", "/missing.png"},
+		},
+		{
+			name:          "pattern matching the file name after a redirect",
+			pattern:       `^tool-\d`,
+			wantFileNames: []string{"tool-2.0.exe"},
+			skippedPaths:  []string{"/files/a.zip", "/missing.png"},
+		},
+		{
+			name:          "pattern matching the URL",
+			pattern:       "/files/",
+			wantFileNames: []string{"a.zip"},
+			skippedPaths:  []string{"/missing.png"},
+		},
+		{
+			name:          "extension and pattern",
+			extensions:    []string{"zip", "exe"},
+			pattern:       "tool",
+			wantFileNames: []string{"tool-2.0.exe"},
+			skippedPaths:  []string{"/files/a.zip", "/missing.png"},
+		},
+		{
+			name:          "nothing matching",
+			extensions:    []string{"mkv"},
+			wantFileNames: []string{},
+			skippedPaths:  []string{"/files/a.zip", "/missing.png"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			server := newTestLinkServer(t, testLinkPage)
+
+			grabbedLinks, err := newTestLinkGrabber().grabLinks(context.Background(), GrabLinksParams{
+				URL:        server.URL + "/pub/index.html",
+				Extensions: testCase.extensions,
+				Pattern:    testCase.pattern,
+			})
+			if err != nil {
+				t.Fatalf("grabLinks() error = %v", err)
+			}
+
+			if fileNames := getTestGrabbedFileNames(grabbedLinks); !slices.Equal(fileNames, testCase.wantFileNames) {
+				t.Errorf("grabLinks() returned %q, want %q", fileNames, testCase.wantFileNames)
+			}
+			headPaths := server.getHeadPaths()
+			for _, skippedPath := range testCase.skippedPaths {
+				if slices.Contains(headPaths, skippedPath) {
+					t.Errorf("HEAD requests sent for %q, want none for %s", headPaths, skippedPath)
+				}
+			}
+		})
+	}
+}
+
+func TestLinkGrabberGrabLinksPastedContent(t *testing.T) {
+	server := newTestLinkServer(t, testLinkPage)
+	content := `<a href="../files/a.zip">archive</a> or http://example.invalid/b.zip`
+
+	testCases := []struct {
+		name          string
+		url           string
+		wantFileNames []string
+	}{
+		{
+			name:          "resolved against the URL",
+			url:           server.URL + "/pub/index.html",
+			wantFileNames: []string{"a.zip", "b.zip"},
+		},
+		{
+			name:          "without a URL",
+			wantFileNames: []string{"b.zip"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			grabbedLinks, err := newTestLinkGrabber().grabLinks(context.Background(), GrabLinksParams{
+				URL:     testCase.url,
+				Content: content,
+			})
+			if err != nil {
+				t.Fatalf("grabLinks() error = %v", err)
+			}
+
+			if fileNames := getTestGrabbedFileNames(grabbedLinks); !slices.Equal(fileNames, testCase.wantFileNames) {
+				t.Errorf("grabLinks() returned %q, want %q", fileNames, testCase.wantFileNames)
+			}
+		})
+	}
+
+	if pageGetCount := server.getPageGetCount(); pageGetCount != 0 {
+		t.Errorf("page fetched %d times, want pasted content to be used instead", pageGetCount)
+	}
+}
+
+func TestLinkGrabberGrabLinksLinkCount(t *testing.T) {
+	server := newTestLinkServer(t, "")
+
+	var content strings.Builder
+	for i := range maxGrabbedLinkCount + 1 {
+		fmt.Fprintf(&content, "%s/files/%d.zip\n", server.URL, i)
+	}
+
+	grabbedLinks, err := newTestLinkGrabber().grabLinks(context.Background(), GrabLinksParams{Content: content.String()})
+	if err != nil {
+		t.Fatalf("grabLinks() error = %v", err)
+	}
+
+	if len(grabbedLinks) != maxGrabbedLinkCount {
+		t.Fatalf("grabLinks() returned %d links, want %d", len(grabbedLinks), maxGrabbedLinkCount)
+	}
+	if fileName := grabbedLinks[len(grabbedLinks)-1].GetFileName(); fileName != fmt.Sprintf("%d.zip", maxGrabbedLinkCount-1) {
+		t.Errorf("last link = %q, want %q", fileName, fmt.Sprintf("%d.zip", maxGrabbedLinkCount-1))
+	}
+	if headPaths := server.getHeadPaths(); len(headPaths) != maxGrabbedLinkCount {
+		t.Errorf("%d HEAD requests sent, want %d", len(headPaths), maxGrabbedLinkCount)
+	}
+}
+
+func TestLinkGrabberGrabLinksPageSize(t *testing.T) {
+	// the second link starts right after the part of the page searched for links
+	page := `<a href="first.zip">first</a>`
+	page += strings.Repeat(" ", grabbedPageMaxSize-len(page)) + `<a href="second.zip">second</a>`
+	server := newTestLinkServer(t, page)
+
+	grabbedLinks, err := newTestLinkGrabber().grabLinks(context.Background(), GrabLinksParams{URL: server.URL + "/pub/index.html"})
+	if err != nil {
+		t.Fatalf("grabLinks() error = %v", err)
+	}
+
+	if fileNames := getTestGrabbedFileNames(grabbedLinks); !slices.Equal(fileNames, []string{"first.zip"}) {
+		t.Errorf("grabLinks() returned %q, want only %q", fileNames, "first.zip")
+	}
+}
+
+func TestLinkGrabberGrabLinksErrors(t *testing.T) {
+	server := newTestLinkServer(t, testLinkPage)
+
+	testCases := []struct {
+		name           string
+		params         GrabLinksParams
+		wantErr        error
+		wantStatusCode int
+	}{
+		{
+			name:    "neither URL nor content",
+			params:  GrabLinksParams{},
+			wantErr: errGrabLinksSourceMissing,
+		},
+		{
+			name:    "FTP URL",
+			params:  GrabLinksParams{URL: "ftp://example.invalid/pub/"},
+			wantErr: errInvalidGrabLinksURL,
+		},
+		{
+			name:    "URL without a host",
+			params:  GrabLinksParams{URL: "http:///pub/"},
+			wantErr: errInvalidGrabLinksURL,
+		},
+		{
+			name:    "content over the size limit",
+			params:  GrabLinksParams{Content: strings.Repeat("a", grabbedPageMaxSize+1)},
+			wantErr: errGrabbedContentTooLarge,
+		},
+		{
+			name:           "page not found",
+			params:         GrabLinksParams{URL: server.URL + "/pub/missing.html"},
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := newTestLinkGrabber().grabLinks(context.Background(), testCase.params)
+
+			var statusError httpStatusError
+			switch {
+			case testCase.wantErr != nil && !errors.Is(err, testCase.wantErr):
+				t.Errorf("grabLinks() error = %v, want %v", err, testCase.wantErr)
+			case testCase.wantStatusCode != 0 && (!errors.As(err, &statusError) || statusError.statusCode != testCase.wantStatusCode):
+				t.Errorf("grabLinks() error = %v, want a %d status", err, testCase.wantStatusCode)
+			}
+		})
+	}
+
+	_, err := newTestLinkGrabber().grabLinks(context.Background(), GrabLinksParams{Content: "http://example.invalid/a.zip", Pattern: "("})
+	if err == nil || !strings.Contains(err.Error(), "invalid link pattern") {
+		t.Errorf("grabLinks() error = %v, want an invalid link pattern", err)
+	}
+}
+
+// testSQLConnector opens connections that begin and commit transactions but run no statements, for handlers whose
+// data accessors are stand-ins.
+type testSQLConnector struct{}
+
+func (c testSQLConnector) Connect(context.Context) (driver.Conn, error) { return testSQLConn{}, nil }
+func (c testSQLConnector) Driver() driver.Driver                        { return c }
+func (c testSQLConnector) Open(string) (driver.Conn, error)             { return testSQLConn{}, nil }
+
+type testSQLConn struct{}
+
+func (c testSQLConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("statements are not supported")
+}
+func (c testSQLConn) Close() error              { return nil }
+func (c testSQLConn) Begin() (driver.Tx, error) { return c, nil }
+func (c testSQLConn) Commit() error             { return nil }
+func (c testSQLConn) Rollback() error           { return nil }
+
+type testTokenHandler struct {
+	accountID uint64
+}
+
+func (h testTokenHandler) GetToken(context.Context, uint64) (string, time.Time, error) {
+	return "token", time.Now().Add(time.Hour), nil
+}
+
+func (h testTokenHandler) GetAccountIDAndExpireTime(context.Context, string) (uint64, time.Time, error) {
+	return h.accountID, time.Now().Add(time.Hour), nil
+}
+
+func (h testTokenHandler) WithDatabase(database.Database) TokenHandler {
+	return h
+}
+
+type testDownloaderRegistry struct{}
+
+func (r testDownloaderRegistry) GetDownloader(go_load.DownloadType) (Downloader, error) {
+	return nil, nil
+}
+
+type testAccountDataAccessor struct {
+	database.AccountDataAccessor
+}
+
+func (a testAccountDataAccessor) GetAccountByID(_ context.Context, id uint64) (database.Account, error) {
+	return database.Account{ID: id, AccountName: "alice"}, nil
+}
+
+// testDownloadTaskDataAccessor keeps the tasks created, calling any other method of the interface panics.
+type testDownloadTaskDataAccessor struct {
+	database.DownloadTaskDataAccessor
+
+	tasks *[]database.DownloadTask
+}
+
+func (a testDownloadTaskDataAccessor) CreateDownloadTask(_ context.Context, task database.DownloadTask) (uint64, error) {
+	*a.tasks = append(*a.tasks, task)
+	return uint64(len(*a.tasks)), nil
+}
+
+func (a testDownloadTaskDataAccessor) WithDatabase(database.Database) database.DownloadTaskDataAccessor {
+	return a
+}
+
+type testWebhookEventPublisher struct{}
+
+func (p testWebhookEventPublisher) Publish(context.Context, WebhookEvent) {}
+
+func newTestLinkURLs(count int) []string {
+	urls := make([]string, 0, count)
+	for i := range count {
+		urls = append(urls, fmt.Sprintf("http://example.com/files/%d.zip", i))
+	}
+	return urls
+}
+
+func TestDownloadTaskHandlerCreateDownloadTasksFromLinks(t *testing.T) {
+	const accountID = 7
+
+	testCases := []struct {
+		name              string
+		urls              []string
+		wantErr           error
+		wantURLs          []string
+		wantDownloadTypes []go_load.DownloadType
+	}{
+		{
+			name:              "every link",
+			urls:              []string{" https://example.com/a.zip ", "ftp://example.com/pub/b.iso", "http://example.com/c"},
+			wantURLs:          []string{"https://example.com/a.zip", "ftp://example.com/pub/b.iso", "http://example.com/c"},
+			wantDownloadTypes: []go_load.DownloadType{go_load.DownloadType_HTTP, go_load.DownloadType_FTP, go_load.DownloadType_HTTP},
+		},
+		{
+			name:     "as many links as allowed",
+			urls:     newTestLinkURLs(maxGrabbedLinkCount),
+			wantURLs: newTestLinkURLs(maxGrabbedLinkCount),
+		},
+		{
+			name:    "no links",
+			urls:    []string{},
+			wantErr: errNoLinksToCreate,
+		},
+		{
+			name:    "too many links",
+			urls:    newTestLinkURLs(maxGrabbedLinkCount + 1),
+			wantErr: errTooManyLinksToCreate,
+		},
+		{
+			// no task is created for the links before it
+			name:    "unsupported scheme",
+			urls:    []string{"https://example.com/a.zip", "sftp://example.com/b.iso"},
+			wantErr: errUnsupportedLinkURLScheme,
+		},
+		{
+			name:    "relative link",
+			urls:    []string{"https://example.com/a.zip", "files/b.zip"},
+			wantErr: errUnsupportedLinkURLScheme,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			createdTasks := make([]database.DownloadTask, 0)
+			handler := downloadTaskHandler{
+				tokenHandler:             testTokenHandler{accountID: accountID},
+				accountDataAccessor:      testAccountDataAccessor{},
+				downloadTaskDataAccessor: testDownloadTaskDataAccessor{tasks: &createdTasks},
+				downloaderRegistry:       testDownloaderRegistry{},
+				webhookEventPublisher:    testWebhookEventPublisher{},
+				goquDatabase:             goqu.New("mysql", sql.OpenDB(testSQLConnector{})),
+				location:                 time.UTC,
+				logger:                   zap.NewNop(),
+			}
+
+			downloadTaskList, err := handler.CreateDownloadTasksFromLinks(context.Background(), CreateDownloadTasksFromLinksParams{
+				Token: "token",
+				URLs:  testCase.urls,
+			})
+			if testCase.wantErr != nil {
+				if !errors.Is(err, testCase.wantErr) {
+					t.Errorf("CreateDownloadTasksFromLinks() error = %v, want %v", err, testCase.wantErr)
+				}
+				if len(createdTasks) != 0 {
+					t.Errorf("%d tasks created, want none", len(createdTasks))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateDownloadTasksFromLinks() error = %v", err)
+			}
+
+			if len(downloadTaskList) != len(testCase.wantURLs) || len(createdTasks) != len(testCase.wantURLs) {
+				t.Fatalf("%d tasks returned and %d created, want %d", len(downloadTaskList), len(createdTasks), len(testCase.wantURLs))
+			}
+			for i, downloadTask := range downloadTaskList {
+				if downloadTask.GetUrl() != testCase.wantURLs[i] || createdTasks[i].URL != testCase.wantURLs[i] {
+					t.Errorf("task %d has URL %q, created with %q, want %q", i, downloadTask.GetUrl(), createdTasks[i].URL, testCase.wantURLs[i])
+				}
+				if downloadTask.GetId() != uint64(i+1) || downloadTask.GetOfAccount().GetId() != accountID ||
+					createdTasks[i].OfAccountID != accountID {
+					t.Errorf("task %d has ID %d and account %d, want %d and %d", i, downloadTask.GetId(), createdTasks[i].OfAccountID, i+1, accountID)
+				}
+				if downloadTask.GetDownloadStatus() != go_load.DownloadStatus_Pending {
+					t.Errorf("task %d has status %v, want %v", i, downloadTask.GetDownloadStatus(), go_load.DownloadStatus_Pending)
+				}
+				if testCase.wantDownloadTypes != nil && downloadTask.GetDownloadType() != testCase.wantDownloadTypes[i] {
+					t.Errorf("task %d has download type %v, want %v", i, downloadTask.GetDownloadType(), testCase.wantDownloadTypes[i])
+				}
+			}
+		})
+	}
+}
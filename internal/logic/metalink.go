@@ -0,0 +1,397 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
+	"go.uber.org/zap"
+)
+
+const (
+	metalinkNamespace    = "urn:ietf:params:xml:ns:metalink"
+	maxMetalinkFileCount = 256
+	// metalinkLowestPriority is the priority of metalink 4 URLs without one, RFC 5854 allows 1 to 999999
+	metalinkLowestPriority = 999999
+)
+
+var (
+	errInvalidMetalink             = errors.New("invalid metalink document")
+	errMetalinkExportNotSupported  = errors.New("only completed HTTP and FTP download tasks of a single file can be exported as metalink")
+	errNoSupportedMetalinkURL      = errors.New("metalink file has no HTTP, HTTPS or FTP URL")
+	errTooManyMetalinkFiles        = errors.New("too many files in metalink document")
+	errMetalinkPieceCountMismatch  = errors.New("metalink pieces do not cover the size of the file")
+	errUnsupportedMetalinkHashType = errors.New("unsupported metalink piece hash type")
+
+	// metalinkHashTypes maps the hash names of metalink 4 (IANA names) and metalink 3 to digest algorithms
+	metalinkHashTypes = map[string]go_load.DigestAlgorithm{
+		"md5":     go_load.DigestAlgorithm_MD5,
+		"sha-1":   go_load.DigestAlgorithm_SHA1,
+		"sha1":    go_load.DigestAlgorithm_SHA1,
+		"sha-256": go_load.DigestAlgorithm_SHA256,
+		"sha256":  go_load.DigestAlgorithm_SHA256,
+		"sha-512": go_load.DigestAlgorithm_SHA512,
+		"sha512":  go_load.DigestAlgorithm_SHA512,
+	}
+	metalinkHashNames = map[go_load.DigestAlgorithm]string{
+		go_load.DigestAlgorithm_MD5:    "md5",
+		go_load.DigestAlgorithm_SHA1:   "sha-1",
+		go_load.DigestAlgorithm_SHA256: "sha-256",
+		go_load.DigestAlgorithm_SHA512: "sha-512",
+	}
+	// metalinkHashPreference lists the digest algorithms from the strongest, only the strongest one of a file is checked
+	metalinkHashPreference = []go_load.DigestAlgorithm{
+		go_load.DigestAlgorithm_SHA512,
+		go_load.DigestAlgorithm_SHA256,
+		go_load.DigestAlgorithm_SHA1,
+		go_load.DigestAlgorithm_MD5,
+	}
+)
+
+// metalinkDocument is a metalink 4 document (RFC 5854). Metalink 3 documents, which nest their files, hashes and URLs
+// one level deeper, are read into the same structure. Namespaces are ignored when reading.
+type metalinkDocument struct {
+	XMLName   xml.Name       `xml:"metalink"`
+	Namespace string         `xml:"xmlns,attr,omitempty"`
+	Generator string         `xml:"generator,omitempty"`
+	Published string         `xml:"published,omitempty"`
+	Files     []metalinkFile `xml:"file"`
+	// metalink 3 keeps files under files, hashes and pieces under verification and URLs under resources
+	FilesV3 *metalinkFiles `xml:"files"`
+}
+
+type metalinkFiles struct {
+	Files []metalinkFile `xml:"file"`
+}
+
+type metalinkFile struct {
+	Name   string          `xml:"name,attr"`
+	Size   int64           `xml:"size,omitempty"`
+	Hashes []metalinkHash  `xml:"hash"`
+	Pieces *metalinkPieces `xml:"pieces"`
+	URLs   []metalinkURL   `xml:"url"`
+	// Verification and Resources are only set in metalink 3 documents
+	Verification *metalinkVerification `xml:"verification"`
+	Resources    *metalinkResources    `xml:"resources"`
+}
+
+type metalinkVerification struct {
+	Hashes []metalinkHash  `xml:"hash"`
+	Pieces *metalinkPieces `xml:"pieces"`
+}
+
+type metalinkResources struct {
+	URLs []metalinkURL `xml:"url"`
+}
+
+type metalinkHash struct {
+	Type  string `xml:"type,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type metalinkPieces struct {
+	Length int64          `xml:"length,attr"`
+	Type   string         `xml:"type,attr"`
+	Hashes []metalinkHash `xml:"hash"`
+}
+
+type metalinkURL struct {
+	// Priority orders metalink 4 URLs from 1, Preference orders metalink 3 URLs from 100
+	Priority   int    `xml:"priority,attr,omitempty"`
+	Preference int    `xml:"preference,attr,omitempty"`
+	Value      string `xml:",chardata"`
+}
+
+func (u metalinkURL) getRank() int {
+	switch {
+	case u.Priority > 0:
+		return u.Priority
+	case u.Preference > 0:
+		return metalinkLowestPriority - u.Preference
+	default:
+		return metalinkLowestPriority
+	}
+}
+
+func parseMetalinkDocument(data []byte) ([]metalinkFile, error) {
+	var document metalinkDocument
+	err := xml.Unmarshal(data, &document)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidMetalink, err)
+	}
+
+	files := document.Files
+	if document.FilesV3 != nil {
+		files = append(files, document.FilesV3.Files...)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: no file", errInvalidMetalink)
+	}
+	if len(files) > maxMetalinkFileCount {
+		return nil, errTooManyMetalinkFiles
+	}
+
+	for i := range files {
+		if verification := files[i].Verification; verification != nil {
+			files[i].Hashes = append(files[i].Hashes, verification.Hashes...)
+			if files[i].Pieces == nil {
+				files[i].Pieces = verification.Pieces
+			}
+		}
+		if resources := files[i].Resources; resources != nil {
+			files[i].URLs = append(files[i].URLs, resources.URLs...)
+		}
+	}
+	return files, nil
+}
+
+// getURLDownloadType returns the download type of an HTTP, HTTPS or FTP URL listed in a metalink or grabbed from a
+// page, URLs of other types are left out.
+func getURLDownloadType(rawURL string) (go_load.DownloadType, bool) {
+	parsedURL, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || parsedURL.Host == "" {
+		return go_load.DownloadType_UndefinedType, false
+	}
+
+	switch strings.ToLower(parsedURL.Scheme) {
+	case "http", "https":
+		return go_load.DownloadType_HTTP, true
+	case "ftp":
+		return go_load.DownloadType_FTP, true
+	default:
+		return go_load.DownloadType_UndefinedType, false
+	}
+}
+
+// toCreateDownloadTaskParams returns the params creating a task for the file. The task downloads from the URL with
+// the highest priority. HTTP URLs are preferred over FTP ones, the other HTTP URLs becoming its mirrors.
+func (f metalinkFile) toCreateDownloadTaskParams(downloadQueueID uint64) (CreateDownloadTaskParams, error) {
+	urls := slices.Clone(f.URLs)
+	slices.SortStableFunc(urls, func(a, b metalinkURL) int {
+		return a.getRank() - b.getRank()
+	})
+
+	var httpURLs, ftpURLs []string
+	for _, metalinkURL := range urls {
+		downloadType, ok := getURLDownloadType(metalinkURL.Value)
+		switch {
+		case !ok:
+			continue
+		case downloadType == go_load.DownloadType_HTTP:
+			httpURLs = append(httpURLs, strings.TrimSpace(metalinkURL.Value))
+		default:
+			ftpURLs = append(ftpURLs, strings.TrimSpace(metalinkURL.Value))
+		}
+	}
+
+	params := CreateDownloadTaskParams{
+		DownloadQueueID: downloadQueueID,
+	}
+	switch {
+	case len(httpURLs) > 0:
+		params.DownloadType = go_load.DownloadType_HTTP
+		params.URL = httpURLs[0]
+		params.MirrorURLs = httpURLs[1:min(len(httpURLs), maxMirrorURLCount+1)]
+	case len(ftpURLs) > 0:
+		params.DownloadType = go_load.DownloadType_FTP
+		params.URL = ftpURLs[0]
+	default:
+		return CreateDownloadTaskParams{}, fmt.Errorf("%w: %q", errNoSupportedMetalinkURL, f.Name)
+	}
+
+	if f.Size > 0 {
+		params.ExpectedSize = uint64(f.Size)
+	}
+
+	hashes := make(map[go_load.DigestAlgorithm]string, len(f.Hashes))
+	for _, hash := range f.Hashes {
+		if algorithm, ok := metalinkHashTypes[strings.ToLower(hash.Type)]; ok {
+			hashes[algorithm] = strings.TrimSpace(hash.Value)
+		}
+	}
+	for _, algorithm := range metalinkHashPreference {
+		if value, ok := hashes[algorithm]; ok {
+			params.ExpectedDigest = &go_load.Digest{Algorithm: algorithm, Value: value}
+			break
+		}
+	}
+
+	if f.Pieces != nil && len(f.Pieces.Hashes) > 0 {
+		pieceDigests, err := f.getPieceDigests()
+		if err != nil {
+			return CreateDownloadTaskParams{}, err
+		}
+		params.PieceDigests = pieceDigests
+	}
+
+	return params, nil
+}
+
+func (f metalinkFile) getPieceDigests() (*go_load.PieceDigests, error) {
+	algorithm, ok := metalinkHashTypes[strings.ToLower(f.Pieces.Type)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", errUnsupportedMetalinkHashType, f.Pieces.Type)
+	}
+
+	if f.Pieces.Length <= 0 {
+		return nil, fmt.Errorf("%w: invalid piece length %d", errInvalidMetalink, f.Pieces.Length)
+	}
+
+	values := make([]string, 0, len(f.Pieces.Hashes))
+	for _, hash := range f.Pieces.Hashes {
+		values = append(values, strings.TrimSpace(hash.Value))
+	}
+
+	if f.Size > 0 && int((f.Size+f.Pieces.Length-1)/f.Pieces.Length) != len(values) {
+		return nil, fmt.Errorf("%w: %q", errMetalinkPieceCountMismatch, f.Name)
+	}
+
+	return &go_load.PieceDigests{
+		Algorithm:   algorithm,
+		PieceLength: uint64(f.Pieces.Length),
+		Values:      values,
+	}, nil
+}
+
+type ImportMetalinkParams struct {
+	Token    string
+	Metalink []byte
+	// DownloadQueueID adds the created tasks to the end of one of the account's download queues
+	DownloadQueueID uint64
+}
+
+type ExportDownloadTaskMetalinkParams struct {
+	Token          string
+	DownloadTaskID uint64
+}
+
+// ImportMetalink creates a download task for every file of a metalink 3 or 4 document, with the mirrors, size, hash and
+// piece hashes the document lists. Nothing is created when a file of the document cannot be downloaded, a task failing
+// to be created stops the import and keeps the tasks created before it.
+func (d downloadTaskHandler) ImportMetalink(ctx context.Context, params ImportMetalinkParams) ([]*go_load.DownloadTask, error) {
+	accountID, _, err := d.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to verify token")
+		return nil, err
+	}
+
+	files, err := parseMetalinkDocument(params.Metalink)
+	if err != nil {
+		d.logger.With(zap.Error(err), zap.Uint64("accountID", accountID)).Warn("failed to parse metalink document")
+		return nil, err
+	}
+
+	createParamsList := make([]CreateDownloadTaskParams, 0, len(files))
+	for _, file := range files {
+		createParams, err := file.toCreateDownloadTaskParams(params.DownloadQueueID)
+		if err != nil {
+			return nil, err
+		}
+
+		// digests are checked here as well, so that a bad one does not leave the document half imported
+		_, err = digestFromProto(createParams.ExpectedDigest)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = pieceDigestsFromProto(createParams.PieceDigests)
+		if err != nil {
+			return nil, err
+		}
+		createParamsList = append(createParamsList, createParams)
+	}
+
+	downloadTaskList := make([]*go_load.DownloadTask, 0, len(createParamsList))
+	for _, createParams := range createParamsList {
+		output, err := d.createDownloadTask(ctx, accountID, createParams)
+		if err != nil {
+			return nil, err
+		}
+		downloadTaskList = append(downloadTaskList, output.DownloadTask)
+	}
+
+	d.logger.With(zap.Uint64("accountID", accountID), zap.Int("taskCount", len(downloadTaskList))).Info("metalink imported")
+	return downloadTaskList, nil
+}
+
+// ExportDownloadTaskMetalink returns a metalink 4 document describing the downloaded file of a completed task, with
+// the URL of the task and its mirrors, its SHA-256 digest, and the expected digest and piece digests it was created with.
+func (d downloadTaskHandler) ExportDownloadTaskMetalink(ctx context.Context, params ExportDownloadTaskMetalinkParams) ([]byte, error) {
+	accountID, _, err := d.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		d.logger.With(zap.Error(err)).Error("failed to verify token")
+		return nil, err
+	}
+
+	task, err := d.getOwnedDownloadTask(ctx, d.downloadTaskDataAccessor, accountID, params.DownloadTaskID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := parseDownloadTaskMetadata(task.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.DownloadStatus != uint16(go_load.DownloadStatus_Success) || len(metadata.Files) > 0 ||
+		(task.DownloadType != uint16(go_load.DownloadType_HTTP) && task.DownloadType != uint16(go_load.DownloadType_FTP)) {
+		return nil, errMetalinkExportNotSupported
+	}
+
+	size := metadata.Size
+	if size <= 0 {
+		size = metadata.DownloadedByteCount
+	}
+
+	file := metalinkFile{
+		// the stored file name is prefixed with the task ID, see getDownloadFileName
+		Name: strings.TrimPrefix(metadata.FileName, fmt.Sprintf("%d_", task.ID)),
+		Size: size,
+	}
+
+	if metadata.SHA256 != "" {
+		file.Hashes = append(file.Hashes, metalinkHash{Type: metalinkHashNames[go_load.DigestAlgorithm_SHA256], Value: metadata.SHA256})
+	}
+	if expectedDigest := metadata.ExpectedDigest; expectedDigest != nil && expectedDigest.Algorithm != go_load.DigestAlgorithm_SHA256 {
+		if hashName, ok := metalinkHashNames[expectedDigest.Algorithm]; ok {
+			file.Hashes = append(file.Hashes, metalinkHash{Type: hashName, Value: expectedDigest.Value})
+		}
+	}
+
+	if pieceDigests := metadata.PieceDigests; pieceDigests != nil {
+		if hashName, ok := metalinkHashNames[pieceDigests.Algorithm]; ok {
+			file.Pieces = &metalinkPieces{Length: pieceDigests.PieceLength, Type: hashName}
+			for _, value := range pieceDigests.Values {
+				file.Pieces.Hashes = append(file.Pieces.Hashes, metalinkHash{Value: value})
+			}
+		}
+	}
+
+	for i, downloadURL := range append([]string{task.URL}, splitMirrorURLs(task.MirrorURLs)...) {
+		file.URLs = append(file.URLs, metalinkURL{Priority: i + 1, Value: downloadURL})
+	}
+
+	document := metalinkDocument{
+		Namespace: metalinkNamespace,
+		Generator: "GoLoad",
+		Published: time.Now().UTC().Format(time.RFC3339),
+		Files:     []metalinkFile{file},
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buffer)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(document)
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,348 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/database"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
+	"go.uber.org/zap"
+)
+
+const (
+	postDownloadStepRename = "rename"
+	postDownloadStepMove   = "move"
+	postDownloadStepChmod  = "chmod"
+	postDownloadStepExec   = "exec"
+	postDownloadStepHTTP   = "http"
+
+	postDownloadOnFailureFail = "fail"
+	postDownloadOnFailureWarn = "warn"
+
+	defaultPostDownloadStepTimeout = time.Minute
+	// maxPostDownloadStepMessageLength bounds the command output or error kept on the task for each step
+	maxPostDownloadStepMessageLength = 1024
+)
+
+var (
+	errInvalidPostDownloadStep = errors.New("invalid post-download step")
+	errPostDownloadStepFailed  = errors.New("post-download step failed")
+	errUnsafePostDownloadPath  = errors.New("post-download step path escapes the download directory")
+)
+
+// postDownloadStepResult records how a post-download step went, Message holds the output of exec steps and the error
+// of failed steps.
+type postDownloadStepResult struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Succeeded bool   `json:"succeeded"`
+	Message   string `json:"message,omitempty"`
+}
+
+func (r postDownloadStepResult) toProto() *go_load.PostDownloadStepResult {
+	return &go_load.PostDownloadStepResult{
+		Name:      r.Name,
+		Type:      r.Type,
+		Succeeded: r.Succeeded,
+		Message:   r.Message,
+	}
+}
+
+type postDownloadStep struct {
+	config   configs.PostDownloadStepConfig
+	name     string
+	failTask bool
+	mode     fs.FileMode
+	timeout  time.Duration
+}
+
+func newPostDownloadSteps(stepConfigs []configs.PostDownloadStepConfig) ([]postDownloadStep, error) {
+	steps := make([]postDownloadStep, 0, len(stepConfigs))
+	for _, stepConfig := range stepConfigs {
+		step := postDownloadStep{
+			config:  stepConfig,
+			name:    stepConfig.Name,
+			timeout: defaultPostDownloadStepTimeout,
+		}
+		if step.name == "" {
+			step.name = stepConfig.Type
+		}
+
+		switch stepConfig.OnFailure {
+		case postDownloadOnFailureFail:
+			step.failTask = true
+		case postDownloadOnFailureWarn, "":
+		default:
+			return nil, fmt.Errorf("%w %q: unknown on_failure %q", errInvalidPostDownloadStep, step.name, stepConfig.OnFailure)
+		}
+
+		if stepConfig.Timeout != "" {
+			var err error
+			step.timeout, err = stepConfig.GetTimeoutDuration()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		switch stepConfig.Type {
+		case postDownloadStepRename:
+			if stepConfig.FileName == "" {
+				return nil, fmt.Errorf("%w %q: rename needs a file name", errInvalidPostDownloadStep, step.name)
+			}
+		case postDownloadStepMove:
+			if stepConfig.Directory == "" {
+				return nil, fmt.Errorf("%w %q: move needs a directory", errInvalidPostDownloadStep, step.name)
+			}
+		case postDownloadStepChmod:
+			mode, err := strconv.ParseUint(stepConfig.Mode, 8, 32)
+			if err != nil || mode > uint64(fs.ModePerm) {
+				return nil, fmt.Errorf("%w %q: invalid mode %q", errInvalidPostDownloadStep, step.name, stepConfig.Mode)
+			}
+			step.mode = fs.FileMode(mode)
+		case postDownloadStepExec:
+			if stepConfig.Command == "" {
+				return nil, fmt.Errorf("%w %q: exec needs a command", errInvalidPostDownloadStep, step.name)
+			}
+		case postDownloadStepHTTP:
+			if stepConfig.URL == "" {
+				return nil, fmt.Errorf("%w %q: http needs a URL", errInvalidPostDownloadStep, step.name)
+			}
+		default:
+			return nil, fmt.Errorf("%w %q: unknown type %q", errInvalidPostDownloadStep, step.name, stepConfig.Type)
+		}
+
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// postDownloadPipeline runs the configured steps on the file of a download task that succeeded.
+type postDownloadPipeline struct {
+	steps        []postDownloadStep
+	accountSteps map[string][]postDownloadStep
+	fileClient   file.Client
+	httpClient   *http.Client
+	logger       *zap.Logger
+}
+
+func newPostDownloadPipeline(
+	configs configs.PostDownloadConfig,
+	fileClient file.Client,
+	logger *zap.Logger,
+) (postDownloadPipeline, error) {
+	steps, err := newPostDownloadSteps(configs.Steps)
+	if err != nil {
+		return postDownloadPipeline{}, err
+	}
+
+	accountSteps := make(map[string][]postDownloadStep, len(configs.AccountSteps))
+	for accountName, stepConfigs := range configs.AccountSteps {
+		accountSteps[accountName], err = newPostDownloadSteps(stepConfigs)
+		if err != nil {
+			return postDownloadPipeline{}, err
+		}
+	}
+
+	return postDownloadPipeline{
+		steps:        steps,
+		accountSteps: accountSteps,
+		fileClient:   fileClient,
+		httpClient:   &http.Client{},
+		logger:       logger,
+	}, nil
+}
+
+func (p postDownloadPipeline) getSteps(accountName string) []postDownloadStep {
+	if steps, ok := p.accountSteps[accountName]; ok {
+		return steps
+	}
+	return p.steps
+}
+
+// run runs the steps of the task's account in order, recording the result of each one in metadata. It returns an
+// error when a step meant to fail the task failed, the steps after it are not run then.
+func (p postDownloadPipeline) run(
+	ctx context.Context,
+	task database.DownloadTask,
+	accountName string,
+	metadata *downloadTaskMetadata,
+) error {
+	metadata.PostDownloadStepResults = nil
+	for _, step := range p.getSteps(accountName) {
+		message, err := p.runStep(ctx, step, p.getVariables(ctx, task, accountName, *metadata), metadata)
+		result := postDownloadStepResult{
+			Name:      step.name,
+			Type:      step.config.Type,
+			Succeeded: err == nil,
+			Message:   message,
+		}
+		if err != nil {
+			result.Message = strings.TrimSpace(err.Error() + "\n" + message)
+		}
+		if len(result.Message) > maxPostDownloadStepMessageLength {
+			result.Message = result.Message[:maxPostDownloadStepMessageLength]
+		}
+		metadata.PostDownloadStepResults = append(metadata.PostDownloadStepResults, result)
+
+		if err == nil {
+			continue
+		}
+
+		p.logger.With(zap.Error(err), zap.Uint64("taskID", task.ID), zap.String("step", step.name)).Warn("post-download step failed")
+		if step.failTask {
+			return fmt.Errorf("%w: %s: %w", errPostDownloadStepFailed, step.name, err)
+		}
+	}
+	return nil
+}
+
+// getVariables returns the values steps can refer to as ${name}.
+func (p postDownloadPipeline) getVariables(
+	ctx context.Context,
+	task database.DownloadTask,
+	accountName string,
+	metadata downloadTaskMetadata,
+) map[string]string {
+	filePath, err := p.fileClient.LocalPath(ctx, metadata.FileName)
+	if err != nil {
+		p.logger.With(zap.Error(err), zap.Uint64("taskID", task.ID)).Warn("failed to get local path of downloaded file")
+	}
+
+	size := metadata.Size
+	if size <= 0 {
+		size = metadata.DownloadedByteCount
+	}
+
+	return map[string]string{
+		"task_id":       strconv.FormatUint(task.ID, 10),
+		"account_id":    strconv.FormatUint(task.OfAccountID, 10),
+		"account_name":  accountName,
+		"download_type": go_load.DownloadType(task.DownloadType).String(),
+		"url":           task.URL,
+		"file_name":     path.Base(metadata.FileName),
+		"file_path":     filePath,
+		"size":          strconv.FormatInt(size, 10),
+		"sha256":        metadata.SHA256,
+	}
+}
+
+func expandPostDownloadVariables(value string, variables map[string]string) string {
+	return os.Expand(value, func(name string) string {
+		return variables[name]
+	})
+}
+
+// runStep runs a single step, rename and move steps update the file name in metadata.
+func (p postDownloadPipeline) runStep(
+	ctx context.Context,
+	step postDownloadStep,
+	variables map[string]string,
+	metadata *downloadTaskMetadata,
+) (string, error) {
+	switch step.config.Type {
+	case postDownloadStepRename:
+		fileName := expandPostDownloadVariables(step.config.FileName, variables)
+		if !isSafePathElement(fileName) {
+			return "", errUnsafePostDownloadPath
+		}
+		return "", p.moveFile(ctx, metadata, path.Join(path.Dir(metadata.FileName), fileName))
+	case postDownloadStepMove:
+		directory := expandPostDownloadVariables(step.config.Directory, variables)
+		if !isSafeRelativePath(directory) {
+			return "", errUnsafePostDownloadPath
+		}
+		return "", p.moveFile(ctx, metadata, path.Join(directory, path.Base(metadata.FileName)))
+	case postDownloadStepChmod:
+		return "", p.fileClient.Chmod(ctx, metadata.FileName, step.mode)
+	case postDownloadStepExec:
+		return p.runCommand(ctx, step, variables)
+	case postDownloadStepHTTP:
+		return p.callHTTPEndpoint(ctx, step, variables)
+	default:
+		return "", fmt.Errorf("%w: unknown type %q", errInvalidPostDownloadStep, step.config.Type)
+	}
+}
+
+func (p postDownloadPipeline) moveFile(ctx context.Context, metadata *downloadTaskMetadata, fileName string) error {
+	if fileName == metadata.FileName {
+		return nil
+	}
+
+	err := p.fileClient.Rename(ctx, metadata.FileName, fileName)
+	if err != nil {
+		return err
+	}
+
+	metadata.FileName = fileName
+	return nil
+}
+
+// runCommand runs the command of an exec step without a shell, with the variables in its arguments and in its
+// environment as GOLOAD_ followed by their upper case name.
+func (p postDownloadPipeline) runCommand(ctx context.Context, step postDownloadStep, variables map[string]string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, step.timeout)
+	defer cancel()
+
+	args := make([]string, 0, len(step.config.Args))
+	for _, arg := range step.config.Args {
+		args = append(args, expandPostDownloadVariables(arg, variables))
+	}
+
+	command := exec.CommandContext(ctx, expandPostDownloadVariables(step.config.Command, variables), args...)
+	command.Env = os.Environ()
+	for name, value := range variables {
+		command.Env = append(command.Env, "GOLOAD_"+strings.ToUpper(name)+"="+value)
+	}
+
+	output, err := command.CombinedOutput()
+	return string(output), err
+}
+
+// callHTTPEndpoint sends the variables as a JSON object to the URL of an http step, any 2xx response is a success.
+func (p postDownloadPipeline) callHTTPEndpoint(ctx context.Context, step postDownloadStep, variables map[string]string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, step.timeout)
+	defer cancel()
+
+	body, err := json.Marshal(variables)
+	if err != nil {
+		return "", err
+	}
+
+	method := step.config.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, expandPostDownloadVariables(step.config.URL, variables), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for name, value := range step.config.Headers {
+		request.Header.Set(name, expandPostDownloadVariables(value, variables))
+	}
+
+	response, err := p.httpClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return "", newHTTPStatusError(response.StatusCode, "unexpected response status: %s", response.Status)
+	}
+	return response.Status, nil
+}
@@ -0,0 +1,81 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	errInvalidTimeOfDay = errors.New("times of day must look like 09:00")
+	errInvalidWeekday   = errors.New("unknown weekday")
+)
+
+// timeWindow is a daily period from start to end, both minutes since midnight. A window whose end is not after its
+// start spans midnight, the part after midnight belongs to the day before.
+type timeWindow struct {
+	start int
+	end   int
+	// weekdays are the days the window starts on, it applies to every day when empty
+	weekdays map[time.Weekday]bool
+}
+
+func parseTimeWindow(start, end string, weekdayNames []string) (timeWindow, error) {
+	startMinute, err := parseTimeOfDay(start)
+	if err != nil {
+		return timeWindow{}, err
+	}
+
+	endMinute, err := parseTimeOfDay(end)
+	if err != nil {
+		return timeWindow{}, err
+	}
+
+	weekdays := make(map[time.Weekday]bool, len(weekdayNames))
+	for _, weekdayName := range weekdayNames {
+		weekday, ok := parseWeekday(weekdayName)
+		if !ok {
+			return timeWindow{}, fmt.Errorf("%w: %q", errInvalidWeekday, weekdayName)
+		}
+		weekdays[weekday] = true
+	}
+
+	return timeWindow{
+		start:    startMinute,
+		end:      endMinute,
+		weekdays: weekdays,
+	}, nil
+}
+
+// parseTimeOfDay returns the minutes since midnight of a time like "09:00".
+func parseTimeOfDay(value string) (int, error) {
+	parsedTime, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", errInvalidTimeOfDay, value)
+	}
+	return parsedTime.Hour()*60 + parsedTime.Minute(), nil
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if strings.EqualFold(name, weekday.String()) || strings.EqualFold(name, weekday.String()[:3]) {
+			return weekday, true
+		}
+	}
+	return 0, false
+}
+
+// includes tells whether the window applies at the given time, which is expected in the time zone of the window.
+func (w timeWindow) includes(now time.Time) bool {
+	appliesOn := func(weekday time.Weekday) bool {
+		return len(w.weekdays) == 0 || w.weekdays[weekday]
+	}
+
+	weekday := now.Weekday()
+	minute := now.Hour()*60 + now.Minute()
+	if w.start < w.end {
+		return appliesOn(weekday) && w.start <= minute && minute < w.end
+	}
+	return appliesOn(weekday) && minute >= w.start || appliesOn((weekday+6)%7) && minute < w.end
+}
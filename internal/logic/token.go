@@ -180,7 +180,7 @@ func (t tokenHandler) GetToken(ctx context.Context, accountID uint64) (string, t
 	token := jwt.NewWithClaims(jwt.SigningMethodRS512, jwt.MapClaims{
 		"kid": t.publicKeyID,
 		"sub": accountID,
-		"exp": expireTime,
+		"exp": expireTime.Unix(),
 	})
 
 	signedToken, err := token.SignedString(t.privateKey)
@@ -0,0 +1,414 @@
+package logic
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/database"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
+	"go.uber.org/zap"
+)
+
+const (
+	minWatchCheckIntervalSec        = 60
+	defaultWatchRetentionCount      = 10
+	maxWatchRetentionCount          = 100
+	watchedDownloadTaskBatchSize    = 100
+	maxConcurrentWatchCheckCount    = 4
+	defaultWatchCheckRequestTimeout = time.Hour
+)
+
+var (
+	errWatchNotSupported        = errors.New("only HTTP download tasks can watch their URL")
+	errWatchWithCronExpression  = errors.New("a watched download task cannot have a cron expression")
+	errWatchWithExtractArchive  = errors.New("the archive of a watched download task cannot be extracted")
+	errWatchCheckIntervalTooLow = fmt.Errorf("the check interval of a watched download task must be at least %d seconds", minWatchCheckIntervalSec)
+	errTooManyRetainedVersions  = fmt.Errorf("a watched download task can keep at most %d versions", maxWatchRetentionCount)
+)
+
+// watchOptionsFromProto checks the watch options of a new task, a retention count of 0 keeps the default number of
+// versions. A watched task downloads a single file once, recurring and extracted downloads cannot be watched.
+func watchOptionsFromProto(params CreateDownloadTaskParams) (*watchOptions, error) {
+	options := params.WatchOptions
+	if options == nil {
+		return nil, nil
+	}
+
+	switch {
+	case params.DownloadType != go_load.DownloadType_HTTP:
+		return nil, errWatchNotSupported
+	case params.CronExpression != "":
+		return nil, errWatchWithCronExpression
+	case params.ExtractArchive:
+		return nil, errWatchWithExtractArchive
+	case options.GetCheckIntervalSec() < minWatchCheckIntervalSec:
+		return nil, errWatchCheckIntervalTooLow
+	}
+
+	retentionCount := options.GetRetentionCount()
+	if retentionCount == 0 {
+		retentionCount = defaultWatchRetentionCount
+	}
+	if retentionCount > maxWatchRetentionCount {
+		return nil, errTooManyRetainedVersions
+	}
+
+	return &watchOptions{
+		CheckIntervalSec: options.GetCheckIntervalSec(),
+		RetentionCount:   retentionCount,
+	}, nil
+}
+
+func (w watchOptions) getCheckInterval() time.Duration {
+	return time.Duration(w.CheckIntervalSec) * time.Second
+}
+
+// getDownloadTaskVersionFileName returns the name of the file a later version of a watched task is stored in, the
+// first version being the file the task downloaded.
+func getDownloadTaskVersionFileName(task database.DownloadTask, versionNumber uint32) (string, error) {
+	baseName, err := getDownloadBaseName(task)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d_v%d_%s", task.ID, versionNumber, baseName), nil
+}
+
+// URLWatcher checks the URLs of completed watched download tasks once their check interval passed, and stores the
+// file as a new version of the task when its content changed. The check is a conditional request with the validators
+// of the last response, an unchanged file is not downloaded again. Versions past the retention count of the task are
+// removed, oldest first. Servers sharing the database claim each due task before checking it.
+type URLWatcher interface {
+	Start(ctx context.Context) error
+}
+
+type urlWatcher struct {
+	downloadTaskDataAccessor        database.DownloadTaskDataAccessor
+	downloadTaskVersionDataAccessor database.DownloadTaskVersionDataAccessor
+	fileClient                      file.Client
+	goquDatabase                    *goqu.Database
+	httpClient                      *http.Client
+	pollInterval                    time.Duration
+	logger                          *zap.Logger
+}
+
+func NewURLWatcher(
+	configs configs.DownloadConfig,
+	downloadTaskDataAccessor database.DownloadTaskDataAccessor,
+	downloadTaskVersionDataAccessor database.DownloadTaskVersionDataAccessor,
+	fileClient file.Client,
+	goquDatabase *goqu.Database,
+	logger *zap.Logger,
+) (URLWatcher, error) {
+	pollInterval := defaultPollInterval
+	if configs.PollInterval != "" {
+		var err error
+		pollInterval, err = configs.GetPollIntervalDuration()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &urlWatcher{
+		downloadTaskDataAccessor:        downloadTaskDataAccessor,
+		downloadTaskVersionDataAccessor: downloadTaskVersionDataAccessor,
+		fileClient:                      fileClient,
+		goquDatabase:                    goquDatabase,
+		httpClient:                      &http.Client{Timeout: defaultWatchCheckRequestTimeout},
+		pollInterval:                    pollInterval,
+		logger:                          logger,
+	}, nil
+}
+
+func (u urlWatcher) Start(ctx context.Context) error {
+	u.logger.With(zap.Duration("pollInterval", u.pollInterval)).Info("starting URL watcher")
+
+	ticker := time.NewTicker(u.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		u.checkDueDownloadTasks(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (u urlWatcher) checkDueDownloadTasks(ctx context.Context) {
+	now := time.Now()
+	tasks, err := u.downloadTaskDataAccessor.GetDownloadTasksByStatusCheckDueBefore(
+		ctx, uint16(go_load.DownloadStatus_Success), now, watchedDownloadTaskBatchSize)
+	if err != nil {
+		u.logger.With(zap.Error(err)).Error("failed to get watched download tasks due for a check")
+		return
+	}
+
+	semaphore := make(chan struct{}, maxConcurrentWatchCheckCount)
+	var waitGroup sync.WaitGroup
+	for _, task := range tasks {
+		metadata, err := parseDownloadTaskMetadata(task.Metadata)
+		if err != nil || metadata.WatchOptions == nil {
+			u.logger.With(zap.Uint64("taskID", task.ID)).Warn("download task due for a check is not watched")
+			continue
+		}
+
+		// the next check is claimed right away, a server going away while checking leaves the task to that check
+		claimed, err := u.downloadTaskDataAccessor.UpdateDownloadTaskNextCheckAtIfDue(
+			ctx, task.ID, uint16(go_load.DownloadStatus_Success), now, now.Add(metadata.WatchOptions.getCheckInterval()))
+		if err != nil {
+			u.logger.With(zap.Error(err), zap.Uint64("taskID", task.ID)).Error("failed to claim watched download task")
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		semaphore <- struct{}{}
+		waitGroup.Add(1)
+		go func(task database.DownloadTask, metadata downloadTaskMetadata) {
+			defer func() {
+				<-semaphore
+				waitGroup.Done()
+			}()
+
+			err := u.checkDownloadTask(ctx, task, metadata)
+			if err != nil {
+				u.logger.With(zap.Error(err), zap.Uint64("taskID", task.ID)).Warn("failed to check watched download task")
+			}
+		}(task, metadata)
+	}
+	waitGroup.Wait()
+}
+
+// checkDownloadTask checks the URL of a watched task once. The first check after the download completed records the
+// downloaded file as the first version instead.
+func (u urlWatcher) checkDownloadTask(ctx context.Context, task database.DownloadTask, metadata downloadTaskMetadata) error {
+	logger := u.logger.With(zap.Uint64("taskID", task.ID), zap.String("url", task.URL))
+
+	if metadata.VersionNumber == 0 {
+		return u.recordFirstDownloadTaskVersion(ctx, task.ID)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, task.URL, nil)
+	if err != nil {
+		return err
+	}
+	if metadata.ETag != "" {
+		request.Header.Set("If-None-Match", metadata.ETag)
+	}
+	if metadata.LastModified != "" {
+		request.Header.Set("If-Modified-Since", metadata.LastModified)
+	}
+
+	response, err := u.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode == http.StatusNotModified:
+		logger.Debug("watched URL not modified")
+		return nil
+	case response.StatusCode != http.StatusOK:
+		return newHTTPStatusError(response.StatusCode, "unexpected response status: %s", response.Status)
+	case !isRemoteFileChanged(metadata, response):
+		logger.Debug("watched URL serves the same file")
+		return nil
+	}
+
+	versionNumber := metadata.VersionNumber + 1
+	fileName, err := getDownloadTaskVersionFileName(task, versionNumber)
+	if err != nil {
+		return err
+	}
+
+	size, sha256, err := u.writeDownloadTaskVersionFile(ctx, fileName, response.Body)
+	if err != nil {
+		u.deleteFiles(ctx, fileName)
+		return err
+	}
+
+	version := database.DownloadTaskVersion{
+		OfDownloadTaskID: task.ID,
+		VersionNumber:    versionNumber,
+		FileName:         fileName,
+		Size:             size,
+		SHA256:           sha256,
+		ETag:             response.Header.Get("ETag"),
+		LastModified:     response.Header.Get("Last-Modified"),
+		CreatedAt:        time.Now(),
+	}
+	return u.addDownloadTaskVersion(ctx, version)
+}
+
+// writeDownloadTaskVersionFile writes the body of a response into the file of a new version, hashing it on the way.
+func (u urlWatcher) writeDownloadTaskVersionFile(ctx context.Context, fileName string, reader io.Reader) (int64, string, error) {
+	writer, err := u.fileClient.Write(ctx, fileName)
+	if err != nil {
+		return 0, "", err
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(writer, hash), reader)
+	closeErr := writer.Close()
+	if err = errors.Join(err, closeErr); err != nil {
+		return 0, "", err
+	}
+
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// recordFirstDownloadTaskVersion records the file a watched task downloaded as its first version.
+func (u urlWatcher) recordFirstDownloadTaskVersion(ctx context.Context, taskID uint64) error {
+	return u.goquDatabase.WithTx(func(tx *goqu.TxDatabase) error {
+		downloadTaskDataAccessor := u.downloadTaskDataAccessor.WithDatabase(tx)
+		task, err := downloadTaskDataAccessor.GetDownloadTaskByIDWithXLock(ctx, taskID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		metadata, err := parseDownloadTaskMetadata(task.Metadata)
+		if err != nil {
+			return err
+		}
+		if metadata.VersionNumber != 0 {
+			return nil
+		}
+
+		size, err := u.fileClient.Size(ctx, metadata.FileName)
+		if err != nil {
+			return err
+		}
+
+		sha256 := metadata.SHA256
+		if sha256 == "" || len(metadata.PostDownloadStepResults) > 0 {
+			digests, err := computeFileDigests(ctx, u.fileClient, metadata.FileName, []go_load.DigestAlgorithm{go_load.DigestAlgorithm_SHA256})
+			if err != nil {
+				return err
+			}
+			sha256 = digests[0].Value
+		}
+
+		_, err = u.downloadTaskVersionDataAccessor.WithDatabase(tx).CreateDownloadTaskVersion(ctx, database.DownloadTaskVersion{
+			OfDownloadTaskID: task.ID,
+			VersionNumber:    1,
+			FileName:         metadata.FileName,
+			Size:             size,
+			SHA256:           sha256,
+			ETag:             metadata.ETag,
+			LastModified:     metadata.LastModified,
+			CreatedAt:        time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+
+		metadata.SHA256 = sha256
+		metadata.VersionNumber = 1
+		return downloadTaskDataAccessor.UpdateDownloadTaskMetadata(ctx, task.ID, metadata.String())
+	})
+}
+
+// addDownloadTaskVersion makes the new version the file of its task, unless the content did not change in which case
+// only the validators of the task are updated. The files of versions past the retention count are removed once the
+// change is committed.
+func (u urlWatcher) addDownloadTaskVersion(ctx context.Context, version database.DownloadTaskVersion) error {
+	logger := u.logger.With(zap.Uint64("taskID", version.OfDownloadTaskID), zap.Uint32("versionNumber", version.VersionNumber))
+
+	// the version file is removed unless it is committed as the latest version
+	unusedFileNames := []string{version.FileName}
+	txErr := u.goquDatabase.WithTx(func(tx *goqu.TxDatabase) error {
+		downloadTaskDataAccessor := u.downloadTaskDataAccessor.WithDatabase(tx)
+		downloadTaskVersionDataAccessor := u.downloadTaskVersionDataAccessor.WithDatabase(tx)
+		task, err := downloadTaskDataAccessor.GetDownloadTaskByIDWithXLock(ctx, version.OfDownloadTaskID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		metadata, err := parseDownloadTaskMetadata(task.Metadata)
+		if err != nil {
+			return err
+		}
+		if task.DownloadStatus != uint16(go_load.DownloadStatus_Success) || metadata.WatchOptions == nil ||
+			metadata.VersionNumber+1 != version.VersionNumber {
+			return nil
+		}
+
+		metadata.ETag = version.ETag
+		metadata.LastModified = version.LastModified
+		if version.SHA256 == metadata.SHA256 {
+			logger.Info("watched URL serves new validators for the same content")
+			return downloadTaskDataAccessor.UpdateDownloadTaskMetadata(ctx, task.ID, metadata.String())
+		}
+
+		_, err = downloadTaskVersionDataAccessor.CreateDownloadTaskVersion(ctx, version)
+		if err != nil {
+			return err
+		}
+
+		metadata.FileName = version.FileName
+		metadata.Size = version.Size
+		metadata.DownloadedByteCount = version.Size
+		metadata.SHA256 = version.SHA256
+		metadata.Segments = nil
+		metadata.VersionNumber = version.VersionNumber
+		err = downloadTaskDataAccessor.UpdateDownloadTaskMetadata(ctx, task.ID, metadata.String())
+		if err != nil {
+			return err
+		}
+		unusedFileNames = nil
+
+		versions, err := downloadTaskVersionDataAccessor.GetDownloadTaskVersionsByDownloadTaskID(ctx, task.ID)
+		if err != nil {
+			return err
+		}
+
+		for len(versions) > int(metadata.WatchOptions.RetentionCount) {
+			err = downloadTaskVersionDataAccessor.DeleteDownloadTaskVersion(ctx, versions[0].ID)
+			if err != nil {
+				return err
+			}
+			unusedFileNames = append(unusedFileNames, versions[0].FileName)
+			versions = versions[1:]
+		}
+
+		logger.Info("stored new version of watched download task")
+		return nil
+	})
+	if txErr != nil {
+		unusedFileNames = []string{version.FileName}
+	}
+
+	u.deleteFiles(ctx, unusedFileNames...)
+	return txErr
+}
+
+func (u urlWatcher) deleteFiles(ctx context.Context, fileNames ...string) {
+	for _, fileName := range fileNames {
+		err := u.fileClient.Delete(ctx, fileName)
+		if err != nil {
+			u.logger.With(zap.Error(err), zap.String("fileName", fileName)).Warn("failed to delete version file")
+		}
+	}
+}
@@ -0,0 +1,439 @@
+package logic
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/database"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	maxWebhookCountPerAccount = 10
+	webhookSecretByteCount    = 32
+	webhookEventIDByteCount   = 16
+)
+
+var (
+	errWebhookNotFound         = errors.New("webhook not found")
+	errInvalidWebhookURL       = errors.New("webhook URL must be an absolute HTTP or HTTPS URL")
+	errInvalidWebhookEventType = errors.New("invalid webhook event type")
+	errTooManyWebhooks         = errors.New("account has too many webhooks")
+)
+
+// webhookEventTypeNames are the names of the event types in delivered payloads.
+var webhookEventTypeNames = map[go_load.WebhookEventType]string{
+	go_load.WebhookEventType_DownloadTaskCreated:    "download_task.created",
+	go_load.WebhookEventType_DownloadTaskStarted:    "download_task.started",
+	go_load.WebhookEventType_DownloadTaskProgressed: "download_task.progressed",
+	go_load.WebhookEventType_DownloadTaskSucceeded:  "download_task.succeeded",
+	go_load.WebhookEventType_DownloadTaskFailed:     "download_task.failed",
+}
+
+type CreateWebhookParams struct {
+	Token string
+	URL   string
+	// EventTypes are the events the webhook receives, it receives all of them when empty
+	EventTypes []go_load.WebhookEventType
+}
+
+// CreateWebhookOutput carries the secret deliveries to the webhook are signed with, it is only ever returned here.
+type CreateWebhookOutput struct {
+	Webhook *go_load.Webhook
+	Secret  string
+}
+
+type GetWebhookListParams struct {
+	Token string
+}
+
+type DeleteWebhookParams struct {
+	Token     string
+	WebhookID uint64
+}
+
+type GetWebhookDeliveryListParams struct {
+	Token     string
+	WebhookID uint64
+	Offset    uint64
+	Limit     uint64
+}
+
+type GetWebhookDeliveryListOutput struct {
+	WebhookDeliveryList       []*go_load.WebhookDelivery
+	TotalWebhookDeliveryCount uint64
+}
+
+// WebhookHandler manages the webhooks of accounts, which receive download task events as signed JSON requests. The
+// delivery log of a webhook lists its deliveries newest first.
+type WebhookHandler interface {
+	CreateWebhook(ctx context.Context, params CreateWebhookParams) (CreateWebhookOutput, error)
+	GetWebhookList(ctx context.Context, params GetWebhookListParams) ([]*go_load.Webhook, error)
+	DeleteWebhook(ctx context.Context, params DeleteWebhookParams) error
+	GetWebhookDeliveryList(ctx context.Context, params GetWebhookDeliveryListParams) (GetWebhookDeliveryListOutput, error)
+}
+
+type webhookHandler struct {
+	tokenHandler                TokenHandler
+	webhookDataAccessor         database.WebhookDataAccessor
+	webhookDeliveryDataAccessor database.WebhookDeliveryDataAccessor
+	logger                      *zap.Logger
+}
+
+func NewWebhookHandler(
+	tokenHandler TokenHandler,
+	webhookDataAccessor database.WebhookDataAccessor,
+	webhookDeliveryDataAccessor database.WebhookDeliveryDataAccessor,
+	logger *zap.Logger,
+) WebhookHandler {
+	return &webhookHandler{
+		tokenHandler:                tokenHandler,
+		webhookDataAccessor:         webhookDataAccessor,
+		webhookDeliveryDataAccessor: webhookDeliveryDataAccessor,
+		logger:                      logger,
+	}
+}
+
+// joinWebhookEventTypes checks the event types of a webhook and returns them as stored in its event_types column.
+func joinWebhookEventTypes(eventTypes []go_load.WebhookEventType) (string, error) {
+	values := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if _, ok := webhookEventTypeNames[eventType]; !ok {
+			return "", errInvalidWebhookEventType
+		}
+
+		value := strconv.Itoa(int(eventType))
+		if !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	return strings.Join(values, ","), nil
+}
+
+// splitWebhookEventTypes returns the event types stored in the event_types column of a webhook.
+func splitWebhookEventTypes(eventTypes string) []go_load.WebhookEventType {
+	if eventTypes == "" {
+		return nil
+	}
+
+	values := strings.Split(eventTypes, ",")
+	webhookEventTypes := make([]go_load.WebhookEventType, 0, len(values))
+	for _, value := range values {
+		eventType, err := strconv.Atoi(value)
+		if err != nil {
+			continue
+		}
+		webhookEventTypes = append(webhookEventTypes, go_load.WebhookEventType(eventType))
+	}
+	return webhookEventTypes
+}
+
+func databaseWebhookToProto(webhook database.Webhook) *go_load.Webhook {
+	return &go_load.Webhook{
+		Id:         webhook.ID,
+		Url:        webhook.URL,
+		EventTypes: splitWebhookEventTypes(webhook.EventTypes),
+	}
+}
+
+func databaseWebhookDeliveryToProto(delivery database.WebhookDelivery) *go_load.WebhookDelivery {
+	var lastAttemptedAt *timestamppb.Timestamp
+	if delivery.LastAttemptedAt.Valid {
+		lastAttemptedAt = timestamppb.New(delivery.LastAttemptedAt.Time)
+	}
+
+	return &go_load.WebhookDelivery{
+		Id:              delivery.ID,
+		WebhookId:       delivery.OfWebhookID,
+		EventType:       go_load.WebhookEventType(delivery.EventType),
+		DownloadTaskId:  delivery.DownloadTaskID,
+		DeliveryStatus:  go_load.WebhookDeliveryStatus(delivery.DeliveryStatus),
+		AttemptCount:    delivery.AttemptCount,
+		CreatedAt:       timestamppb.New(delivery.CreatedAt),
+		NextAttemptAt:   timestamppb.New(delivery.NextAttemptAt),
+		LastAttemptedAt: lastAttemptedAt,
+		HttpStatusCode:  delivery.HTTPStatusCode,
+		ErrorMessage:    delivery.ErrorMessage,
+		Payload:         delivery.Payload,
+	}
+}
+
+// getOwnedWebhook returns the webhook only if it belongs to the account.
+func (w webhookHandler) getOwnedWebhook(ctx context.Context, accountID uint64, webhookID uint64) (database.Webhook, error) {
+	webhook, err := w.webhookDataAccessor.GetWebhookByID(ctx, webhookID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.Webhook{}, errWebhookNotFound
+		}
+		return database.Webhook{}, err
+	}
+
+	if webhook.OfAccountID != accountID {
+		w.logger.With(zap.Uint64("accountID", accountID), zap.Uint64("webhookID", webhookID)).Warn("account does not own webhook")
+		return database.Webhook{}, errWebhookNotFound
+	}
+
+	return webhook, nil
+}
+
+func (w webhookHandler) CreateWebhook(ctx context.Context, params CreateWebhookParams) (CreateWebhookOutput, error) {
+	accountID, _, err := w.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		w.logger.With(zap.Error(err)).Error("failed to verify token")
+		return CreateWebhookOutput{}, err
+	}
+
+	parsedURL, err := url.Parse(params.URL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return CreateWebhookOutput{}, errInvalidWebhookURL
+	}
+
+	eventTypes, err := joinWebhookEventTypes(params.EventTypes)
+	if err != nil {
+		return CreateWebhookOutput{}, err
+	}
+
+	webhooks, err := w.webhookDataAccessor.GetWebhooksByAccountID(ctx, accountID)
+	if err != nil {
+		return CreateWebhookOutput{}, err
+	}
+	if len(webhooks) >= maxWebhookCountPerAccount {
+		return CreateWebhookOutput{}, errTooManyWebhooks
+	}
+
+	secret := make([]byte, webhookSecretByteCount)
+	_, err = rand.Read(secret)
+	if err != nil {
+		return CreateWebhookOutput{}, err
+	}
+
+	webhook := database.Webhook{
+		OfAccountID: accountID,
+		URL:         params.URL,
+		Secret:      hex.EncodeToString(secret),
+		EventTypes:  eventTypes,
+	}
+	webhook.ID, err = w.webhookDataAccessor.CreateWebhook(ctx, webhook)
+	if err != nil {
+		return CreateWebhookOutput{}, err
+	}
+
+	return CreateWebhookOutput{
+		Webhook: databaseWebhookToProto(webhook),
+		Secret:  webhook.Secret,
+	}, nil
+}
+
+func (w webhookHandler) GetWebhookList(ctx context.Context, params GetWebhookListParams) ([]*go_load.Webhook, error) {
+	accountID, _, err := w.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		w.logger.With(zap.Error(err)).Error("failed to verify token")
+		return nil, err
+	}
+
+	webhooks, err := w.webhookDataAccessor.GetWebhooksByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookList := make([]*go_load.Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		webhookList = append(webhookList, databaseWebhookToProto(webhook))
+	}
+	return webhookList, nil
+}
+
+// DeleteWebhook removes the webhook together with its delivery log, deliveries not made yet are dropped.
+func (w webhookHandler) DeleteWebhook(ctx context.Context, params DeleteWebhookParams) error {
+	accountID, _, err := w.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		w.logger.With(zap.Error(err)).Error("failed to verify token")
+		return err
+	}
+
+	_, err = w.getOwnedWebhook(ctx, accountID, params.WebhookID)
+	if err != nil {
+		return err
+	}
+
+	return w.webhookDataAccessor.DeleteWebhook(ctx, params.WebhookID)
+}
+
+func (w webhookHandler) GetWebhookDeliveryList(ctx context.Context, params GetWebhookDeliveryListParams) (GetWebhookDeliveryListOutput, error) {
+	accountID, _, err := w.tokenHandler.GetAccountIDAndExpireTime(ctx, params.Token)
+	if err != nil {
+		w.logger.With(zap.Error(err)).Error("failed to verify token")
+		return GetWebhookDeliveryListOutput{}, err
+	}
+
+	_, err = w.getOwnedWebhook(ctx, accountID, params.WebhookID)
+	if err != nil {
+		return GetWebhookDeliveryListOutput{}, err
+	}
+
+	totalWebhookDeliveryCount, err := w.webhookDeliveryDataAccessor.GetWebhookDeliveryCountByWebhookID(ctx, params.WebhookID)
+	if err != nil {
+		return GetWebhookDeliveryListOutput{}, err
+	}
+
+	limit := getListPageLimit(params.Limit)
+	deliveries, err := w.webhookDeliveryDataAccessor.GetWebhookDeliveriesByWebhookID(ctx, params.WebhookID, params.Offset, limit)
+	if err != nil {
+		return GetWebhookDeliveryListOutput{}, err
+	}
+
+	webhookDeliveryList := make([]*go_load.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		webhookDeliveryList = append(webhookDeliveryList, databaseWebhookDeliveryToProto(delivery))
+	}
+
+	return GetWebhookDeliveryListOutput{
+		WebhookDeliveryList:       webhookDeliveryList,
+		TotalWebhookDeliveryCount: totalWebhookDeliveryCount,
+	}, nil
+}
+
+// WebhookEvent is something that happened to a download task, DownloadTask being the task as it is after the event.
+type WebhookEvent struct {
+	EventType    go_load.WebhookEventType
+	DownloadTask database.DownloadTask
+	// ProgressMilestone is the percentage of the download a DownloadTaskProgressed event reports passing
+	ProgressMilestone uint32
+}
+
+// webhookEventPayload is the JSON body delivered to webhooks.
+type webhookEventPayload struct {
+	EventID           string                     `json:"event_id"`
+	EventType         string                     `json:"event_type"`
+	CreatedAt         time.Time                  `json:"created_at"`
+	DownloadTask      webhookDownloadTaskPayload `json:"download_task"`
+	ProgressMilestone uint32                     `json:"progress_milestone,omitempty"`
+}
+
+type webhookDownloadTaskPayload struct {
+	ID                  uint64 `json:"id"`
+	AccountID           uint64 `json:"account_id"`
+	DownloadType        string `json:"download_type"`
+	URL                 string `json:"url"`
+	DownloadStatus      string `json:"download_status"`
+	DownloadedByteCount int64  `json:"downloaded_byte_count"`
+	TotalByteCount      int64  `json:"total_byte_count"`
+	AttemptCount        uint32 `json:"attempt_count"`
+	SHA256              string `json:"sha256,omitempty"`
+	FailureReason       string `json:"failure_reason,omitempty"`
+}
+
+// WebhookEventPublisher records download task events for delivery to the webhooks of the task's account receiving
+// them, WebhookDispatcher then delivers them. Events are best effort, failing to record one does not fail what caused
+// it, but once recorded a delivery is attempted until it succeeds or is dead-lettered.
+type WebhookEventPublisher interface {
+	Publish(ctx context.Context, event WebhookEvent)
+}
+
+type webhookEventPublisher struct {
+	webhookDataAccessor         database.WebhookDataAccessor
+	webhookDeliveryDataAccessor database.WebhookDeliveryDataAccessor
+	logger                      *zap.Logger
+}
+
+func NewWebhookEventPublisher(
+	webhookDataAccessor database.WebhookDataAccessor,
+	webhookDeliveryDataAccessor database.WebhookDeliveryDataAccessor,
+	logger *zap.Logger,
+) WebhookEventPublisher {
+	return &webhookEventPublisher{
+		webhookDataAccessor:         webhookDataAccessor,
+		webhookDeliveryDataAccessor: webhookDeliveryDataAccessor,
+		logger:                      logger,
+	}
+}
+
+func (w webhookEventPublisher) Publish(ctx context.Context, event WebhookEvent) {
+	// the event already happened, it is recorded even when the request that caused it is going away
+	ctx = context.WithoutCancel(ctx)
+	logger := w.logger.With(zap.Uint64("taskID", event.DownloadTask.ID), zap.String("eventType", event.EventType.String()))
+
+	webhooks, err := w.webhookDataAccessor.GetWebhooksByAccountID(ctx, event.DownloadTask.OfAccountID)
+	if err != nil {
+		logger.With(zap.Error(err)).Warn("failed to get webhooks to publish event to")
+		return
+	}
+
+	webhooks = slices.DeleteFunc(webhooks, func(webhook database.Webhook) bool {
+		eventTypes := splitWebhookEventTypes(webhook.EventTypes)
+		return len(eventTypes) > 0 && !slices.Contains(eventTypes, event.EventType)
+	})
+	if len(webhooks) == 0 {
+		return
+	}
+
+	payload, err := w.getPayload(event)
+	if err != nil {
+		logger.With(zap.Error(err)).Warn("failed to create webhook event payload")
+		return
+	}
+
+	now := time.Now()
+	for _, webhook := range webhooks {
+		_, err = w.webhookDeliveryDataAccessor.CreateWebhookDelivery(ctx, database.WebhookDelivery{
+			OfWebhookID:    webhook.ID,
+			EventType:      uint16(event.EventType),
+			DownloadTaskID: event.DownloadTask.ID,
+			Payload:        payload,
+			DeliveryStatus: uint16(go_load.WebhookDeliveryStatus_DeliveryPending),
+			CreatedAt:      now,
+			NextAttemptAt:  now,
+		})
+		if err != nil {
+			logger.With(zap.Error(err), zap.Uint64("webhookID", webhook.ID)).Warn("failed to record webhook delivery")
+		}
+	}
+}
+
+func (w webhookEventPublisher) getPayload(event WebhookEvent) (string, error) {
+	task := event.DownloadTask
+	metadata, err := parseDownloadTaskMetadata(task.Metadata)
+	if err != nil {
+		return "", err
+	}
+
+	eventID := make([]byte, webhookEventIDByteCount)
+	_, err = rand.Read(eventID)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(webhookEventPayload{
+		EventID:   hex.EncodeToString(eventID),
+		EventType: webhookEventTypeNames[event.EventType],
+		CreatedAt: time.Now().UTC(),
+		DownloadTask: webhookDownloadTaskPayload{
+			ID:                  task.ID,
+			AccountID:           task.OfAccountID,
+			DownloadType:        go_load.DownloadType(task.DownloadType).String(),
+			URL:                 task.URL,
+			DownloadStatus:      go_load.DownloadStatus(task.DownloadStatus).String(),
+			DownloadedByteCount: metadata.DownloadedByteCount,
+			TotalByteCount:      metadata.Size,
+			AttemptCount:        task.AttemptCount,
+			SHA256:              metadata.SHA256,
+			FailureReason:       metadata.FailureReason,
+		},
+		ProgressMilestone: event.ProgressMilestone,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(payload), nil
+}
@@ -0,0 +1,238 @@
+package logic
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/database"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/generated/grpc/go_load"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultWebhookRequestTimeout       = 10 * time.Second
+	webhookDeliveryBatchSize           = 100
+	maxConcurrentWebhookDeliveryCount  = 8
+	maxWebhookDeliveryErrorMessageSize = 1024
+
+	// the signature is the hex HMAC-SHA256 of the timestamp header, a dot and the body, keyed with the webhook secret
+	webhookSignatureHeader = "X-GoLoad-Signature"
+	webhookTimestampHeader = "X-GoLoad-Timestamp"
+	webhookEventHeader     = "X-GoLoad-Event"
+	webhookDeliveryHeader  = "X-GoLoad-Delivery"
+)
+
+// getWebhookSignature returns the value of the signature header of a delivery. Receivers compute it again from the
+// body and timestamp they got and compare, after checking the timestamp is recent to turn away replayed deliveries.
+func getWebhookSignature(secret string, timestamp int64, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write([]byte(payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookDispatcher delivers recorded webhook events. A delivery that fails, on anything but a 2xx response, is retried
+// with backoff and dead-lettered once it is out of attempts. Servers sharing the database claim each due delivery
+// before attempting it, so that it is only attempted by one of them at a time.
+type WebhookDispatcher interface {
+	Start(ctx context.Context) error
+}
+
+type webhookDispatcher struct {
+	webhookDataAccessor         database.WebhookDataAccessor
+	webhookDeliveryDataAccessor database.WebhookDeliveryDataAccessor
+	retryPolicy                 downloadRetryPolicy
+	httpClient                  *http.Client
+	pollInterval                time.Duration
+	requestTimeout              time.Duration
+	logger                      *zap.Logger
+}
+
+func NewWebhookDispatcher(
+	configs configs.WebhookConfig,
+	webhookDataAccessor database.WebhookDataAccessor,
+	webhookDeliveryDataAccessor database.WebhookDeliveryDataAccessor,
+	logger *zap.Logger,
+) (WebhookDispatcher, error) {
+	pollInterval := defaultPollInterval
+	if configs.PollInterval != "" {
+		var err error
+		pollInterval, err = configs.GetPollIntervalDuration()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	requestTimeout := defaultWebhookRequestTimeout
+	if configs.RequestTimeout != "" {
+		var err error
+		requestTimeout, err = configs.GetRequestTimeoutDuration()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	retryPolicy, err := newDownloadRetryPolicy(configs.RetryConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookDispatcher{
+		webhookDataAccessor:         webhookDataAccessor,
+		webhookDeliveryDataAccessor: webhookDeliveryDataAccessor,
+		retryPolicy:                 retryPolicy,
+		httpClient: &http.Client{
+			Timeout: requestTimeout,
+			// a redirect is not a delivery, the receiver has to answer at the registered URL
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		pollInterval:   pollInterval,
+		requestTimeout: requestTimeout,
+		logger:         logger,
+	}, nil
+}
+
+func (w webhookDispatcher) Start(ctx context.Context) error {
+	w.logger.With(zap.Duration("pollInterval", w.pollInterval)).Info("starting webhook dispatcher")
+
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.deliverDueWebhookDeliveries(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w webhookDispatcher) deliverDueWebhookDeliveries(ctx context.Context) {
+	now := time.Now()
+	deliveries, err := w.webhookDeliveryDataAccessor.GetWebhookDeliveriesByStatusDueBefore(
+		ctx, uint16(go_load.WebhookDeliveryStatus_DeliveryPending), now, webhookDeliveryBatchSize)
+	if err != nil {
+		w.logger.With(zap.Error(err)).Error("failed to get due webhook deliveries")
+		return
+	}
+
+	semaphore := make(chan struct{}, maxConcurrentWebhookDeliveryCount)
+	var waitGroup sync.WaitGroup
+	for _, delivery := range deliveries {
+		// the delivery is pushed back past the time an attempt can take, if this server goes away while delivering it
+		// another one picks it up then
+		claimed, err := w.webhookDeliveryDataAccessor.UpdateWebhookDeliveryNextAttemptAtIfDue(
+			ctx, delivery.ID, uint16(go_load.WebhookDeliveryStatus_DeliveryPending), now, now.Add(2*w.requestTimeout))
+		if err != nil {
+			w.logger.With(zap.Error(err), zap.Uint64("deliveryID", delivery.ID)).Error("failed to claim webhook delivery")
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		semaphore <- struct{}{}
+		waitGroup.Add(1)
+		go func(delivery database.WebhookDelivery) {
+			defer func() {
+				<-semaphore
+				waitGroup.Done()
+			}()
+			w.attemptWebhookDelivery(ctx, delivery)
+		}(delivery)
+	}
+	waitGroup.Wait()
+}
+
+// attemptWebhookDelivery sends the delivery once and records how it went.
+func (w webhookDispatcher) attemptWebhookDelivery(ctx context.Context, delivery database.WebhookDelivery) {
+	logger := w.logger.With(zap.Uint64("deliveryID", delivery.ID), zap.Uint64("webhookID", delivery.OfWebhookID))
+
+	webhook, err := w.webhookDataAccessor.GetWebhookByID(ctx, delivery.OfWebhookID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.With(zap.Error(err)).Warn("failed to get webhook of delivery")
+		}
+		// a deleted webhook takes its deliveries with it, the claim runs out otherwise and it is tried again
+		return
+	}
+
+	now := time.Now()
+	httpStatusCode, err := w.sendWebhookDelivery(ctx, webhook, delivery, now)
+
+	delivery.AttemptCount++
+	delivery.LastAttemptedAt = sql.NullTime{Time: now, Valid: true}
+	delivery.HTTPStatusCode = uint32(httpStatusCode)
+	delivery.ErrorMessage = ""
+	switch {
+	case err == nil:
+		delivery.DeliveryStatus = uint16(go_load.WebhookDeliveryStatus_Delivered)
+		logger.Info("webhook delivery succeeded")
+	case delivery.AttemptCount >= w.retryPolicy.maxAttemptCount:
+		delivery.DeliveryStatus = uint16(go_load.WebhookDeliveryStatus_DeadLettered)
+		delivery.ErrorMessage = err.Error()
+		logger.With(zap.Error(err), zap.Uint32("attemptCount", delivery.AttemptCount)).Warn("webhook delivery dead-lettered")
+	default:
+		delivery.NextAttemptAt = now.Add(w.retryPolicy.getBackoff(delivery.AttemptCount))
+		delivery.ErrorMessage = err.Error()
+		logger.With(zap.Error(err), zap.Time("nextAttemptAt", delivery.NextAttemptAt)).Info("webhook delivery failed, retrying it later")
+	}
+	if len(delivery.ErrorMessage) > maxWebhookDeliveryErrorMessageSize {
+		delivery.ErrorMessage = delivery.ErrorMessage[:maxWebhookDeliveryErrorMessageSize]
+	}
+
+	// the attempt was made, how it went is recorded even on shutdown
+	err = w.webhookDeliveryDataAccessor.UpdateWebhookDelivery(context.WithoutCancel(ctx), delivery)
+	if err != nil {
+		logger.With(zap.Error(err)).Error("failed to record webhook delivery attempt")
+	}
+}
+
+// sendWebhookDelivery posts the payload of the delivery to the webhook, returning the status code of the response
+// when there was one.
+func (w webhookDispatcher) sendWebhookDelivery(
+	ctx context.Context,
+	webhook database.Webhook,
+	delivery database.WebhookDelivery,
+	now time.Time,
+) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := now.Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "GoLoad-Webhook")
+	request.Header.Set(webhookEventHeader, webhookEventTypeNames[go_load.WebhookEventType(delivery.EventType)])
+	request.Header.Set(webhookDeliveryHeader, strconv.FormatUint(delivery.ID, 10))
+	request.Header.Set(webhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(webhookSignatureHeader, getWebhookSignature(webhook.Secret, timestamp, delivery.Payload))
+
+	response, err := w.httpClient.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return response.StatusCode, fmt.Errorf("unexpected response status: %s", response.Status)
+	}
+	return response.StatusCode, nil
+}
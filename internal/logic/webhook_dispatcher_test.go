@@ -0,0 +1,48 @@
+package logic
+
+import "testing"
+
+func TestGetWebhookSignature(t *testing.T) {
+	const payload = `{"event":"download_task.completed"}`
+
+	testCases := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		payload   string
+		want      string
+	}{
+		{
+			name:      "delivery",
+			secret:    "whsec_test",
+			timestamp: 1700000000,
+			payload:   payload,
+			want:      "sha256=d93cf467f28a5779e50caddf61ced7c1397957d58e7737647a771fcbde1ba595",
+		},
+		{
+			name:      "other secret",
+			secret:    "other",
+			timestamp: 1700000000,
+			payload:   payload,
+			want:      "sha256=3f2dc2b20c61ef7c33fcd8c43a020c48e9db60738767104f929e95b1a4754a4d",
+		},
+		{
+			name: "empty secret and payload",
+			want: "sha256=b849d5a581847b281957065739df36df2463d1977ea8d6e1e4e6cf33fadc68c3",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := getWebhookSignature(testCase.secret, testCase.timestamp, testCase.payload)
+			if got != testCase.want {
+				t.Errorf("getWebhookSignature() = %s, want %s", got, testCase.want)
+			}
+		})
+	}
+
+	// the timestamp is signed, so a captured delivery cannot be replayed with a fresh one
+	if getWebhookSignature("whsec_test", 1700000000, payload) == getWebhookSignature("whsec_test", 1700000001, payload) {
+		t.Errorf("getWebhookSignature() does not depend on the timestamp")
+	}
+}
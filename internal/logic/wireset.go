@@ -6,4 +6,21 @@ var WireSet = wire.NewSet(
 	NewAccountHandler,
 	NewHashHandler,
     NewTokenHandler,
+    NewDownloadTaskHandler,
+    NewDownloadQueueHandler,
+    NewDownloadTaskExecutor,
+    NewDownloadTaskScheduler,
+    NewDownloaderRegistry,
+    NewHTTPDownloader,
+    NewFTPDownloader,
+    NewSFTPDownloader,
+    NewStreamDownloader,
+    NewBitTorrentDownloader,
+    NewS3Downloader,
+    NewBandwidthLimiter,
+    NewFairShareHandler,
+    NewWebhookHandler,
+    NewWebhookEventPublisher,
+    NewWebhookDispatcher,
+    NewURLWatcher,
 )
@@ -7,6 +7,7 @@ package wiring
 
 import (
 	"github.com/google/wire"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/app"
 	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
 	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess"
 	"github.com/quockhanhcao/my-internet-download-manager/internal/handler"
@@ -21,9 +22,15 @@ var WireSet = wire.NewSet(
 	logic.WireSet,
 	handler.WireSet,
 	utils.WireSet,
+	app.WireSet,
 )
 
 func InitializeGRPCServer(configFilePath configs.ConfigFilePath) (grpc.Server, func(), error) {
 	wire.Build(WireSet)
 	return nil, nil, nil
 }
+
+func InitializeServer(configFilePath configs.ConfigFilePath) (*app.Server, func(), error) {
+	wire.Build(WireSet)
+	return nil, nil, nil
+}
@@ -8,12 +8,15 @@ package wiring
 
 import (
 	"github.com/google/wire"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/app"
 	"github.com/quockhanhcao/my-internet-download-manager/internal/configs"
 	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess"
 	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/cache"
 	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/database"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/dataacess/file"
 	"github.com/quockhanhcao/my-internet-download-manager/internal/handler"
 	"github.com/quockhanhcao/my-internet-download-manager/internal/handler/grpc"
+	"github.com/quockhanhcao/my-internet-download-manager/internal/handler/http"
 	"github.com/quockhanhcao/my-internet-download-manager/internal/logic"
 	"github.com/quockhanhcao/my-internet-download-manager/internal/utils"
 )
@@ -60,7 +63,58 @@ func InitializeGRPCServer(configFilePath configs.ConfigFilePath) (grpc.Server, f
 	}
 	accountNameCache := cache.NewAccountNameCache(cacheCache, logger)
 	accountHandler := logic.NewAccountHandler(accountDataAccessor, accountPasswordDataAccessor, tokenPublicKeyDataAccessor, hashHandler, tokenHandler, goquDatabase, logger, accountNameCache)
-	goLoadServiceServer := grpc.NewHandler(accountHandler)
+	downloadConfig := config.DownloadConfig
+	downloadTaskDataAccessor := database.NewDownloadTaskDataAccessor(goquDatabase, logger)
+	downloadQueueDataAccessor := database.NewDownloadQueueDataAccessor(goquDatabase, logger)
+	downloadTaskAttemptDataAccessor := database.NewDownloadTaskAttemptDataAccessor(goquDatabase, logger)
+	blobDataAccessor := database.NewBlobDataAccessor(goquDatabase, logger)
+	downloadTaskVersionDataAccessor := database.NewDownloadTaskVersionDataAccessor(goquDatabase, logger)
+	fileClient, err := file.NewLocalClient(downloadConfig, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	httpDownloader := logic.NewHTTPDownloader(fileClient, logger)
+	ftpDownloader, err := logic.NewFTPDownloader(downloadConfig, fileClient, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	sftpDownloader, err := logic.NewSFTPDownloader(downloadConfig, fileClient, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	streamDownloader := logic.NewStreamDownloader(fileClient, logger)
+	bitTorrentDownloader, err := logic.NewBitTorrentDownloader(downloadConfig, fileClient, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	s3Downloader := logic.NewS3Downloader(downloadConfig, fileClient, logger)
+	downloaderRegistry := logic.NewDownloaderRegistry(httpDownloader, ftpDownloader, sftpDownloader, streamDownloader, bitTorrentDownloader, s3Downloader)
+	webhookDataAccessor := database.NewWebhookDataAccessor(goquDatabase, logger)
+	webhookDeliveryDataAccessor := database.NewWebhookDeliveryDataAccessor(goquDatabase, logger)
+	webhookEventPublisher := logic.NewWebhookEventPublisher(webhookDataAccessor, webhookDeliveryDataAccessor, logger)
+	downloadTaskHandler, err := logic.NewDownloadTaskHandler(downloadConfig, tokenHandler, accountDataAccessor, downloadTaskDataAccessor, downloadQueueDataAccessor, downloadTaskAttemptDataAccessor, blobDataAccessor, downloadTaskVersionDataAccessor, fileClient, downloaderRegistry, webhookEventPublisher, goquDatabase, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	downloadQueueHandler := logic.NewDownloadQueueHandler(tokenHandler, downloadQueueDataAccessor, downloadTaskDataAccessor, goquDatabase, logger)
+	fairShareHandler := logic.NewFairShareHandler(authConfig, downloadConfig, tokenHandler, accountDataAccessor, downloadTaskDataAccessor, logger)
+	webhookHandler := logic.NewWebhookHandler(tokenHandler, webhookDataAccessor, webhookDeliveryDataAccessor, logger)
+	goLoadServiceServer := grpc.NewHandler(accountHandler, downloadTaskHandler, downloadQueueHandler, fairShareHandler, webhookHandler)
 	server := grpc.NewServer(goLoadServiceServer)
 	return server, func() {
 		cleanup3()
@@ -69,6 +123,145 @@ func InitializeGRPCServer(configFilePath configs.ConfigFilePath) (grpc.Server, f
 	}, nil
 }
 
+func InitializeServer(configFilePath configs.ConfigFilePath) (*app.Server, func(), error) {
+	config, err := configs.NewConfig(configFilePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	databaseConfig := config.DatabaseConfig
+	db, cleanup, err := database.InitializeDB(databaseConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	goquDatabase := database.InitializeGoquDB(db)
+	logConfig := config.LogConfig
+	logger, cleanup2, err := utils.InitializeLogger(logConfig)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	accountDataAccessor := database.NewAccountDataAccessor(goquDatabase, logger)
+	accountPasswordDataAccessor := database.NewAccountPasswordDataAccessor(goquDatabase, logger)
+	tokenPublicKeyDataAccessor := database.NewTokenPublicKeyDataAccessor(goquDatabase, logger)
+	authConfig := config.AuthConfig
+	hashHandler := logic.NewHashHandler(authConfig, logger)
+	cacheConfig := config.CacheConfig
+	client, cleanup3, err := cache.InitializeRedisClient(cacheConfig, logger)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	cacheCache := cache.NewRedisClient(client, logger)
+	tokenPublicKeyCache := cache.NewTokenPublicKeyCache(cacheCache, logger)
+	tokenHandler, err := logic.NewTokenHandler(authConfig, tokenPublicKeyDataAccessor, accountDataAccessor, tokenPublicKeyCache, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	accountNameCache := cache.NewAccountNameCache(cacheCache, logger)
+	accountHandler := logic.NewAccountHandler(accountDataAccessor, accountPasswordDataAccessor, tokenPublicKeyDataAccessor, hashHandler, tokenHandler, goquDatabase, logger, accountNameCache)
+	downloadConfig := config.DownloadConfig
+	downloadTaskDataAccessor := database.NewDownloadTaskDataAccessor(goquDatabase, logger)
+	downloadQueueDataAccessor := database.NewDownloadQueueDataAccessor(goquDatabase, logger)
+	downloadTaskAttemptDataAccessor := database.NewDownloadTaskAttemptDataAccessor(goquDatabase, logger)
+	blobDataAccessor := database.NewBlobDataAccessor(goquDatabase, logger)
+	downloadTaskVersionDataAccessor := database.NewDownloadTaskVersionDataAccessor(goquDatabase, logger)
+	fileClient, err := file.NewLocalClient(downloadConfig, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	httpDownloader := logic.NewHTTPDownloader(fileClient, logger)
+	ftpDownloader, err := logic.NewFTPDownloader(downloadConfig, fileClient, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	sftpDownloader, err := logic.NewSFTPDownloader(downloadConfig, fileClient, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	streamDownloader := logic.NewStreamDownloader(fileClient, logger)
+	bitTorrentDownloader, err := logic.NewBitTorrentDownloader(downloadConfig, fileClient, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	s3Downloader := logic.NewS3Downloader(downloadConfig, fileClient, logger)
+	downloaderRegistry := logic.NewDownloaderRegistry(httpDownloader, ftpDownloader, sftpDownloader, streamDownloader, bitTorrentDownloader, s3Downloader)
+	webhookDataAccessor := database.NewWebhookDataAccessor(goquDatabase, logger)
+	webhookDeliveryDataAccessor := database.NewWebhookDeliveryDataAccessor(goquDatabase, logger)
+	webhookEventPublisher := logic.NewWebhookEventPublisher(webhookDataAccessor, webhookDeliveryDataAccessor, logger)
+	downloadTaskHandler, err := logic.NewDownloadTaskHandler(downloadConfig, tokenHandler, accountDataAccessor, downloadTaskDataAccessor, downloadQueueDataAccessor, downloadTaskAttemptDataAccessor, blobDataAccessor, downloadTaskVersionDataAccessor, fileClient, downloaderRegistry, webhookEventPublisher, goquDatabase, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	downloadQueueHandler := logic.NewDownloadQueueHandler(tokenHandler, downloadQueueDataAccessor, downloadTaskDataAccessor, goquDatabase, logger)
+	fairShareHandler := logic.NewFairShareHandler(authConfig, downloadConfig, tokenHandler, accountDataAccessor, downloadTaskDataAccessor, logger)
+	webhookHandler := logic.NewWebhookHandler(tokenHandler, webhookDataAccessor, webhookDeliveryDataAccessor, logger)
+	goLoadServiceServer := grpc.NewHandler(accountHandler, downloadTaskHandler, downloadQueueHandler, fairShareHandler, webhookHandler)
+	server := grpc.NewServer(goLoadServiceServer)
+	httpServer := http.NewServer()
+	postDownloadConfig := config.PostDownloadConfig
+	webhookConfig := config.WebhookConfig
+	bandwidthLimiter, err := logic.NewBandwidthLimiter(downloadConfig, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	downloadTaskExecutor, err := logic.NewDownloadTaskExecutor(downloadConfig, postDownloadConfig, webhookConfig, downloadTaskDataAccessor, downloadQueueDataAccessor, accountDataAccessor, downloadTaskAttemptDataAccessor, blobDataAccessor, downloaderRegistry, bandwidthLimiter, webhookEventPublisher, fileClient, goquDatabase, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	downloadTaskScheduler, err := logic.NewDownloadTaskScheduler(downloadConfig, downloadTaskDataAccessor, downloadQueueDataAccessor, fileClient, webhookEventPublisher, goquDatabase, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	webhookDispatcher, err := logic.NewWebhookDispatcher(webhookConfig, webhookDataAccessor, webhookDeliveryDataAccessor, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	urlWatcher, err := logic.NewURLWatcher(downloadConfig, downloadTaskDataAccessor, downloadTaskVersionDataAccessor, fileClient, goquDatabase, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	appServer := app.NewServer(server, httpServer, downloadTaskExecutor, downloadTaskScheduler, webhookDispatcher, urlWatcher, logger)
+	return appServer, func() {
+		cleanup3()
+		cleanup2()
+		cleanup()
+	}, nil
+}
+
 // wire.go:
 
-var WireSet = wire.NewSet(configs.WireSet, dataacess.WireSet, logic.WireSet, handler.WireSet, utils.WireSet)
+var WireSet = wire.NewSet(configs.WireSet, dataacess.WireSet, logic.WireSet, handler.WireSet, utils.WireSet, app.WireSet)